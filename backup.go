@@ -0,0 +1,106 @@
+package godis
+
+import "io"
+
+const defaultBackupScanCount = 100
+
+//KeyDump the serialized value of a key,created by BackupKeys
+type KeyDump struct {
+	Key   string
+	Value []byte //value serialized by DUMP
+	TTL   int64  //remaining time to live in milliseconds when the key was dumped,0 means no expire
+}
+
+//BackupKeys scan the keys matching pattern and dump them with their ttl,
+//keys deleted or expired during the scan are skipped.
+//the dumps can be restored to another redis by RestoreKeys,
+//the serialized format is only compatible with the same or a newer redis version
+func (r *Redis) BackupKeys(pattern string) ([]KeyDump, error) {
+	dumps := make([]KeyDump, 0)
+	params := NewScanParams().Match(pattern).Count(defaultBackupScanCount)
+	cursor := "0"
+	for {
+		result, err := r.Scan(cursor, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range result.Results {
+			dump, ok, err := r.dumpKey(key)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				dumps = append(dumps, dump)
+			}
+		}
+		cursor = result.Cursor
+		if cursor == "0" {
+			break
+		}
+	}
+	return dumps, nil
+}
+
+func (r *Redis) dumpKey(key string) (KeyDump, bool, error) {
+	value, err := r.Dump(key)
+	if err == ErrNil || (err == nil && value == nil) {
+		return KeyDump{}, false, nil
+	}
+	if err != nil {
+		return KeyDump{}, false, err
+	}
+	ttl, err := r.PTTL(key)
+	if err != nil {
+		return KeyDump{}, false, err
+	}
+	switch {
+	case ttl == -2:
+		//expired after DUMP
+		return KeyDump{}, false, nil
+	case ttl < 0:
+		ttl = 0
+	}
+	return KeyDump{Key: key, Value: value, TTL: ttl}, true, nil
+}
+
+//RestoreKeys restore the dumps created by BackupKeys,the ttl of every key is counted from now,
+//an existing key is replaced if replace is true,otherwise restoring stops with the BUSYKEY error
+//
+//return the count of restored keys
+func (r *Redis) RestoreKeys(dumps []KeyDump, replace bool) (int, error) {
+	count := 0
+	for _, dump := range dumps {
+		var err error
+		if replace {
+			_, err = r.RestoreReplace(dump.Key, int(dump.TTL), dump.Value)
+		} else {
+			_, err = r.Restore(dump.Key, int(dump.TTL), dump.Value)
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+//SyncDump download a snapshot of the whole dataset in the RDB format by the replication handshake,PSYNC,
+//and write it to w,such as to back up a redis without access to its files or redis-cli.
+//the master creates the snapshot like BGSAVE,the newlines it sends meanwhile keep the read from timing out.
+//after the snapshot the master streams its writes to the connection like to a replica,
+//so the connection is closed when SyncDump returns,the next command dials again
+//
+//return the size of the snapshot written to w
+func (r *Redis) SyncDump(w io.Writer) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	defer r.client.close()
+	err = r.client.psync("?", -1)
+	if err != nil {
+		return 0, err
+	}
+	_, _, n, err := r.client.getSyncPayload(w)
+	return n, err
+}
@@ -0,0 +1,98 @@
+package godis
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestRedis_BackupKeys(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	for i := 0; i < 150; i++ {
+		redis.Set("backup:"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	redis.Set("other", "good")
+	redis.PExpire("backup:0", 100000)
+
+	dumps, err := redis.BackupKeys("backup:*")
+	assert.Nil(t, err)
+	assert.Len(t, dumps, 150)
+	for _, dump := range dumps {
+		if dump.Key == "backup:0" {
+			assert.True(t, dump.TTL > 0 && dump.TTL <= 100000)
+		} else {
+			assert.Equal(t, int64(0), dump.TTL)
+		}
+	}
+
+	target := NewRedis(&Option{Host: "localhost", Port: 6379, Db: 1})
+	defer target.Close()
+	c, err := target.RestoreKeys(dumps, false)
+	assert.Nil(t, err)
+	assert.Equal(t, 150, c)
+	s, err := target.Get("backup:149")
+	assert.Nil(t, err)
+	assert.Equal(t, "149", s)
+	ttl, err := target.PTTL("backup:0")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+	exists, err := target.Exists("other")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), exists)
+
+	c, err = target.RestoreKeys(dumps, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, c)
+	c, err = target.RestoreKeys(dumps, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 150, c)
+
+	dumps, err = redis.BackupKeys("missing:*")
+	assert.Nil(t, err)
+	assert.Empty(t, dumps)
+
+	redisBroken := NewRedis(&Option{Host: "localhost1"})
+	defer redisBroken.Close()
+	_, err = redisBroken.BackupKeys("backup:*")
+	assert.NotNil(t, err)
+	_, err = redisBroken.RestoreKeys([]KeyDump{{Key: "backup:0"}}, true)
+	assert.NotNil(t, err)
+}
+
+func TestRedis_SyncDump(t *testing.T) {
+	option, closeServer := newFakeServer(t, map[string][]string{
+		"PSYNC ? -1": {"+FULLRESYNC 8de1787ba490483314a4d30f1c628bc5025eb761 0\r\n\n\n$9\r\nREDIS0009*3\r\n$3\r\nSET\r\n"},
+		"PING":       {"+PONG\r\n"},
+		"QUIT":       {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(option)
+	defer redis.Close()
+	var buf bytes.Buffer
+	n, err := redis.SyncDump(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(9), n)
+	assert.Equal(t, "REDIS0009", buf.String())
+	//the replication link is closed,the next command dials again
+	assert.False(t, redis.client.isConnected())
+	s, err := redis.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+}
+
+func TestRedis_SyncDumpError(t *testing.T) {
+	option, closeServer := newFakeServer(t, map[string][]string{
+		"PSYNC ? -1": {"-NOPERM this user has no permissions to run the 'psync' command\r\n"},
+		"QUIT":       {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(option)
+	defer redis.Close()
+	var buf bytes.Buffer
+	_, err := redis.SyncDump(&buf)
+	assert.EqualError(t, err, "NOPERM this user has no permissions to run the 'psync' command")
+	assert.Equal(t, 0, buf.Len())
+}
@@ -0,0 +1,324 @@
+package godis
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultParallelWorkers = 8
+
+const (
+	//TTLNoExpiry the ttl of TtlMulti for a key without expire
+	TTLNoExpiry time.Duration = -1
+	//TTLMissing the ttl of TtlMulti for a key which doesn't exist
+	TTLMissing time.Duration = -2
+)
+
+//MGetResult the value of a key got by MGetParallel
+type MGetResult struct {
+	Key   string
+	Value string
+	Found bool  //false if key does not exist or isn't a string
+	Err   error //error of the MGET of the slot of key,the keys of the other slots are unaffected
+}
+
+//objArrBuilder keep the multi bulk reply as it is,so nil elements are told from empty strings
+type objArrBuilder struct {
+}
+
+func (b *objArrBuilder) build(data interface{}) (interface{}, error) {
+	if data == nil {
+		return []interface{}{}, nil
+	}
+	switch data.(type) {
+	case []interface{}:
+		return data, nil
+	}
+	return nil, fmt.Errorf("unexpected type:%T", data)
+}
+
+//zipValues map the keys to their values,the nil values of missing keys are left out
+func zipValues(keys []string, values [][]byte) map[string]string {
+	m := make(map[string]string, len(keys))
+	for i, value := range values {
+		if i < len(keys) && value != nil {
+			m[keys[i]] = string(value)
+		}
+	}
+	return m
+}
+
+//objArrToBytesArr convert the elements of a multi bulk reply,nil elements are nil
+func objArrToBytesArr(arr []interface{}) [][]byte {
+	values := make([][]byte, 0, len(arr))
+	for _, item := range arr {
+		value, _ := item.([]byte)
+		values = append(values, value)
+	}
+	return values
+}
+
+//pttlToDuration convert the reply of PTTL,the negative replies are TTLNoExpiry and TTLMissing
+func pttlToDuration(ttl int64) time.Duration {
+	if ttl < 0 {
+		return time.Duration(ttl)
+	}
+	return time.Duration(ttl) * time.Millisecond
+}
+
+//<editor-fold desc="batchcommands">
+
+//HGetAllMulti get all the fields and values of every hash in keys,
+//the HGETALL commands are pipelined,so it takes one round trip,
+//return the hashes by key,a key which doesn't exist has an empty hash
+func (r *Redis) HGetAllMulti(keys ...string) (map[string]map[string]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if err := r.client.hgetAll(key); err != nil {
+			return nil, err
+		}
+	}
+	hashes := make(map[string]map[string]string, len(keys))
+	var firstErr error
+	for _, key := range keys {
+		//every reply is read even if one fails,so the connection is left clean
+		hash, err := StrArrToMapReply(r.client.getMultiBulkReply())
+		if err != nil {
+			if _, ok := err.(*ConnectError); ok {
+				return nil, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		hashes[key] = hash
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return hashes, nil
+}
+
+//MGetMap get the values of keys by MGET,
+//return the values by key,the keys which don't exist or aren't strings are left out
+func (r *Redis) MGetMap(keys ...string) (map[string]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.mget(keys...)
+	if err != nil {
+		return nil, err
+	}
+	values, err := r.client.getBinaryMultiBulkReply()
+	if err != nil {
+		return nil, err
+	}
+	return zipValues(keys, values), nil
+}
+
+//TtlMulti get the ttl of every key in keys by PTTL,such as to audit the expires of a cache,
+//the PTTL commands are pipelined,so it takes one round trip,
+//return the ttls by key,TTLMissing for a key which doesn't exist and TTLNoExpiry for a key without expire
+func (r *Redis) TtlMulti(keys ...string) (map[string]time.Duration, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if err := r.client.pttl(key); err != nil {
+			return nil, err
+		}
+	}
+	ttls := make(map[string]time.Duration, len(keys))
+	var firstErr error
+	for _, key := range keys {
+		//every reply is read even if one fails,so the connection is left clean
+		ttl, err := r.client.getIntegerReply()
+		if err != nil {
+			if _, ok := err.(*ConnectError); ok {
+				return nil, err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		ttls[key] = pttlToDuration(ttl)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return ttls, nil
+}
+
+//HGetAllMulti get the hashes of keys in any slots,
+//every node gets the HGETALL of its keys pipelined in parallel,see comment in batch.go
+func (r *RedisCluster) HGetAllMulti(keys ...string) (map[string]map[string]string, error) {
+	p := r.Pipelined()
+	responses := make([]*Response, 0, len(keys))
+	for _, key := range keys {
+		resp, err := p.queue(key, StrArrBuilder, cmdHGetAll, []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+	}
+	if err := p.Sync(); err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]map[string]string, len(keys))
+	for i, resp := range responses {
+		hash, err := StrArrToMapReply(ToStrArrReply(resp.Get()))
+		if err != nil {
+			return nil, err
+		}
+		hashes[keys[i]] = hash
+	}
+	return hashes, nil
+}
+
+//TtlMulti get the ttls of keys in any slots,
+//every node gets the PTTL of its keys pipelined in parallel,see comment in batch.go
+func (r *RedisCluster) TtlMulti(keys ...string) (map[string]time.Duration, error) {
+	p := r.Pipelined()
+	responses := make([]*Response, 0, len(keys))
+	for _, key := range keys {
+		resp, err := p.queue(key, Int64Builder, cmdPTTL, []byte(key))
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+	}
+	if err := p.Sync(); err != nil {
+		return nil, err
+	}
+	ttls := make(map[string]time.Duration, len(keys))
+	for i, resp := range responses {
+		ttl, err := ToInt64Reply(resp.Get())
+		if err != nil {
+			return nil, err
+		}
+		ttls[keys[i]] = pttlToDuration(ttl)
+	}
+	return ttls, nil
+}
+
+//MGetMap get the values of keys in any slots,
+//the keys are split by slot and every node gets the MGET of its slots in parallel,see comment in batch.go
+func (r *RedisCluster) MGetMap(keys ...string) (map[string]string, error) {
+	p := r.Pipelined()
+	groups := groupBySlot(keys)
+	responses := make([]*Response, 0, len(groups))
+	for _, group := range groups {
+		resp, err := p.queue(group.keys[0], &objArrBuilder{}, cmdMGet, StrArrToByteArrArr(group.keys)...)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+	}
+	if err := p.Sync(); err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(keys))
+	for i, group := range groups {
+		obj, err := responses[i].Get()
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range zipValues(group.keys, objArrToBytesArr(obj.([]interface{}))) {
+			m[key] = value
+		}
+	}
+	return m, nil
+}
+
+//MGetParallel get the values of keys in any slots,
+//the keys are split by slot and the MGET of every slot runs as a command of its own,
+//with the retries and redirections of the cluster,at most ClusterOption.ParallelWorkers at a time.
+//unlike MGetMap,a failed slot doesn't fail the others,
+//return the results in the order of keys,the keys of a failed slot have its error
+func (r *RedisCluster) MGetParallel(keys ...string) []MGetResult {
+	results := make([]MGetResult, len(keys))
+	for i, key := range keys {
+		results[i].Key = key
+	}
+	groups := groupBySlot(keys)
+	workers := r.parallelWorkers
+	if workers <= 0 {
+		workers = defaultParallelWorkers
+	}
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+	queue := make(chan *slotGroup)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range queue {
+				r.mgetSlot(group, results)
+			}
+		}()
+	}
+	for _, group := range groups {
+		queue <- group
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
+
+//mgetSlot get the values of the keys of one slot into results,at the indexes of the keys
+func (r *RedisCluster) mgetSlot(group *slotGroup, results []MGetResult) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.MGetMap(group.keys...)
+	}
+	reply, err := command.runBatch(len(group.keys), group.keys...)
+	for i, index := range group.indexes {
+		if err != nil {
+			results[index].Err = err
+			continue
+		}
+		results[index].Value, results[index].Found = reply.(map[string]string)[group.keys[i]]
+	}
+}
+
+//HGetAllMulti  see comment in batch.go
+func (p *PooledRedis) HGetAllMulti(keys ...string) (map[string]map[string]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HGetAllMulti(keys...)
+}
+
+//MGetMap  see comment in batch.go
+func (p *PooledRedis) MGetMap(keys ...string) (map[string]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.MGetMap(keys...)
+}
+
+//TtlMulti  see comment in batch.go
+func (p *PooledRedis) TtlMulti(keys ...string) (map[string]time.Duration, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.TtlMulti(keys...)
+}
+
+//</editor-fold>
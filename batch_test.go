@@ -0,0 +1,137 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRedis_HGetAllMulti(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.HMSet("godis1", map[string]string{"a": "1", "b": "2"})
+	redis.HSet("godis2", "c", "3")
+	hashes, err := redis.HGetAllMulti("godis2", "godis1", "godis3")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"godis1": {"a": "1", "b": "2"},
+		"godis2": {"c": "3"},
+		"godis3": {},
+	}, hashes)
+
+	//a failed reply doesn't break the replies after it
+	redis.Set("godis4", "a")
+	_, err = redis.HGetAllMulti("godis1", "godis4", "godis2")
+	assert.NotNil(t, err)
+	hash, err := redis.HGetAll("godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"c": "3"}, hash)
+
+	pipeline := redis.Pipelined()
+	pipeline.Exists("godis4")
+	_, err = redis.HGetAllMulti("godis1")
+	assert.NotNil(t, err)
+}
+
+func TestRedis_MGetMap(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.MSet("godis1", "good1", "godis2", "")
+	m, err := redis.MGetMap("godis1", "godis2", "godis3")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"godis1": "good1", "godis2": ""}, m)
+	_, ok := m["godis3"]
+	assert.False(t, ok)
+
+	pooled := NewPooledRedis(option, &PoolConfig{MaxTotal: 2})
+	m, err = pooled.MGetMap("godis1", "godis3")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"godis1": "good1"}, m)
+	hashes, err := pooled.HGetAllMulti("godis3")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{"godis3": {}}, hashes)
+}
+
+func TestRedisCluster_MGetMap(t *testing.T) {
+	flushAll()
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("localhost"), int64(6379)}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("127.0.0.1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	cluster.MSetCluster("godis", "good", "godis1", "good1", "{godis}2", "")
+	m, err := cluster.MGetMap("godis1", "godis", "godis3", "{godis}2")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"godis": "good", "godis1": "good1", "{godis}2": ""}, m)
+
+	cluster.HSet("godis4", "a", "1")
+	hashes, err := cluster.HGetAllMulti("godis4", "godis5")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]string{"godis4": {"a": "1"}, "godis5": {}}, hashes)
+	_, err = cluster.HGetAllMulti("godis4", "godis")
+	assert.NotNil(t, err)
+}
+
+func TestRedisCluster_MGetParallel(t *testing.T) {
+	flushAll()
+	fake, closeServer := newFakeServer(t, map[string][]string{
+		"MGET a": {"-ERR boom\r\n"},
+		"MGET d": {"-ERR boom\r\n"},
+		"QUIT":   {"+OK\r\n"},
+	})
+	defer closeServer()
+	//the slots of a and d are served by the fake server
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("localhost"), int64(6379)}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte(fake.Host), int64(fake.Port)}},
+	})
+	defer cluster.Close()
+	cluster.parallelWorkers = 2
+	cluster.Set("godis", "good")
+	cluster.Set("{godis}1", "good1")
+	cluster.Set("c", "")
+	results := cluster.MGetParallel("godis", "a", "b", "{godis}1", "c", "d")
+	assert.Len(t, results, 6)
+	assert.Equal(t, MGetResult{Key: "godis", Value: "good", Found: true}, results[0])
+	assert.Equal(t, "a", results[1].Key)
+	assert.NotNil(t, results[1].Err)
+	assert.Equal(t, MGetResult{Key: "b"}, results[2])
+	assert.Equal(t, MGetResult{Key: "{godis}1", Value: "good1", Found: true}, results[3])
+	assert.Equal(t, MGetResult{Key: "c", Value: "", Found: true}, results[4])
+	assert.Equal(t, "d", results[5].Key)
+	assert.NotNil(t, results[5].Err)
+	assert.Empty(t, cluster.MGetParallel())
+}
+
+func TestRedis_TtlMulti(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.SetEx("godis1", 100, "a")
+	redis.Set("godis2", "b")
+	ttls, err := redis.TtlMulti("godis1", "godis2", "godis3")
+	assert.Nil(t, err)
+	assert.Len(t, ttls, 3)
+	assert.True(t, ttls["godis1"] > 99*time.Second && ttls["godis1"] <= 100*time.Second)
+	assert.Equal(t, TTLNoExpiry, ttls["godis2"])
+	assert.Equal(t, TTLMissing, ttls["godis3"])
+
+	pooled := NewPooledRedis(option, &PoolConfig{MaxTotal: 1})
+	defer pooled.Close()
+	ttls, err = pooled.TtlMulti("godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"godis2": TTLNoExpiry}, ttls)
+
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("localhost"), int64(6379)}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("127.0.0.1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	ttls, err = cluster.TtlMulti("godis1", "godis2", "godis3")
+	assert.Nil(t, err)
+	assert.True(t, ttls["godis1"] > 0)
+	assert.Equal(t, TTLNoExpiry, ttls["godis2"])
+	assert.Equal(t, TTLMissing, ttls["godis3"])
+}
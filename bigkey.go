@@ -0,0 +1,184 @@
+package godis
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	defaultBigKeyTopN      = 10
+	defaultBigKeyScanCount = 100
+)
+
+//BigKey a key found by BigKeyScan
+type BigKey struct {
+	Key      string
+	Type     string //string,list,set,zset,hash or stream
+	Encoding string //internal representation reported by OBJECT ENCODING
+	Size     int64  //length of a string,or count of elements of the other types
+	Memory   int64  //bytes estimated by MEMORY USAGE,-1 if the server doesn't support it
+}
+
+//BigKeyScanOption option of BigKeyScan
+type BigKeyScanOption struct {
+	Pattern  string        //scan only the keys matching the pattern,all keys by default
+	TopN     int           //count of biggest keys kept for every type,default 10
+	Count    int           //COUNT hint of every SCAN,default 100
+	Samples  int           //SAMPLES of MEMORY USAGE,0 means the server default
+	Interval time.Duration //sleep between SCAN batches,to reduce the load on a busy instance
+}
+
+//BigKeyReport the result of BigKeyScan
+type BigKeyReport struct {
+	ScannedKeys int64
+	Keys        map[string]int64    //type -> count of keys
+	Memory      map[string]int64    //type -> total bytes of the keys,0 if MEMORY USAGE isn't supported
+	TopKeys     map[string][]BigKey //type -> biggest keys,sorted by memory then size in descending order
+}
+
+//BigKeyScan scan the keyspace and find the biggest keys of every type,for capacity analysis.
+//the size is sampled by STRLEN,LLEN,SCARD,ZCARD,HLEN or XLEN,and the memory is estimated by MEMORY USAGE,
+//keys deleted during the scan are skipped.
+//
+//the scan sends several commands for every key,use Interval to reduce the load on a live instance
+func BigKeyScan(redis *Redis, option *BigKeyScanOption) (*BigKeyReport, error) {
+	if option == nil {
+		option = &BigKeyScanOption{}
+	}
+	topN := option.TopN
+	if topN <= 0 {
+		topN = defaultBigKeyTopN
+	}
+	count := option.Count
+	if count <= 0 {
+		count = defaultBigKeyScanCount
+	}
+	params := NewScanParams().Count(count)
+	if option.Pattern != "" {
+		params.Match(option.Pattern)
+	}
+	scanner := &bigKeyScanner{redis: redis, samples: option.Samples, memorySupported: true}
+	report := &BigKeyReport{
+		Keys:    make(map[string]int64),
+		Memory:  make(map[string]int64),
+		TopKeys: make(map[string][]BigKey),
+	}
+	cursor := "0"
+	for {
+		result, err := redis.Scan(cursor, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range result.Results {
+			bigKey, ok, err := scanner.sample(key)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			report.add(bigKey, topN)
+		}
+		cursor = result.Cursor
+		if cursor == "0" {
+			break
+		}
+		if option.Interval > 0 {
+			time.Sleep(option.Interval)
+		}
+	}
+	return report, nil
+}
+
+//add count the key and keep it if it is one of the topN biggest keys of its type
+func (r *BigKeyReport) add(key BigKey, topN int) {
+	r.ScannedKeys++
+	r.Keys[key.Type]++
+	if key.Memory > 0 {
+		r.Memory[key.Type] += key.Memory
+	}
+	top := r.TopKeys[key.Type]
+	i := sort.Search(len(top), func(i int) bool {
+		return biggerKey(key, top[i])
+	})
+	if i >= topN {
+		return
+	}
+	top = append(top, BigKey{})
+	copy(top[i+1:], top[i:])
+	top[i] = key
+	if len(top) > topN {
+		top = top[:topN]
+	}
+	r.TopKeys[key.Type] = top
+}
+
+func biggerKey(a, b BigKey) bool {
+	if a.Memory != b.Memory {
+		return a.Memory > b.Memory
+	}
+	return a.Size > b.Size
+}
+
+type bigKeyScanner struct {
+	redis           *Redis
+	samples         int
+	memorySupported bool //false once MEMORY USAGE is rejected,such as by an old redis
+}
+
+//sample the type,size and memory of the key,ok is false if the key doesn't exist any more
+func (s *bigKeyScanner) sample(key string) (BigKey, bool, error) {
+	typ, err := s.redis.Type(key)
+	if err != nil {
+		return BigKey{}, false, err
+	}
+	var size int64
+	switch typ {
+	case "none":
+		return BigKey{}, false, nil
+	case "string":
+		size, err = s.redis.StrLen(key)
+	case "list":
+		size, err = s.redis.LLen(key)
+	case "set":
+		size, err = s.redis.SCard(key)
+	case "zset":
+		size, err = s.redis.ZCard(key)
+	case "hash":
+		size, err = s.redis.HLen(key)
+	case "stream":
+		size, err = s.redis.XLen(key)
+	}
+	if err != nil {
+		return BigKey{}, false, err
+	}
+	//the encoding is only informative,a server rejecting OBJECT ENCODING doesn't fail the scan
+	encoding, err := s.redis.ObjectEncoding(key)
+	if _, ok := err.(*DataError); err != nil && err != ErrNil && !ok {
+		return BigKey{}, false, err
+	}
+	memory, err := s.memory(key)
+	if err != nil {
+		return BigKey{}, false, err
+	}
+	return BigKey{Key: key, Type: typ, Encoding: encoding, Size: size, Memory: memory}, true, nil
+}
+
+func (s *bigKeyScanner) memory(key string) (int64, error) {
+	if !s.memorySupported {
+		return -1, nil
+	}
+	samples := make([]int, 0, 1)
+	if s.samples > 0 {
+		samples = append(samples, s.samples)
+	}
+	memory, err := s.redis.MemoryUsage(key, samples...)
+	if err == ErrNil {
+		return -1, nil
+	}
+	if _, ok := err.(*DataError); ok {
+		s.memorySupported = false
+		return -1, nil
+	}
+	return memory, err
+}
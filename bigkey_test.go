@@ -0,0 +1,64 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"testing"
+)
+
+func TestBigKeyScan(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.Set("godis", "good")
+	redis.Set("godis_big", "a very big value of godis")
+	for i := 0; i < 5; i++ {
+		redis.RPush("list", strconv.Itoa(i))
+		redis.HSet("hash", strconv.Itoa(i), "v")
+	}
+	redis.SAdd("set", "a", "b")
+	redis.ZAdd("zset", 1, "a")
+
+	report, err := BigKeyScan(redis, &BigKeyScanOption{TopN: 1, Count: 2})
+	require.Nil(t, err)
+	assert.Equal(t, int64(6), report.ScannedKeys)
+	assert.Equal(t, int64(2), report.Keys["string"])
+	for _, typ := range []string{"string", "list", "hash", "set", "zset"} {
+		require.Len(t, report.TopKeys[typ], 1, typ)
+	}
+	assert.Equal(t, "godis_big", report.TopKeys["string"][0].Key)
+	assert.Equal(t, int64(25), report.TopKeys["string"][0].Size)
+	assert.Equal(t, int64(5), report.TopKeys["list"][0].Size)
+	assert.Equal(t, int64(5), report.TopKeys["hash"][0].Size)
+	assert.Equal(t, int64(2), report.TopKeys["set"][0].Size)
+	assert.Equal(t, int64(1), report.TopKeys["zset"][0].Size)
+
+	report, err = BigKeyScan(redis, &BigKeyScanOption{Pattern: "godis*"})
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), report.ScannedKeys)
+	require.Len(t, report.TopKeys["string"], 2)
+	assert.Equal(t, []string{"godis_big", "godis"}, []string{report.TopKeys["string"][0].Key, report.TopKeys["string"][1].Key})
+
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SCAN 0 COUNT 100":         {"*2\r\n$1\r\n0\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n"},
+		"TYPE a":                   {"+hash\r\n"},
+		"TYPE b":                   {"+hash\r\n"},
+		"HLEN a":                   {":10\r\n"},
+		"HLEN b":                   {":2\r\n"},
+		"OBJECT ENCODING a":        {"$9\r\nhashtable\r\n"},
+		"OBJECT ENCODING b":        {"$8\r\nlistpack\r\n"},
+		"MEMORY USAGE a SAMPLES 5": {":100\r\n"},
+		"MEMORY USAGE b SAMPLES 5": {":300\r\n"},
+	})
+	defer closeServer()
+	fake := NewRedis(fakeOption)
+	defer fake.Close()
+	report, err = BigKeyScan(fake, &BigKeyScanOption{Samples: 5})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(400), report.Memory["hash"])
+	assert.Equal(t, []BigKey{
+		{Key: "b", Type: "hash", Encoding: "listpack", Size: 2, Memory: 300},
+		{Key: "a", Type: "hash", Encoding: "hashtable", Size: 10, Memory: 100},
+	}, report.TopKeys["hash"])
+}
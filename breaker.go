@@ -0,0 +1,143 @@
+package godis
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerProbeInterval    = time.Second
+)
+
+//CircuitState state of a circuit breaker
+type CircuitState int
+
+const (
+	//CircuitClosed redis is healthy,every attempt is allowed
+	CircuitClosed CircuitState = iota
+	//CircuitOpen redis is down,attempts fail fast with ErrCircuitOpen until ProbeInterval passes
+	CircuitOpen
+	//CircuitHalfOpen one probe attempt is allowed,its result closes or opens the circuit again
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+//CircuitBreakerOption circuit breaker options
+type CircuitBreakerOption struct {
+	FailureThreshold int                         //consecutive failures to open the circuit,default 5
+	ProbeInterval    time.Duration               //time the circuit stays open before a probe is allowed,default 1s
+	OnStateChange    func(from, to CircuitState) //called on every state change,it must not block
+}
+
+//CircuitBreaker fail fast when redis is down instead of waiting out the connect timeout of every attempt.
+//
+//after FailureThreshold consecutive connection failures the circuit opens,attempts fail with ErrCircuitOpen,
+//after ProbeInterval one probe attempt is allowed,the circuit closes if it succeeds,otherwise it opens again.
+//only connection errors are failures,errors replied by redis are not.
+//
+//set it to Option.CircuitBreaker to guard dialing redis,one breaker can be shared by many connections,
+//such as a pool,or wrap any call with Do.
+//
+//CircuitBreaker is safe for concurrent use
+type CircuitBreaker struct {
+	option CircuitBreakerOption
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+//NewCircuitBreaker create new circuit breaker
+func NewCircuitBreaker(option *CircuitBreakerOption) *CircuitBreaker {
+	opt := CircuitBreakerOption{}
+	if option != nil {
+		opt = *option
+	}
+	if opt.FailureThreshold <= 0 {
+		opt.FailureThreshold = defaultBreakerFailureThreshold
+	}
+	if opt.ProbeInterval <= 0 {
+		opt.ProbeInterval = defaultBreakerProbeInterval
+	}
+	return &CircuitBreaker{option: opt}
+}
+
+//State the current state
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+//Do run fn if the circuit allows,otherwise return ErrCircuitOpen without running it,
+//a ConnectError returned by fn counts as a failure
+func (b *CircuitBreaker) Do(fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.done(err)
+	return err
+}
+
+//allow return an error wrapping ErrCircuitOpen if the attempt is rejected
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	from := b.state
+	switch {
+	case b.state == CircuitHalfOpen:
+		//the probe is running
+		b.mu.Unlock()
+		return newDisconnectedError(ErrCircuitOpen.Error(), ErrCircuitOpen)
+	case b.state == CircuitOpen && time.Since(b.openedAt) < b.option.ProbeInterval:
+		b.mu.Unlock()
+		return newDisconnectedError(ErrCircuitOpen.Error(), ErrCircuitOpen)
+	case b.state == CircuitOpen:
+		b.state = CircuitHalfOpen
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+	return nil
+}
+
+//done record the result of an allowed attempt
+func (b *CircuitBreaker) done(err error) {
+	var connectErr *ConnectError
+	failed := errors.As(err, &connectErr) && !errors.Is(err, ErrCircuitOpen)
+	b.mu.Lock()
+	from := b.state
+	if failed {
+		b.failures++
+		if b.state == CircuitHalfOpen || b.failures >= b.option.FailureThreshold {
+			b.state = CircuitOpen
+			b.openedAt = time.Now()
+		}
+	} else {
+		b.failures = 0
+		b.state = CircuitClosed
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+func (b *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.option.OnStateChange != nil {
+		b.option.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,79 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var mu sync.Mutex
+	changes := make([]string, 0)
+	breaker := NewCircuitBreaker(&CircuitBreakerOption{
+		FailureThreshold: 2,
+		ProbeInterval:    200 * time.Millisecond,
+		OnStateChange: func(from, to CircuitState) {
+			mu.Lock()
+			changes = append(changes, from.String()+"->"+to.String())
+			mu.Unlock()
+		},
+	})
+	redis := NewRedis(&Option{Host: "localhost", Port: 6380, CircuitBreaker: breaker})
+	defer redis.Close()
+
+	_, err := redis.Ping()
+	assert.NotNil(t, err)
+	assert.Equal(t, CircuitClosed, breaker.State())
+	_, err = redis.Ping()
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	//fail fast without dialing
+	_, err = redis.Ping()
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.IsType(t, &ConnectError{}, err)
+
+	//the probe fails,so the circuit opens again
+	time.Sleep(250 * time.Millisecond)
+	_, err = redis.Ping()
+	assert.False(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	//the probe succeeds,so the circuit closes
+	time.Sleep(250 * time.Millisecond)
+	opt := *option
+	opt.CircuitBreaker = breaker
+	redis1 := NewRedis(&opt)
+	defer redis1.Close()
+	s, err := redis1.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	mu.Lock()
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}, changes)
+	mu.Unlock()
+
+	//errors replied by redis are not failures
+	breaker = NewCircuitBreaker(&CircuitBreakerOption{FailureThreshold: 1})
+	err = breaker.Do(func() error {
+		return newDataError("ERR wrong type")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, CircuitClosed, breaker.State())
+	err = breaker.Do(func() error {
+		return newConnectError("connection refused")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, CircuitOpen, breaker.State())
+	called := false
+	err = breaker.Do(func() error {
+		called = true
+		return nil
+	})
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.False(t, called)
+	assert.Equal(t, "unknown", CircuitState(10).String())
+}
@@ -0,0 +1,213 @@
+package godis
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBulkBatchSize = 1000
+	defaultBulkQueueSize = 10000
+)
+
+//BulkOption option of BulkLoader
+type BulkOption struct {
+	BatchSize int                                            //max count of commands sent as one pipeline,default 1000
+	QueueSize int                                            //max count of commands waiting to be sent,default 10000,Add blocks when it is full
+	OnError   func(command string, args []string, err error) //called when a command fails,it must not block
+}
+
+//BulkStats counters of BulkLoader
+type BulkStats struct {
+	Queued  int64         //commands added
+	Loaded  int64         //commands executed successfully
+	Failed  int64         //commands failed,by an error reply or a broken connection
+	Elapsed time.Duration //time since the loader is created,until it is closed
+}
+
+//Rate commands replied per second
+func (s BulkStats) Rate() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Loaded+s.Failed) / s.Elapsed.Seconds()
+}
+
+//BulkLoader mass insertion,such as importing millions of records.
+//
+//the commands are queued by Add and sent in large pipelines by a background goroutine with its own connection,
+//the replies are only checked for errors,so the caller never waits for a round trip,
+//Add blocks while the queue is full,so a fast producer is slowed down to the speed of the server.
+//failed commands are not retried,they are counted and reported to OnError.
+//
+//	loader := godis.NewBulkLoader(option, nil)
+//	for _, user := range users {
+//		loader.Add("HSET", "user:"+user.ID, "name", user.Name)
+//	}
+//	err := loader.Close()
+//	fmt.Printf("%+v %.0f/s\n", loader.Stats(), loader.Stats().Rate())
+//
+//BulkLoader is safe for concurrent use
+type BulkLoader struct {
+	option  BulkOption
+	target  Option
+	redis   *Redis
+	queue   chan bulkCommand
+	stopped chan struct{}
+	start   time.Time
+
+	mu     sync.RWMutex
+	closed bool
+	end    time.Time
+
+	queued int64
+	loaded int64
+	failed int64
+}
+
+//bulkCommand a command waiting to be sent
+type bulkCommand struct {
+	name string
+	args [][]byte
+}
+
+//NewBulkLoader create new bulk loader,the commands are sent by a new connection to the redis of option
+func NewBulkLoader(option *Option, bulkOption *BulkOption) *BulkLoader {
+	opt := BulkOption{}
+	if bulkOption != nil {
+		opt = *bulkOption
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = defaultBulkBatchSize
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = defaultBulkQueueSize
+	}
+	b := &BulkLoader{
+		option:  opt,
+		target:  *option,
+		redis:   NewRedis(option),
+		queue:   make(chan bulkCommand, opt.QueueSize),
+		stopped: make(chan struct{}),
+		start:   time.Now(),
+	}
+	go b.run()
+	return b
+}
+
+//Add queue the command,such as Add("SET", "key", "value"),
+//it blocks while the queue is full,return ErrBulkLoaderClosed if the loader is closed
+func (b *BulkLoader) Add(command string, args ...string) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrBulkLoaderClosed
+	}
+	atomic.AddInt64(&b.queued, 1)
+	b.queue <- bulkCommand{name: command, args: StrArrToByteArrArr(args)}
+	return nil
+}
+
+//Stats the counters and throughput of the loader
+func (b *BulkLoader) Stats() BulkStats {
+	b.mu.RLock()
+	end := b.end
+	b.mu.RUnlock()
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return BulkStats{
+		Queued:  atomic.LoadInt64(&b.queued),
+		Loaded:  atomic.LoadInt64(&b.loaded),
+		Failed:  atomic.LoadInt64(&b.failed),
+		Elapsed: end.Sub(b.start),
+	}
+}
+
+//Close wait until the queued commands are replied and close the connection,
+//return ErrBulkLoaderClosed if it is already closed
+func (b *BulkLoader) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrBulkLoaderClosed
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+	<-b.stopped
+	b.mu.Lock()
+	b.end = time.Now()
+	b.mu.Unlock()
+	return b.redis.Close()
+}
+
+//run send the queued commands until the queue is closed,
+//the commands waiting in the queue are sent as one pipeline
+func (b *BulkLoader) run() {
+	defer close(b.stopped)
+	batch := make([]bulkCommand, 0, b.option.BatchSize)
+	for command := range b.queue {
+		batch = append(batch[:0], command)
+	drain:
+		for len(batch) < b.option.BatchSize {
+			select {
+			case more, ok := <-b.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, more)
+			default:
+				break drain
+			}
+		}
+		if !b.load(batch) {
+			//the connection is broken,dial again for the next batch
+			b.redis.Close()
+			b.redis = NewRedis(&b.target)
+		}
+	}
+}
+
+//load send the batch and check the replies,return false if the connection is broken
+func (b *BulkLoader) load(batch []bulkCommand) bool {
+	for i, command := range batch {
+		if err := b.redis.client.sendCommandByStr(command.name, command.args...); err != nil {
+			if i > 0 {
+				//the commands sent before are replied by the broken connection as errors
+				b.check(batch[:i])
+			}
+			b.fail(batch[i:], err)
+			return false
+		}
+	}
+	return b.check(batch)
+}
+
+//check read the replies of the sent commands,only the errors are kept
+func (b *BulkLoader) check(batch []bulkCommand) bool {
+	replies, err := b.redis.client.getAll()
+	if err != nil {
+		b.fail(batch, err)
+		return false
+	}
+	for i, reply := range replies.([]interface{}) {
+		if err, ok := reply.(error); ok && i < len(batch) {
+			b.fail(batch[i:i+1], err)
+			continue
+		}
+		atomic.AddInt64(&b.loaded, 1)
+	}
+	return !b.redis.client.broken
+}
+
+func (b *BulkLoader) fail(batch []bulkCommand, err error) {
+	atomic.AddInt64(&b.failed, int64(len(batch)))
+	if b.option.OnError == nil {
+		return
+	}
+	for _, command := range batch {
+		b.option.OnError(command.name, ByteArrArrToStrArr(command.args), err)
+	}
+}
@@ -0,0 +1,69 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestBulkLoader(t *testing.T) {
+	flushAll()
+	var mu sync.Mutex
+	failed := make([]string, 0)
+	loader := NewBulkLoader(option, &BulkOption{
+		BatchSize: 100,
+		QueueSize: 10,
+		OnError: func(command string, args []string, err error) {
+			mu.Lock()
+			failed = append(failed, command+" "+args[0])
+			mu.Unlock()
+		},
+	})
+	for i := 0; i < 1000; i++ {
+		err := loader.Add("SET", "godis"+strconv.Itoa(i), strconv.Itoa(i))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, loader.Add("HSET", "hash", "a", "1"))
+	assert.Nil(t, loader.Add("INCR", "godis1"))
+	assert.Nil(t, loader.Add("LPUSH", "hash", "a"))
+	assert.Nil(t, loader.Close())
+	assert.Equal(t, ErrBulkLoaderClosed, loader.Add("SET", "godis", "good"))
+	assert.Equal(t, ErrBulkLoaderClosed, loader.Close())
+
+	stats := loader.Stats()
+	assert.Equal(t, int64(1003), stats.Queued)
+	assert.Equal(t, int64(1002), stats.Loaded)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.True(t, stats.Elapsed > 0)
+	assert.True(t, stats.Rate() > 0)
+	assert.Equal(t, stats, loader.Stats())
+	assert.Equal(t, []string{"LPUSH hash"}, failed)
+
+	redis := NewRedis(option)
+	defer redis.Close()
+	c, _ := redis.DbSize()
+	assert.Equal(t, int64(1001), c)
+	s, _ := redis.Get("godis999")
+	assert.Equal(t, "999", s)
+	s, _ = redis.Get("godis1")
+	assert.Equal(t, "2", s)
+}
+
+func TestBulkLoader_Broken(t *testing.T) {
+	var mu sync.Mutex
+	var failed int
+	loader := NewBulkLoader(&Option{Host: "localhost", Port: 6380}, &BulkOption{
+		OnError: func(command string, args []string, err error) {
+			mu.Lock()
+			failed++
+			mu.Unlock()
+		},
+	})
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, loader.Add("SET", "godis", "good"))
+	}
+	loader.Close()
+	assert.Equal(t, int64(10), loader.Stats().Failed)
+	assert.Equal(t, 10, failed)
+}
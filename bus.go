@@ -0,0 +1,325 @@
+package godis
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBusWorkers       = 1
+	defaultBusQueueSize     = 100
+	defaultBusRetryInterval = time.Second
+)
+
+//Codec encode the events of Bus
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+//JSONCodec encode the events by encoding/json
+type JSONCodec struct{}
+
+//Marshal encode v as json
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+//Unmarshal decode json data into v
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+//BusOption event bus options
+type BusOption struct {
+	Codec         Codec                         //encode the events,default JSONCodec
+	Workers       int                           //goroutines handling the events of a topic,default 1,which keeps the events in order
+	QueueSize     int                           //events of a topic waiting for the workers,default 100,more events block the subscription
+	RetryInterval time.Duration                 //wait before subscribing again after the connection is lost,default 1s
+	PingInterval  time.Duration                 //keep-alive of the subscription,see RedisPubSub.PingInterval,0 means disabled
+	OnError       func(topic string, err error) //called when an event can't be decoded,a handler fails or panics,or the subscription fails
+}
+
+//Bus distributed event bus built on pub/sub,the events are encoded by the codec.
+//
+//the handlers of a topic run in a pool of Workers goroutines,
+//a handler panicking is recovered and reported to OnError,the other handlers are not affected.
+//all the topics share one subscribed connection borrowed from the pool,
+//it subscribes again when the connection is lost,events published meanwhile are lost.
+//
+//Bus is safe for concurrent use
+type Bus struct {
+	pool   *Pool
+	option BusOption
+
+	mu      sync.Mutex
+	topics  map[string]*busTopic
+	pubsub  *RedisPubSub    //the running subscription,nil until it is acknowledged
+	pending map[string]bool //topics the starting subscription is subscribing
+	running bool
+	closed  bool
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+//busTopic the handlers of a topic and their workers
+type busTopic struct {
+	name     string
+	handlers []*busHandler
+	events   chan []byte
+	wg       sync.WaitGroup
+}
+
+//busHandler a handler decoding the event into the type of its argument
+type busHandler struct {
+	fn  reflect.Value
+	arg reflect.Type
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+//NewBus create new event bus
+func NewBus(pool *Pool, option *BusOption) *Bus {
+	opt := BusOption{}
+	if option != nil {
+		opt = *option
+	}
+	if opt.Codec == nil {
+		opt.Codec = JSONCodec{}
+	}
+	if opt.Workers <= 0 {
+		opt.Workers = defaultBusWorkers
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = defaultBusQueueSize
+	}
+	if opt.RetryInterval <= 0 {
+		opt.RetryInterval = defaultBusRetryInterval
+	}
+	return &Bus{
+		pool:    pool,
+		option:  opt,
+		topics:  make(map[string]*busTopic),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+//Publish encode v by the codec and publish it to the topic,return the count of clients that received it
+func (b *Bus) Publish(topic string, v interface{}) (int64, error) {
+	data, err := b.option.Codec.Marshal(v)
+	if err != nil {
+		return 0, err
+	}
+	redis, err := b.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Publish(topic, string(data))
+}
+
+//Subscribe register the handler of the events of topic,
+//handler must be a func with one argument,such as func(event *Event) or func(event Event) error,
+//the event is decoded into a new value of the type of the argument
+func (b *Bus) Subscribe(topic string, handler interface{}) error {
+	h, err := newBusHandler(handler)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return newDataError("bus is closed")
+	}
+	if t, ok := b.topics[topic]; ok {
+		t.handlers = append(t.handlers, h)
+		return nil
+	}
+	t := &busTopic{name: topic, handlers: []*busHandler{h}, events: make(chan []byte, b.option.QueueSize)}
+	b.topics[topic] = t
+	for i := 0; i < b.option.Workers; i++ {
+		t.wg.Add(1)
+		go b.work(t)
+	}
+	if b.pubsub != nil {
+		return b.pubsub.Subscribe(topic)
+	}
+	if !b.running {
+		b.running = true
+		go b.run()
+	}
+	return nil
+}
+
+//Close unsubscribe the topics,wait until the handlers of the received events return
+func (b *Bus) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.done)
+	running := b.running
+	var err error
+	if b.pubsub != nil {
+		err = b.pubsub.UnSubscribe()
+	}
+	b.mu.Unlock()
+	if running {
+		<-b.stopped
+	}
+	for _, t := range b.topics {
+		close(t.events)
+		t.wg.Wait()
+	}
+	return err
+}
+
+//run subscribe the topics until the bus is closed
+func (b *Bus) run() {
+	defer close(b.stopped)
+	for {
+		err := b.subscribe()
+		b.mu.Lock()
+		closed := b.closed
+		b.mu.Unlock()
+		if closed {
+			return
+		}
+		if err != nil {
+			b.report("", err)
+		}
+		select {
+		case <-time.After(b.option.RetryInterval):
+		case <-b.done:
+			return
+		}
+	}
+}
+
+//subscribe subscribe all the topics and dispatch the events until the subscription ends
+func (b *Bus) subscribe() error {
+	redis, err := b.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	b.mu.Lock()
+	topics := make([]string, 0, len(b.topics))
+	b.pending = make(map[string]bool)
+	for topic := range b.topics {
+		topics = append(topics, topic)
+		b.pending[topic] = true
+	}
+	b.mu.Unlock()
+	pubsub := &RedisPubSub{PingInterval: b.option.PingInterval}
+	pubsub.OnSubscribe = func(channel string, subscribedChannels int) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.closed {
+			//closed before the subscription is acknowledged
+			pubsub.UnSubscribe(channel)
+			return
+		}
+		if b.pubsub != nil {
+			return
+		}
+		b.pubsub = pubsub
+		//the topics added while the subscription was starting
+		for topic := range b.topics {
+			if !b.pending[topic] {
+				pubsub.Subscribe(topic)
+			}
+		}
+	}
+	pubsub.OnReceive = func(msg *Message) {
+		if msg.Kind != MessageKindMessage {
+			return
+		}
+		b.mu.Lock()
+		t := b.topics[msg.Channel]
+		b.mu.Unlock()
+		if t == nil {
+			return
+		}
+		select {
+		case t.events <- msg.Payload:
+		default:
+			//the workers are busy,wait unless the bus is closed
+			select {
+			case t.events <- msg.Payload:
+			case <-b.done:
+			}
+		}
+	}
+	defer func() {
+		b.mu.Lock()
+		b.pubsub = nil
+		b.mu.Unlock()
+	}()
+	return redis.Subscribe(pubsub, topics...)
+}
+
+//work handle the events of the topic until its queue is closed
+func (b *Bus) work(t *busTopic) {
+	defer t.wg.Done()
+	for data := range t.events {
+		b.mu.Lock()
+		handlers := t.handlers
+		b.mu.Unlock()
+		for _, h := range handlers {
+			if err := h.call(b.option.Codec, data); err != nil {
+				b.report(t.name, err)
+			}
+		}
+	}
+}
+
+func (b *Bus) report(topic string, err error) {
+	if b.option.OnError != nil {
+		b.option.OnError(topic, err)
+	}
+}
+
+func newBusHandler(handler interface{}) (*busHandler, error) {
+	fn := reflect.ValueOf(handler)
+	if fn.Kind() != reflect.Func {
+		return nil, newDataError("handler must be a func")
+	}
+	t := fn.Type()
+	if t.NumIn() != 1 || t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != errorType) {
+		return nil, newDataError("handler must have one argument and return nothing or an error")
+	}
+	return &busHandler{fn: fn, arg: t.In(0)}, nil
+}
+
+//call decode the event into a new value and call the handler,a panic is returned as an error
+func (h *busHandler) call(codec Codec, data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	var arg reflect.Value
+	if h.arg.Kind() == reflect.Ptr {
+		arg = reflect.New(h.arg.Elem())
+		err = codec.Unmarshal(data, arg.Interface())
+	} else {
+		ptr := reflect.New(h.arg)
+		err = codec.Unmarshal(data, ptr.Interface())
+		arg = ptr.Elem()
+	}
+	if err != nil {
+		return err
+	}
+	out := h.fn.Call([]reflect.Value{arg})
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type busEvent struct {
+	ID   int
+	Name string
+}
+
+func TestBus(t *testing.T) {
+	flushAll()
+	pool := NewPool(nil, option)
+	defer pool.Destroy()
+	var mu sync.Mutex
+	errs := make([]string, 0)
+	bus := NewBus(pool, &BusOption{
+		OnError: func(topic string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, topic+" "+err.Error())
+		},
+	})
+	received := make(chan interface{}, 10)
+	assert.Nil(t, bus.Subscribe("orders", func(event *busEvent) {
+		received <- event
+	}))
+	assert.Nil(t, bus.Subscribe("orders", func(event busEvent) error {
+		if event.ID == 2 {
+			panic("bad order")
+		}
+		received <- event
+		return nil
+	}))
+	assert.Nil(t, bus.Subscribe("names", func(name string) error {
+		return errors.New("failed " + name)
+	}))
+	assert.NotNil(t, bus.Subscribe("orders", "not a func"))
+	assert.NotNil(t, bus.Subscribe("orders", func(a, b string) {}))
+	assert.NotNil(t, bus.Subscribe("orders", func(a string) int { return 0 }))
+
+	//wait until the topics are subscribed
+	for i := 0; i < 100; i++ {
+		bus.mu.Lock()
+		subscribed := bus.pubsub != nil
+		bus.mu.Unlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	_, err := bus.Publish("orders", &busEvent{ID: 1, Name: "a"})
+	assert.Nil(t, err)
+	assert.Equal(t, &busEvent{ID: 1, Name: "a"}, <-received)
+	assert.Equal(t, busEvent{ID: 1, Name: "a"}, <-received)
+	_, err = bus.Publish("orders", busEvent{ID: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, &busEvent{ID: 2}, <-received)
+	_, err = bus.Publish("names", "godis")
+	assert.Nil(t, err)
+	_, err = bus.Publish("orders", "not an order")
+	assert.Nil(t, err)
+	_, err = bus.Publish("orders", func() {})
+	assert.NotNil(t, err)
+
+	assert.Nil(t, bus.Close())
+	assert.Nil(t, bus.Close())
+	assert.NotNil(t, bus.Subscribe("orders", func(event *busEvent) {}))
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, errs, 4)
+	assert.Contains(t, errs, "orders handler panic: bad order")
+	assert.Contains(t, errs, "names failed godis")
+}
@@ -0,0 +1,189 @@
+package godis
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheLockTTL   = 5 * time.Second
+	defaultCacheLockRetry = 50 * time.Millisecond
+)
+
+//cacheUnlockScript delete the lock only if it is still held by the token
+const cacheUnlockScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+//CacheOption cache options
+type CacheOption struct {
+	DistributedLock bool          //take a redis lock while loading,so only one process loads a missing key
+	LockTTL         time.Duration //the lock expires after LockTTL,default 5 seconds
+	LockWait        time.Duration //time to wait for the value loaded by another process before loading it anyway,default LockTTL
+	LockRetry       time.Duration //interval to check the value loaded by another process,default 50ms
+}
+
+//Cache cache-aside helper,load a missing key once and store it in redis.
+//
+//concurrent GetOrLoad of the same key in the process share one loader call,
+//with DistributedLock,processes also take the lock key+":lock" before loading,
+//the others wait for the value instead of calling the loader.
+//
+//Cache is safe for concurrent use
+type Cache struct {
+	pool   *Pool
+	option CacheOption
+	mu     sync.Mutex
+	calls  map[string]*cacheCall
+}
+
+//cacheCall an in-flight load of a key
+type cacheCall struct {
+	wg    sync.WaitGroup
+	value string
+	err   error
+}
+
+//NewCache create new cache
+func NewCache(pool *Pool, option *CacheOption) *Cache {
+	opt := CacheOption{}
+	if option != nil {
+		opt = *option
+	}
+	if opt.LockTTL <= 0 {
+		opt.LockTTL = defaultCacheLockTTL
+	}
+	if opt.LockWait <= 0 {
+		opt.LockWait = opt.LockTTL
+	}
+	if opt.LockRetry <= 0 {
+		opt.LockRetry = defaultCacheLockRetry
+	}
+	return &Cache{pool: pool, option: opt, calls: make(map[string]*cacheCall)}
+}
+
+//GetOrLoad get the value of key,if the key does not exist,
+//call loader and store its value with ttl,0 ttl means the value never expires.
+//the error of loader is returned and nothing is stored
+func (c *Cache) GetOrLoad(key string, ttl time.Duration, loader func() (string, error)) (string, error) {
+	value, ok, err := c.get(key)
+	if err != nil || ok {
+		return value, err
+	}
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+	call := &cacheCall{}
+	call.wg.Add(1)
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	call.value, call.err = c.load(key, ttl, loader)
+	call.wg.Done()
+	c.mu.Lock()
+	delete(c.calls, key)
+	c.mu.Unlock()
+	return call.value, call.err
+}
+
+//Invalidate delete key,so the next GetOrLoad loads it again
+func (c *Cache) Invalidate(key string) error {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	_, err = redis.Del(key)
+	return err
+}
+
+func (c *Cache) load(key string, ttl time.Duration, loader func() (string, error)) (string, error) {
+	if !c.option.DistributedLock {
+		return c.loadAndSet(key, ttl, loader)
+	}
+	token := randomKeySuffix()
+	locked, err := c.lock(key, token)
+	if err != nil {
+		return "", err
+	}
+	if locked {
+		defer c.unlock(key, token)
+		//the value may be stored by the previous holder of the lock
+		value, ok, err := c.get(key)
+		if err != nil || ok {
+			return value, err
+		}
+		return c.loadAndSet(key, ttl, loader)
+	}
+	deadline := time.Now().Add(c.option.LockWait)
+	for time.Now().Before(deadline) {
+		time.Sleep(c.option.LockRetry)
+		value, ok, err := c.get(key)
+		if err != nil || ok {
+			return value, err
+		}
+	}
+	return c.loadAndSet(key, ttl, loader)
+}
+
+func (c *Cache) loadAndSet(key string, ttl time.Duration, loader func() (string, error)) (string, error) {
+	value, err := loader()
+	if err != nil {
+		return "", err
+	}
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	if ttl > 0 {
+		_, err = redis.PSetEx(key, int64(ttl/time.Millisecond), value)
+	} else {
+		_, err = redis.Set(key, value)
+	}
+	return value, err
+}
+
+//get the value of key and whether it exists
+func (c *Cache) get(key string) (string, bool, error) {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return "", false, err
+	}
+	defer redis.Close()
+	if err := redis.client.get(key); err != nil {
+		return "", false, err
+	}
+	reply, err := redis.client.getOne()
+	if err != nil {
+		return "", false, err
+	}
+	value, _ := reply.([]byte)
+	if value == nil {
+		return "", false, nil
+	}
+	return string(value), true, nil
+}
+
+func (c *Cache) lock(key, token string) (bool, error) {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return false, err
+	}
+	defer redis.Close()
+	status, err := redis.SetWithParamsAndTime(key+":lock", token, "nx", "px", int64(c.option.LockTTL/time.Millisecond))
+	if err == ErrNil {
+		return false, nil
+	}
+	return status == keywordOk.name, err
+}
+
+func (c *Cache) unlock(key, token string) {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return
+	}
+	defer redis.Close()
+	redis.Eval(cacheUnlockScript, 1, key+":lock", token)
+}
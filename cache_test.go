@@ -0,0 +1,89 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrLoad(t *testing.T) {
+	flushAll()
+	pool := NewPool(&PoolConfig{MaxTotal: 20}, option)
+	defer pool.Destroy()
+	for _, distributed := range []bool{false, true} {
+		cache := NewCache(pool, &CacheOption{DistributedLock: distributed, LockRetry: 10 * time.Millisecond})
+		assert.Nil(t, cache.Invalidate("godis"))
+		var calls int32
+		loader := func() (string, error) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(100 * time.Millisecond)
+			return "good", nil
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := cache.GetOrLoad("godis", time.Minute, loader)
+				assert.Nil(t, err)
+				assert.Equal(t, "good", value)
+			}()
+		}
+		//another process loading the same key
+		other := NewCache(pool, &CacheOption{DistributedLock: distributed, LockRetry: 10 * time.Millisecond})
+		value, err := other.GetOrLoad("godis", time.Minute, loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "good", value)
+		wg.Wait()
+		if distributed {
+			assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		} else {
+			assert.True(t, atomic.LoadInt32(&calls) <= 2)
+		}
+	}
+
+	redis := NewRedis(option)
+	defer redis.Close()
+	ttl, err := redis.PTTL("godis")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+	c, err := redis.Exists("godis:lock")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+
+	cache := NewCache(pool, nil)
+	value, err := cache.GetOrLoad("empty", 0, func() (string, error) {
+		return "", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "", value)
+	value, err = cache.GetOrLoad("empty", 0, func() (string, error) {
+		return "loaded again", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "", value)
+	ttl, err = redis.TTL("empty")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), ttl)
+
+	_, err = cache.GetOrLoad("failed", time.Minute, func() (string, error) {
+		return "", errors.New("load failed")
+	})
+	assert.NotNil(t, err)
+	c, err = redis.Exists("failed")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+
+	brokenPool := NewPool(nil, &Option{Host: "localhost1"})
+	defer brokenPool.Destroy()
+	broken := NewCache(brokenPool, &CacheOption{DistributedLock: true})
+	_, err = broken.GetOrLoad("godis", time.Minute, func() (string, error) {
+		t.Error("loader should not be called")
+		return "", nil
+	})
+	assert.NotNil(t, err)
+	assert.NotNil(t, broken.Invalidate("godis"))
+}
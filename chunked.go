@@ -0,0 +1,143 @@
+package godis
+
+import "io"
+
+//defaultChunkSize bytes of a value read by one GETRANGE or written by one SET/APPEND
+const defaultChunkSize = 64 * 1024
+
+//RangeReader read the string value of a key in chunks by GETRANGE,see Redis.GetRangeReader
+type RangeReader struct {
+	redis     *Redis
+	key       string
+	chunkSize int64
+	offset    int64  //offset of the next chunk
+	buf       []byte //unread bytes of the last chunk
+	err       error  //io.EOF after the last chunk
+}
+
+//Read read the value into p,return io.EOF after the end of the value
+func (r *RangeReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.fill()
+		if len(r.buf) == 0 {
+			return 0, r.err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+//fill read the next chunk,a chunk shorter than chunkSize is the last one
+func (r *RangeReader) fill() {
+	chunk, err := r.redis.GetRangeBytes(r.key, r.offset, r.offset+r.chunkSize-1)
+	if err != nil {
+		r.err = err
+		return
+	}
+	r.offset += int64(len(chunk))
+	r.buf = chunk
+	if int64(len(chunk)) < r.chunkSize {
+		r.err = io.EOF
+	}
+}
+
+//<editor-fold desc="chunkedcommands">
+
+//GetRangeBytes same as GetRange,but return the bytes,so a binary value isn't converted into a string
+func (r *Redis) GetRangeBytes(key string, start, end int64) ([]byte, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.getrange(key, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getBinaryBulkReply()
+}
+
+//SetRangeBytes same as SetRange,but the value is bytes
+//
+// Return value
+// Integer reply: the length of the string after it was modified by the command.
+func (r *Redis) SetRangeBytes(key string, offset int64, value []byte) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.setrangeBytes(key, offset, value)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//GetRangeReader read the string value of key by GETRANGE in chunks of chunkSize bytes,64KB by default,
+//so a value of many MB isn't buffered in memory as a whole.
+//every chunk is read by its own command,a value changed while it is read may be read partly old and partly new,
+//a key which doesn't exist is read as an empty value.
+//the reader uses r,r must not be used by other commands until the reader returns an error or io.EOF
+//
+//	_, err := io.Copy(file, redis.GetRangeReader("backup"))
+func (r *Redis) GetRangeReader(key string, chunkSize ...int) *RangeReader {
+	size := int64(defaultChunkSize)
+	if len(chunkSize) > 0 && chunkSize[0] > 0 {
+		size = int64(chunkSize[0])
+	}
+	return &RangeReader{redis: r, key: key, chunkSize: size}
+}
+
+//SetFromReader set key to the content of reader in chunks of chunkSize bytes,64KB if it isn't positive,
+//the first chunk by SET and the others by APPEND,so a value of many MB isn't buffered in memory as a whole.
+//the value is visible while it is written,write it to a temporary key and RENAME it to replace a value at once.
+//when reading fails,the bytes read before the error are kept
+//
+//return the length of the value written
+func (r *Redis) SetFromReader(key string, reader io.Reader, chunkSize int) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	buf := make([]byte, chunkSize)
+	var written int64
+	for {
+		n, readErr := io.ReadFull(reader, buf)
+		if n > 0 || written == 0 {
+			if err := r.writeChunk(key, buf[:n], written == 0); err != nil {
+				return written, err
+			}
+			written += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			return written, nil
+		}
+		if readErr != nil {
+			return written, readErr
+		}
+	}
+}
+
+//writeChunk replace the value by the first chunk,append the others
+func (r *Redis) writeChunk(key string, chunk []byte, first bool) error {
+	if first {
+		if err := r.client.setBytes(key, chunk); err != nil {
+			return err
+		}
+		_, err := r.client.getStatusCodeReply()
+		return err
+	}
+	if err := r.client.appendBytes(key, chunk); err != nil {
+		return err
+	}
+	_, err := r.client.getIntegerReply()
+	return err
+}
+
+//</editor-fold>
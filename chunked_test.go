@@ -0,0 +1,69 @@
+package godis
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestRedis_SetFromReader(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	value := bytes.Repeat([]byte("godis\x00\xff"), 1000)
+	redis.Set("godis", "old value")
+	n, err := redis.SetFromReader("godis", bytes.NewReader(value), 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(value)), n)
+	c, _ := redis.StrLen("godis")
+	assert.Equal(t, int64(len(value)), c)
+
+	read, err := ioutil.ReadAll(redis.GetRangeReader("godis", 999))
+	assert.Nil(t, err)
+	assert.Equal(t, value, read)
+	read, err = ioutil.ReadAll(redis.GetRangeReader("godis"))
+	assert.Nil(t, err)
+	assert.Equal(t, value, read)
+
+	//an empty reader sets an empty value
+	n, err = redis.SetFromReader("godis", bytes.NewReader(nil), 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+	s, _ := redis.Get("godis")
+	assert.Equal(t, "", s)
+	read, err = ioutil.ReadAll(redis.GetRangeReader("missing"))
+	assert.Nil(t, err)
+	assert.Empty(t, read)
+
+	//the chunks before the failure are kept
+	failing := io.MultiReader(bytes.NewReader([]byte("abcdef")), &failingReader{})
+	n, err = redis.SetFromReader("godis", failing, 4)
+	assert.EqualError(t, err, "read failed")
+	assert.Equal(t, int64(6), n)
+	s, _ = redis.Get("godis")
+	assert.Equal(t, "abcdef", s)
+
+	c, err = redis.SetRangeBytes("godis", 2, []byte{0, 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), c)
+	b, err := redis.GetRangeBytes("godis", 1, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{'b', 0, 1}, b)
+
+	m, _ := redis.Multi()
+	_, err = redis.SetFromReader("godis", bytes.NewReader(value), 0)
+	assert.NotNil(t, err)
+	_, err = ioutil.ReadAll(redis.GetRangeReader("godis"))
+	assert.NotNil(t, err)
+	m.Discard()
+}
+
+type failingReader struct {
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
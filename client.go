@@ -2,15 +2,43 @@ package godis
 
 import (
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 //Client send command to redis, and receive data from redis
 type client struct {
 	*connection
-	Password  string
-	Db        int
-	isInMulti bool
-	isInWatch bool
+	Username      string
+	Password      string
+	Db            int
+	ClientName    string
+	initialDb     int //db of the option,selected again by RESET
+	isInMulti     bool
+	isInWatch     bool
+	isInSubscribe bool //listening to channels,only subscribe commands can be sent
+	readOnly      bool
+
+	lazyConnect bool
+	lazyMu      sync.Mutex
+	lazy        *lazyConnector
+
+	detectVersion bool
+	versionMu     sync.Mutex
+	version       *ServerVersion //version of the connected server,nil if unknown
+
+	filters []CommandFilter //check the commands before sending,see Option.CommandFilters
+
+	onConnect   func() error //run at the end of the handshake,see Option.OnConnect
+	handshaking bool         //onConnect is running,its commands are sent by the socket being connected
+}
+
+//lazyConnector dials and authenticates exactly once for all concurrent first callers,
+//a failed attempt is replaced by a fresh one so the next command retries
+type lazyConnector struct {
+	once sync.Once
+	err  error
 }
 
 //NewClient
@@ -20,12 +48,40 @@ func newClient(option *Option) *client {
 		db = option.Db
 	}
 	client := &client{
-		Password:  option.Password,
-		Db:        db,
-		isInMulti: false,
-		isInWatch: false,
+		Username:   option.Username,
+		Password:   option.Password,
+		Db:         db,
+		ClientName: option.ClientName,
+		initialDb:  db,
+		isInMulti:  false,
+		isInWatch:  false,
+		readOnly:   option.ReadOnly,
+
+		lazyConnect: option.LazyConnect,
+		lazy:        &lazyConnector{},
+
+		detectVersion: option.DetectVersion,
+
+		filters: option.CommandFilters,
 	}
 	client.connection = newConnection(option.Host, option.Port, option.ConnectionTimeout, option.SoTimeout)
+	client.connection.handshake = client.handshake
+	client.connection.setTCPOptions(option.WriteTimeout, option.KeepAlive, option.DisableTCPNoDelay)
+	client.connection.setTransport(option.Network, option.TLSConfig)
+	client.connection.dialFunc = option.Dialer
+	client.connection.breakOnReadOnly = option.BreakOnReadOnly
+	client.connection.returnErrNil = option.ReturnErrNil
+	client.connection.infiniteBlockingRead = option.InfiniteBlockingRead
+	client.connection.profiler = option.Profiler
+	client.connection.breaker = option.CircuitBreaker
+	client.connection.keyMapper = option.KeyMapper
+	client.connection.commandTimeout = option.CommandTimeout
+	client.connection.detectConcurrentUse = option.DetectConcurrentUse
+	if option.OnSlowCommand != nil {
+		client.connection.slowThreshold = option.SlowThreshold
+		client.connection.onSlowCommand = option.OnSlowCommand
+	}
+	client.connection.setDisconnectPolicy(option.DisconnectPolicy, option.MaxQueueSize, option.QueueTTL, option.BlockTimeout)
 	return client
 }
 
@@ -37,6 +93,11 @@ func (c *client) port() int {
 	return c.connection.port
 }
 
+//addr the address of the host connected,empty if it is never connected
+func (c *client) addr() string {
+	return c.connection.addr
+}
+
 //Receive
 func (c *client) receive() (interface{}, error) {
 	return c.connection.getOne()
@@ -44,27 +105,200 @@ func (c *client) receive() (interface{}, error) {
 
 //Connect
 func (c *client) connect() error {
-	err := c.connection.connect()
+	return c.connection.connect()
+}
+
+//handshake authenticate,select db,set client name and enable readonly,
+//it runs on every new socket,so a reconnected connection is in the same state as before
+func (c *client) handshake() error {
+	if c.Username != "" {
+		if err := c.handshakeCommand(cmdAuth, []byte(c.Username), []byte(c.Password)); err != nil {
+			return authError(err)
+		}
+	} else if c.Password != "" {
+		if err := c.handshakeCommand(cmdAuth, []byte(c.Password)); err != nil {
+			return authError(err)
+		}
+	}
+	if c.Db > 0 {
+		if err := c.handshakeCommand(cmdSelect, IntToByteArr(c.Db)); err != nil {
+			return err
+		}
+	}
+	if c.ClientName != "" {
+		if err := c.handshakeCommand(cmdClient, keywordSetName.getRaw(), []byte(c.ClientName)); err != nil {
+			return err
+		}
+	}
+	if c.readOnly {
+		if err := c.handshakeCommand(cmdReadonly); err != nil {
+			return err
+		}
+	}
+	//the server may be replaced by another version after reconnecting
+	c.setServerVersion(nil)
+	if c.detectVersion {
+		if err := c.detectServerVersion(); err != nil {
+			return err
+		}
+	}
+	if c.onConnect != nil {
+		c.handshaking = true
+		defer func() { c.handshaking = false }()
+		return c.onConnect()
+	}
+	return nil
+}
+
+//detectServerVersion query the version by INFO server,the version stays unknown if INFO is rejected,such as by acl
+func (c *client) detectServerVersion() error {
+	err := c.connection.sendCommand(cmdInfo, []byte("server"))
+	if err != nil {
+		return err
+	}
+	info, err := c.getBulkReply()
+	if _, ok := err.(*DataError); ok {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
-	if c.Password != "" {
-		err = c.auth(c.Password)
-		if err != nil {
+	if version, err := parseInfoVersion(info); err == nil {
+		c.setServerVersion(version)
+	}
+	return nil
+}
+
+func (c *client) serverVersion() *ServerVersion {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	return c.version
+}
+
+func (c *client) setServerVersion(version *ServerVersion) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	c.version = version
+}
+
+//checkVersion return UnsupportedVersionError if the server version is known to be older than the command
+func (c *client) checkVersion(name string) error {
+	since, ok := commandSince[name]
+	if !ok {
+		return nil
+	}
+	if c.detectVersion {
+		//connect first,so the version is detected by the handshake
+		if err := c.connection.connect(); err != nil {
+			return err
+		}
+	}
+	version := c.serverVersion()
+	if version == nil || !version.Less(since) {
+		return nil
+	}
+	return newUnsupportedVersionError(name, since, *version)
+}
+
+//authError the error of AUTH,the error replies are ErrAuth,such as ERR invalid password of the old versions
+func authError(err error) error {
+	if dataErr, ok := err.(*DataError); ok && dataErr.cause == nil {
+		return newAuthError(dataErr.Message)
+	}
+	return err
+}
+
+func (c *client) handshakeCommand(cmd protocolCommand, args ...[]byte) error {
+	err := c.connection.sendCommand(cmd, args...)
+	if err != nil {
+		return err
+	}
+	_, err = c.getStatusCodeReply()
+	return err
+}
+
+//ensureConnected connect to redis on first use when lazy connect is enabled
+func (c *client) ensureConnected() error {
+	if !c.lazyConnect || c.handshaking {
+		return nil
+	}
+	c.lazyMu.Lock()
+	lazy := c.lazy
+	c.lazyMu.Unlock()
+	lazy.once.Do(func() {
+		lazy.err = c.connect()
+		if lazy.err != nil {
+			c.connection.close()
+		}
+	})
+	if lazy.err != nil {
+		c.resetLazy(lazy)
+	}
+	return lazy.err
+}
+
+//resetLazy make the next command connect again
+func (c *client) resetLazy(lazy *lazyConnector) {
+	c.lazyMu.Lock()
+	if c.lazy == lazy {
+		c.lazy = &lazyConnector{}
+	}
+	c.lazyMu.Unlock()
+}
+
+//sendCommand send command to redis, connect first if necessary
+func (c *client) sendCommand(cmd protocolCommand, args ...[]byte) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	if err := c.checkVersion(cmd.name); err != nil {
+		return err
+	}
+	if len(c.filters) > 0 {
+		if err := c.filterCommand(cmd.name, cmd.spec, ByteArrArrToStrArr(args)); err != nil {
 			return err
 		}
-		_, err = c.getStatusCodeReply()
-		if err != nil {
+	}
+	return c.connection.sendCommand(cmd, args...)
+}
+
+//sendCommandStr send command whose arguments are strings to redis, connect first if necessary
+func (c *client) sendCommandStr(cmd protocolCommand, args ...string) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	if err := c.checkVersion(cmd.name); err != nil {
+		return err
+	}
+	if len(c.filters) > 0 {
+		if err := c.filterCommand(cmd.name, cmd.spec, args); err != nil {
 			return err
 		}
 	}
-	if c.Db > 0 {
-		err = c.selectDb(c.Db)
-		if err != nil {
+	return c.connection.sendCommandStr(cmd, args...)
+}
+
+//sendCommandByStr send command to redis, connect first if necessary
+func (c *client) sendCommandByStr(cmd string, args ...[]byte) error {
+	if err := c.ensureConnected(); err != nil {
+		return err
+	}
+	if err := c.checkVersion(strings.ToUpper(cmd)); err != nil {
+		return err
+	}
+	if len(c.filters) > 0 {
+		spec, _ := LookupCommandSpec(cmd)
+		if err := c.filterCommand(strings.ToUpper(cmd), spec, ByteArrArrToStrArr(args)); err != nil {
 			return err
 		}
-		_, err = c.getStatusCodeReply()
-		if err != nil {
+	}
+	return c.connection.sendCommandByStr(cmd, args...)
+}
+
+//filterCommand run the command filters in order,the first error is returned
+func (c *client) filterCommand(name string, spec *CommandSpec, args []string) error {
+	for _, filter := range c.filters {
+		if err := filter(name, spec, args); err != nil {
 			return err
 		}
 	}
@@ -73,6 +307,9 @@ func (c *client) connect() error {
 
 //Close
 func (c *client) close() error {
+	c.lazyMu.Lock()
+	c.lazy = &lazyConnector{}
+	c.lazyMu.Unlock()
 	return c.connection.close()
 }
 
@@ -88,13 +325,13 @@ func (c *client) quit() error {
 
 //Info
 func (c *client) info(section ...string) error {
-	return c.sendCommand(cmdInfo, StrArrToByteArrArr(section)...)
+	return c.sendCommandStr(cmdInfo, section...)
 }
 
 //Auth
 func (c *client) auth(password string) error {
 	c.Password = password
-	return c.sendCommand(cmdAuth, []byte(password))
+	return c.sendCommandStr(cmdAuth, password)
 }
 
 //Select
@@ -102,8 +339,23 @@ func (c *client) selectDb(index int) error {
 	return c.sendCommand(cmdSelect, IntToByteArr(index))
 }
 
+func (c *client) swapDB(index1, index2 int) error {
+	return c.sendCommand(cmdSwapDB, IntToByteArr(index1), IntToByteArr(index2))
+}
+
+func (c *client) reset() error {
+	return c.sendCommand(cmdReset)
+}
+
+func (c *client) lolwut(version ...int) error {
+	if len(version) > 0 {
+		return c.sendCommand(cmdLolwut, keywordVersion.getRaw(), IntToByteArr(version[0]))
+	}
+	return c.sendCommand(cmdLolwut)
+}
+
 func (c *client) set(key, value string) error {
-	return c.sendCommand(cmdSet, []byte(key), []byte(value))
+	return c.sendCommandStr(cmdSet, key, value)
 }
 
 func (c *client) setWithParamsAndTime(key, value, nxxx, expx string, time int64) error {
@@ -111,35 +363,35 @@ func (c *client) setWithParamsAndTime(key, value, nxxx, expx string, time int64)
 }
 
 func (c *client) setWithParams(key, value, nxxx string) error {
-	return c.sendCommand(cmdSet, []byte(key), []byte(value), []byte(nxxx))
+	return c.sendCommandStr(cmdSet, key, value, nxxx)
 }
 
 func (c *client) get(key string) error {
-	return c.sendCommand(cmdGet, []byte(key))
+	return c.sendCommandStr(cmdGet, key)
 }
 
 func (c *client) del(keys ...string) error {
-	return c.sendCommand(cmdDel, StrArrToByteArrArr(keys)...)
+	return c.sendCommandStr(cmdDel, keys...)
 }
 
 func (c *client) exists(keys ...string) error {
-	return c.sendCommand(cmdExists, StrArrToByteArrArr(keys)...)
+	return c.sendCommandStr(cmdExists, keys...)
 }
 
 func (c *client) typeKey(key string) error {
-	return c.sendCommand(cmdType, []byte(key))
+	return c.sendCommandStr(cmdType, key)
 }
 
 func (c *client) keys(pattern string) error {
-	return c.sendCommand(cmdKeys, []byte(pattern))
+	return c.sendCommandStr(cmdKeys, pattern)
 }
 
 func (c *client) rename(oldKey, newKey string) error {
-	return c.sendCommand(cmdRename, []byte(oldKey), []byte(newKey))
+	return c.sendCommandStr(cmdRename, oldKey, newKey)
 }
 
 func (c *client) renamenx(oldKey, newKey string) error {
-	return c.sendCommand(cmdRenameNx, []byte(oldKey), []byte(newKey))
+	return c.sendCommandStr(cmdRenameNx, oldKey, newKey)
 }
 
 func (c *client) expire(key string, seconds int) error {
@@ -159,11 +411,11 @@ func (c *client) pExpireAt(key string, unixTime int64) error {
 }
 
 func (c *client) ttl(key string) error {
-	return c.sendCommand(cmdTTL, []byte(key))
+	return c.sendCommandStr(cmdTTL, key)
 }
 
 func (c *client) pttl(key string) error {
-	return c.sendCommand(cmdPTTL, []byte(key))
+	return c.sendCommandStr(cmdPTTL, key)
 }
 
 func (c *client) move(key string, dbIndex int) error {
@@ -171,15 +423,19 @@ func (c *client) move(key string, dbIndex int) error {
 }
 
 func (c *client) getSet(key, value string) error {
-	return c.sendCommand(cmdGetSet, []byte(key), []byte(value))
+	return c.sendCommandStr(cmdGetSet, key, value)
+}
+
+func (c *client) getDel(key string) error {
+	return c.sendCommandStr(cmdGetDel, key)
 }
 
 func (c *client) mget(keys ...string) error {
-	return c.sendCommand(cmdMGet, StrArrToByteArrArr(keys)...)
+	return c.sendCommandStr(cmdMGet, keys...)
 }
 
 func (c *client) setnx(key, value string) error {
-	return c.sendCommand(cmdSetNx, []byte(key), []byte(value))
+	return c.sendCommandStr(cmdSetNx, key, value)
 }
 
 func (c *client) setex(key string, seconds int, value string) error {
@@ -191,11 +447,11 @@ func (c *client) pSetEx(key string, milliseconds int64, value string) error {
 }
 
 func (c *client) mset(keysvalues ...string) error {
-	return c.sendCommand(cmdMSet, StrArrToByteArrArr(keysvalues)...)
+	return c.sendCommandStr(cmdMSet, keysvalues...)
 }
 
 func (c *client) msetnx(keysvalues ...string) error {
-	return c.sendCommand(cmdMSetNx, StrArrToByteArrArr(keysvalues)...)
+	return c.sendCommandStr(cmdMSetNx, keysvalues...)
 }
 
 func (c *client) decrBy(key string, decrement int64) error {
@@ -203,7 +459,7 @@ func (c *client) decrBy(key string, decrement int64) error {
 }
 
 func (c *client) decr(key string) error {
-	return c.sendCommand(cmdDecr, []byte(key))
+	return c.sendCommandStr(cmdDecr, key)
 }
 
 func (c *client) incrBy(key string, increment int64) error {
@@ -211,11 +467,11 @@ func (c *client) incrBy(key string, increment int64) error {
 }
 
 func (c *client) incr(key string) error {
-	return c.sendCommand(cmdIncr, []byte(key))
+	return c.sendCommandStr(cmdIncr, key)
 }
 
 func (c *client) append(key, value string) error {
-	return c.sendCommand(cmdAppend, []byte(key), []byte(value))
+	return c.sendCommandStr(cmdAppend, key, value)
 }
 
 func (c *client) substr(key string, start, end int) error {
@@ -223,15 +479,15 @@ func (c *client) substr(key string, start, end int) error {
 }
 
 func (c *client) hset(key, field, value string) error {
-	return c.sendCommand(cmdHSet, []byte(key), []byte(field), []byte(value))
+	return c.sendCommandStr(cmdHSet, key, field, value)
 }
 
 func (c *client) hget(key, field string) error {
-	return c.sendCommand(cmdHGet, []byte(key), []byte(field))
+	return c.sendCommandStr(cmdHGet, key, field)
 }
 
 func (c *client) hsetnx(key, field, value string) error {
-	return c.sendCommand(cmdHSetNx, []byte(key), []byte(field), []byte(value))
+	return c.sendCommandStr(cmdHSetNx, key, field, value)
 }
 
 func (c *client) hmset(key string, hash map[string]string) error {
@@ -253,7 +509,7 @@ func (c *client) hincrBy(key, field string, increment int64) error {
 }
 
 func (c *client) hexists(key, field string) error {
-	return c.sendCommand(cmdHExists, []byte(key), []byte(field))
+	return c.sendCommandStr(cmdHExists, key, field)
 }
 
 func (c *client) hdel(key string, fields ...string) error {
@@ -261,19 +517,19 @@ func (c *client) hdel(key string, fields ...string) error {
 }
 
 func (c *client) hlen(key string) error {
-	return c.sendCommand(cmdHLen, []byte(key))
+	return c.sendCommandStr(cmdHLen, key)
 }
 
 func (c *client) hkeys(key string) error {
-	return c.sendCommand(cmdHKeys, []byte(key))
+	return c.sendCommandStr(cmdHKeys, key)
 }
 
 func (c *client) hvals(key string) error {
-	return c.sendCommand(cmdHVals, []byte(key))
+	return c.sendCommandStr(cmdHVals, key)
 }
 
 func (c *client) hgetAll(key string) error {
-	return c.sendCommand(cmdHGetAll, []byte(key))
+	return c.sendCommandStr(cmdHGetAll, key)
 }
 
 func (c *client) rpush(key string, fields ...string) error {
@@ -285,7 +541,7 @@ func (c *client) lpush(key string, fields ...string) error {
 }
 
 func (c *client) llen(key string) error {
-	return c.sendCommand(cmdLLen, []byte(key))
+	return c.sendCommandStr(cmdLLen, key)
 }
 
 func (c *client) lrange(key string, start, end int64) error {
@@ -308,16 +564,43 @@ func (c *client) lrem(key string, count int64, value string) error {
 	return c.sendCommand(cmdLRem, []byte(key), Int64ToByteArr(count), []byte(value))
 }
 
+func (c *client) lpos(key, element string, params ...*LPosParams) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key), []byte(element))
+	for _, p := range params {
+		arr = append(arr, p.params...)
+	}
+	return c.sendCommand(cmdLPos, arr...)
+}
+
+func (c *client) lposCount(key, element string, count int64, params ...*LPosParams) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key), []byte(element))
+	for _, p := range params {
+		arr = append(arr, p.params...)
+	}
+	arr = append(arr, keywordCount.getRaw(), Int64ToByteArr(count))
+	return c.sendCommand(cmdLPos, arr...)
+}
+
 func (c *client) lpop(key string) error {
-	return c.sendCommand(cmdLPop, []byte(key))
+	return c.sendCommandStr(cmdLPop, key)
 }
 
 func (c *client) rPop(key string) error {
-	return c.sendCommand(cmdRPop, []byte(key))
+	return c.sendCommandStr(cmdRPop, key)
+}
+
+func (c *client) lpopCount(key string, count int64) error {
+	return c.sendCommand(cmdLPop, []byte(key), Int64ToByteArr(count))
+}
+
+func (c *client) rpopCount(key string, count int64) error {
+	return c.sendCommand(cmdRPop, []byte(key), Int64ToByteArr(count))
 }
 
 func (c *client) rpopLpush(srcKey, destKey string) error {
-	return c.sendCommand(cmdRPopLPush, []byte(srcKey), []byte(destKey))
+	return c.sendCommandStr(cmdRPopLPush, srcKey, destKey)
 }
 
 func (c *client) sAdd(key string, members ...string) error {
@@ -325,7 +608,7 @@ func (c *client) sAdd(key string, members ...string) error {
 }
 
 func (c *client) sMembers(key string) error {
-	return c.sendCommand(cmdSMembers, []byte(key))
+	return c.sendCommandStr(cmdSMembers, key)
 }
 
 func (c *client) sRem(key string, members ...string) error {
@@ -333,7 +616,7 @@ func (c *client) sRem(key string, members ...string) error {
 }
 
 func (c *client) sPop(key string) error {
-	return c.sendCommand(cmdSPop, []byte(key))
+	return c.sendCommandStr(cmdSPop, key)
 }
 
 func (c *client) sPopBatch(key string, count int64) error {
@@ -341,27 +624,39 @@ func (c *client) sPopBatch(key string, count int64) error {
 }
 
 func (c *client) smove(srcKey, destKey, member string) error {
-	return c.sendCommand(cmdSMove, []byte(srcKey), []byte(destKey), []byte(member))
+	return c.sendCommandStr(cmdSMove, srcKey, destKey, member)
 }
 
 func (c *client) sCard(key string) error {
-	return c.sendCommand(cmdSCard, []byte(key))
+	return c.sendCommandStr(cmdSCard, key)
 }
 
 func (c *client) sIsMember(key, member string) error {
-	return c.sendCommand(cmdSIsMember, []byte(key), []byte(member))
+	return c.sendCommandStr(cmdSIsMember, key, member)
 }
 
 func (c *client) sInter(keys ...string) error {
-	return c.sendCommand(cmdSInter, StrArrToByteArrArr(keys)...)
+	return c.sendCommandStr(cmdSInter, keys...)
 }
 
 func (c *client) sInterStore(destKey string, keys ...string) error {
 	return c.sendCommand(cmdSInterStore, StrStrArrToByteArrArr(destKey, keys)...)
 }
 
+func (c *client) sInterCard(limit int64, keys ...string) error {
+	arr := make([][]byte, 0, len(keys)+3)
+	arr = append(arr, IntToByteArr(len(keys)))
+	for _, key := range keys {
+		arr = append(arr, []byte(key))
+	}
+	if limit > 0 {
+		arr = append(arr, keywordLimit.getRaw(), Int64ToByteArr(limit))
+	}
+	return c.sendCommand(cmdSInterCard, arr...)
+}
+
 func (c *client) sUnion(keys ...string) error {
-	return c.sendCommand(cmdSUnion, StrArrToByteArrArr(keys)...)
+	return c.sendCommandStr(cmdSUnion, keys...)
 }
 
 func (c *client) sUnionStore(destKey string, keys ...string) error {
@@ -369,7 +664,7 @@ func (c *client) sUnionStore(destKey string, keys ...string) error {
 }
 
 func (c *client) sDiff(keys ...string) error {
-	return c.sendCommand(cmdSDiff, StrArrToByteArrArr(keys)...)
+	return c.sendCommandStr(cmdSDiff, keys...)
 }
 
 func (c *client) sDiffStore(destKey string, keys ...string) error {
@@ -377,7 +672,7 @@ func (c *client) sDiffStore(destKey string, keys ...string) error {
 }
 
 func (c *client) sRandMember(key string) error {
-	return c.sendCommand(cmdSRandMember, []byte(key))
+	return c.sendCommandStr(cmdSRandMember, key)
 }
 
 func (c *client) zAdd(key string, score float64, member string, params ...*ZAddParams) error {
@@ -420,11 +715,11 @@ func (c *client) zIncrBy(key string, score float64, member string) error {
 }
 
 func (c *client) zRank(key, member string) error {
-	return c.sendCommand(cmdZRank, []byte(key), []byte(member))
+	return c.sendCommandStr(cmdZRank, key, member)
 }
 
 func (c *client) zRevRank(key, member string) error {
-	return c.sendCommand(cmdZRevRank, []byte(key), []byte(member))
+	return c.sendCommandStr(cmdZRevRank, key, member)
 }
 
 func (c *client) zRevRange(key string, start, end int64) error {
@@ -440,15 +735,66 @@ func (c *client) ZRevRangeWithScores(key string, start, end int64) error {
 }
 
 func (c *client) zCard(key string) error {
-	return c.sendCommand(cmdZCard, []byte(key))
+	return c.sendCommandStr(cmdZCard, key)
 }
 
 func (c *client) zScore(key, member string) error {
-	return c.sendCommand(cmdZScore, []byte(key), []byte(member))
+	return c.sendCommandStr(cmdZScore, key, member)
+}
+
+func (c *client) zPopMin(key string, count ...int64) error {
+	if len(count) == 0 {
+		return c.sendCommandStr(cmdZPopMin, key)
+	}
+	return c.sendCommand(cmdZPopMin, []byte(key), Int64ToByteArr(count[0]))
+}
+
+func (c *client) zPopMax(key string, count ...int64) error {
+	if len(count) == 0 {
+		return c.sendCommandStr(cmdZPopMax, key)
+	}
+	return c.sendCommand(cmdZPopMax, []byte(key), Int64ToByteArr(count[0]))
+}
+
+func (c *client) bzPopMin(timeout int, keys ...string) error {
+	arr := StrArrToByteArrArr(keys)
+	arr = append(arr, IntToByteArr(timeout))
+	return c.sendCommand(cmdBZPopMin, arr...)
+}
+
+func (c *client) bzPopMax(timeout int, keys ...string) error {
+	arr := StrArrToByteArrArr(keys)
+	arr = append(arr, IntToByteArr(timeout))
+	return c.sendCommand(cmdBZPopMax, arr...)
+}
+
+func (c *client) blmPop(timeout int, direction *PopDirection, count int64, keys ...string) error {
+	return c.sendCommand(cmdBLMPop, mpopArgs(timeout, direction, count, keys)...)
+}
+
+func (c *client) bzmPop(timeout int, direction *PopDirection, count int64, keys ...string) error {
+	return c.sendCommand(cmdBZMPop, mpopArgs(timeout, direction, count, keys)...)
+}
+
+//mpopArgs the arguments of BLMPOP and BZMPOP,COUNT is left out if count isn't positive
+func mpopArgs(timeout int, direction *PopDirection, count int64, keys []string) [][]byte {
+	arr := make([][]byte, 0, len(keys)+5)
+	arr = append(arr, IntToByteArr(timeout), IntToByteArr(len(keys)))
+	arr = append(arr, StrArrToByteArrArr(keys)...)
+	arr = append(arr, direction.getRaw())
+	if count > 0 {
+		arr = append(arr, keywordCount.getRaw(), Int64ToByteArr(count))
+	}
+	return arr
 }
 
 func (c *client) watch(keys ...string) error {
-	return c.sendCommand(cmdWatch, StrArrToByteArrArr(keys)...)
+	err := c.sendCommand(cmdWatch, StrArrToByteArrArr(keys)...)
+	if err != nil {
+		return err
+	}
+	c.isInWatch = true
+	return nil
 }
 
 func (c *client) sort(key string, sortingParameters ...*SortParams) error {
@@ -472,11 +818,11 @@ func (c *client) sortMulti(key, destKey string, sortingParameters ...*SortParams
 }
 
 func (c *client) blpop(args []string) error {
-	return c.sendCommand(cmdBLPop, StrArrToByteArrArr(args)...)
+	return c.sendCommandStr(cmdBLPop, args...)
 }
 
 func (c *client) brpop(args []string) error {
-	return c.sendCommand(cmdBRPop, StrArrToByteArrArr(args)...)
+	return c.sendCommandStr(cmdBRPop, args...)
 }
 
 func (c *client) zCount(key string, min, max float64) error {
@@ -549,12 +895,77 @@ func (c *client) zinterstoreWithParams(destKey string, params *ZParams, sets ...
 	return c.sendCommand(cmdZInterStore, arr...)
 }
 
+func (c *client) zdiff(withScores bool, keys ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, IntToByteArr(len(keys)))
+	for _, k := range keys {
+		arr = append(arr, []byte(k))
+	}
+	if withScores {
+		arr = append(arr, keywordWithScores.getRaw())
+	}
+	return c.sendCommand(cmdZDiff, arr...)
+}
+
+func (c *client) zdiffstore(destKey string, keys ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(destKey))
+	arr = append(arr, IntToByteArr(len(keys)))
+	for _, k := range keys {
+		arr = append(arr, []byte(k))
+	}
+	return c.sendCommand(cmdZDiffStore, arr...)
+}
+
+func (c *client) zunion(params *ZParams, withScores bool, sets ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, IntToByteArr(len(sets)))
+	for _, s := range sets {
+		arr = append(arr, []byte(s))
+	}
+	if params != nil {
+		arr = append(arr, params.getParams()...)
+	}
+	if withScores {
+		arr = append(arr, keywordWithScores.getRaw())
+	}
+	return c.sendCommand(cmdZUnion, arr...)
+}
+
+func (c *client) zinter(params *ZParams, withScores bool, sets ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, IntToByteArr(len(sets)))
+	for _, s := range sets {
+		arr = append(arr, []byte(s))
+	}
+	if params != nil {
+		arr = append(arr, params.getParams()...)
+	}
+	if withScores {
+		arr = append(arr, keywordWithScores.getRaw())
+	}
+	return c.sendCommand(cmdZInter, arr...)
+}
+
+func (c *client) zintercard(limit int64, keys ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, IntToByteArr(len(keys)))
+	for _, k := range keys {
+		arr = append(arr, []byte(k))
+	}
+	if limit > 0 {
+		arr = append(arr, keywordLimit.getRaw())
+		arr = append(arr, Int64ToByteArr(limit))
+	}
+	return c.sendCommand(cmdZInterCard, arr...)
+}
+
 func (c *client) zlexcount(key, min, max string) error {
-	return c.sendCommand(cmdZLexCount, []byte(key), []byte(min), []byte(max))
+	return c.sendCommandStr(cmdZLexCount, key, min, max)
 }
 
 func (c *client) zrangeByLex(key, min, max string) error {
-	return c.sendCommand(cmdZRangeByLex, []byte(key), []byte(min), []byte(max))
+	return c.sendCommandStr(cmdZRangeByLex, key, min, max)
 }
 
 func (c *client) zrangeByLexBatch(key, min, max string, offset, count int) error {
@@ -563,7 +974,7 @@ func (c *client) zrangeByLexBatch(key, min, max string, offset, count int) error
 }
 
 func (c *client) zrevrangeByLex(key, max, min string) error {
-	return c.sendCommand(cmdZRevRangeByLex, []byte(key), []byte(max), []byte(min))
+	return c.sendCommandStr(cmdZRevRangeByLex, key, max, min)
 }
 
 func (c *client) zrevrangeByLexBatch(key, max, min string, offset, count int) error {
@@ -572,11 +983,11 @@ func (c *client) zrevrangeByLexBatch(key, max, min string, offset, count int) er
 }
 
 func (c *client) zremrangeByLex(key, min, max string) error {
-	return c.sendCommand(cmdZRemRangeByLex, []byte(key), []byte(min), []byte(max))
+	return c.sendCommandStr(cmdZRemRangeByLex, key, min, max)
 }
 
 func (c *client) strLen(key string) error {
-	return c.sendCommand(cmdStrLen, []byte(key))
+	return c.sendCommandStr(cmdStrLen, key)
 }
 
 func (c *client) lPushX(key string, string ...string) error {
@@ -584,7 +995,7 @@ func (c *client) lPushX(key string, string ...string) error {
 }
 
 func (c *client) persist(key string) error {
-	return c.sendCommand(cmdPersist, []byte(key))
+	return c.sendCommandStr(cmdPersist, key)
 }
 
 func (c *client) rPushX(key string, string ...string) error {
@@ -592,7 +1003,7 @@ func (c *client) rPushX(key string, string ...string) error {
 }
 
 func (c *client) echo(string string) error {
-	return c.sendCommand(cmdEcho, []byte(string))
+	return c.sendCommandStr(cmdEcho, string)
 }
 
 func (c *client) brpoplpush(source, destination string, timeout int) error {
@@ -611,28 +1022,56 @@ func (c *client) setrange(key string, offset int64, value string) error {
 	return c.sendCommand(cmdSetRange, []byte(key), Int64ToByteArr(offset), []byte(value))
 }
 
+func (c *client) psync(replicationID string, offset int64) error {
+	return c.sendCommand(cmdPSync, []byte(replicationID), Int64ToByteArr(offset))
+}
+
 func (c *client) getrange(key string, startOffset, endOffset int64) error {
 	return c.sendCommand(cmdGetRange, []byte(key), Int64ToByteArr(startOffset), Int64ToByteArr(endOffset))
 }
 
+func (c *client) setrangeBytes(key string, offset int64, value []byte) error {
+	return c.sendCommand(cmdSetRange, []byte(key), Int64ToByteArr(offset), value)
+}
+
+func (c *client) setBytes(key string, value []byte) error {
+	return c.sendCommand(cmdSet, []byte(key), value)
+}
+
+func (c *client) appendBytes(key string, value []byte) error {
+	return c.sendCommand(cmdAppend, []byte(key), value)
+}
+
 func (c *client) publish(channel, message string) error {
-	return c.sendCommand(cmdPublish, []byte(channel), []byte(message))
+	return c.sendCommandStr(cmdPublish, channel, message)
 }
 
 func (c *client) unsubscribe(channels ...string) error {
-	return c.sendCommand(cmdUnSubscribe, StrArrToByteArrArr(channels)...)
+	return c.sendCommandStr(cmdUnSubscribe, channels...)
 }
 
 func (c *client) psubscribe(patterns ...string) error {
-	return c.sendCommand(cmdPSubscribe, StrArrToByteArrArr(patterns)...)
+	return c.sendCommandStr(cmdPSubscribe, patterns...)
 }
 
 func (c *client) punsubscribe(patterns ...string) error {
-	return c.sendCommand(cmdPUnSubscribe, StrArrToByteArrArr(patterns)...)
+	return c.sendCommandStr(cmdPUnSubscribe, patterns...)
 }
 
 func (c *client) subscribe(channels ...string) error {
-	return c.sendCommand(cmdSubscribe, StrArrToByteArrArr(channels)...)
+	return c.sendCommandStr(cmdSubscribe, channels...)
+}
+
+func (c *client) spublish(channel, message string) error {
+	return c.sendCommandStr(cmdSPublish, channel, message)
+}
+
+func (c *client) ssubscribe(channels ...string) error {
+	return c.sendCommandStr(cmdSSubscribe, channels...)
+}
+
+func (c *client) sunsubscribe(channels ...string) error {
+	return c.sendCommandStr(cmdSUnSubscribe, channels...)
 }
 
 func (c *client) pubsub(subcommand string, args ...string) error {
@@ -643,8 +1082,12 @@ func (c *client) configSet(parameter, value string) error {
 	return c.sendCommand(cmdConfig, keywordSet.getRaw(), []byte(parameter), []byte(value))
 }
 
-func (c *client) configGet(pattern string) error {
-	return c.sendCommand(cmdConfig, keywordGet.getRaw(), []byte(pattern))
+func (c *client) configGet(patterns ...string) error {
+	return c.sendCommandStr(cmdConfig, StrStrArrToStrArr(keywordGet.name, patterns)...)
+}
+
+func (c *client) configRewrite() error {
+	return c.sendCommand(cmdConfig, keywordRewrite.getRaw())
 }
 
 func (c *client) eval(script string, keyCount int, params ...string) error {
@@ -663,6 +1106,22 @@ func (c *client) evalsha(sha1 string, keyCount int, params ...string) error {
 	return c.sendCommand(cmdEvalSha, arr...)
 }
 
+func (c *client) evalRo(script string, keyCount int, params ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(script))
+	arr = append(arr, IntToByteArr(keyCount))
+	arr = append(arr, StrArrToByteArrArr(params)...)
+	return c.sendCommand(cmdEvalRo, arr...)
+}
+
+func (c *client) evalshaRo(sha1 string, keyCount int, params ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(sha1))
+	arr = append(arr, IntToByteArr(keyCount))
+	arr = append(arr, StrArrToByteArrArr(params)...)
+	return c.sendCommand(cmdEvalShaRo, arr...)
+}
+
 func (c *client) scriptExists(sha1 ...string) error {
 	arr := make([][]byte, 0)
 	arr = append(arr, keywordExists.getRaw())
@@ -675,17 +1134,21 @@ func (c *client) scriptLoad(script string) error {
 }
 
 func (c *client) sentinel(args ...string) error {
-	return c.sendCommand(cmdSentinel, StrArrToByteArrArr(args)...)
+	return c.sendCommandStr(cmdSentinel, args...)
 }
 
 func (c *client) dump(key string) error {
-	return c.sendCommand(cmdDump, []byte(key))
+	return c.sendCommandStr(cmdDump, key)
 }
 
 func (c *client) restore(key string, ttl int, serializedValue []byte) error {
 	return c.sendCommand(cmdRestore, []byte(key), IntToByteArr(ttl), serializedValue)
 }
 
+func (c *client) restoreReplace(key string, ttl int, serializedValue []byte) error {
+	return c.sendCommand(cmdRestore, []byte(key), IntToByteArr(ttl), serializedValue, keywordReplace.getRaw())
+}
+
 func (c *client) incrByFloat(key string, increment float64) error {
 	return c.sendCommand(cmdIncrByFloat, []byte(key), Float64ToByteArr(increment))
 }
@@ -702,10 +1165,18 @@ func (c *client) clientGetname() error {
 	return c.sendCommand(cmdClient, keywordGetName.getRaw())
 }
 
+func (c *client) clientKillFilter(filter *ClientFilter) error {
+	return c.sendCommand(cmdClient, append([][]byte{keywordKill.getRaw()}, filter.killParams()...)...)
+}
+
 func (c *client) clientList() error {
 	return c.sendCommand(cmdClient, keywordList.getRaw())
 }
 
+func (c *client) clientListFilter(filter *ClientFilter) error {
+	return c.sendCommand(cmdClient, append([][]byte{keywordList.getRaw()}, filter.listParams()...)...)
+}
+
 func (c *client) clientSetname(name string) error {
 	return c.sendCommand(cmdClient, keywordSetName.getRaw(), []byte(name))
 }
@@ -772,16 +1243,24 @@ func (c *client) geopos(key string, members ...string) error {
 	return c.sendCommand(cmdGeoPos, StrStrArrToByteArrArr(key, members)...)
 }
 
-func (c *client) flushDB() error {
-	return c.sendCommand(cmdFlushDB)
+func (c *client) flushDB(mode ...*FlushMode) error {
+	return c.sendCommand(cmdFlushDB, flushModeArgs(mode)...)
 }
 
 func (c *client) dbSize() error {
 	return c.sendCommand(cmdDbSize)
 }
 
-func (c *client) flushAll() error {
-	return c.sendCommand(cmdFlushAll)
+func (c *client) flushAll(mode ...*FlushMode) error {
+	return c.sendCommand(cmdFlushAll, flushModeArgs(mode)...)
+}
+
+func flushModeArgs(mode []*FlushMode) [][]byte {
+	arr := make([][]byte, 0)
+	for _, m := range mode {
+		arr = append(arr, m.getRaw())
+	}
+	return arr
 }
 
 func (c *client) save() error {
@@ -800,8 +1279,12 @@ func (c *client) lastsave() error {
 	return c.sendCommand(cmdLastSave)
 }
 
-func (c *client) shutdown() error {
-	return c.sendCommand(cmdShutdown)
+func (c *client) shutdown(params ...*ShutdownParams) error {
+	arr := make([][]byte, 0)
+	for _, p := range params {
+		arr = append(arr, p.params...)
+	}
+	return c.sendCommand(cmdShutdown, arr...)
 }
 
 func (c *client) slaveof(host string, port int) error {
@@ -849,11 +1332,15 @@ func (c *client) linsert(key string, where *ListOption, pivot, value string) err
 }
 
 func (c *client) bitcount(key string) error {
-	return c.sendCommand(cmdBitCount, []byte(key))
+	return c.sendCommandStr(cmdBitCount, key)
 }
 
-func (c *client) bitcountRange(key string, start, end int64) error {
-	return c.sendCommand(cmdBitCount, []byte(key), Int64ToByteArr(start), Int64ToByteArr(end))
+func (c *client) bitcountRange(key string, start, end int64, unit ...*BitUnit) error {
+	arr := [][]byte{[]byte(key), Int64ToByteArr(start), Int64ToByteArr(end)}
+	for _, u := range unit {
+		arr = append(arr, []byte(u.name))
+	}
+	return c.sendCommand(cmdBitCount, arr...)
 }
 
 func (c *client) bitpos(key string, value bool, params ...*BitPosParams) error {
@@ -861,7 +1348,7 @@ func (c *client) bitpos(key string, value bool, params ...*BitPosParams) error {
 	arr = append(arr, []byte(key))
 	arr = append(arr, BoolToByteArr(value))
 	for _, p := range params {
-		arr = append(arr, p.params...)
+		arr = append(arr, p.getParams()...)
 	}
 	return c.sendCommand(cmdBitPos, arr...)
 }
@@ -881,10 +1368,57 @@ func (c *client) hscan(key, cursor string, params ...*ScanParams) error {
 	arr = append(arr, []byte(cursor))
 	for _, p := range params {
 		arr = append(arr, p.getParams()...)
+		if p.noValues {
+			arr = append(arr, keywordNoValues.getRaw())
+		}
 	}
 	return c.sendCommand(cmdHScan, arr...)
 }
 
+func (c *client) hexpire(key string, seconds int64, fields []string, condition ...*ExpireCondition) error {
+	return c.hashExpire(cmdHExpire, key, seconds, fields, condition...)
+}
+
+func (c *client) hpexpire(key string, milliseconds int64, fields []string, condition ...*ExpireCondition) error {
+	return c.hashExpire(cmdHPExpire, key, milliseconds, fields, condition...)
+}
+
+func (c *client) hexpireAt(key string, unixTime int64, fields []string, condition ...*ExpireCondition) error {
+	return c.hashExpire(cmdHExpireAt, key, unixTime, fields, condition...)
+}
+
+//hashExpire send key ttl [NX|XX|GT|LT] FIELDS numfields field [field ...]
+func (c *client) hashExpire(command protocolCommand, key string, ttl int64, fields []string, condition ...*ExpireCondition) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key))
+	arr = append(arr, Int64ToByteArr(ttl))
+	for _, cond := range condition {
+		arr = append(arr, cond.getRaw())
+	}
+	arr = append(arr, hashFields(fields)...)
+	return c.sendCommand(command, arr...)
+}
+
+func (c *client) hpersist(key string, fields ...string) error {
+	return c.sendCommand(cmdHPersist, append([][]byte{[]byte(key)}, hashFields(fields)...)...)
+}
+
+func (c *client) httl(key string, fields ...string) error {
+	return c.sendCommand(cmdHTTL, append([][]byte{[]byte(key)}, hashFields(fields)...)...)
+}
+
+func (c *client) hpttl(key string, fields ...string) error {
+	return c.sendCommand(cmdHPTTL, append([][]byte{[]byte(key)}, hashFields(fields)...)...)
+}
+
+//hashFields encode the fields as FIELDS numfields field [field ...]
+func hashFields(fields []string) [][]byte {
+	arr := make([][]byte, 0, len(fields)+2)
+	arr = append(arr, keywordFields.getRaw())
+	arr = append(arr, IntToByteArr(len(fields)))
+	return append(arr, StrArrToByteArrArr(fields)...)
+}
+
 func (c *client) sscan(key, cursor string, params ...*ScanParams) error {
 	arr := make([][]byte, 0)
 	arr = append(arr, []byte(key))
@@ -906,7 +1440,12 @@ func (c *client) zscan(key, cursor string, params ...*ScanParams) error {
 }
 
 func (c *client) unwatch() error {
-	return c.sendCommand(cmdUnwatch)
+	err := c.sendCommand(cmdUnwatch)
+	if err != nil {
+		return err
+	}
+	c.isInWatch = false
+	return nil
 }
 
 func (c *client) blpopTimout(timeout int, keys ...string) error {
@@ -927,6 +1466,18 @@ func (c *client) brpopTimout(timeout int, keys ...string) error {
 	return c.brpop(arr)
 }
 
+//parseBlockTimeout the timeout of BLPOP and BRPOP args,the last arg in seconds
+func parseBlockTimeout(args []string) time.Duration {
+	if len(args) == 0 {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(args[len(args)-1], 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
 func (c *client) pfadd(key string, elements ...string) error {
 	return c.sendCommand(cmdPfAdd, StrStrArrToByteArrArr(key, elements)...)
 }
@@ -960,6 +1511,10 @@ func (c *client) bitfield(key string, arguments ...string) error {
 	return c.sendCommand(cmdBitField, StrStrArrToByteArrArr(key, arguments)...)
 }
 
+func (c *client) bitfieldRo(key string, arguments ...string) error {
+	return c.sendCommand(cmdBitFieldRo, StrStrArrToByteArrArr(key, arguments)...)
+}
+
 func (c *client) randomKey() error {
 	return c.sendCommand(cmdRandomKey)
 }
@@ -990,7 +1545,11 @@ func (c *client) pfmerge(destkey string, sourcekeys ...string) error {
 }
 
 func (c *client) pfcount(keys ...string) error {
-	return c.sendCommand(cmdPfCount, StrArrToByteArrArr(keys)...)
+	return c.sendCommandStr(cmdPfCount, keys...)
+}
+
+func (c *client) pfdebug(subcommand, key string) error {
+	return c.sendCommandStr(cmdPfDebug, subcommand, key)
 }
 
 func (c *client) slowlogReset() error {
@@ -1022,8 +1581,18 @@ func (c *client) objectIdletime(str string) error {
 	return c.sendCommand(cmdObject, keywordIdleTime.getRaw(), []byte(str))
 }
 
+func (c *client) memoryUsage(key string, samples ...int) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, keywordUsage.getRaw())
+	arr = append(arr, []byte(key))
+	for _, s := range samples {
+		arr = append(arr, keywordSamples.getRaw(), IntToByteArr(s))
+	}
+	return c.sendCommand(cmdMemory, arr...)
+}
+
 func (c *client) clusterNodes() error {
-	return c.sendCommand(cmdCluster, []byte(clusterNodes))
+	return c.sendCommandStr(cmdCluster, clusterNodes)
 }
 
 func (c *client) clusterMeet(ip string, port int) error {
@@ -1049,7 +1618,7 @@ func (c *client) clusterDelSlots(slots ...int) error {
 }
 
 func (c *client) clusterInfo() error {
-	return c.sendCommand(cmdCluster, []byte(clusterInfo))
+	return c.sendCommandStr(cmdCluster, clusterInfo)
 }
 
 func (c *client) clusterGetKeysInSlot(slot int, count int) error {
@@ -1073,15 +1642,15 @@ func (c *client) clusterSetSlotStable(slot int) error {
 }
 
 func (c *client) clusterForget(nodeID string) error {
-	return c.sendCommand(cmdCluster, []byte(clusterForget), []byte(nodeID))
+	return c.sendCommandStr(cmdCluster, clusterForget, nodeID)
 }
 
 func (c *client) clusterFlushSlots() error {
-	return c.sendCommand(cmdCluster, []byte(clusterFlushSlot))
+	return c.sendCommandStr(cmdCluster, clusterFlushSlot)
 }
 
 func (c *client) clusterKeySlot(key string) error {
-	return c.sendCommand(cmdCluster, []byte(clusterKeySlot), []byte(key))
+	return c.sendCommandStr(cmdCluster, clusterKeySlot, key)
 }
 
 func (c *client) clusterCountKeysInSlot(slot int) error {
@@ -1089,23 +1658,43 @@ func (c *client) clusterCountKeysInSlot(slot int) error {
 }
 
 func (c *client) clusterSaveConfig() error {
-	return c.sendCommand(cmdCluster, []byte(clusterSaveConfig))
+	return c.sendCommandStr(cmdCluster, clusterSaveConfig)
 }
 
 func (c *client) clusterReplicate(nodeID string) error {
-	return c.sendCommand(cmdCluster, []byte(clusterReplicate), []byte(nodeID))
+	return c.sendCommandStr(cmdCluster, clusterReplicate, nodeID)
 }
 
 func (c *client) clusterSlaves(nodeID string) error {
-	return c.sendCommand(cmdCluster, []byte(clusterSlaves), []byte(nodeID))
+	return c.sendCommandStr(cmdCluster, clusterSlaves, nodeID)
 }
 
 func (c *client) clusterFailover() error {
-	return c.sendCommand(cmdCluster, []byte(clusterFailOver))
+	return c.sendCommandStr(cmdCluster, clusterFailOver)
 }
 
 func (c *client) clusterSlots() error {
-	return c.sendCommand(cmdCluster, []byte(clusterSlots))
+	return c.sendCommandStr(cmdCluster, clusterSlots)
+}
+
+func (c *client) clusterShards() error {
+	return c.sendCommandStr(cmdCluster, clusterShards)
+}
+
+func (c *client) clusterMyID() error {
+	return c.sendCommandStr(cmdCluster, clusterMyID)
+}
+
+func (c *client) clusterLinks() error {
+	return c.sendCommandStr(cmdCluster, clusterLinks)
+}
+
+func (c *client) clusterBumpEpoch() error {
+	return c.sendCommandStr(cmdCluster, clusterBumpEpoch)
+}
+
+func (c *client) clusterCountFailureReports(nodeID string) error {
+	return c.sendCommandStr(cmdCluster, clusterCountFailureReports, nodeID)
 }
 
 func (c *client) clusterReset(resetType Reset) error {
@@ -1113,23 +1702,23 @@ func (c *client) clusterReset(resetType Reset) error {
 }
 
 func (c *client) sentinelMasters() error {
-	return c.sendCommand(cmdSentinel, []byte(sentinelMasters))
+	return c.sendCommandStr(cmdSentinel, sentinelMasters)
 }
 
 func (c *client) sentinelGetMasterAddrByName(masterName string) error {
-	return c.sendCommand(cmdSentinel, []byte(sentinelGetMasterAddrByName), []byte(masterName))
+	return c.sendCommandStr(cmdSentinel, sentinelGetMasterAddrByName, masterName)
 }
 
 func (c *client) sentinelReset(pattern string) error {
-	return c.sendCommand(cmdSentinel, []byte(sentinelReset), []byte(pattern))
+	return c.sendCommandStr(cmdSentinel, sentinelReset, pattern)
 }
 
 func (c *client) sentinelSlaves(masterName string) error {
-	return c.sendCommand(cmdSentinel, []byte(sentinelSlaves), []byte(masterName))
+	return c.sendCommandStr(cmdSentinel, sentinelSlaves, masterName)
 }
 
 func (c *client) sentinelFailover(masterName string) error {
-	return c.sendCommand(cmdSentinel, []byte(sentinelFailOver), []byte(masterName))
+	return c.sendCommandStr(cmdSentinel, sentinelFailOver, masterName)
 }
 
 func (c *client) sentinelMonitor(masterName, ip string, port, quorum int) error {
@@ -1137,7 +1726,31 @@ func (c *client) sentinelMonitor(masterName, ip string, port, quorum int) error
 }
 
 func (c *client) sentinelRemove(masterName string) error {
-	return c.sendCommand(cmdSentinel, []byte(sentinelRemove), []byte(masterName))
+	return c.sendCommandStr(cmdSentinel, sentinelRemove, masterName)
+}
+
+func (c *client) sentinelMaster(masterName string) error {
+	return c.sendCommandStr(cmdSentinel, sentinelMaster, masterName)
+}
+
+func (c *client) sentinelSentinels(masterName string) error {
+	return c.sendCommandStr(cmdSentinel, sentinelSentinels, masterName)
+}
+
+func (c *client) sentinelCkQuorum(masterName string) error {
+	return c.sendCommandStr(cmdSentinel, sentinelCkQuorum, masterName)
+}
+
+func (c *client) sentinelFlushConfig() error {
+	return c.sendCommandStr(cmdSentinel, sentinelFlushConfig)
+}
+
+func (c *client) sentinelSimulateFailure(failures ...*SimulateFailure) error {
+	args := []string{sentinelSimulateFailure}
+	for _, f := range failures {
+		args = append(args, f.name)
+	}
+	return c.sendCommandStr(cmdSentinel, args...)
 }
 
 func (c *client) sentinelSet(masterName string, parameterMap map[string]string) error {
@@ -1152,7 +1765,94 @@ func (c *client) sentinelSet(masterName string, parameterMap map[string]string)
 }
 
 func (c *client) pubsubChannels(pattern string) error {
-	return c.sendCommand(cmdPubSub, []byte(pubSubChannels), []byte(pattern))
+	return c.sendCommandStr(cmdPubSub, pubSubChannels, pattern)
+}
+
+func (c *client) pubsubShardChannels(pattern string) error {
+	return c.sendCommandStr(cmdPubSub, pubSubShardChannels, pattern)
+}
+
+func (c *client) pubsubShardNumSub(channels ...string) error {
+	return c.sendCommand(cmdPubSub, StrStrArrToByteArrArr(pubSubShardNumSub, channels)...)
+}
+
+func (c *client) xadd(key, id string, hash map[string]string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key), []byte(id))
+	for k, v := range hash {
+		arr = append(arr, []byte(k), []byte(v))
+	}
+	return c.sendCommand(cmdXAdd, arr...)
+}
+
+func (c *client) xlen(key string) error {
+	return c.sendCommandStr(cmdXLen, key)
+}
+
+func (c *client) xdel(key string, ids ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key))
+	arr = append(arr, StrArrToByteArrArr(ids)...)
+	return c.sendCommand(cmdXDel, arr...)
+}
+
+func (c *client) xack(key, group string, ids ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key), []byte(group))
+	arr = append(arr, StrArrToByteArrArr(ids)...)
+	return c.sendCommand(cmdXAck, arr...)
+}
+
+func (c *client) xgroupCreate(key, group, id string, mkStream bool) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, keywordCreate.getRaw(), []byte(key), []byte(group), []byte(id))
+	if mkStream {
+		arr = append(arr, keywordMkStream.getRaw())
+	}
+	return c.sendCommand(cmdXGroup, arr...)
+}
+
+func (c *client) xreadGroup(group, consumer string, count int, block time.Duration, streams ...string) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, keywordGroup.getRaw(), []byte(group), []byte(consumer))
+	if count > 0 {
+		arr = append(arr, keywordCount.getRaw(), IntToByteArr(count))
+	}
+	if block > 0 {
+		arr = append(arr, keywordBlock.getRaw(), Int64ToByteArr(int64(block/time.Millisecond)))
+	}
+	arr = append(arr, keywordStreams.getRaw())
+	arr = append(arr, StrArrToByteArrArr(streams)...)
+	return c.sendCommand(cmdXReadGroup, arr...)
+}
+
+func (c *client) xpending(key, group, start, end string, count int64, params ...*XPendingParams) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key), []byte(group))
+	consumer := ""
+	for _, p := range params {
+		if p.idle > 0 {
+			arr = append(arr, keywordIdle.getRaw(), Int64ToByteArr(int64(p.idle/time.Millisecond)))
+		}
+		if p.consumer != "" {
+			consumer = p.consumer
+		}
+	}
+	arr = append(arr, []byte(start), []byte(end), Int64ToByteArr(count))
+	if consumer != "" {
+		arr = append(arr, []byte(consumer))
+	}
+	return c.sendCommand(cmdXPending, arr...)
+}
+
+func (c *client) xautoClaim(key, group, consumer string, minIdle time.Duration, start string, count int) error {
+	arr := make([][]byte, 0)
+	arr = append(arr, []byte(key), []byte(group), []byte(consumer),
+		Int64ToByteArr(int64(minIdle/time.Millisecond)), []byte(start))
+	if count > 0 {
+		arr = append(arr, keywordCount.getRaw(), IntToByteArr(count))
+	}
+	return c.sendCommand(cmdXAutoClaim, arr...)
 }
 
 func (c *client) multi() error {
@@ -1,11 +1,13 @@
 package godis
 
 import (
+	"context"
 	"errors"
 	"math/rand"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,6 +18,8 @@ const (
 type redisClusterInfoCache struct {
 	nodes sync.Map
 	slots sync.Map
+	//nodeLock serializes the creation of the node pools,so a pool is never dialed and thrown away
+	nodeLock sync.Mutex
 
 	rwLock        sync.RWMutex
 	rLock         sync.Mutex
@@ -26,14 +30,16 @@ type redisClusterInfoCache struct {
 	connectionTimeout time.Duration
 	soTimeout         time.Duration
 	password          string
+	dialer            DialFunc
 }
 
-func newRedisClusterInfoCache(connectionTimeout, soTimeout time.Duration, password string, poolConfig *PoolConfig) *redisClusterInfoCache {
+func newRedisClusterInfoCache(connectionTimeout, soTimeout time.Duration, password string, poolConfig *PoolConfig, dialer DialFunc) *redisClusterInfoCache {
 	return &redisClusterInfoCache{
 		poolConfig:        poolConfig,
 		connectionTimeout: connectionTimeout,
 		soTimeout:         soTimeout,
 		password:          password,
+		dialer:            dialer,
 	}
 }
 
@@ -99,10 +105,17 @@ func (r *redisClusterInfoCache) discoverClusterSlots(redis *Redis) error {
 	if err != nil {
 		return err
 	}
+	r.applyClusterSlots(slots)
+	return nil
+}
+
+//applyClusterSlots reassign the slots,create pools of new nodes and close pools of departed nodes
+func (r *redisClusterInfoCache) applyClusterSlots(slots []interface{}) {
 	r.slots.Range(func(key, value interface{}) bool {
 		r.slots.Delete(key)
 		return true
 	})
+	liveNodes := make(map[string]bool)
 	for _, s := range slots {
 		slotInfo := s.([]interface{})
 		size := len(slotInfo)
@@ -110,14 +123,35 @@ func (r *redisClusterInfoCache) discoverClusterSlots(redis *Redis) error {
 			continue
 		}
 		slotNums := r.getAssignedSlotArray(slotInfo)
-		hostInfos := slotInfo[masterNodeIndex].([]interface{})
-		if len(hostInfos) == 0 {
-			continue
+		for i := masterNodeIndex; i < size; i++ {
+			hostInfos := slotInfo[i].([]interface{})
+			if len(hostInfos) == 0 {
+				continue
+			}
+			host, port := r.generateHostAndPort(hostInfos)
+			liveNodes[host+":"+strconv.Itoa(port)] = true
+			r.setupNodeIfNotExist(false, host, port)
+			if i == masterNodeIndex {
+				r.assignSlotsToNode(true, slotNums, host, port)
+			}
 		}
-		host, port := r.generateHostAndPort(hostInfos)
-		r.assignSlotsToNode(true, slotNums, host, port)
 	}
-	return nil
+	r.nodes.Range(func(key, value interface{}) bool {
+		if !liveNodes[key.(string)] {
+			r.nodes.Delete(key)
+			if value != nil {
+				go r.closeNodePool(value.(*Pool))
+			}
+		}
+		return true
+	})
+}
+
+//closeNodePool wait for the in-flight commands of the departed node at most soTimeout,then close its pool
+func (r *redisClusterInfoCache) closeNodePool(pool *Pool) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.soTimeout)
+	defer cancel()
+	_ = pool.Shutdown(ctx)
 }
 
 func (r *redisClusterInfoCache) reset(lock bool) {
@@ -155,12 +189,19 @@ func (r *redisClusterInfoCache) setupNodeIfNotExist(lock bool, host string, port
 	if ok && existingPool != nil {
 		return existingPool.(*Pool)
 	}
+	r.nodeLock.Lock()
+	defer r.nodeLock.Unlock()
+	existingPool, ok = r.nodes.Load(nodeKey)
+	if ok && existingPool != nil {
+		return existingPool.(*Pool)
+	}
 	nodePool := NewPool(r.poolConfig, &Option{
 		Host:              host,
 		Port:              port,
 		ConnectionTimeout: r.connectionTimeout,
 		SoTimeout:         r.soTimeout,
 		Password:          r.password,
+		Dialer:            r.dialer,
 	})
 	r.nodes.Store(nodeKey, nodePool)
 	return nodePool
@@ -218,6 +259,23 @@ func (r *redisClusterInfoCache) getNodes() map[string]*Pool {
 	return ret
 }
 
+//getMasterNodes the nodes serving slots,keyed by host:port
+func (r *redisClusterInfoCache) getMasterNodes() map[string]*Pool {
+	masters := make(map[*Pool]bool)
+	r.slots.Range(func(key, value interface{}) bool {
+		masters[value.(*Pool)] = true
+		return true
+	})
+	ret := make(map[string]*Pool)
+	r.nodes.Range(func(key, value interface{}) bool {
+		if value != nil && masters[value.(*Pool)] {
+			ret[key.(string)] = value.(*Pool)
+		}
+		return true
+	})
+	return ret
+}
+
 func (r *redisClusterInfoCache) getSlotPool(slot int) *Pool {
 	if value, ok := r.slots.Load(slot); ok {
 		return value.(*Pool)
@@ -227,10 +285,17 @@ func (r *redisClusterInfoCache) getSlotPool(slot int) *Pool {
 
 type redisClusterConnectionHandler struct {
 	cache *redisClusterInfoCache
+
+	disableMovedRefresh  bool
+	movedRefreshInterval time.Duration
+	lastRefresh          int64 //unix nano of the last topology refresh
+	retryNonIdempotent   bool  //retry the commands which aren't idempotent after ambiguous connection failures
+	stopRefresh          chan struct{}
+	closeOnce            sync.Once
 }
 
-func newRedisClusterConnectionHandler(nodes []string, connectionTimeout, soTimeout time.Duration, password string, poolConfig *PoolConfig) *redisClusterConnectionHandler {
-	cache := newRedisClusterInfoCache(connectionTimeout, soTimeout, password, poolConfig)
+func newRedisClusterConnectionHandler(nodes []string, connectionTimeout, soTimeout time.Duration, password string, poolConfig *PoolConfig, dialer DialFunc) *redisClusterConnectionHandler {
+	cache := newRedisClusterInfoCache(connectionTimeout, soTimeout, password, poolConfig, dialer)
 	for _, node := range nodes {
 		arr := strings.Split(node, ":")
 		port, err := strconv.Atoi(arr[1])
@@ -238,8 +303,9 @@ func newRedisClusterConnectionHandler(nodes []string, connectionTimeout, soTimeo
 			continue
 		}
 		redis := NewRedis(&Option{
-			Host: arr[0],
-			Port: port,
+			Host:   arr[0],
+			Port:   port,
+			Dialer: dialer,
 		})
 		if password != "" {
 			_, err := redis.Auth(password)
@@ -297,7 +363,12 @@ func (r *redisClusterConnectionHandler) getNodes() map[string]*Pool {
 	return r.cache.getNodes()
 }
 
+func (r *redisClusterConnectionHandler) getMasterNodes() map[string]*Pool {
+	return r.cache.getMasterNodes()
+}
+
 func (r *redisClusterConnectionHandler) renewSlotCache(redis ...*Redis) {
+	atomic.StoreInt64(&r.lastRefresh, time.Now().UnixNano())
 	if len(redis) == 0 {
 		_ = r.cache.renewClusterSlots(nil)
 		return
@@ -307,6 +378,49 @@ func (r *redisClusterConnectionHandler) renewSlotCache(redis ...*Redis) {
 	}
 }
 
+//renewSlotCacheOnMoved refresh the topology on MOVED,
+//or only reassign the moved slot if the refresh on MOVED is disabled or the last refresh is too recent
+func (r *redisClusterConnectionHandler) renewSlotCacheOnMoved(redis *Redis, moved *MovedDataError) {
+	last := atomic.LoadInt64(&r.lastRefresh)
+	if r.disableMovedRefresh || time.Now().UnixNano()-last < int64(r.movedRefreshInterval) {
+		r.cache.assignSlotToNode(moved.Slot, moved.Host, moved.Port)
+		return
+	}
+	r.renewSlotCache(redis)
+}
+
+//startTopologyRefresh refresh the topology every interval until close
+func (r *redisClusterConnectionHandler) startTopologyRefresh(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	r.stopRefresh = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stopRefresh:
+				return
+			case <-ticker.C:
+				r.renewSlotCache()
+			}
+		}
+	}()
+}
+
+//close stop the topology refresh and destroy the pools of all nodes
+func (r *redisClusterConnectionHandler) close() {
+	r.closeOnce.Do(func() {
+		if r.stopRefresh != nil {
+			close(r.stopRefresh)
+		}
+		r.cache.wLock.Lock()
+		r.cache.reset(false)
+		r.cache.wLock.Unlock()
+	})
+}
+
 type redisClusterHashTagUtil struct {
 }
 
@@ -428,6 +542,10 @@ func (r *redisClusterCommand) runWithRetries(key []byte, attempts int, tryRandom
 	case *NoReachableClusterNodeError:
 		return nil, err
 	case *ConnectError:
+		if connection.client.ambiguous() && !r.connectionHandler.retryNonIdempotent {
+			//redis may have executed the command before the connection failed
+			return nil, err
+		}
 		_ = r.releaseConnection(connection)
 		if attempts <= 1 {
 			r.connectionHandler.renewSlotCache()
@@ -435,7 +553,7 @@ func (r *redisClusterCommand) runWithRetries(key []byte, attempts int, tryRandom
 		}
 		return r.runWithRetries(key, attempts-1, tryRandomNode, redirect)
 	case *MovedDataError:
-		r.connectionHandler.renewSlotCache(connection)
+		r.connectionHandler.renewSlotCacheOnMoved(connection, err.(*MovedDataError))
 		_ = r.releaseConnection(connection)
 		return r.runWithRetries(key, attempts-1, false, err)
 	}
@@ -474,12 +592,24 @@ type ClusterOption struct {
 	MaxAttempts       int           //when operation or socket is not alright,then program will attempt retry
 	Password          string        //cluster redis password
 	PoolConfig        *PoolConfig   //redis connection pool config
+	Dialer            DialFunc      //dial the connections to the nodes instead of net.Dialer,see Option.Dialer
+
+	TopologyRefreshInterval time.Duration //refresh the cluster topology periodically,0 means no periodic refresh
+	DisableMovedRefresh     bool          //on MOVED only reassign the moved slot instead of refreshing the whole topology
+	MovedRefreshInterval    time.Duration //the minimum interval between refreshes triggered by MOVED,only the moved slot is reassigned in between
+
+	//retry the commands which aren't idempotent,such as INCR and LPUSH,when the connection fails after they are sent,
+	//they may be executed twice,by default the error is returned,see CommandSpec.Idempotent
+	RetryNonIdempotent bool
+
+	ParallelWorkers int //max concurrent commands of the parallel batch commands,such as MGetParallel,default 8
 }
 
 //RedisCluster redis cluster tool
 type RedisCluster struct {
 	MaxAttempts       int
 	connectionHandler *redisClusterConnectionHandler
+	parallelWorkers   int
 }
 
 //NewRedisCluster constructor
@@ -495,12 +625,23 @@ func NewRedisCluster(option *ClusterOption) *RedisCluster {
 	if option.SoTimeout == 0 {
 		soTimeout = 5 * time.Second
 	}
+	connectionHandler := newRedisClusterConnectionHandler(option.Nodes, conTimeout, soTimeout, option.Password, option.PoolConfig, option.Dialer)
+	connectionHandler.disableMovedRefresh = option.DisableMovedRefresh
+	connectionHandler.movedRefreshInterval = option.MovedRefreshInterval
+	connectionHandler.retryNonIdempotent = option.RetryNonIdempotent
+	connectionHandler.startTopologyRefresh(option.TopologyRefreshInterval)
 	return &RedisCluster{
 		MaxAttempts:       option.MaxAttempts,
-		connectionHandler: newRedisClusterConnectionHandler(option.Nodes, conTimeout, soTimeout, option.Password, option.PoolConfig),
+		connectionHandler: connectionHandler,
+		parallelWorkers:   option.ParallelWorkers,
 	}
 }
 
+//Close stop the topology refresh and close the pools of all nodes
+func (r *RedisCluster) Close() {
+	r.connectionHandler.close()
+}
+
 //<editor-fold desc="rediscommands">
 
 //Set set key/value,without timeout
@@ -611,6 +752,33 @@ func (r *RedisCluster) PTTL(key string) (int64, error) {
 	return ToInt64Reply(command.run(key))
 }
 
+//Dump  see comment in redis.go
+func (r *RedisCluster) Dump(key string) ([]byte, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.Dump(key)
+	}
+	return ToByteArrReply(command.run(key))
+}
+
+//Restore  see comment in redis.go
+func (r *RedisCluster) Restore(key string, ttl int, serializedValue []byte) (string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.Restore(key, ttl, serializedValue)
+	}
+	return ToStrReply(command.run(key))
+}
+
+//RestoreReplace  see comment in redis.go
+func (r *RedisCluster) RestoreReplace(key string, ttl int, serializedValue []byte) (string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.RestoreReplace(key, ttl, serializedValue)
+	}
+	return ToStrReply(command.run(key))
+}
+
 //SetBitWithBool see redis command
 func (r *RedisCluster) SetBitWithBool(key string, offset int64, value bool) (bool, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -665,6 +833,15 @@ func (r *RedisCluster) GetSet(key, value string) (string, error) {
 	return ToStrReply(command.run(key))
 }
 
+//GetDel see redis command
+func (r *RedisCluster) GetDel(key string) (string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.GetDel(key)
+	}
+	return ToStrReply(command.run(key))
+}
+
 //SetNx see redis command
 func (r *RedisCluster) SetNx(key, value string) (int64, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -944,6 +1121,24 @@ func (r *RedisCluster) LRem(key string, count int64, value string) (int64, error
 	return ToInt64Reply(command.run(key))
 }
 
+//LPos see redis command
+func (r *RedisCluster) LPos(key, element string, params ...*LPosParams) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.LPos(key, element, params...)
+	}
+	return ToInt64Reply(command.run(key))
+}
+
+//LPosCount see redis command
+func (r *RedisCluster) LPosCount(key, element string, count int64, params ...*LPosParams) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.LPosCount(key, element, count, params...)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
 //LPop see redis command
 func (r *RedisCluster) LPop(key string) (string, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -962,6 +1157,24 @@ func (r *RedisCluster) RPop(key string) (string, error) {
 	return ToStrReply(command.run(key))
 }
 
+//LPopCount see redis command
+func (r *RedisCluster) LPopCount(key string, count int64) ([]string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.LPopCount(key, count)
+	}
+	return ToStrArrReply(command.run(key))
+}
+
+//RPopCount see redis command
+func (r *RedisCluster) RPopCount(key string, count int64) ([]string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.RPopCount(key, count)
+	}
+	return ToStrArrReply(command.run(key))
+}
+
 //SAdd see redis command
 func (r *RedisCluster) SAdd(key string, members ...string) (int64, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -980,6 +1193,15 @@ func (r *RedisCluster) SMembers(key string) ([]string, error) {
 	return ToStrArrReply(command.run(key))
 }
 
+//SMembersMap  see comment in redis.go
+func (r *RedisCluster) SMembersMap(key string) (map[string]struct{}, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.SMembersMap(key)
+	}
+	return ToStrSetReply(command.run(key))
+}
+
 //SRem see redis command
 func (r *RedisCluster) SRem(key string, members ...string) (int64, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -1160,6 +1382,24 @@ func (r *RedisCluster) ZScore(key, member string) (float64, error) {
 	return ToFloat64Reply(command.run(key))
 }
 
+//ZPopMin  see comment in redis.go
+func (r *RedisCluster) ZPopMin(key string, count ...int64) ([]Tuple, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZPopMin(key, count...)
+	}
+	return ToTupleArrReply(command.run(key))
+}
+
+//ZPopMax  see comment in redis.go
+func (r *RedisCluster) ZPopMax(key string, count ...int64) ([]Tuple, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZPopMax(key, count...)
+	}
+	return ToTupleArrReply(command.run(key))
+}
+
 //Sort  see comment in redis.go
 func (r *RedisCluster) Sort(key string, params ...*SortParams) ([]string, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -1359,10 +1599,10 @@ func (r *RedisCluster) BitCount(key string) (int64, error) {
 }
 
 //BitCountRange  see comment in redis.go
-func (r *RedisCluster) BitCountRange(key string, start int64, end int64) (int64, error) {
+func (r *RedisCluster) BitCountRange(key string, start int64, end int64, unit ...*BitUnit) (int64, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
 	command.execute = func(redis *Redis) (interface{}, error) {
-		return redis.BitCountRange(key, start, end)
+		return redis.BitCountRange(key, start, end, unit...)
 	}
 	return ToInt64Reply(command.run(key))
 }
@@ -1385,6 +1625,60 @@ func (r *RedisCluster) HScan(key, cursor string, params ...*ScanParams) (*ScanRe
 	return ToScanResultReply(command.run(key))
 }
 
+//HExpire  see comment in redis.go
+func (r *RedisCluster) HExpire(key string, seconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.HExpire(key, seconds, fields, condition...)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
+//HPExpire  see comment in redis.go
+func (r *RedisCluster) HPExpire(key string, milliseconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.HPExpire(key, milliseconds, fields, condition...)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
+//HExpireAt  see comment in redis.go
+func (r *RedisCluster) HExpireAt(key string, unixTime int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.HExpireAt(key, unixTime, fields, condition...)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
+//HPersist  see comment in redis.go
+func (r *RedisCluster) HPersist(key string, fields ...string) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.HPersist(key, fields...)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
+//HTtl  see comment in redis.go
+func (r *RedisCluster) HTtl(key string, fields ...string) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.HTtl(key, fields...)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
+//HPTtl  see comment in redis.go
+func (r *RedisCluster) HPTtl(key string, fields ...string) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.HPTtl(key, fields...)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
 //SScan  see comment in redis.go
 func (r *RedisCluster) SScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -1484,6 +1778,105 @@ func (r *RedisCluster) BitField(key string, arguments ...string) ([]int64, error
 	return ToInt64ArrReply(command.run(key))
 }
 
+//BitFieldWithArgs  see comment in redis.go
+func (r *RedisCluster) BitFieldWithArgs(key string, args *BitFieldArgs) ([]*int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.BitFieldWithArgs(key, args)
+	}
+	reply, err := command.run(key)
+	if err != nil {
+		return nil, err
+	}
+	return reply.([]*int64), nil
+}
+
+//BitFieldRo  see comment in redis.go
+func (r *RedisCluster) BitFieldRo(key string, args *BitFieldArgs) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.BitFieldRo(key, args)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
+//</editor-fold>
+
+//<editor-fold desc="streamcommands">
+
+//XAdd  see comment in redis.go
+func (r *RedisCluster) XAdd(key, id string, hash map[string]string) (string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XAdd(key, id, hash)
+	}
+	return ToStrReply(command.run(key))
+}
+
+//XLen  see comment in redis.go
+func (r *RedisCluster) XLen(key string) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XLen(key)
+	}
+	return ToInt64Reply(command.run(key))
+}
+
+//XDel  see comment in redis.go
+func (r *RedisCluster) XDel(key string, ids ...string) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XDel(key, ids...)
+	}
+	return ToInt64Reply(command.run(key))
+}
+
+//XAck  see comment in redis.go
+func (r *RedisCluster) XAck(key, group string, ids ...string) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XAck(key, group, ids...)
+	}
+	return ToInt64Reply(command.run(key))
+}
+
+//XGroupCreate  see comment in redis.go
+func (r *RedisCluster) XGroupCreate(key, group, id string, mkStream bool) (string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XGroupCreate(key, group, id, mkStream)
+	}
+	return ToStrReply(command.run(key))
+}
+
+//XReadGroup  see comment in redis.go,all the streams must be in the same slot
+func (r *RedisCluster) XReadGroup(group, consumer string, count int, block time.Duration, streams ...string) ([]StreamEntries, error) {
+	keys := streams[:len(streams)/2]
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XReadGroup(group, consumer, count, block, streams...)
+	}
+	return ToStreamEntriesArrReply(command.runBatch(len(keys), keys...))
+}
+
+//XPending  see comment in redis.go
+func (r *RedisCluster) XPending(key, group, start, end string, count int64, params ...*XPendingParams) ([]StreamPendingEntry, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XPending(key, group, start, end, count, params...)
+	}
+	return ToStreamPendingEntryArrReply(command.run(key))
+}
+
+//XAutoClaim  see comment in redis.go
+func (r *RedisCluster) XAutoClaim(key, group, consumer string, minIdle time.Duration, start string, count int) (*StreamClaimResult, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.XAutoClaim(key, group, consumer, minIdle, start, count)
+	}
+	return ToStreamClaimResultReply(command.run(key))
+}
+
 //</editor-fold>
 
 //<editor-fold desc="multikeycommands">
@@ -1526,6 +1919,42 @@ func (r *RedisCluster) BRPopTimeout(timeout int, keys ...string) ([]string, erro
 	return ToStrArrReply(command.runBatch(len(keys), keys...))
 }
 
+//BZPopMin  see comment in redis.go
+func (r *RedisCluster) BZPopMin(timeout int, keys ...string) (*KeyedTuple, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.BZPopMin(timeout, keys...)
+	}
+	return ToKeyedTupleReply(command.runBatch(len(keys), keys...))
+}
+
+//BLMPop  see comment in redis.go
+func (r *RedisCluster) BLMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedElements, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.BLMPop(timeout, direction, count, keys...)
+	}
+	return ToKeyedElementsReply(command.runBatch(len(keys), keys...))
+}
+
+//BZMPop  see comment in redis.go
+func (r *RedisCluster) BZMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedTuples, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.BZMPop(timeout, direction, count, keys...)
+	}
+	return ToKeyedTuplesReply(command.runBatch(len(keys), keys...))
+}
+
+//BZPopMax  see comment in redis.go
+func (r *RedisCluster) BZPopMax(timeout int, keys ...string) (*KeyedTuple, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.BZPopMax(timeout, keys...)
+	}
+	return ToKeyedTupleReply(command.runBatch(len(keys), keys...))
+}
+
 //BLPop  see comment in redis.go
 func (r *RedisCluster) BLPop(args ...string) ([]string, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -1644,6 +2073,21 @@ func (r *RedisCluster) SInterStore(destKey string, keys ...string) (int64, error
 	return ToInt64Reply(command.runBatch(len(arr), arr...))
 }
 
+//SInterCard  see comment in redis.go
+func (r *RedisCluster) SInterCard(limit int64, keys ...string) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.SInterCard(limit, keys...)
+	}
+	return ToInt64Reply(command.runBatch(len(keys), keys...))
+}
+
+//EstimateIntersection  see comment in redis.go,
+//the fallback temp key is put in the slot of the first key
+func (r *RedisCluster) EstimateIntersection(keys ...string) (int64, error) {
+	return estimateIntersection(r, keys...)
+}
+
 //SMove  see comment in redis.go
 func (r *RedisCluster) SMove(srcKey, destKey, member string) (int64, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -1721,6 +2165,79 @@ func (r *RedisCluster) ZUnionStoreWithParams(destKey string, params *ZParams, se
 	return ToInt64Reply(command.runBatch(len(arr), arr...))
 }
 
+//ZDiff see redis command
+func (r *RedisCluster) ZDiff(keys ...string) ([]string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZDiff(keys...)
+	}
+	return ToStrArrReply(command.runBatch(len(keys), keys...))
+}
+
+//ZDiffWithScores see redis command
+func (r *RedisCluster) ZDiffWithScores(keys ...string) ([]Tuple, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZDiffWithScores(keys...)
+	}
+	return ToTupleArrReply(command.runBatch(len(keys), keys...))
+}
+
+//ZDiffStore see redis command
+func (r *RedisCluster) ZDiffStore(destKey string, keys ...string) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZDiffStore(destKey, keys...)
+	}
+	arr := StrStrArrToStrArr(destKey, keys)
+	return ToInt64Reply(command.runBatch(len(arr), arr...))
+}
+
+//ZUnion see redis command
+func (r *RedisCluster) ZUnion(params *ZParams, keys ...string) ([]string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZUnion(params, keys...)
+	}
+	return ToStrArrReply(command.runBatch(len(keys), keys...))
+}
+
+//ZUnionWithScores see redis command
+func (r *RedisCluster) ZUnionWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZUnionWithScores(params, keys...)
+	}
+	return ToTupleArrReply(command.runBatch(len(keys), keys...))
+}
+
+//ZInter see redis command
+func (r *RedisCluster) ZInter(params *ZParams, keys ...string) ([]string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZInter(params, keys...)
+	}
+	return ToStrArrReply(command.runBatch(len(keys), keys...))
+}
+
+//ZInterWithScores see redis command
+func (r *RedisCluster) ZInterWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZInterWithScores(params, keys...)
+	}
+	return ToTupleArrReply(command.runBatch(len(keys), keys...))
+}
+
+//ZInterCard see redis command
+func (r *RedisCluster) ZInterCard(limit int64, keys ...string) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.ZInterCard(limit, keys...)
+	}
+	return ToInt64Reply(command.runBatch(len(keys), keys...))
+}
+
 //BRPopLPush see redis command
 func (r *RedisCluster) BRPopLPush(source, destination string, timeout int) (string, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -1773,6 +2290,81 @@ func (r *RedisCluster) PSubscribe(redisPubSub *RedisPubSub, patterns ...string)
 	return nil
 }
 
+//SPublish  see comment in redis.go,the message is sent to the node owning the slot of channel
+func (r *RedisCluster) SPublish(channel, message string) (int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.SPublish(channel, message)
+	}
+	return ToInt64Reply(command.run(channel))
+}
+
+//SSubscribe  see comment in redis.go,subscribe on the node owning the slot of the channels
+func (r *RedisCluster) SSubscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		err := redis.SSubscribe(redisPubSub, channels...)
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	_, err := command.runBatch(len(channels), channels...)
+	return err
+}
+
+//PubSubShardChannels  see comment in redis.go,the channels of all the nodes are merged
+func (r *RedisCluster) PubSubShardChannels(pattern string) ([]string, error) {
+	set := make(map[string]struct{})
+	err := r.forEachNode(func(redis *Redis) error {
+		channels, err := redis.PubSubShardChannels(pattern)
+		for _, c := range channels {
+			set[c] = struct{}{}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	channels := make([]string, 0, len(set))
+	for c := range set {
+		channels = append(channels, c)
+	}
+	return channels, nil
+}
+
+//PubSubShardNumSub  see comment in redis.go,the subscribers of all the nodes are summed
+func (r *RedisCluster) PubSubShardNumSub(channels ...string) (map[string]int64, error) {
+	sum := make(map[string]int64, len(channels))
+	err := r.forEachNode(func(redis *Redis) error {
+		m, err := redis.PubSubShardNumSub(channels...)
+		for c, n := range m {
+			sum[c] += n
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sum, nil
+}
+
+//forEachNode run fn on every known node of the cluster,masters and replicas
+func (r *RedisCluster) forEachNode(fn func(redis *Redis) error) error {
+	for _, pool := range r.connectionHandler.getNodes() {
+		redis, err := pool.GetResource()
+		if err != nil {
+			return err
+		}
+		err = fn(redis)
+		redis.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 //BitOp see redis command
 func (r *RedisCluster) BitOp(op BitOP, destKey string, srcKeys ...string) (int64, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
@@ -1823,6 +2415,24 @@ func (r *RedisCluster) PfCount(keys ...string) (int64, error) {
 	return ToInt64Reply(command.runBatch(len(keys), keys...))
 }
 
+//PfDebugGetReg see redis command
+func (r *RedisCluster) PfDebugGetReg(key string) ([]int64, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.PfDebugGetReg(key)
+	}
+	return ToInt64ArrReply(command.run(key))
+}
+
+//PfDebugEncoding see redis command
+func (r *RedisCluster) PfDebugEncoding(key string) (string, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.PfDebugEncoding(key)
+	}
+	return ToStrReply(command.run(key))
+}
+
 //</editor-fold>
 
 //<editor-fold desc="scriptcommands">
@@ -1845,6 +2455,42 @@ func (r *RedisCluster) EvalSha(sha1 string, keyCount int, params ...string) (int
 	return command.runBatch(keyCount, params...)
 }
 
+//EvalTimeout see redis command
+func (r *RedisCluster) EvalTimeout(timeout time.Duration, script string, keyCount int, params ...string) (interface{}, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.EvalTimeout(timeout, script, keyCount, params...)
+	}
+	return command.runBatch(keyCount, params...)
+}
+
+//EvalShaTimeout see redis command
+func (r *RedisCluster) EvalShaTimeout(timeout time.Duration, sha1 string, keyCount int, params ...string) (interface{}, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.EvalShaTimeout(timeout, sha1, keyCount, params...)
+	}
+	return command.runBatch(keyCount, params...)
+}
+
+//EvalRo see redis command
+func (r *RedisCluster) EvalRo(script string, keyCount int, params ...string) (interface{}, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.EvalRo(script, keyCount, params...)
+	}
+	return command.runBatch(keyCount, params...)
+}
+
+//EvalShaRo see redis command
+func (r *RedisCluster) EvalShaRo(sha1 string, keyCount int, params ...string) (interface{}, error) {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return redis.EvalShaRo(sha1, keyCount, params...)
+	}
+	return command.runBatch(keyCount, params...)
+}
+
 //ScriptExists see redis command
 func (r *RedisCluster) ScriptExists(key string, sha1 ...string) ([]bool, error) {
 	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
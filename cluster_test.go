@@ -101,7 +101,7 @@ func TestRedisCluster_Bitpos(t *testing.T) {
 	clearKeys(redis)
 
 	redis.Set("godis", "\x00\xff\xf0")
-	s, err := redis.BitPos("godis", true, &BitPosParams{params: [][]byte{IntToByteArr(0)}})
+	s, err := redis.BitPos("godis", true, NewBitPosParams(0))
 	assert.Nil(t, err)
 	assert.Equal(t, int64(8), s)
 }
@@ -1426,3 +1426,46 @@ func TestRedisCluster_Basic(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, resp)
 }
+
+func TestRedisCluster_TopologyRefresh(t *testing.T) {
+	handler := newRedisClusterConnectionHandler(nil, time.Second, time.Second, "", nil, nil)
+	defer handler.close()
+	node := func(host string) []interface{} {
+		return []interface{}{[]byte(host), int64(6379)}
+	}
+	handler.cache.applyClusterSlots([]interface{}{
+		[]interface{}{int64(0), int64(99), node("localhost"), node("127.0.0.1")},
+		[]interface{}{int64(100), int64(199)},
+	})
+	nodes := handler.getNodes()
+	assert.Len(t, nodes, 2)
+	localhost := nodes["localhost:6379"]
+	assert.Equal(t, localhost, handler.cache.getSlotPool(50))
+	assert.Nil(t, handler.cache.getSlotPool(150))
+
+	//localhost departs,slot 0-99 migrates to 127.0.0.1
+	handler.cache.applyClusterSlots([]interface{}{
+		[]interface{}{int64(0), int64(99), node("127.0.0.1")},
+	})
+	nodes = handler.getNodes()
+	assert.Len(t, nodes, 1)
+	assert.Equal(t, nodes["127.0.0.1:6379"], handler.cache.getSlotPool(50))
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, localhost.internalPool.IsClosed())
+
+	//MOVED within the refresh interval only reassigns the moved slot
+	handler.movedRefreshInterval = time.Hour
+	handler.lastRefresh = time.Now().UnixNano()
+	handler.renewSlotCacheOnMoved(nil, newMovedDataError("MOVED 150 localhost:6379", "localhost", 6379, 150))
+	assert.Equal(t, handler.getNodes()["localhost:6379"], handler.cache.getSlotPool(150))
+	handler.movedRefreshInterval = 0
+	handler.disableMovedRefresh = true
+	handler.renewSlotCacheOnMoved(nil, newMovedDataError("MOVED 151 localhost:6379", "localhost", 6379, 151))
+	assert.Equal(t, handler.getNodes()["localhost:6379"], handler.cache.getSlotPool(151))
+
+	handler.startTopologyRefresh(10 * time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	handler.close()
+	handler.close()
+	assert.Empty(t, handler.getNodes())
+}
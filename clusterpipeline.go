@@ -0,0 +1,417 @@
+package godis
+
+import (
+	"fmt"
+	"sync"
+)
+
+//ClusterPipeline cluster pipeline, get it by RedisCluster.Pipelined.
+//commands are buffered until Sync, then grouped by the node serving the slot of their key,
+//every group is sent down the connection of its node in parallel,
+//and the replies are filled into the responses in submission order.
+//commands redirected by MOVED or ASK, or failed by a connection error, are retried one by one,
+//unless they aren't idempotent and the connection failed after they were sent,see ClusterOption.RetryNonIdempotent
+type ClusterPipeline struct {
+	cluster  *RedisCluster
+	commands []*clusterPipelinedCommand
+	mu       sync.Mutex
+}
+
+type clusterPipelinedCommand struct {
+	key      string
+	command  protocolCommand
+	args     [][]byte
+	response *Response
+	reply    interface{}
+	sent     bool //written to the connection,a connection error doesn't tell whether redis executed it
+}
+
+//Pipelined create a cluster pipeline
+func (r *RedisCluster) Pipelined() *ClusterPipeline {
+	return &ClusterPipeline{cluster: r}
+}
+
+func (p *ClusterPipeline) queue(key string, builder Builder, command protocolCommand, args ...[]byte) (*Response, error) {
+	if key == "" {
+		return nil, newClusterOperationError("no way to dispatch this command to Redis cluster")
+	}
+	response := newResponse()
+	response.builder = builder
+	p.mu.Lock()
+	p.commands = append(p.commands, &clusterPipelinedCommand{
+		key:      key,
+		command:  command,
+		args:     args,
+		response: response,
+	})
+	p.mu.Unlock()
+	return response, nil
+}
+
+//Send queue any command routed by key,the reply is converted by builder
+func (p *ClusterPipeline) Send(key string, builder Builder, command string, args ...string) (*Response, error) {
+	return p.queue(key, builder, newProtocolCommand(command), StrArrToByteArrArr(args)...)
+}
+
+//Sync send the buffered commands to their nodes and fill the replies into the responses,
+//return ClusterPipelineError if some commands failed, the other responses are still filled
+func (p *ClusterPipeline) Sync() error {
+	p.mu.Lock()
+	commands := p.commands
+	p.commands = nil
+	p.mu.Unlock()
+	if len(commands) == 0 {
+		return nil
+	}
+	handler := p.cluster.connectionHandler
+	crc16 := newCRC16()
+	groups := make(map[*Pool][]*clusterPipelinedCommand)
+	for _, c := range commands {
+		slot := int(crc16.getStringSlot(c.key))
+		pool := handler.cache.getSlotPool(slot)
+		if pool == nil {
+			handler.renewSlotCache()
+			pool = handler.cache.getSlotPool(slot)
+		}
+		//commands of unknown slots are sent to any node,the redirection fixes them
+		groups[pool] = append(groups[pool], c)
+	}
+	var wg sync.WaitGroup
+	for pool, group := range groups {
+		wg.Add(1)
+		go func(pool *Pool, group []*clusterPipelinedCommand) {
+			defer wg.Done()
+			p.syncGroup(pool, group)
+		}(pool, group)
+	}
+	wg.Wait()
+
+	movedRenewed := false
+	errs := make(map[int]error)
+	for i, c := range commands {
+		switch c.reply.(type) {
+		case *MovedDataError:
+			if !movedRenewed {
+				handler.renewSlotCacheOnMoved(nil, c.reply.(*MovedDataError))
+				movedRenewed = true
+			}
+			p.retry(c, c.reply.(error))
+		case *AskDataError:
+			p.retry(c, c.reply.(error))
+		case *ConnectError:
+			if c.sent && !c.command.idempotent() && !handler.retryNonIdempotent {
+				break
+			}
+			p.retry(c, nil)
+		}
+		if err, ok := c.reply.(error); ok {
+			errs[i] = err
+		}
+		c.response.set(c.reply)
+	}
+	if len(errs) > 0 {
+		return newClusterPipelineError(errs)
+	}
+	return nil
+}
+
+//syncGroup pipeline the commands down one connection of pool,or any node if pool is nil
+func (p *ClusterPipeline) syncGroup(pool *Pool, group []*clusterPipelinedCommand) {
+	var redis *Redis
+	var err error
+	if pool != nil {
+		redis, err = pool.GetResource()
+	} else {
+		redis, err = p.cluster.connectionHandler.getConnection()
+	}
+	if err != nil {
+		for _, c := range group {
+			c.reply = asConnectError(err)
+		}
+		return
+	}
+	defer redis.Close()
+	for i, c := range group {
+		if err := redis.client.sendCommand(c.command, c.args...); err != nil {
+			for _, failed := range group[i:] {
+				failed.reply = err
+			}
+			group = group[:i]
+			break
+		}
+		c.sent = true
+	}
+	all, err := redis.client.connection.getAll()
+	if err != nil {
+		for _, c := range group {
+			c.reply = err
+		}
+		return
+	}
+	for i, reply := range all.([]interface{}) {
+		if i < len(group) {
+			group[i].reply = reply
+		}
+	}
+}
+
+//retry run the command alone,following redirect if it is not nil
+func (p *ClusterPipeline) retry(c *clusterPipelinedCommand, redirect error) {
+	command := newRedisClusterCommand(p.cluster.MaxAttempts, p.cluster.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		if err := redis.client.sendCommand(c.command, c.args...); err != nil {
+			return nil, err
+		}
+		return redis.client.getOne()
+	}
+	reply, err := command.runWithRetries([]byte(c.key), p.cluster.MaxAttempts, false, redirect)
+	if err != nil {
+		c.reply = err
+		return
+	}
+	c.reply = reply
+}
+
+//<editor-fold desc="clusterpipeline">
+
+//Set see redis command
+func (p *ClusterPipeline) Set(key, value string) (*Response, error) {
+	return p.queue(key, StrBuilder, cmdSet, []byte(key), []byte(value))
+}
+
+//Get see redis command
+func (p *ClusterPipeline) Get(key string) (*Response, error) {
+	return p.queue(key, StrBuilder, cmdGet, []byte(key))
+}
+
+//Del see redis command
+func (p *ClusterPipeline) Del(key string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdDel, []byte(key))
+}
+
+//Exists see redis command
+func (p *ClusterPipeline) Exists(key string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdExists, []byte(key))
+}
+
+//Expire see redis command
+func (p *ClusterPipeline) Expire(key string, seconds int) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdExpire, []byte(key), IntToByteArr(seconds))
+}
+
+//Incr see redis command
+func (p *ClusterPipeline) Incr(key string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdIncr, []byte(key))
+}
+
+//IncrBy see redis command
+func (p *ClusterPipeline) IncrBy(key string, increment int64) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdIncrBy, []byte(key), Int64ToByteArr(increment))
+}
+
+//HSet see redis command
+func (p *ClusterPipeline) HSet(key, field, value string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdHSet, []byte(key), []byte(field), []byte(value))
+}
+
+//HGet see redis command
+func (p *ClusterPipeline) HGet(key, field string) (*Response, error) {
+	return p.queue(key, StrBuilder, cmdHGet, []byte(key), []byte(field))
+}
+
+//LPush see redis command
+func (p *ClusterPipeline) LPush(key string, members ...string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdLPush, StrStrArrToByteArrArr(key, members)...)
+}
+
+//RPush see redis command
+func (p *ClusterPipeline) RPush(key string, members ...string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdRPush, StrStrArrToByteArrArr(key, members)...)
+}
+
+//LRange see redis command
+func (p *ClusterPipeline) LRange(key string, start, stop int64) (*Response, error) {
+	return p.queue(key, StrArrBuilder, cmdLRange, []byte(key), Int64ToByteArr(start), Int64ToByteArr(stop))
+}
+
+//LPopCount see redis command
+func (p *ClusterPipeline) LPopCount(key string, count int64) (*Response, error) {
+	return p.queue(key, StrArrBuilder, cmdLPop, []byte(key), Int64ToByteArr(count))
+}
+
+//RPopCount see redis command
+func (p *ClusterPipeline) RPopCount(key string, count int64) (*Response, error) {
+	return p.queue(key, StrArrBuilder, cmdRPop, []byte(key), Int64ToByteArr(count))
+}
+
+//SAdd see redis command
+func (p *ClusterPipeline) SAdd(key string, members ...string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdSAdd, StrStrArrToByteArrArr(key, members)...)
+}
+
+//SMembers see redis command
+func (p *ClusterPipeline) SMembers(key string) (*Response, error) {
+	return p.queue(key, StrArrBuilder, cmdSMembers, []byte(key))
+}
+
+//ZAdd see redis command
+func (p *ClusterPipeline) ZAdd(key string, score float64, member string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdZAdd, []byte(key), Float64ToByteArr(score), []byte(member))
+}
+
+//ZRange see redis command
+func (p *ClusterPipeline) ZRange(key string, start, stop int64) (*Response, error) {
+	return p.queue(key, StrArrBuilder, cmdZRange, []byte(key), Int64ToByteArr(start), Int64ToByteArr(stop))
+}
+
+//PfAdd see redis command
+func (p *ClusterPipeline) PfAdd(key string, elements ...string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdPfAdd, StrStrArrToByteArrArr(key, elements)...)
+}
+
+//PfCount see redis command
+func (p *ClusterPipeline) PfCount(key string) (*Response, error) {
+	return p.queue(key, Int64Builder, cmdPfCount, []byte(key))
+}
+
+//</editor-fold>
+
+//<editor-fold desc="clustermultikey">
+
+//HashTag return the part of key used to compute its slot,
+//keys with the same hash tag, such as {user1000}.following and {user1000}.followers, are in the same slot
+func HashTag(key string) string {
+	return newRedisClusterHashTagUtil().getHashTag(key)
+}
+
+//slotGroup keys of one slot and their positions in the arguments
+type slotGroup struct {
+	keys    []string
+	indexes []int
+}
+
+func groupBySlot(keys []string) []*slotGroup {
+	crc16 := newCRC16()
+	groups := make([]*slotGroup, 0)
+	slots := make(map[uint16]*slotGroup)
+	for i, key := range keys {
+		slot := crc16.getStringSlot(key)
+		group, ok := slots[slot]
+		if !ok {
+			group = &slotGroup{}
+			slots[slot] = group
+			groups = append(groups, group)
+		}
+		group.keys = append(group.keys, key)
+		group.indexes = append(group.indexes, i)
+	}
+	return groups
+}
+
+//MGetCluster get the values of keys in any slots,
+//the keys are split by slot and every node gets the MGET of its slots in parallel,
+//the values are returned in the order of keys
+func (r *RedisCluster) MGetCluster(keys ...string) ([]string, error) {
+	p := r.Pipelined()
+	groups := groupBySlot(keys)
+	responses := make([]*Response, 0, len(groups))
+	for _, group := range groups {
+		resp, err := p.queue(group.keys[0], StrArrBuilder, cmdMGet, StrArrToByteArrArr(group.keys)...)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+	}
+	if err := p.Sync(); err != nil {
+		return nil, err
+	}
+	values := make([]string, len(keys))
+	for i, group := range groups {
+		obj, err := responses[i].Get()
+		if err != nil {
+			return nil, err
+		}
+		arr, ok := obj.([]string)
+		if !ok || len(arr) != len(group.keys) {
+			return nil, newProtocolError(fmt.Sprintf("unexpected MGET reply:%v", obj))
+		}
+		for j, value := range arr {
+			values[group.indexes[j]] = value
+		}
+	}
+	return values, nil
+}
+
+//MSetCluster set key/value pairs in any slots,
+//the pairs are split by slot and every node gets the MSET of its slots in parallel,
+//it is not atomic across slots,some slots may be set when an error is returned
+func (r *RedisCluster) MSetCluster(kvs ...string) (string, error) {
+	if len(kvs)%2 != 0 {
+		return "", newDataError("wrong number of arguments for MSetCluster")
+	}
+	keys := make([]string, 0, len(kvs)/2)
+	for i := 0; i < len(kvs); i += 2 {
+		keys = append(keys, kvs[i])
+	}
+	p := r.Pipelined()
+	groups := groupBySlot(keys)
+	responses := make([]*Response, 0, len(groups))
+	for _, group := range groups {
+		args := make([][]byte, 0, len(group.keys)*2)
+		for _, i := range group.indexes {
+			args = append(args, []byte(kvs[i*2]), []byte(kvs[i*2+1]))
+		}
+		resp, err := p.queue(group.keys[0], StrBuilder, cmdMSet, args...)
+		if err != nil {
+			return "", err
+		}
+		responses = append(responses, resp)
+	}
+	if err := p.Sync(); err != nil {
+		return "", err
+	}
+	for _, resp := range responses {
+		obj, err := resp.Get()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := obj.(string); !ok {
+			return "", newProtocolError(fmt.Sprintf("unexpected MSET reply:%v", obj))
+		}
+	}
+	return keywordOk.name, nil
+}
+
+//DelCluster delete keys in any slots,
+//the keys are split by slot and every node gets the DEL of its slots in parallel,
+//return the number of deleted keys
+func (r *RedisCluster) DelCluster(keys ...string) (int64, error) {
+	p := r.Pipelined()
+	groups := groupBySlot(keys)
+	responses := make([]*Response, 0, len(groups))
+	for _, group := range groups {
+		resp, err := p.queue(group.keys[0], Int64Builder, cmdDel, StrArrToByteArrArr(group.keys)...)
+		if err != nil {
+			return 0, err
+		}
+		responses = append(responses, resp)
+	}
+	if err := p.Sync(); err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, resp := range responses {
+		obj, err := resp.Get()
+		if err != nil {
+			return 0, err
+		}
+		n, ok := obj.(int64)
+		if !ok {
+			return 0, newProtocolError(fmt.Sprintf("unexpected DEL reply:%v", obj))
+		}
+		count += n
+	}
+	return count, nil
+}
+
+//</editor-fold>
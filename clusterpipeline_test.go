@@ -0,0 +1,156 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+//newFakeRedisCluster a cluster whose slots are served by the standalone redis under different host names
+func newFakeRedisCluster(slots []interface{}) *RedisCluster {
+	handler := newRedisClusterConnectionHandler(nil, time.Second, time.Second, "", nil, nil)
+	handler.cache.applyClusterSlots(slots)
+	return &RedisCluster{MaxAttempts: 2, connectionHandler: handler}
+}
+
+func TestClusterPipeline_Sync(t *testing.T) {
+	flushAll()
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("localhost"), int64(6379)}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("127.0.0.1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	p := cluster.Pipelined()
+	assert.Nil(t, p.Sync())
+
+	keys := []string{"godis", "godis1", "godis2", "godis3", "godis4", "godis5"}
+	sets := make([]*Response, 0)
+	for _, key := range keys {
+		resp, err := p.Set(key, key)
+		assert.Nil(t, err)
+		sets = append(sets, resp)
+	}
+	gets := make([]*Response, 0)
+	for _, key := range keys {
+		resp, err := p.Get(key)
+		assert.Nil(t, err)
+		gets = append(gets, resp)
+	}
+	_, err := sets[0].Get()
+	assert.NotNil(t, err)
+	assert.Nil(t, p.Sync())
+	for i, key := range keys {
+		obj, err := sets[i].Get()
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", obj)
+		obj, err = gets[i].Get()
+		assert.Nil(t, err)
+		assert.Equal(t, key, obj)
+	}
+
+	incr, _ := p.Incr("godis")
+	rpush, _ := p.RPush("godis6", "a", "b")
+	lrange, _ := p.LRange("godis6", 0, -1)
+	send, _ := p.Send("godis6", Int64Builder, "llen", "godis6")
+	_, err = p.Set("", "good")
+	assert.NotNil(t, err)
+	err = p.Sync()
+	assert.NotNil(t, err)
+	assert.Len(t, err.(*ClusterPipelineError).Errors, 1)
+	assert.NotNil(t, err.(*ClusterPipelineError).Errors[0])
+	_, err = incr.Get()
+	assert.NotNil(t, err)
+	obj, err := rpush.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), obj)
+	obj, err = lrange.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, obj)
+	obj, err = send.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), obj)
+}
+
+func TestClusterPipeline_PartialFailure(t *testing.T) {
+	flushAll()
+	crc16 := newCRC16()
+	slot := int64(crc16.getStringSlot("godis"))
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{slot, slot, []interface{}{[]byte("localhost1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	for s := 0; s < 16384; s++ {
+		if int64(s) != slot {
+			cluster.connectionHandler.cache.assignSlotToNode(s, "localhost", 6379)
+		}
+	}
+	p := cluster.Pipelined()
+	broken, _ := p.Set("godis", "good")
+	ok, _ := p.Set("godis1", "good")
+	err := p.Sync()
+	assert.NotNil(t, err)
+	assert.Len(t, err.(*ClusterPipelineError).Errors, 1)
+	assert.NotNil(t, err.(*ClusterPipelineError).Errors[0])
+	_, err = broken.Get()
+	assert.NotNil(t, err)
+	obj, err := ok.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", obj)
+}
+
+func TestRedisCluster_MGetCluster(t *testing.T) {
+	flushAll()
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("localhost"), int64(6379)}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("127.0.0.1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	s, err := cluster.MSetCluster("godis", "good", "godis1", "good1", "{godis}2", "good2")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	_, err = cluster.MSetCluster("godis", "good", "godis1")
+	assert.NotNil(t, err)
+
+	arr, err := cluster.MGetCluster("godis1", "godis", "godis3", "{godis}2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"good1", "good", "", "good2"}, arr)
+
+	c, err := cluster.DelCluster("godis", "godis1", "godis3", "{godis}2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+	arr, err = cluster.MGetCluster("godis", "godis1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", ""}, arr)
+
+	assert.Equal(t, "godis", HashTag("{godis}2"))
+	assert.Equal(t, "godis2", HashTag("godis2"))
+	assert.Equal(t, newCRC16().getStringSlot(HashTag("{godis}2")), newCRC16().getStringSlot("godis"))
+
+	brokenCluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(16383), []interface{}{[]byte("localhost1"), int64(6379)}},
+	})
+	defer brokenCluster.Close()
+	_, err = brokenCluster.MGetCluster("godis", "godis1")
+	assert.NotNil(t, err)
+	_, err = brokenCluster.MSetCluster("godis", "good")
+	assert.NotNil(t, err)
+	_, err = brokenCluster.DelCluster("godis")
+	assert.NotNil(t, err)
+}
+
+func TestRedisCluster_MGetCluster_UnexpectedReply(t *testing.T) {
+	node, closeNode := newFakeServer(t, map[string][]string{
+		"MGET {godis}1 {godis}2": {"*-1\r\n"},
+		"PING":                   {"+PONG\r\n"},
+		"QUIT":                   {"+OK\r\n"},
+	})
+	defer closeNode()
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(16383), []interface{}{[]byte("localhost"), int64(node.Port)}},
+	})
+	defer cluster.Close()
+	//a reply not matching the keys fails instead of dropping the values
+	_, err := cluster.MGetCluster("{godis}1", "{godis}2")
+	assert.True(t, errors.Is(err, ErrProtocol))
+}
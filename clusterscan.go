@@ -0,0 +1,134 @@
+package godis
+
+import (
+	"sort"
+	"sync"
+)
+
+//ClusterScanIterator iterate the keys of every master of the cluster,see RedisCluster.ClusterScan
+//
+//ClusterScanIterator is not safe for concurrent use
+type ClusterScanIterator struct {
+	params *ScanParams
+	nodes  []*clusterScanNode //sorted by host:port
+	keys   []string
+	err    error
+}
+
+//clusterScanNode the cursor of a master
+type clusterScanNode struct {
+	node   string
+	pool   *Pool
+	cursor string
+	done   bool
+}
+
+//ClusterScan iterate the keys of the whole cluster matching match,empty match means all keys.
+//unlike Scan,the pattern doesn't need a hash tag,every master is scanned with its own cursor,
+//a page of every unfinished master is scanned concurrently by Next and their keys are merged.
+//count is the COUNT hint of every SCAN,0 means the default of redis.
+//
+//the masters are the ones known when ClusterScan is called,
+//keys of slots migrated during the iteration may be missed or returned twice,like Scan of redis.
+//
+//	it := cluster.ClusterScan("user:*", 100)
+//	for it.Next() {
+//		for _, key := range it.Keys() {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+func (r *RedisCluster) ClusterScan(match string, count int) *ClusterScanIterator {
+	params := NewScanParams()
+	if match != "" {
+		params.Match(match)
+	}
+	if count > 0 {
+		params.Count(count)
+	}
+	it := &ClusterScanIterator{params: params}
+	for node, pool := range r.connectionHandler.getMasterNodes() {
+		it.nodes = append(it.nodes, &clusterScanNode{node: node, pool: pool, cursor: "0"})
+	}
+	sort.Slice(it.nodes, func(i, j int) bool {
+		return it.nodes[i].node < it.nodes[j].node
+	})
+	return it
+}
+
+//Next scan the next page of every unfinished master,
+//return false when all the masters are finished or a scan failed,see Err.
+//a page may be empty while the iteration isn't finished
+func (it *ClusterScanIterator) Next() bool {
+	it.keys = nil
+	if it.err != nil {
+		return false
+	}
+	nodes := make([]*clusterScanNode, 0, len(it.nodes))
+	for _, node := range it.nodes {
+		if !node.done {
+			nodes = append(nodes, node)
+		}
+	}
+	if len(nodes) == 0 {
+		return false
+	}
+	results := make([]*ScanResult, len(nodes))
+	errs := make([]error, len(nodes))
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node *clusterScanNode) {
+			defer wg.Done()
+			redis, err := node.pool.GetResource()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			defer redis.Close()
+			results[i], errs[i] = redis.Scan(node.cursor, it.params)
+		}(i, node)
+	}
+	wg.Wait()
+	keys := make([]string, 0)
+	for i, node := range nodes {
+		if errs[i] != nil {
+			//the cursors of the failed masters are kept,the others have moved on
+			if it.err == nil {
+				it.err = errs[i]
+			}
+			continue
+		}
+		node.cursor = results[i].Cursor
+		node.done = node.cursor == "0"
+		keys = append(keys, results[i].Results...)
+	}
+	if it.err != nil {
+		return false
+	}
+	it.keys = keys
+	return true
+}
+
+//Keys the keys of the page scanned by the last Next
+func (it *ClusterScanIterator) Keys() []string {
+	return it.keys
+}
+
+//Err the error of the failed scan,nil if the iteration finished normally
+func (it *ClusterScanIterator) Err() error {
+	return it.err
+}
+
+//Cursors the cursors of the unfinished masters,keyed by host:port,empty when the iteration is finished
+func (it *ClusterScanIterator) Cursors() map[string]string {
+	cursors := make(map[string]string, len(it.nodes))
+	for _, node := range it.nodes {
+		if !node.done {
+			cursors[node.node] = node.cursor
+		}
+	}
+	return cursors
+}
@@ -0,0 +1,61 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestRedisCluster_ClusterScan(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	for i := 0; i < 20; i++ {
+		redis.Set("user:"+strconv.Itoa(i), "godis")
+	}
+	redis.Set("other", "godis")
+	//both masters are the same server,every key is returned by both
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("localhost"), int64(6379)}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("127.0.0.1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	it := cluster.ClusterScan("user:*", 5)
+	assert.Equal(t, map[string]string{"127.0.0.1:6379": "0", "localhost:6379": "0"}, it.Cursors())
+	keys := make([]string, 0)
+	for it.Next() {
+		keys = append(keys, it.Keys()...)
+	}
+	assert.Nil(t, it.Err())
+	assert.Len(t, keys, 40)
+	sort.Strings(keys)
+	assert.Equal(t, "user:0", keys[0])
+	assert.Equal(t, "user:9", keys[39])
+	assert.Empty(t, it.Cursors())
+	assert.False(t, it.Next())
+
+	//replicas aren't scanned
+	cluster = newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(16383), []interface{}{[]byte("localhost"), int64(6379)}, []interface{}{[]byte("127.0.0.1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	assert.Len(t, cluster.connectionHandler.getNodes(), 2)
+	it = cluster.ClusterScan("", 0)
+	keys = make([]string, 0)
+	for it.Next() {
+		keys = append(keys, it.Keys()...)
+	}
+	assert.Nil(t, it.Err())
+	assert.Len(t, keys, 21)
+
+	//the cursor of the failed master is kept
+	cluster = newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(16383), []interface{}{[]byte("localhost"), int64(1)}},
+	})
+	defer cluster.Close()
+	it = cluster.ClusterScan("", 0)
+	assert.False(t, it.Next())
+	assert.NotNil(t, it.Err())
+	assert.Equal(t, map[string]string{"localhost:1": "0"}, it.Cursors())
+}
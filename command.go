@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 //ZAddParams ...
@@ -28,6 +30,18 @@ func (p *ZAddParams) NX() *ZAddParams {
 	return p
 }
 
+//GT set GT parameter, Only update existing elements if the new score is greater than the current score,available since redis 6.2
+func (p *ZAddParams) GT() *ZAddParams {
+	p.params["GT"] = "GT"
+	return p
+}
+
+//LT set LT parameter, Only update existing elements if the new score is less than the current score,available since redis 6.2
+func (p *ZAddParams) LT() *ZAddParams {
+	p.params["LT"] = "LT"
+	return p
+}
+
 //CH set CH parameter, Modify the return value from the number of new elements added, to the total number of elements changed
 func (p *ZAddParams) CH() *ZAddParams {
 	p.params["CH"] = "CH"
@@ -44,6 +58,12 @@ func (p *ZAddParams) getByteParams(key []byte, args ...[]byte) [][]byte {
 	if p.Contains("NX") {
 		arr = append(arr, []byte("NX"))
 	}
+	if p.Contains("GT") {
+		arr = append(arr, []byte("GT"))
+	}
+	if p.Contains("LT") {
+		arr = append(arr, []byte("LT"))
+	}
 	if p.Contains("CH") {
 		arr = append(arr, []byte("CH"))
 	}
@@ -59,11 +79,188 @@ func (p *ZAddParams) Contains(key string) bool {
 	return ok
 }
 
-//BitPosParams bitpos params
+//BitUnit the unit of the range of BITCOUNT and BITPOS,available since redis 7.0
+type BitUnit struct {
+	name string
+}
+
+var (
+	//BitUnitByte the range is indexed by byte,the default unit
+	BitUnitByte = &BitUnit{"BYTE"}
+	//BitUnitBit the range is indexed by bit
+	BitUnitBit = &BitUnit{"BIT"}
+)
+
+//BitPosParams bitpos params,the range to search from start to end,
+//the end of the string if end isn't set
 type BitPosParams struct {
+	start  int64
+	end    int64
+	hasEnd bool
+	unit   *BitUnit
+}
+
+//NewBitPosParams create new bitpos params searching from start
+func NewBitPosParams(start int64) *BitPosParams {
+	return &BitPosParams{start: start}
+}
+
+//End set the end of the range
+func (p *BitPosParams) End(end int64) *BitPosParams {
+	p.end = end
+	p.hasEnd = true
+	return p
+}
+
+//Unit set the unit of the range,it's sent only if the end is set
+func (p *BitPosParams) Unit(unit *BitUnit) *BitPosParams {
+	p.unit = unit
+	return p
+}
+
+func (p *BitPosParams) getParams() [][]byte {
+	arr := [][]byte{Int64ToByteArr(p.start)}
+	if !p.hasEnd {
+		return arr
+	}
+	arr = append(arr, Int64ToByteArr(p.end))
+	if p.unit != nil {
+		arr = append(arr, []byte(p.unit.name))
+	}
+	return arr
+}
+
+//BitFieldArgs the operations of BITFIELD,they are executed in order.
+//
+//encoding is i or u followed by the bit width,such as i8 or u16,up to i64 and u63,
+//offset is the bit offset of the field,a malformed encoding or a negative offset fails the command before it is sent
+type BitFieldArgs struct {
+	args     []string
+	readOnly bool
+	err      error
+}
+
+//BitFieldOverflow overflow behavior of the following INCRBY and SET operations of BITFIELD
+type BitFieldOverflow struct {
+	name string
+}
+
+var (
+	//OverflowWrap wrap around,the default behavior
+	OverflowWrap = &BitFieldOverflow{"WRAP"}
+	//OverflowSat saturate to the minimum or maximum value
+	OverflowSat = &BitFieldOverflow{"SAT"}
+	//OverflowFail don't execute the operation,its result is nil
+	OverflowFail = &BitFieldOverflow{"FAIL"}
+)
+
+//NewBitFieldArgs create new bitfield args instance
+func NewBitFieldArgs() *BitFieldArgs {
+	return &BitFieldArgs{args: make([]string, 0), readOnly: true}
+}
+
+//Get get the value of the field
+func (a *BitFieldArgs) Get(encoding string, offset int64) *BitFieldArgs {
+	return a.add(keywordGet.name, encoding, offset)
+}
+
+//Set set the value of the field,its result is the old value
+func (a *BitFieldArgs) Set(encoding string, offset int64, value int64) *BitFieldArgs {
+	a.readOnly = false
+	return a.add(keywordSet.name, encoding, offset, strconv.FormatInt(value, 10))
+}
+
+//IncrBy increment the value of the field,its result is the new value
+func (a *BitFieldArgs) IncrBy(encoding string, offset int64, increment int64) *BitFieldArgs {
+	a.readOnly = false
+	return a.add(keywordIncrBy.name, encoding, offset, strconv.FormatInt(increment, 10))
+}
+
+//Overflow set the overflow behavior of the following SET and INCRBY operations
+func (a *BitFieldArgs) Overflow(overflow *BitFieldOverflow) *BitFieldArgs {
+	a.args = append(a.args, keywordOverflow.name, overflow.name)
+	return a
+}
+
+func (a *BitFieldArgs) add(operation, encoding string, offset int64, value ...string) *BitFieldArgs {
+	if a.err == nil {
+		a.err = checkBitFieldEncoding(encoding)
+	}
+	if a.err == nil && offset < 0 {
+		a.err = newDataError("ERR bit offset is not an integer or out of range")
+	}
+	a.args = append(a.args, operation, encoding, strconv.FormatInt(offset, 10))
+	a.args = append(a.args, value...)
+	return a
+}
+
+//checkBitFieldEncoding check the encoding is i1 to i64 or u1 to u63
+func checkBitFieldEncoding(encoding string) error {
+	if len(encoding) >= 2 {
+		bits, err := strconv.Atoi(encoding[1:])
+		if err == nil && ((encoding[0] == 'i' && bits >= 1 && bits <= 64) || (encoding[0] == 'u' && bits >= 1 && bits <= 63)) {
+			return nil
+		}
+	}
+	return newDataError("ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+}
+
+//LPosParams lpos params
+type LPosParams struct {
+	params [][]byte
+}
+
+//NewLPosParams create new lpos params instance
+func NewLPosParams() *LPosParams {
+	return &LPosParams{params: make([][]byte, 0)}
+}
+
+//Rank return the rank-th match,a negative rank searches from the tail to the head
+func (p *LPosParams) Rank(rank int64) *LPosParams {
+	p.params = append(p.params, keywordRank.getRaw(), Int64ToByteArr(rank))
+	return p
+}
+
+//MaxLen compare at most maxLen elements,0 means all elements
+func (p *LPosParams) MaxLen(maxLen int64) *LPosParams {
+	p.params = append(p.params, keywordMaxLen.getRaw(), Int64ToByteArr(maxLen))
+	return p
+}
+
+//ShutdownParams shutdown params
+type ShutdownParams struct {
 	params [][]byte
 }
 
+//NewShutdownParams create new shutdown params instance
+func NewShutdownParams() *ShutdownParams {
+	return &ShutdownParams{params: make([][]byte, 0)}
+}
+
+//NoSave don't save the dataset even if save points are configured
+func (p *ShutdownParams) NoSave() *ShutdownParams {
+	p.params = append(p.params, keywordNoSave.getRaw())
+	return p
+}
+
+//Save save the dataset even if no save points are configured
+func (p *ShutdownParams) Save() *ShutdownParams {
+	p.params = append(p.params, keywordSave.getRaw())
+	return p
+}
+
+//Now don't wait for lagging replicas,since redis 7.0
+func (p *ShutdownParams) Now() *ShutdownParams {
+	p.params = append(p.params, keywordNow.getRaw())
+	return p
+}
+
+//Force ignore the errors of saving the dataset,since redis 7.0
+func (p *ShutdownParams) Force() *ShutdownParams {
+	p.params = append(p.params, keywordForce.getRaw())
+	return p
+}
+
 //SortParams sort params
 type SortParams struct {
 	params []string
@@ -127,10 +324,25 @@ func (p *SortParams) Get(patterns ...string) *SortParams {
 	return p
 }
 
+//withPrefix copy the params with prefix prepended to the patterns of BY and GET,they are key patterns
+func (p *SortParams) withPrefix(prefix string) *SortParams {
+	params := make([]string, len(p.params))
+	copy(params, p.params)
+	for i := 0; i+1 < len(params); i++ {
+		pattern := params[i+1]
+		if (params[i] == keywordBy.name && !strings.EqualFold(pattern, keywordNosort.name)) || (params[i] == keywordGet.name && pattern != "#") {
+			params[i+1] = prefix + pattern
+			i++
+		}
+	}
+	return &SortParams{params: params}
+}
+
 //ScanParams scan,hscan,sscan,zscan params
 type ScanParams struct {
 	//params map[*keyword][]byte
-	params map[string]string
+	params   map[string]string
+	noValues bool
 }
 
 //NewScanParams create scan params instance
@@ -150,6 +362,19 @@ func (s *ScanParams) Count(count int) *ScanParams {
 	return s
 }
 
+//Type scan only keys of the given type,such as string,list,set,zset,hash,stream
+func (s *ScanParams) Type(keyType string) *ScanParams {
+	s.params[keywordType.name] = keyType
+	return s
+}
+
+//NoValues hscan return fields only,without values,available since redis 7.4,
+//it is ignored by scan,sscan and zscan
+func (s *ScanParams) NoValues() *ScanParams {
+	s.noValues = true
+	return s
+}
+
 //getParams get all scan params
 func (s ScanParams) getParams() [][]byte {
 	arr := make([][]byte, 0)
@@ -160,6 +385,34 @@ func (s ScanParams) getParams() [][]byte {
 	return arr
 }
 
+//withPrefix copy the params matching only the keys starting with prefix,
+//the match pattern is matched against the rest of the keys
+func (s ScanParams) withPrefix(prefix string) *ScanParams {
+	params := make(map[string]string, len(s.params)+1)
+	for k, v := range s.params {
+		params[k] = v
+	}
+	match := s.GetMatch()
+	if match == "" {
+		match = "*"
+	}
+	params[keywordMatch.name] = escapeGlob(prefix) + match
+	return &ScanParams{params: params, noValues: s.noValues}
+}
+
+//escapeGlob escape the special characters of glob-style patterns,so s is matched literally
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
 //GetMatch get the match param value
 func (s ScanParams) GetMatch() string {
 	if v, ok := s.params[keywordMatch.name]; ok {
@@ -168,6 +421,14 @@ func (s ScanParams) GetMatch() string {
 	return ""
 }
 
+//GetType get the type param value
+func (s ScanParams) GetType() string {
+	if v, ok := s.params[keywordType.name]; ok {
+		return v
+	}
+	return ""
+}
+
 //ListOption  list option
 type ListOption struct {
 	name string // name  ...
@@ -190,6 +451,73 @@ var (
 	ListOptionAfter = newListOption("AFTER")
 )
 
+//PopDirection the end popped by BLMPOP and BZMPOP,PopLeft|PopRight for lists,PopMin|PopMax for sorted sets
+type PopDirection struct {
+	name string
+}
+
+//getRaw get the direction name byte array
+func (d *PopDirection) getRaw() []byte {
+	return []byte(d.name)
+}
+
+var (
+	//PopLeft pop the elements from the head of the list
+	PopLeft = &PopDirection{"LEFT"}
+	//PopRight pop the elements from the tail of the list
+	PopRight = &PopDirection{"RIGHT"}
+	//PopMin pop the members with the lowest scores of the sorted set
+	PopMin = &PopDirection{"MIN"}
+	//PopMax pop the members with the highest scores of the sorted set
+	PopMax = &PopDirection{"MAX"}
+)
+
+//FlushMode flush mode of FLUSHALL and FLUSHDB,ASYNC|SYNC
+type FlushMode struct {
+	name string // name of flush mode
+}
+
+//getRaw get the flush mode name byte array
+func (f *FlushMode) getRaw() []byte {
+	return []byte(f.name)
+}
+
+func newFlushMode(name string) *FlushMode {
+	return &FlushMode{name}
+}
+
+var (
+	//FlushAsync free the keys in a background thread,the command returns at once
+	FlushAsync = newFlushMode("ASYNC")
+	//FlushSync free the keys before the command returns
+	FlushSync = newFlushMode("SYNC")
+)
+
+//ExpireCondition condition of setting the expiration,NX|XX|GT|LT
+type ExpireCondition struct {
+	name string // name of condition
+}
+
+//getRaw get the condition name byte array
+func (e *ExpireCondition) getRaw() []byte {
+	return []byte(e.name)
+}
+
+func newExpireCondition(name string) *ExpireCondition {
+	return &ExpireCondition{name}
+}
+
+var (
+	//ExpireNX set the expiration only when there is no expiration
+	ExpireNX = newExpireCondition("NX")
+	//ExpireXX set the expiration only when there is an expiration
+	ExpireXX = newExpireCondition("XX")
+	//ExpireGT set the expiration only when the new expiration is greater than the current one
+	ExpireGT = newExpireCondition("GT")
+	//ExpireLT set the expiration only when the new expiration is less than the current one
+	ExpireLT = newExpireCondition("LT")
+)
+
 //GeoUnit geo unit,m|mi|km|ft
 type GeoUnit struct {
 	name string // name of geo unit
@@ -299,6 +627,34 @@ type Tuple struct {
 	score   float64
 }
 
+//GetElement get the member of the tuple
+func (t Tuple) GetElement() string {
+	return t.element
+}
+
+//GetScore get the score of the tuple
+func (t Tuple) GetScore() float64 {
+	return t.score
+}
+
+//KeyedTuple zset tuple with the key which it is popped from
+type KeyedTuple struct {
+	Key string
+	Tuple
+}
+
+//KeyedElements list elements with the key which they are popped from,the reply of BLMPOP
+type KeyedElements struct {
+	Key      string
+	Elements []string
+}
+
+//KeyedTuples zset tuples with the key which they are popped from,the reply of BZMPOP
+type KeyedTuples struct {
+	Key    string
+	Tuples []Tuple
+}
+
 //GeoRadiusResponse geo radius response
 type GeoRadiusResponse struct {
 	member     string
@@ -316,12 +672,194 @@ type GeoCoordinate struct {
 	latitude  float64
 }
 
+//ClusterSlotNode node address of a CLUSTER SLOTS range
+type ClusterSlotNode struct {
+	Host string
+	Port int
+	ID   string //empty before redis 4.0
+}
+
+//ClusterSlotRange slot range of CLUSTER SLOTS
+type ClusterSlotRange struct {
+	Start    int
+	End      int
+	Master   ClusterSlotNode
+	Replicas []ClusterSlotNode
+}
+
+//ClusterShardNode node of a CLUSTER SHARDS shard
+type ClusterShardNode struct {
+	ID                string
+	Port              int
+	TLSPort           int
+	IP                string
+	Endpoint          string
+	Hostname          string
+	Role              string //master or replica
+	ReplicationOffset int64
+	Health            string //online, failed or loading
+}
+
+//ClusterShard shard of CLUSTER SHARDS
+type ClusterShard struct {
+	Slots [][2]int //inclusive slot ranges
+	Nodes []ClusterShardNode
+}
+
+//ClusterLink link of CLUSTER LINKS
+type ClusterLink struct {
+	Direction           string //to for the links established by the node,from for the links accepted from the peer
+	Node                string //id of the peer
+	CreateTime          int64  //unix time in milliseconds when the link was established
+	Events              string //events the link is waiting for,r or w
+	SendBufferAllocated int64  //allocated size of the send buffer
+	SendBufferUsed      int64  //used size of the send buffer
+}
+
+//ClusterNode node of CLUSTER NODES
+type ClusterNode struct {
+	ID          string
+	Addr        string //ip:port
+	Host        string
+	Port        int
+	BusPort     int    //cluster bus port,0 if absent
+	Hostname    string //announced hostname,since redis 7.0
+	Flags       []string
+	MasterID    string //empty if the node is a master
+	PingSent    int64  //unix milliseconds of the pending ping,0 if none
+	PongRecv    int64  //unix milliseconds of the last pong
+	ConfigEpoch int64
+	LinkState   string         //connected or disconnected
+	Slots       [][2]int       //inclusive slot ranges served by the node
+	Migrating   map[int]string //slot to the id of the node it is migrating to
+	Importing   map[int]string //slot to the id of the node it is importing from
+}
+
+//HasFlag whether the node has the flag,such as master,slave,myself,fail,fail?
+func (n ClusterNode) HasFlag(flag string) bool {
+	for _, f := range n.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+//ServerInfo INFO parsed by sections,the common fields are typed,the others are in Sections
+type ServerInfo struct {
+	Sections    map[string]map[string]string //lower case section name -> field -> value
+	Memory      MemoryInfo
+	Clients     ClientsInfo
+	Replication ReplicationInfo
+	Keyspace    map[int]KeyspaceInfo //db index -> keys of the db
+}
+
+//Get the value of the field in the section,the section name is case insensitive
+func (s *ServerInfo) Get(section, field string) (string, bool) {
+	value, ok := s.Sections[strings.ToLower(section)][field]
+	return value, ok
+}
+
+//MemoryInfo memory section of INFO
+type MemoryInfo struct {
+	UsedMemory         int64 //bytes allocated by redis
+	UsedMemoryRss      int64 //bytes allocated as seen by the operating system
+	UsedMemoryPeak     int64
+	MaxMemory          int64 //0 means no limit
+	MaxMemoryPolicy    string
+	FragmentationRatio float64
+}
+
+//ClientsInfo clients section of INFO
+type ClientsInfo struct {
+	ConnectedClients int64
+	BlockedClients   int64
+	MaxClients       int64
+}
+
+//ReplicationInfo replication section of INFO
+type ReplicationInfo struct {
+	Role             string //master or slave
+	ConnectedSlaves  int64
+	MasterHost       string //empty if the role is master
+	MasterPort       int
+	MasterLinkStatus string //up or down
+	MasterReplOffset int64
+	Slaves           []SlaveInfo
+}
+
+//SlaveInfo a replica connected to the master
+type SlaveInfo struct {
+	IP     string
+	Port   int
+	State  string //such as online,wait_bgsave
+	Offset int64
+	Lag    int64 //seconds since the last ack
+}
+
+//KeyspaceInfo keys of a db,in the keyspace section of INFO
+type KeyspaceInfo struct {
+	Keys    int64
+	Expires int64 //count of keys with an expiration
+	AvgTTL  int64 //average ttl in milliseconds of the keys with an expiration
+}
+
 //ScanResult scan result struct
 type ScanResult struct {
 	Cursor  string
 	Results []string
 }
 
+//StreamEntry entry of a stream
+type StreamEntry struct {
+	ID     string
+	Fields map[string]string //nil if the entry was deleted while pending
+}
+
+//StreamEntries entries read from a stream
+type StreamEntries struct {
+	Stream  string
+	Entries []StreamEntry
+}
+
+//StreamPendingEntry entry of XPENDING with start and end
+type StreamPendingEntry struct {
+	ID         string
+	Consumer   string
+	Idle       time.Duration //time since the entry was last delivered
+	Deliveries int64         //times the entry was delivered
+}
+
+//StreamClaimResult result of XAUTOCLAIM
+type StreamClaimResult struct {
+	Next    string        //start id of the next call,0-0 means the whole pending list was scanned
+	Entries []StreamEntry //claimed entries
+	Deleted []string      //ids of pending entries no longer in the stream,since redis 7.0
+}
+
+//XPendingParams xpending params
+type XPendingParams struct {
+	idle     time.Duration
+	consumer string
+}
+
+//NewXPendingParams create new xpending params instance
+func NewXPendingParams() *XPendingParams {
+	return &XPendingParams{}
+}
+
+//Idle only return entries idle for at least minIdle,available since redis 6.2
+func (p *XPendingParams) Idle(minIdle time.Duration) *XPendingParams {
+	p.idle = minIdle
+	return p
+}
+
+//Consumer only return entries owned by consumer
+func (p *XPendingParams) Consumer(consumer string) *XPendingParams {
+	p.consumer = consumer
+	return p
+}
+
 //ZParams zset operation params
 type ZParams struct {
 	params []string
@@ -353,6 +891,11 @@ func newZParams() *ZParams {
 	return &ZParams{params: make([]string, 0)}
 }
 
+//NewZParams create a new zparams instance
+func NewZParams() *ZParams {
+	return newZParams()
+}
+
 //Aggregate aggregate,sum|min|max
 type Aggregate struct {
 	name string // name of Aggregate
@@ -377,9 +920,11 @@ var (
 	AggregateMax = newAggregate("MAX")
 )
 
-//RedisPubSub redis pubsub struct
+//RedisPubSub redis pubsub struct,
+//the events of sharded channels are also delivered to OnMessage,OnSubscribe and OnUnSubscribe
 type RedisPubSub struct {
 	subscribedChannels int
+	shardedChannels    int
 	redis              *Redis
 	OnMessage          func(channel, message string)                 //receive message
 	OnPMessage         func(pattern string, channel, message string) //receive pattern message
@@ -388,13 +933,102 @@ type RedisPubSub struct {
 	OnPUnSubscribe     func(pattern string, subscribedChannels int)  //listen pattern unsubscribe event
 	OnPSubscribe       func(pattern string, subscribedChannels int)  //listen pattern subscribe event
 	OnPong             func(channel string)                          //listen heart beat event
+
+	//OnReceive receive every event as a typed message before the callback of its kind,
+	//use it for binary payloads or to handle all the events in one place
+	OnReceive func(msg *Message)
+
+	//PingInterval send PING every interval while subscribed,so a connection dropped silently,
+	//such as by a NAT or a load balancer,is detected,0 means disabled
+	PingInterval time.Duration
+	//PingTimeout the subscription fails with ErrPingTimeout if nothing is received
+	//within PingInterval plus PingTimeout,default PingInterval
+	PingTimeout time.Duration
+
+	writeMu sync.Mutex //serialize the commands sent by the callbacks and the keep-alive
+}
+
+//MessageKind kind of the event received by RedisPubSub
+type MessageKind int
+
+const (
+	//MessageKindSubscribe a channel is subscribed
+	MessageKindSubscribe MessageKind = iota
+	//MessageKindUnSubscribe a channel is unsubscribed
+	MessageKindUnSubscribe
+	//MessageKindPSubscribe a pattern is subscribed
+	MessageKindPSubscribe
+	//MessageKindPUnSubscribe a pattern is unsubscribed
+	MessageKindPUnSubscribe
+	//MessageKindSSubscribe a sharded channel is subscribed
+	MessageKindSSubscribe
+	//MessageKindSUnSubscribe a sharded channel is unsubscribed
+	MessageKindSUnSubscribe
+	//MessageKindMessage a message published to a subscribed channel
+	MessageKindMessage
+	//MessageKindPMessage a message published to a channel matching a subscribed pattern
+	MessageKindPMessage
+	//MessageKindSMessage a message published to a subscribed sharded channel
+	MessageKindSMessage
+	//MessageKindPong the reply of PING in subscribe mode
+	MessageKindPong
+)
+
+var pubSubMessageKinds = map[string]MessageKind{
+	keywordSubscribe.name:    MessageKindSubscribe,
+	keywordUnsubscribe.name:  MessageKindUnSubscribe,
+	keywordPSubscribe.name:   MessageKindPSubscribe,
+	cmdPUnSubscribe.name:     MessageKindPUnSubscribe,
+	keywordSSubscribe.name:   MessageKindSSubscribe,
+	keywordSUnsubscribe.name: MessageKindSUnSubscribe,
+	keywordMessage.name:      MessageKindMessage,
+	keywordPMessage.name:     MessageKindPMessage,
+	keywordSMessage.name:     MessageKindSMessage,
+	keywordPong.name:         MessageKindPong,
+}
+
+func (k MessageKind) String() string {
+	switch k {
+	case MessageKindSubscribe:
+		return "subscribe"
+	case MessageKindUnSubscribe:
+		return "unsubscribe"
+	case MessageKindPSubscribe:
+		return "psubscribe"
+	case MessageKindPUnSubscribe:
+		return "punsubscribe"
+	case MessageKindSSubscribe:
+		return "ssubscribe"
+	case MessageKindSUnSubscribe:
+		return "sunsubscribe"
+	case MessageKindMessage:
+		return "message"
+	case MessageKindPMessage:
+		return "pmessage"
+	case MessageKindSMessage:
+		return "smessage"
+	case MessageKindPong:
+		return "pong"
+	}
+	return "unknown"
+}
+
+//Message event received by RedisPubSub
+type Message struct {
+	Kind    MessageKind
+	Channel string //channel of the message or the (un)subscribed channel,empty for pattern events and pong
+	Pattern string //pattern matched by the channel of a pmessage,or the (un)subscribed pattern
+	Payload []byte //the published message or the argument of PING,the bytes are kept as they are published
+	Count   int    //count of subscriptions left after a (un)subscribe event
 }
 
 //Subscribe subscribe some channels
 func (r *RedisPubSub) Subscribe(channels ...string) error {
 	r.redis.mu.RLock()
 	defer r.redis.mu.RUnlock()
-	if r.redis.client == nil {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if !r.redis.client.isInSubscribe {
 		return newConnectError("redisPubSub is not subscribed to a Redis instance")
 	}
 	err := r.redis.client.subscribe(channels...)
@@ -412,7 +1046,9 @@ func (r *RedisPubSub) Subscribe(channels ...string) error {
 func (r *RedisPubSub) UnSubscribe(channels ...string) error {
 	r.redis.mu.RLock()
 	defer r.redis.mu.RUnlock()
-	if r.redis.client == nil {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if !r.redis.client.isInSubscribe {
 		return newConnectError("redisPubSub is not subscribed to a Redis instance")
 	}
 	err := r.redis.client.unsubscribe(channels...)
@@ -430,7 +1066,9 @@ func (r *RedisPubSub) UnSubscribe(channels ...string) error {
 func (r *RedisPubSub) PSubscribe(channels ...string) error {
 	r.redis.mu.RLock()
 	defer r.redis.mu.RUnlock()
-	if r.redis.client == nil {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if !r.redis.client.isInSubscribe {
 		return newConnectError("redisPubSub is not subscribed to a Redis instance")
 	}
 	err := r.redis.client.psubscribe(channels...)
@@ -448,7 +1086,9 @@ func (r *RedisPubSub) PSubscribe(channels ...string) error {
 func (r *RedisPubSub) PUnSubscribe(channels ...string) error {
 	r.redis.mu.RLock()
 	defer r.redis.mu.RUnlock()
-	if r.redis.client == nil {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if !r.redis.client.isInSubscribe {
 		return newConnectError("redisPubSub is not subscribed to a Redis instance")
 	}
 	err := r.redis.client.punsubscribe(channels...)
@@ -462,8 +1102,65 @@ func (r *RedisPubSub) PUnSubscribe(channels ...string) error {
 	return nil
 }
 
+//SSubscribe subscribe some sharded channels
+func (r *RedisPubSub) SSubscribe(channels ...string) error {
+	r.redis.mu.RLock()
+	defer r.redis.mu.RUnlock()
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if !r.redis.client.isInSubscribe {
+		return newConnectError("redisPubSub is not subscribed to a Redis instance")
+	}
+	err := r.redis.client.ssubscribe(channels...)
+	if err != nil {
+		return err
+	}
+	err = r.redis.client.flush()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//SUnSubscribe unsubscribe some sharded channels
+func (r *RedisPubSub) SUnSubscribe(channels ...string) error {
+	r.redis.mu.RLock()
+	defer r.redis.mu.RUnlock()
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if !r.redis.client.isInSubscribe {
+		return newConnectError("redisPubSub is not subscribed to a Redis instance")
+	}
+	err := r.redis.client.sunsubscribe(channels...)
+	if err != nil {
+		return err
+	}
+	err = r.redis.client.flush()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//Ping send PING on the subscribed connection,the reply is delivered to OnPong with the argument
+func (r *RedisPubSub) Ping(argument ...string) error {
+	r.redis.mu.RLock()
+	defer r.redis.mu.RUnlock()
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if !r.redis.client.isInSubscribe {
+		return newConnectError("redisPubSub is not subscribed to a Redis instance")
+	}
+	err := r.redis.client.sendCommandStr(cmdPing, argument...)
+	if err != nil {
+		return err
+	}
+	return r.redis.client.flush()
+}
+
 func (r *RedisPubSub) proceed(redis *Redis, channels ...string) error {
 	r.redis = redis
+	r.redis.client.isInSubscribe = true
 	err := r.redis.client.subscribe(channels...)
 	if err != nil {
 		return err
@@ -476,11 +1173,26 @@ func (r *RedisPubSub) proceed(redis *Redis, channels ...string) error {
 }
 
 func (r *RedisPubSub) isSubscribed() bool {
-	return r.subscribedChannels > 0
+	return r.subscribedChannels > 0 || r.shardedChannels > 0
+}
+
+func (r *RedisPubSub) proceedWithShards(redis *Redis, channels ...string) error {
+	r.redis = redis
+	r.redis.client.isInSubscribe = true
+	err := r.redis.client.ssubscribe(channels...)
+	if err != nil {
+		return err
+	}
+	err = r.redis.client.flush()
+	if err != nil {
+		return err
+	}
+	return r.process(redis)
 }
 
 func (r *RedisPubSub) proceedWithPatterns(redis *Redis, patterns ...string) error {
 	r.redis = redis
+	r.redis.client.isInSubscribe = true
 	err := r.redis.client.psubscribe(patterns...)
 	if err != nil {
 		return err
@@ -493,30 +1205,28 @@ func (r *RedisPubSub) proceedWithPatterns(redis *Redis, patterns ...string) erro
 }
 
 func (r *RedisPubSub) process(redis *Redis) error {
+	timeout := r.keepAliveTimeout()
+	if err := redis.client.connection.setSubscribeTimeout(timeout); err != nil {
+		return err
+	}
+	defer redis.client.connection.clearSubscribeTimeout()
+	stop := r.keepAlive()
+	defer stop()
+	received := time.Now()
 	for {
 		reply, err := redis.client.connection.getRawObjectMultiBulkReply()
 		if err != nil {
+			if timeout > 0 && time.Since(received) >= timeout {
+				return newDisconnectedError(ErrPingTimeout.Error(), ErrPingTimeout)
+			}
 			return err
 		}
-		respUpper := strings.ToUpper(string(reply[0].([]byte)))
-		switch respUpper {
-		case keywordSubscribe.name:
-			r.processSubscribe(reply)
-		case keywordUnsubscribe.name:
-			r.processUnSubscribe(reply)
-		case keywordMessage.name:
-			r.processMessage(reply)
-		case keywordPMessage.name:
-			r.processPMessage(reply)
-		case keywordPSubscribe.name:
-			r.processPSubscribe(reply)
-		case cmdPUnSubscribe.name:
-			r.processPUnSubscribe(reply)
-		case keywordPong.name:
-			r.processPong(reply)
-		default:
-			return fmt.Errorf("unknown message type: %v", reply)
+		received = time.Now()
+		msg, err := parsePubSubMessage(reply)
+		if err != nil {
+			return err
 		}
+		r.dispatch(msg)
 		if !r.isSubscribed() {
 			break
 		}
@@ -526,90 +1236,142 @@ func (r *RedisPubSub) process(redis *Redis) error {
 	// Reset pipeline count because subscribe() calls would have increased it but nothing decremented it.
 	redis.client.resetPipelinedCount()
 	// Invalidate instance since this thread is no longer listening
-	r.redis.client = nil
+	r.redis.client.isInSubscribe = false
 	return nil
 }
 
-func (r *RedisPubSub) processSubscribe(reply []interface{}) {
-	r.subscribedChannels = int(reply[2].(int64))
-	bChannel := reply[1].([]byte)
-	strChannel := ""
-	if bChannel != nil {
-		strChannel = string(bChannel)
+//keepAliveTimeout max time waiting for the next reply,0 means no deadline
+func (r *RedisPubSub) keepAliveTimeout() time.Duration {
+	if r.PingInterval <= 0 {
+		return 0
 	}
-	r.OnSubscribe(strChannel, r.subscribedChannels)
-}
-
-func (r *RedisPubSub) processUnSubscribe(reply []interface{}) {
-	r.subscribedChannels = int(reply[2].(int64))
-	bChannel := reply[1].([]byte)
-	strChannel := ""
-	if bChannel != nil {
-		strChannel = string(bChannel)
+	if r.PingTimeout <= 0 {
+		return 2 * r.PingInterval
 	}
-	r.OnUnSubscribe(strChannel, r.subscribedChannels)
+	return r.PingInterval + r.PingTimeout
 }
 
-func (r *RedisPubSub) processMessage(reply []interface{}) {
-	bChannel := reply[1].([]byte)
-	bMsg := reply[2].([]byte)
-	strChannel := ""
-	if bChannel != nil {
-		strChannel = string(bChannel)
+//keepAlive ping every PingInterval until stop is called,no ping is sent after stop returns,
+//a failed ping is detected by the read timeout,so the error is ignored
+func (r *RedisPubSub) keepAlive() (stop func()) {
+	if r.PingInterval <= 0 {
+		return func() {}
 	}
-	strMsg := ""
-	if bChannel != nil {
-		strMsg = string(bMsg)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(r.PingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.Ping()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
 	}
-	r.OnMessage(strChannel, strMsg)
 }
 
-func (r *RedisPubSub) processPMessage(reply []interface{}) {
-	bPattern := reply[1].([]byte)
-	bChannel := reply[2].([]byte)
-	bMsg := reply[3].([]byte)
-	strPattern := ""
-	if bPattern != nil {
-		strPattern = string(bPattern)
+//parsePubSubMessage convert a reply received in subscribe mode into a message,
+//the payload keeps the bytes of the reply,so binary payloads are delivered as they are
+func parsePubSubMessage(reply []interface{}) (*Message, error) {
+	if len(reply) < 2 {
+		return nil, fmt.Errorf("unknown message type: %v", reply)
 	}
-	strChannel := ""
-	if bChannel != nil {
-		strChannel = string(bChannel)
+	kind, ok := pubSubMessageKinds[strings.ToUpper(string(pubSubBytes(reply[0])))]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type: %v", reply)
 	}
-	strMsg := ""
-	if bChannel != nil {
-		strMsg = string(bMsg)
+	msg := &Message{Kind: kind}
+	switch kind {
+	case MessageKindMessage, MessageKindSMessage:
+		if len(reply) < 3 {
+			return nil, fmt.Errorf("unknown message type: %v", reply)
+		}
+		msg.Channel = string(pubSubBytes(reply[1]))
+		msg.Payload = pubSubBytes(reply[2])
+	case MessageKindPMessage:
+		if len(reply) < 4 {
+			return nil, fmt.Errorf("unknown message type: %v", reply)
+		}
+		msg.Pattern = string(pubSubBytes(reply[1]))
+		msg.Channel = string(pubSubBytes(reply[2]))
+		msg.Payload = pubSubBytes(reply[3])
+	case MessageKindPong:
+		msg.Payload = pubSubBytes(reply[1])
+	case MessageKindPSubscribe, MessageKindPUnSubscribe:
+		if len(reply) < 3 {
+			return nil, fmt.Errorf("unknown message type: %v", reply)
+		}
+		msg.Pattern = string(pubSubBytes(reply[1]))
+		msg.Count = pubSubCount(reply[2])
+	default:
+		if len(reply) < 3 {
+			return nil, fmt.Errorf("unknown message type: %v", reply)
+		}
+		msg.Channel = string(pubSubBytes(reply[1]))
+		msg.Count = pubSubCount(reply[2])
 	}
-	r.OnPMessage(strPattern, strChannel, strMsg)
+	return msg, nil
 }
 
-func (r *RedisPubSub) processPSubscribe(reply []interface{}) {
-	r.subscribedChannels = int(reply[2].(int64))
-	bPattern := reply[1].([]byte)
-	strPattern := ""
-	if bPattern != nil {
-		strPattern = string(bPattern)
-	}
-	r.OnPSubscribe(strPattern, r.subscribedChannels)
+func pubSubBytes(reply interface{}) []byte {
+	b, _ := reply.([]byte)
+	return b
 }
 
-func (r *RedisPubSub) processPUnSubscribe(reply []interface{}) {
-	r.subscribedChannels = int(reply[2].(int64))
-	bPattern := reply[1].([]byte)
-	strPattern := ""
-	if bPattern != nil {
-		strPattern = string(bPattern)
-	}
-	r.OnPUnSubscribe(strPattern, r.subscribedChannels)
+func pubSubCount(reply interface{}) int {
+	n, _ := reply.(int64)
+	return int(n)
 }
 
-func (r *RedisPubSub) processPong(reply []interface{}) {
-	bPattern := reply[1].([]byte)
-	strPattern := ""
-	if bPattern != nil {
-		strPattern = string(bPattern)
+//dispatch update the subscription counts and deliver the message to OnReceive and the callback of its kind
+func (r *RedisPubSub) dispatch(msg *Message) {
+	switch msg.Kind {
+	case MessageKindSubscribe, MessageKindUnSubscribe, MessageKindPSubscribe, MessageKindPUnSubscribe:
+		r.subscribedChannels = msg.Count
+	case MessageKindSSubscribe, MessageKindSUnSubscribe:
+		r.shardedChannels = msg.Count
+	}
+	if r.OnReceive != nil {
+		r.OnReceive(msg)
+	}
+	switch msg.Kind {
+	case MessageKindSubscribe, MessageKindSSubscribe:
+		if r.OnSubscribe != nil {
+			r.OnSubscribe(msg.Channel, msg.Count)
+		}
+	case MessageKindUnSubscribe, MessageKindSUnSubscribe:
+		if r.OnUnSubscribe != nil {
+			r.OnUnSubscribe(msg.Channel, msg.Count)
+		}
+	case MessageKindPSubscribe:
+		if r.OnPSubscribe != nil {
+			r.OnPSubscribe(msg.Pattern, msg.Count)
+		}
+	case MessageKindPUnSubscribe:
+		if r.OnPUnSubscribe != nil {
+			r.OnPUnSubscribe(msg.Pattern, msg.Count)
+		}
+	case MessageKindMessage, MessageKindSMessage:
+		if r.OnMessage != nil {
+			r.OnMessage(msg.Channel, string(msg.Payload))
+		}
+	case MessageKindPMessage:
+		if r.OnPMessage != nil {
+			r.OnPMessage(msg.Pattern, msg.Channel, string(msg.Payload))
+		}
+	case MessageKindPong:
+		if r.OnPong != nil {
+			r.OnPong(string(msg.Payload))
+		}
 	}
-	r.OnPong(strPattern)
 }
 
 //BitOP bit operation struct
@@ -666,6 +1428,260 @@ func NewDebugParamsReload() *DebugParams {
 	return &DebugParams{command: []string{"RELOAD"}}
 }
 
+//NewDebugParams create debug params with any subcommand and its arguments
+func NewDebugParams(subcommand string, args ...string) *DebugParams {
+	return &DebugParams{command: StrStrArrToStrArr(subcommand, args)}
+}
+
+//NewDebugParamsSleep create debug params with sleep,the server is blocked for the duration
+func NewDebugParamsSleep(duration time.Duration) *DebugParams {
+	return &DebugParams{command: []string{"SLEEP", strconv.FormatFloat(duration.Seconds(), 'f', -1, 64)}}
+}
+
+//NewDebugParamsSetActiveExpire create debug params with set-active-expire,
+//disabling it keeps the expired keys until they are accessed
+func NewDebugParamsSetActiveExpire(enabled bool) *DebugParams {
+	return &DebugParams{command: []string{"SET-ACTIVE-EXPIRE", string(BoolToByteArr(enabled))}}
+}
+
+//NewDebugParamsQuicklistPackedThreshold create debug params with quicklist-packed-threshold,
+//list elements larger than threshold bytes are stored as plain nodes
+func NewDebugParamsQuicklistPackedThreshold(threshold int64) *DebugParams {
+	return &DebugParams{command: []string{"QUICKLIST-PACKED-THRESHOLD", strconv.FormatInt(threshold, 10)}}
+}
+
+//NewDebugParamsStringMatchLen create debug params with stringmatch-len,run the fuzz test of the glob matcher
+func NewDebugParamsStringMatchLen() *DebugParams {
+	return &DebugParams{command: []string{"STRINGMATCH-LEN"}}
+}
+
+//DebugObjectInfo the reply of DEBUG OBJECT
+type DebugObjectInfo struct {
+	Encoding         string
+	RefCount         int64
+	SerializedLength int64 //length of the value serialized by RDB
+	LRU              int64
+	LRUSecondsIdle   int64
+	Fields           map[string]string //all the fields of the reply,including the typed ones
+}
+
+//SentinelMasterInfo the reply of SENTINEL MASTER
+type SentinelMasterInfo struct {
+	Name                  string
+	IP                    string
+	Port                  int
+	RunID                 string
+	Flags                 []string //such as master,s_down,o_down,disconnected
+	LinkPendingCommands   int64
+	LastPingSent          int64 //milliseconds since the pending ping was sent,0 if there is none
+	LastOkPingReply       int64 //milliseconds since the last valid ping reply
+	LastPingReply         int64 //milliseconds since the last ping reply
+	DownAfterMilliseconds int64
+	InfoRefresh           int64 //milliseconds since INFO was refreshed
+	RoleReported          string
+	ConfigEpoch           int64
+	NumSlaves             int64
+	NumOtherSentinels     int64
+	Quorum                int64
+	FailoverTimeout       int64
+	ParallelSyncs         int64
+	Fields                map[string]string //all the fields of the reply,including the typed ones
+}
+
+//SimulateFailure failure of SENTINEL SIMULATE-FAILURE
+type SimulateFailure struct {
+	name string
+}
+
+func newSimulateFailure(name string) *SimulateFailure {
+	return &SimulateFailure{name}
+}
+
+var (
+	//SimulateFailureCrashAfterElection crash after the sentinel is elected as leader
+	SimulateFailureCrashAfterElection = newSimulateFailure("crash-after-election")
+	//SimulateFailureCrashAfterPromotion crash after a replica is promoted
+	SimulateFailureCrashAfterPromotion = newSimulateFailure("crash-after-promotion")
+)
+
+//ClientType type of the clients,filtered by CLIENT KILL and CLIENT LIST
+type ClientType struct {
+	name string
+}
+
+func newClientType(name string) *ClientType {
+	return &ClientType{name}
+}
+
+var (
+	//ClientTypeNormal the normal clients,including the ones in MULTI
+	ClientTypeNormal = newClientType("normal")
+	//ClientTypePubSub the clients subscribed to channels or patterns
+	ClientTypePubSub = newClientType("pubsub")
+	//ClientTypeReplica the replicas connected to this master,since redis 5.0
+	ClientTypeReplica = newClientType("replica")
+	//ClientTypeMaster the connection of this replica to its master,since redis 5.0
+	ClientTypeMaster = newClientType("master")
+)
+
+//ClientFilter filter of CLIENT KILL and CLIENT LIST,a client must match all the conditions set
+type ClientFilter struct {
+	id       int64
+	hasID    bool
+	addr     string
+	laddr    string
+	typ      *ClientType
+	user     string
+	maxAge   int64
+	skipMe   bool
+	noSkipMe bool
+}
+
+//NewClientFilter create new client filter instance,without conditions it matches all the clients
+func NewClientFilter() *ClientFilter {
+	return &ClientFilter{}
+}
+
+//ID the client with the id,see CLIENT ID
+func (f *ClientFilter) ID(id int64) *ClientFilter {
+	f.id = id
+	f.hasID = true
+	return f
+}
+
+//Addr the client connected from the address,ip:port
+func (f *ClientFilter) Addr(addr string) *ClientFilter {
+	f.addr = addr
+	return f
+}
+
+//LAddr the clients connected to the local address of the server,ip:port,since redis 6.2
+func (f *ClientFilter) LAddr(laddr string) *ClientFilter {
+	f.laddr = laddr
+	return f
+}
+
+//Type the clients of the type,such as ClientTypePubSub
+func (f *ClientFilter) Type(typ *ClientType) *ClientFilter {
+	f.typ = typ
+	return f
+}
+
+//User the clients authenticated as the ACL user,since redis 6.0
+func (f *ClientFilter) User(user string) *ClientFilter {
+	f.user = user
+	return f
+}
+
+//MaxAge the clients connected for maxAge or longer,in seconds,since redis 7.4
+func (f *ClientFilter) MaxAge(maxAge time.Duration) *ClientFilter {
+	f.maxAge = int64(maxAge / time.Second)
+	return f
+}
+
+//SkipMe whether CLIENT KILL skips the client sending it,the server skips it by default
+func (f *ClientFilter) SkipMe(skip bool) *ClientFilter {
+	f.skipMe = skip
+	f.noSkipMe = !skip
+	return f
+}
+
+//killParams the filters of CLIENT KILL,all of them are sent to the server
+func (f *ClientFilter) killParams() [][]byte {
+	params := make([][]byte, 0)
+	if f.hasID {
+		params = append(params, keywordID.getRaw(), Int64ToByteArr(f.id))
+	}
+	if f.addr != "" {
+		params = append(params, keywordAddr.getRaw(), []byte(f.addr))
+	}
+	if f.laddr != "" {
+		params = append(params, keywordLAddr.getRaw(), []byte(f.laddr))
+	}
+	if f.typ != nil {
+		params = append(params, keywordType.getRaw(), []byte(f.typ.name))
+	}
+	if f.user != "" {
+		params = append(params, keywordUser.getRaw(), []byte(f.user))
+	}
+	if f.maxAge > 0 {
+		params = append(params, keywordMaxAge.getRaw(), Int64ToByteArr(f.maxAge))
+	}
+	if f.skipMe || f.noSkipMe {
+		skip := keywordYes
+		if f.noSkipMe {
+			skip = keywordNo
+		}
+		params = append(params, keywordSkipMe.getRaw(), skip.getRaw())
+	}
+	return params
+}
+
+//listParams the filters of CLIENT LIST,the server accepts either ID or TYPE,
+//the other conditions are checked by match
+func (f *ClientFilter) listParams() [][]byte {
+	if f.hasID {
+		return [][]byte{keywordID.getRaw(), Int64ToByteArr(f.id)}
+	}
+	if f.typ != nil {
+		return [][]byte{keywordType.getRaw(), []byte(f.typ.name)}
+	}
+	return [][]byte{}
+}
+
+//match whether the client matches all the conditions of the filter
+func (f *ClientFilter) match(info ClientInfo) bool {
+	if f.hasID && info.ID != f.id {
+		return false
+	}
+	if f.addr != "" && info.Addr != f.addr {
+		return false
+	}
+	if f.laddr != "" && info.LAddr != f.laddr {
+		return false
+	}
+	if f.typ != nil && info.Type() != f.typ {
+		return false
+	}
+	if f.user != "" && info.User != f.user {
+		return false
+	}
+	return f.maxAge <= 0 || info.Age >= f.maxAge
+}
+
+//ClientInfo a client of CLIENT LIST
+type ClientInfo struct {
+	ID    int64
+	Addr  string //address of the client,ip:port
+	LAddr string //local address of the server the client connected to,since redis 6.2
+	FD    int64  //-1 if the client is internal
+	Name  string //set by CLIENT SETNAME
+	Age   int64  //seconds since the client connected
+	Idle  int64  //seconds since the last command
+	Flags string //such as N for none,P for pubsub,S for replica,M for master,x for MULTI
+	DB    int
+	Sub   int64  //count of the subscribed channels
+	PSub  int64  //count of the subscribed patterns
+	Multi int64  //count of the commands queued in MULTI,-1 if not in MULTI
+	Cmd   string //the last command,such as client|list
+	User  string //the authenticated ACL user,since redis 6.0
+
+	Fields map[string]string //all the fields of the client,including the typed ones
+}
+
+//Type the type of the client by its flags,such as ClientTypePubSub
+func (c ClientInfo) Type() *ClientType {
+	switch {
+	case strings.Contains(c.Flags, "M"):
+		return ClientTypeMaster
+	case strings.Contains(c.Flags, "S") && !strings.Contains(c.Flags, "O"):
+		return ClientTypeReplica
+	case strings.Contains(c.Flags, "P"):
+		return ClientTypePubSub
+	}
+	return ClientTypeNormal
+}
+
 //Reset reset struct
 type Reset struct {
 	name string //name of reset
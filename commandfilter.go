@@ -0,0 +1,81 @@
+package godis
+
+import "strings"
+
+//CommandFilter check a command before it is sent,such as to block the dangerous commands or to log the writes,
+//a non-nil error fails the command without sending it,see Option.CommandFilters.
+//name is upper case,spec is nil if the command is unknown to this client,args are the arguments after the name
+type CommandFilter func(name string, spec *CommandSpec, args []string) error
+
+//commandSet upper case command names,with the subcommands such as CONFIG|SET
+type commandSet map[string]bool
+
+func newCommandSet(names []string) commandSet {
+	set := make(commandSet, len(names))
+	for _, name := range names {
+		set[strings.ToUpper(name)] = true
+	}
+	return set
+}
+
+//match return the entry of the set matching the command,the command name first,then with the subcommand
+func (s commandSet) match(name string, args []string) (string, bool) {
+	if s[name] {
+		return name, true
+	}
+	if len(args) > 0 {
+		sub := name + "|" + strings.ToUpper(args[0])
+		if s[sub] {
+			return sub, true
+		}
+	}
+	return "", false
+}
+
+//DenyCommands fail the commands in names with CommandDeniedError,
+//a name is a command such as FLUSHALL,or a subcommand such as CONFIG|SET to deny CONFIG SET only
+//
+//	option.CommandFilters = []godis.CommandFilter{godis.DenyCommands("KEYS", "FLUSHALL", "FLUSHDB", "DEBUG", "CONFIG|SET")}
+func DenyCommands(names ...string) CommandFilter {
+	denied := newCommandSet(names)
+	return func(name string, spec *CommandSpec, args []string) error {
+		if entry, ok := denied.match(name, args); ok {
+			return newCommandDeniedError(entry)
+		}
+		return nil
+	}
+}
+
+//AllowCommands fail the commands not in names with CommandDeniedError,
+//a name is a command such as GET,or a subcommand such as CLIENT|SETNAME to allow CLIENT SETNAME only
+func AllowCommands(names ...string) CommandFilter {
+	allowed := newCommandSet(names)
+	return func(name string, spec *CommandSpec, args []string) error {
+		if _, ok := allowed.match(name, args); ok {
+			return nil
+		}
+		return newCommandDeniedError(name)
+	}
+}
+
+//keylessWrites the commands which write the dataset without a key argument
+var keylessWrites = newCommandSet([]string{"FLUSHALL", "FLUSHDB", "SWAPDB"})
+
+//LogWriteCommands call log with every command which may write the dataset before it is sent,
+//such as SET,EVAL and FLUSHALL,but not PING or CONFIG SET,
+//the commands unknown to this client are considered writes,log must not block
+func LogWriteCommands(log func(name string, args []string)) CommandFilter {
+	return func(name string, spec *CommandSpec, args []string) error {
+		if isWriteCommand(name, spec) {
+			log(name, args)
+		}
+		return nil
+	}
+}
+
+func isWriteCommand(name string, spec *CommandSpec) bool {
+	if spec == nil {
+		return true
+	}
+	return !spec.ReadOnly && (spec.FirstKey != 0 || spec.NumKeys != 0 || keylessWrites[name])
+}
@@ -0,0 +1,77 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDenyCommands(t *testing.T) {
+	flushAll()
+	filtered := *option
+	filtered.CommandFilters = []CommandFilter{DenyCommands("keys", "FLUSHALL", "CONFIG|SET")}
+	redis := NewRedis(&filtered)
+	defer redis.Close()
+	_, err := redis.Set("godis", "good")
+	assert.Nil(t, err)
+	_, err = redis.Keys("*")
+	assert.True(t, errors.Is(err, ErrCommandDenied))
+	assert.Equal(t, "KEYS", err.(*CommandDeniedError).Command)
+	_, err = redis.FlushAll()
+	assert.EqualError(t, err, "command FLUSHALL is denied")
+	_, err = redis.ConfigSet("timeout", "30")
+	assert.Equal(t, "CONFIG|SET", err.(*CommandDeniedError).Command)
+	err = redis.SendByStr("flushall")
+	assert.True(t, errors.Is(err, ErrCommandDenied))
+
+	//the denied command isn't sent,so the connection is still usable
+	s, err := redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	p := redis.Pipelined()
+	p.MSet("godis1", "good1")
+	p.Keys("*")
+	assert.Nil(t, p.Sync())
+	c, _ := redis.Exists("godis1")
+	assert.Equal(t, int64(1), c)
+}
+
+func TestAllowCommands(t *testing.T) {
+	flushAll()
+	filtered := *option
+	filtered.Db = 1
+	filtered.ClientName = "godis"
+	filtered.CommandFilters = []CommandFilter{AllowCommands("GET", "CLIENT|LIST")}
+	//the handshake isn't checked
+	redis := NewRedis(&filtered)
+	defer redis.Close()
+	_, err := redis.Get("godis")
+	assert.Nil(t, err)
+	_, err = redis.Set("godis", "good")
+	assert.EqualError(t, err, "command SET is denied")
+	_, err = redis.ClientKill("127.0.0.1:1")
+	assert.EqualError(t, err, "command CLIENT is denied")
+}
+
+func TestLogWriteCommands(t *testing.T) {
+	flushAll()
+	logged := make([]string, 0)
+	filtered := *option
+	filtered.CommandFilters = []CommandFilter{
+		LogWriteCommands(func(name string, args []string) {
+			logged = append(logged, name)
+		}),
+		DenyCommands("DEL"),
+	}
+	redis := NewRedis(&filtered)
+	defer redis.Close()
+	redis.Ping()
+	redis.Set("godis", "good")
+	redis.Get("godis")
+	redis.Eval("return 1", 0)
+	redis.Del("godis")
+	redis.FlushDB()
+	redis.SendByStr("UNKNOWN")
+	//the filters run in order,so the denied DEL is logged too
+	assert.Equal(t, []string{"SET", "EVAL", "DEL", "FLUSHDB", "UNKNOWN"}, logged)
+}
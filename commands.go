@@ -0,0 +1,270 @@
+package godis
+
+import "time"
+
+//KeyCommands commands of generic keys
+type KeyCommands interface {
+	Del(keys ...string) (int64, error)
+	Exists(keys ...string) (int64, error)
+	Expire(key string, seconds int) (int64, error)
+	ExpireAt(key string, unixTimeSeconds int64) (int64, error)
+	PExpire(key string, milliseconds int64) (int64, error)
+	PExpireAt(key string, millisecondsTimestamp int64) (int64, error)
+	Persist(key string) (int64, error)
+	TTL(key string) (int64, error)
+	PTTL(key string) (int64, error)
+	Type(key string) (string, error)
+	Rename(oldKey, newKey string) (string, error)
+	RenameNx(oldKey, newKey string) (int64, error)
+	Dump(key string) ([]byte, error)
+	Restore(key string, ttl int, serializedValue []byte) (string, error)
+	RestoreReplace(key string, ttl int, serializedValue []byte) (string, error)
+	Scan(cursor string, params ...*ScanParams) (*ScanResult, error)
+	Sort(key string, params ...*SortParams) ([]string, error)
+	SortStore(srcKey, destKey string, params ...*SortParams) (int64, error)
+}
+
+//StringCommands commands of strings
+type StringCommands interface {
+	Append(key, value string) (int64, error)
+	Decr(key string) (int64, error)
+	DecrBy(key string, decrement int64) (int64, error)
+	Get(key string) (string, error)
+	GetRange(key string, start, end int64) (string, error)
+	GetScan(key string, dest interface{}) error
+	GetSet(key, value string) (string, error)
+	GetDel(key string) (string, error)
+	Incr(key string) (int64, error)
+	IncrBy(key string, increment int64) (int64, error)
+	IncrByFloat(key string, increment float64) (float64, error)
+	MGet(keys ...string) ([]string, error)
+	MSet(kvs ...string) (string, error)
+	MSetNx(kvs ...string) (int64, error)
+	PSetEx(key string, milliseconds int64, value string) (string, error)
+	Set(key, value string) (string, error)
+	SetEx(key string, seconds int, value string) (string, error)
+	SetNx(key, value string) (int64, error)
+	SetRange(key string, offset int64, value string) (int64, error)
+	SetWithParams(key, value, nxxx string) (string, error)
+	SetWithParamsAndTime(key, value, nxxx, expx string, time int64) (string, error)
+	StrLen(key string) (int64, error)
+	SubStr(key string, start, end int) (string, error)
+}
+
+//BitCommands commands of bitmaps
+type BitCommands interface {
+	BitCount(key string) (int64, error)
+	BitCountRange(key string, start, end int64, unit ...*BitUnit) (int64, error)
+	BitField(key string, arguments ...string) ([]int64, error)
+	BitFieldWithArgs(key string, args *BitFieldArgs) ([]*int64, error)
+	BitFieldRo(key string, args *BitFieldArgs) ([]int64, error)
+	BitOp(op BitOP, destKey string, srcKeys ...string) (int64, error)
+	BitPos(key string, value bool, params ...*BitPosParams) (int64, error)
+	GetBit(key string, offset int64) (bool, error)
+	SetBit(key string, offset int64, value string) (bool, error)
+	SetBitWithBool(key string, offset int64, value bool) (bool, error)
+}
+
+//HashCommands commands of hashes
+type HashCommands interface {
+	HDel(key string, fields ...string) (int64, error)
+	HExists(key, field string) (bool, error)
+	HExpire(key string, seconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error)
+	HExpireAt(key string, unixTime int64, fields []string, condition ...*ExpireCondition) ([]int64, error)
+	HGet(key, field string) (string, error)
+	HGetAll(key string) (map[string]string, error)
+	HGetAllScan(key string, dest interface{}) error
+	HGetScan(key, field string, dest interface{}) error
+	HIncrBy(key, field string, value int64) (int64, error)
+	HIncrByFloat(key, field string, increment float64) (float64, error)
+	HKeys(key string) ([]string, error)
+	HLen(key string) (int64, error)
+	HMGet(key string, fields ...string) ([]string, error)
+	HMGetScan(key string, dest interface{}, fields ...string) error
+	HMSet(key string, hash map[string]string) (string, error)
+	HPExpire(key string, milliseconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error)
+	HPTtl(key string, fields ...string) ([]int64, error)
+	HPersist(key string, fields ...string) ([]int64, error)
+	HScan(key, cursor string, params ...*ScanParams) (*ScanResult, error)
+	HSet(key, field, value string) (int64, error)
+	HSetNx(key, field, value string) (int64, error)
+	HTtl(key string, fields ...string) ([]int64, error)
+	HVals(key string) ([]string, error)
+}
+
+//ListCommands commands of lists
+type ListCommands interface {
+	BLPop(args ...string) ([]string, error)
+	BLMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedElements, error)
+	BLPopTimeout(timeout int, keys ...string) ([]string, error)
+	BRPop(args ...string) ([]string, error)
+	BRPopTimeout(timeout int, keys ...string) ([]string, error)
+	BRPopLPush(srcKey, destKey string, timeout int) (string, error)
+	LIndex(key string, index int64) (string, error)
+	LInsert(key string, where *ListOption, pivot, value string) (int64, error)
+	LLen(key string) (int64, error)
+	LPop(key string) (string, error)
+	LPopCount(key string, count int64) ([]string, error)
+	LPos(key, element string, params ...*LPosParams) (int64, error)
+	LPosCount(key, element string, count int64, params ...*LPosParams) ([]int64, error)
+	LPush(key string, members ...string) (int64, error)
+	LPushX(key string, members ...string) (int64, error)
+	LRange(key string, start, stop int64) ([]string, error)
+	LRangeScan(key string, start, stop int64, dest interface{}) error
+	LRem(key string, count int64, value string) (int64, error)
+	LSet(key string, index int64, value string) (string, error)
+	LTrim(key string, start, stop int64) (string, error)
+	RPop(key string) (string, error)
+	RPopCount(key string, count int64) ([]string, error)
+	RPopLPush(srcKey, destKey string) (string, error)
+	RPush(key string, members ...string) (int64, error)
+	RPushX(key string, members ...string) (int64, error)
+}
+
+//SetCommands commands of sets
+type SetCommands interface {
+	SAdd(key string, members ...string) (int64, error)
+	SCard(key string) (int64, error)
+	SDiff(keys ...string) ([]string, error)
+	SDiffStore(destKey string, srcKeys ...string) (int64, error)
+	SInter(keys ...string) ([]string, error)
+	SInterStore(destKey string, srcKeys ...string) (int64, error)
+	SInterCard(limit int64, keys ...string) (int64, error)
+	SIsMember(key, member string) (bool, error)
+	SMembers(key string) ([]string, error)
+	SMembersMap(key string) (map[string]struct{}, error)
+	SMembersScan(key string, dest interface{}) error
+	SMove(srcKey, destKey, member string) (int64, error)
+	SPop(key string) (string, error)
+	SPopBatch(key string, count int64) ([]string, error)
+	SRandMember(key string) (string, error)
+	SRandMemberBatch(key string, count int) ([]string, error)
+	SRem(key string, members ...string) (int64, error)
+	SScan(key, cursor string, params ...*ScanParams) (*ScanResult, error)
+	SUnion(keys ...string) ([]string, error)
+	SUnionStore(destKey string, srcKeys ...string) (int64, error)
+}
+
+//SortedSetCommands commands of sorted sets
+type SortedSetCommands interface {
+	BZPopMax(timeout int, keys ...string) (*KeyedTuple, error)
+	BZPopMin(timeout int, keys ...string) (*KeyedTuple, error)
+	BZMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedTuples, error)
+	ZAdd(key string, score float64, member string, params ...*ZAddParams) (int64, error)
+	ZAddByMap(key string, scoreMembers map[string]float64, params ...*ZAddParams) (int64, error)
+	ZCard(key string) (int64, error)
+	ZCount(key string, min, max float64) (int64, error)
+	ZDiff(keys ...string) ([]string, error)
+	ZDiffStore(destKey string, srcKeys ...string) (int64, error)
+	ZDiffWithScores(keys ...string) ([]Tuple, error)
+	ZIncrBy(key string, increment float64, member string, params ...*ZAddParams) (float64, error)
+	ZInter(params *ZParams, keys ...string) ([]string, error)
+	ZInterCard(limit int64, keys ...string) (int64, error)
+	ZInterStore(destKey string, srcKeys ...string) (int64, error)
+	ZInterStoreWithParams(destKey string, params *ZParams, srcKeys ...string) (int64, error)
+	ZInterWithScores(params *ZParams, keys ...string) ([]Tuple, error)
+	ZLexCount(key, min, max string) (int64, error)
+	ZPopMax(key string, count ...int64) ([]Tuple, error)
+	ZPopMin(key string, count ...int64) ([]Tuple, error)
+	ZRange(key string, start, stop int64) ([]string, error)
+	ZRangeByLex(key, min, max string) ([]string, error)
+	ZRangeByLexBatch(key, min, max string, offset, count int) ([]string, error)
+	ZRangeByScore(key string, min, max float64) ([]string, error)
+	ZRangeByScoreBatch(key string, min, max float64, offset, count int) ([]string, error)
+	ZRangeByScoreWithScores(key string, min, max float64) ([]Tuple, error)
+	ZRangeByScoreWithScoresBatch(key string, min, max float64, offset, count int) ([]Tuple, error)
+	ZRangeWithScores(key string, start, end int64) ([]Tuple, error)
+	ZRank(key, member string) (int64, error)
+	ZRem(key string, members ...string) (int64, error)
+	ZRemRangeByLex(key, min, max string) (int64, error)
+	ZRemRangeByRank(key string, start, stop int64) (int64, error)
+	ZRemRangeByScore(key string, min, max float64) (int64, error)
+	ZRevRange(key string, start, stop int64) ([]string, error)
+	ZRevRangeByLex(key, max, min string) ([]string, error)
+	ZRevRangeByLexBatch(key, max, min string, offset, count int) ([]string, error)
+	ZRevRangeByScore(key string, max, min float64) ([]string, error)
+	ZRevRangeByScoreWithScores(key string, max, min float64) ([]Tuple, error)
+	ZRevRangeByScoreWithScoresBatch(key string, max, min float64, offset, count int) ([]Tuple, error)
+	ZRevRangeWithScores(key string, start, end int64) ([]Tuple, error)
+	ZRevRank(key, member string) (int64, error)
+	ZScan(key, cursor string, params ...*ScanParams) (*ScanResult, error)
+	ZScore(key, member string) (float64, error)
+	ZUnion(params *ZParams, keys ...string) ([]string, error)
+	ZUnionStore(destKey string, srcKeys ...string) (int64, error)
+	ZUnionStoreWithParams(destKey string, params *ZParams, srcKeys ...string) (int64, error)
+	ZUnionWithScores(params *ZParams, keys ...string) ([]Tuple, error)
+}
+
+//GeoCommands commands of geospatial indexes
+type GeoCommands interface {
+	GeoAdd(key string, longitude, latitude float64, member string) (int64, error)
+	GeoAddByMap(key string, memberCoordinateMap map[string]GeoCoordinate) (int64, error)
+	GeoDist(key, member1, member2 string, unit ...*GeoUnit) (float64, error)
+	GeoHash(key string, members ...string) ([]string, error)
+	GeoPos(key string, members ...string) ([]*GeoCoordinate, error)
+	GeoRadius(key string, longitude, latitude, radius float64, unit *GeoUnit, param ...*GeoRadiusParams) ([]GeoRadiusResponse, error)
+	GeoRadiusByMember(key, member string, radius float64, unit *GeoUnit, param ...*GeoRadiusParams) ([]GeoRadiusResponse, error)
+}
+
+//HyperLogLogCommands commands of hyperloglogs
+type HyperLogLogCommands interface {
+	PfAdd(key string, elements ...string) (int64, error)
+	PfCount(keys ...string) (int64, error)
+	PfMerge(destKey string, srcKeys ...string) (string, error)
+	PfDebugGetReg(key string) ([]int64, error)
+	PfDebugEncoding(key string) (string, error)
+}
+
+//StreamCommands commands of streams
+type StreamCommands interface {
+	XAck(key, group string, ids ...string) (int64, error)
+	XAdd(key, id string, hash map[string]string) (string, error)
+	XAutoClaim(key, group, consumer string, minIdle time.Duration, start string, count int) (*StreamClaimResult, error)
+	XDel(key string, ids ...string) (int64, error)
+	XGroupCreate(key, group, id string, mkStream bool) (string, error)
+	XLen(key string) (int64, error)
+	XPending(key, group, start, end string, count int64, params ...*XPendingParams) ([]StreamPendingEntry, error)
+	XReadGroup(group, consumer string, count int, block time.Duration, streams ...string) ([]StreamEntries, error)
+}
+
+//ScriptCommands commands of lua scripts
+type ScriptCommands interface {
+	Eval(script string, keyCount int, params ...string) (interface{}, error)
+	EvalSha(sha1 string, keyCount int, params ...string) (interface{}, error)
+	EvalRo(script string, keyCount int, params ...string) (interface{}, error)
+	EvalShaRo(sha1 string, keyCount int, params ...string) (interface{}, error)
+}
+
+//PubSubCommands commands of pub/sub
+type PubSubCommands interface {
+	Publish(channel, message string) (int64, error)
+	Subscribe(redisPubSub *RedisPubSub, channels ...string) error
+	PSubscribe(redisPubSub *RedisPubSub, patterns ...string) error
+	SPublish(channel, message string) (int64, error)
+	SSubscribe(redisPubSub *RedisPubSub, channels ...string) error
+	PubSubShardChannels(pattern string) ([]string, error)
+	PubSubShardNumSub(channels ...string) (map[string]int64, error)
+}
+
+//UniversalClient the commands shared by the standalone and the cluster clients,
+//write the application against it,so the client can be swapped,such as by a mock in tests
+type UniversalClient interface {
+	KeyCommands
+	StringCommands
+	BitCommands
+	HashCommands
+	ListCommands
+	SetCommands
+	SortedSetCommands
+	GeoCommands
+	HyperLogLogCommands
+	StreamCommands
+	ScriptCommands
+	PubSubCommands
+}
+
+var (
+	_ UniversalClient = (*Redis)(nil)
+	_ UniversalClient = (*RedisCluster)(nil)
+	_ UniversalClient = (*MirroredRedis)(nil)
+)
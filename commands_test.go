@@ -0,0 +1,40 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+//countVisit application code written against the interfaces
+func countVisit(client UniversalClient, page string) (int64, error) {
+	if _, err := client.SAdd("pages", page); err != nil {
+		return 0, err
+	}
+	return client.HIncrBy("visits", page, 1)
+}
+
+func TestUniversalClient(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	count, err := countVisit(redis, "home")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+
+	secondary := *option
+	secondary.Db = 1
+	mirrored := NewMirroredRedis(option, &secondary, nil)
+	count, err = countVisit(mirrored, "home")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+	mirrored.Close()
+
+	var strings StringCommands = redis
+	_, err = strings.Set("godis", "good")
+	assert.Nil(t, err)
+	var keys KeyCommands = redis
+	c, err := keys.Exists("godis", "pages", "visits")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+	redis.FlushAll()
+}
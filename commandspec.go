@@ -0,0 +1,225 @@
+package godis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//CommandSpec the spec of a redis command,same as COMMAND INFO,
+//it is used to validate the arguments before sending and to find the keys of a command
+type CommandSpec struct {
+	Name     string
+	Arity    int  //the number of arguments including the command name,a negative arity means at least -Arity arguments
+	ReadOnly bool //the command never writes,so it can be served by a replica
+	FirstKey int  //position of the first key,0 means the command has no key
+	LastKey  int  //position of the last key,a negative position counts from the end,-1 is the last argument
+	KeyStep  int  //step between the keys,such as 2 for MSET
+	NumKeys  int  //position of the number of keys for the commands with movable keys,such as EVAL,0 means none
+
+	//executing the command twice leaves the same data as once,so it is safe to retry after an ambiguous failure,
+	//the reply of the retry may differ,such as SADD of an added member returns 0,all read only commands are idempotent
+	Idempotent bool
+}
+
+//commandSpecs the specs of the commands sent by this client,keyed by upper case name
+var commandSpecs = newCommandSpecs(
+	//keys
+	idempotent(spec("DEL", -2, false, 1, -1, 1)), idempotent(spec("UNLINK", -2, false, 1, -1, 1)),
+	spec("EXISTS", -2, true, 1, -1, 1), idempotent(spec("TOUCH", -2, false, 1, -1, 1)),
+	spec("TYPE", 2, true, 1, 1, 1), spec("KEYS", 2, true, 0, 0, 0), spec("RANDOMKEY", 1, true, 0, 0, 0),
+	spec("RENAME", 3, false, 1, 2, 1), spec("RENAMENX", 3, false, 1, 2, 1), spec("MOVE", 3, false, 1, 1, 1),
+	spec("EXPIRE", -3, false, 1, 1, 1), idempotent(spec("EXPIREAT", -3, false, 1, 1, 1)),
+	spec("PEXPIRE", -3, false, 1, 1, 1), idempotent(spec("PEXPIREAT", -3, false, 1, 1, 1)),
+	spec("TTL", 2, true, 1, 1, 1), spec("PTTL", 2, true, 1, 1, 1), idempotent(spec("PERSIST", 2, false, 1, 1, 1)),
+	spec("DUMP", 2, true, 1, 1, 1), spec("RESTORE", -4, false, 1, 1, 1),
+	spec("SORT", -2, false, 1, 1, 1), spec("SCAN", -2, true, 0, 0, 0), spec("OBJECT", -2, true, 2, 2, 1),
+	spec("MIGRATE", -6, false, 3, 3, 1),
+	//strings
+	idempotent(spec("SET", -3, false, 1, 1, 1)), spec("GET", 2, true, 1, 1, 1), spec("GETSET", 3, false, 1, 1, 1),
+	spec("GETDEL", 2, false, 1, 1, 1),
+	spec("MGET", -2, true, 1, -1, 1), spec("SETNX", 3, false, 1, 1, 1), idempotent(spec("SETEX", 4, false, 1, 1, 1)),
+	idempotent(spec("PSETEX", 4, false, 1, 1, 1)), idempotent(spec("MSET", -3, false, 1, -1, 2)), spec("MSETNX", -3, false, 1, -1, 2),
+	spec("DECRBY", 3, false, 1, 1, 1), spec("DECR", 2, false, 1, 1, 1), spec("INCRBY", 3, false, 1, 1, 1),
+	spec("INCR", 2, false, 1, 1, 1), spec("INCRBYFLOAT", 3, false, 1, 1, 1), spec("APPEND", 3, false, 1, 1, 1),
+	spec("SUBSTR", 4, true, 1, 1, 1), spec("STRLEN", 2, true, 1, 1, 1),
+	idempotent(spec("SETRANGE", 4, false, 1, 1, 1)), spec("GETRANGE", 4, true, 1, 1, 1),
+	spec("SETBIT", 4, false, 1, 1, 1), spec("GETBIT", 3, true, 1, 1, 1), spec("BITPOS", -3, true, 1, 1, 1),
+	spec("BITCOUNT", -2, true, 1, 1, 1), spec("BITOP", -4, false, 2, -1, 1), spec("BITFIELD", -2, false, 1, 1, 1),
+	spec("BITFIELD_RO", -2, true, 1, 1, 1),
+	//hashes
+	idempotent(spec("HSET", -4, false, 1, 1, 1)), spec("HGET", 3, true, 1, 1, 1), spec("HSETNX", 4, false, 1, 1, 1),
+	idempotent(spec("HMSET", -4, false, 1, 1, 1)), spec("HMGET", -3, true, 1, 1, 1), spec("HINCRBY", 4, false, 1, 1, 1),
+	spec("HINCRBYFLOAT", 4, false, 1, 1, 1), spec("HEXISTS", 3, true, 1, 1, 1), idempotent(spec("HDEL", -3, false, 1, 1, 1)),
+	spec("HLEN", 2, true, 1, 1, 1), spec("HKEYS", 2, true, 1, 1, 1), spec("HVALS", 2, true, 1, 1, 1),
+	spec("HGETALL", 2, true, 1, 1, 1), spec("HSTRLEN", 3, true, 1, 1, 1), spec("HSCAN", -3, true, 1, 1, 1),
+	spec("HEXPIRE", -6, false, 1, 1, 1), spec("HPEXPIRE", -6, false, 1, 1, 1), idempotent(spec("HEXPIREAT", -6, false, 1, 1, 1)),
+	idempotent(spec("HPERSIST", -5, false, 1, 1, 1)), spec("HTTL", -5, true, 1, 1, 1), spec("HPTTL", -5, true, 1, 1, 1),
+	//lists
+	spec("RPUSH", -3, false, 1, 1, 1), spec("LPUSH", -3, false, 1, 1, 1),
+	spec("RPUSHX", -3, false, 1, 1, 1), spec("LPUSHX", -3, false, 1, 1, 1),
+	spec("LLEN", 2, true, 1, 1, 1), spec("LRANGE", 4, true, 1, 1, 1), spec("LTRIM", 4, false, 1, 1, 1),
+	spec("LINDEX", 3, true, 1, 1, 1), idempotent(spec("LSET", 4, false, 1, 1, 1)), spec("LREM", 4, false, 1, 1, 1),
+	spec("LPOS", -3, true, 1, 1, 1), spec("LINSERT", 5, false, 1, 1, 1),
+	spec("LPOP", -2, false, 1, 1, 1), spec("RPOP", -2, false, 1, 1, 1), spec("RPOPLPUSH", 3, false, 1, 2, 1),
+	spec("BLPOP", -3, false, 1, -2, 1), spec("BRPOP", -3, false, 1, -2, 1), spec("BRPOPLPUSH", 4, false, 1, 2, 1),
+	movable("LMPOP", -4, false, 0, 1), movable("BLMPOP", -5, false, 0, 2),
+	//sets
+	idempotent(spec("SADD", -3, false, 1, 1, 1)), spec("SMEMBERS", 2, true, 1, 1, 1), idempotent(spec("SREM", -3, false, 1, 1, 1)),
+	spec("SPOP", -2, false, 1, 1, 1), spec("SMOVE", 4, false, 1, 2, 1), spec("SCARD", 2, true, 1, 1, 1),
+	spec("SISMEMBER", 3, true, 1, 1, 1), spec("SRANDMEMBER", -2, true, 1, 1, 1), spec("SSCAN", -3, true, 1, 1, 1),
+	spec("SINTER", -2, true, 1, -1, 1), spec("SINTERSTORE", -3, false, 1, -1, 1),
+	movable("SINTERCARD", -3, true, 0, 1),
+	spec("SUNION", -2, true, 1, -1, 1), spec("SUNIONSTORE", -3, false, 1, -1, 1),
+	spec("SDIFF", -2, true, 1, -1, 1), spec("SDIFFSTORE", -3, false, 1, -1, 1),
+	//sorted sets
+	spec("ZADD", -4, false, 1, 1, 1), spec("ZRANGE", -4, true, 1, 1, 1), spec("ZREVRANGE", -4, true, 1, 1, 1),
+	idempotent(spec("ZREM", -3, false, 1, 1, 1)), spec("ZINCRBY", 4, false, 1, 1, 1), spec("ZCARD", 2, true, 1, 1, 1),
+	spec("ZRANK", -3, true, 1, 1, 1), spec("ZREVRANK", -3, true, 1, 1, 1), spec("ZSCORE", 3, true, 1, 1, 1),
+	spec("ZCOUNT", 4, true, 1, 1, 1), spec("ZLEXCOUNT", 4, true, 1, 1, 1),
+	spec("ZRANGEBYSCORE", -4, true, 1, 1, 1), spec("ZREVRANGEBYSCORE", -4, true, 1, 1, 1),
+	spec("ZRANGEBYLEX", -4, true, 1, 1, 1), spec("ZREVRANGEBYLEX", -4, true, 1, 1, 1),
+	spec("ZREMRANGEBYRANK", 4, false, 1, 1, 1), spec("ZREMRANGEBYSCORE", 4, false, 1, 1, 1),
+	spec("ZREMRANGEBYLEX", 4, false, 1, 1, 1), spec("ZSCAN", -3, true, 1, 1, 1),
+	spec("ZPOPMIN", -2, false, 1, 1, 1), spec("ZPOPMAX", -2, false, 1, 1, 1),
+	spec("BZPOPMIN", -3, false, 1, -2, 1), spec("BZPOPMAX", -3, false, 1, -2, 1),
+	movable("ZMPOP", -4, false, 0, 1), movable("BZMPOP", -5, false, 0, 2),
+	movable("ZUNIONSTORE", -4, false, 1, 2), movable("ZINTERSTORE", -4, false, 1, 2), movable("ZDIFFSTORE", -4, false, 1, 2),
+	movable("ZUNION", -3, true, 0, 1), movable("ZINTER", -3, true, 0, 1), movable("ZDIFF", -3, true, 0, 1),
+	movable("ZINTERCARD", -3, true, 0, 1),
+	//hyperloglog and geo
+	spec("PFADD", -2, false, 1, 1, 1), spec("PFCOUNT", -2, true, 1, -1, 1), spec("PFMERGE", -2, false, 1, -1, 1),
+	spec("PFDEBUG", 3, false, 2, 2, 1),
+	spec("GEOADD", -5, false, 1, 1, 1), spec("GEODIST", -4, true, 1, 1, 1),
+	spec("GEOHASH", -2, true, 1, 1, 1), spec("GEOPOS", -2, true, 1, 1, 1),
+	spec("GEORADIUS", -6, false, 1, 1, 1), spec("GEORADIUS_RO", -6, true, 1, 1, 1),
+	spec("GEORADIUSBYMEMBER", -5, false, 1, 1, 1), spec("GEORADIUSBYMEMBER_RO", -5, true, 1, 1, 1),
+	//streams
+	spec("XADD", -5, false, 1, 1, 1), spec("XLEN", 2, true, 1, 1, 1), spec("XDEL", -3, false, 1, 1, 1),
+	spec("XTRIM", -4, false, 1, 1, 1), spec("XRANGE", -4, true, 1, 1, 1), spec("XREVRANGE", -4, true, 1, 1, 1),
+	spec("XREAD", -4, true, 0, 0, 0), spec("XREADGROUP", -7, false, 0, 0, 0),
+	idempotent(spec("XACK", -4, false, 1, 1, 1)), spec("XGROUP", -2, false, 2, 2, 1), spec("XPENDING", -3, true, 1, 1, 1),
+	spec("XCLAIM", -6, false, 1, 1, 1), spec("XAUTOCLAIM", -6, false, 1, 1, 1),
+	//scripts
+	movable("EVAL", -3, false, 0, 2), movable("EVALSHA", -3, false, 0, 2),
+	movable("EVAL_RO", -3, true, 0, 2), movable("EVALSHA_RO", -3, true, 0, 2), spec("SCRIPT", -2, false, 0, 0, 0),
+	//transactions and pub/sub
+	spec("MULTI", 1, false, 0, 0, 0), spec("EXEC", 1, false, 0, 0, 0), spec("DISCARD", 1, false, 0, 0, 0),
+	idempotent(spec("WATCH", -2, false, 1, -1, 1)), idempotent(spec("UNWATCH", 1, false, 0, 0, 0)),
+	spec("PUBLISH", 3, false, 0, 0, 0), spec("SUBSCRIBE", -2, false, 0, 0, 0), spec("UNSUBSCRIBE", -1, false, 0, 0, 0),
+	spec("PSUBSCRIBE", -2, false, 0, 0, 0), spec("PUNSUBSCRIBE", -1, false, 0, 0, 0),
+	spec("SPUBLISH", 3, false, 1, 1, 1), spec("SSUBSCRIBE", -2, false, 1, -1, 1), spec("SUNSUBSCRIBE", -1, false, 1, -1, 1),
+	spec("PUBSUB", -2, false, 0, 0, 0),
+	//connection and server
+	idempotent(spec("PING", -1, false, 0, 0, 0)), idempotent(spec("ECHO", 2, false, 0, 0, 0)), spec("QUIT", -1, false, 0, 0, 0),
+	spec("AUTH", -2, false, 0, 0, 0), idempotent(spec("SELECT", 2, false, 0, 0, 0)), spec("SWAPDB", 3, false, 0, 0, 0),
+	spec("RESET", 1, false, 0, 0, 0), spec("CLIENT", -2, false, 0, 0, 0), idempotent(spec("READONLY", 1, false, 0, 0, 0)),
+	idempotent(spec("ASKING", 1, false, 0, 0, 0)), spec("DBSIZE", 1, true, 0, 0, 0), idempotent(spec("FLUSHDB", -1, false, 0, 0, 0)),
+	idempotent(spec("FLUSHALL", -1, false, 0, 0, 0)), spec("SAVE", 1, false, 0, 0, 0), spec("BGSAVE", -1, false, 0, 0, 0),
+	spec("BGREWRITEAOF", 1, false, 0, 0, 0), idempotent(spec("LASTSAVE", 1, false, 0, 0, 0)), spec("SHUTDOWN", -1, false, 0, 0, 0),
+	idempotent(spec("INFO", -1, false, 0, 0, 0)), spec("MONITOR", 1, false, 0, 0, 0), spec("SLAVEOF", 3, false, 0, 0, 0),
+	spec("CONFIG", -2, false, 0, 0, 0), spec("SYNC", 1, false, 0, 0, 0), spec("PSYNC", -3, false, 0, 0, 0), spec("DEBUG", -2, false, 0, 0, 0),
+	spec("SLOWLOG", -2, false, 0, 0, 0), idempotent(spec("TIME", 1, false, 0, 0, 0)), spec("WAIT", 3, false, 0, 0, 0),
+	spec("CLUSTER", -2, false, 0, 0, 0), spec("SENTINEL", -2, false, 0, 0, 0), spec("MODULE", -2, false, 0, 0, 0),
+	spec("MEMORY", -2, false, 0, 0, 0), spec("LOLWUT", -1, false, 0, 0, 0),
+)
+
+func spec(name string, arity int, readOnly bool, firstKey, lastKey, keyStep int) *CommandSpec {
+	return &CommandSpec{Name: name, Arity: arity, ReadOnly: readOnly, Idempotent: readOnly, FirstKey: firstKey, LastKey: lastKey, KeyStep: keyStep}
+}
+
+//idempotent mark the command idempotent,such as SET and DEL
+func idempotent(s *CommandSpec) *CommandSpec {
+	s.Idempotent = true
+	return s
+}
+
+//movable spec of a command whose keys follow the number of keys at numKeys,such as EVAL,
+//firstKey is the position of the key before the number of keys,such as the destination of ZUNIONSTORE
+func movable(name string, arity int, readOnly bool, firstKey, numKeys int) *CommandSpec {
+	return &CommandSpec{Name: name, Arity: arity, ReadOnly: readOnly, Idempotent: readOnly, FirstKey: firstKey, LastKey: firstKey, KeyStep: 1, NumKeys: numKeys}
+}
+
+func newCommandSpecs(specs ...*CommandSpec) map[string]*CommandSpec {
+	m := make(map[string]*CommandSpec, len(specs))
+	for _, s := range specs {
+		m[s.Name] = s
+	}
+	return m
+}
+
+//LookupCommandSpec find the spec of a command by name,names are case insensitive
+func LookupCommandSpec(name string) (*CommandSpec, bool) {
+	s, ok := commandSpecs[strings.ToUpper(name)]
+	return s, ok
+}
+
+//IsIdempotentCommand whether executing the command twice leaves the same data as once,
+//such commands are retried after ambiguous connection failures,unknown commands are not idempotent
+func IsIdempotentCommand(command string) bool {
+	spec, ok := LookupCommandSpec(command)
+	return ok && spec.Idempotent
+}
+
+//CheckArity check the number of arguments,excluding the command name
+func (s *CommandSpec) CheckArity(argCount int) error {
+	n := argCount + 1
+	if (s.Arity > 0 && n != s.Arity) || (s.Arity < 0 && n < -s.Arity) {
+		return newDataError(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(s.Name)))
+	}
+	return nil
+}
+
+//Keys the keys in args,args exclude the command name
+func (s *CommandSpec) Keys(args ...string) []string {
+	indexes := s.keyIndexes(args)
+	keys := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		keys = append(keys, args[i])
+	}
+	return keys
+}
+
+//keyIndexes the positions of the keys in args,args exclude the command name
+func (s *CommandSpec) keyIndexes(args []string) []int {
+	indexes := make([]int, 0)
+	if s.Name == "XREAD" || s.Name == "XREADGROUP" {
+		//the keys are the first half of the arguments after STREAMS
+		for i, arg := range args {
+			if strings.EqualFold(arg, keywordStreams.name) {
+				n := (len(args) - i - 1) / 2
+				for j := i + 1; j <= i+n; j++ {
+					indexes = append(indexes, j)
+				}
+				return indexes
+			}
+		}
+		return indexes
+	}
+	if s.FirstKey > 0 && s.FirstKey <= len(args) {
+		last := s.LastKey
+		if last < 0 {
+			last += len(args) + 1
+		}
+		if last > len(args) {
+			last = len(args)
+		}
+		for i := s.FirstKey; i <= last; i += s.KeyStep {
+			indexes = append(indexes, i-1)
+		}
+	}
+	if s.NumKeys > 0 && s.NumKeys <= len(args) {
+		n, err := strconv.Atoi(args[s.NumKeys-1])
+		if err != nil || n < 0 {
+			return indexes
+		}
+		end := s.NumKeys + n
+		if end > len(args) {
+			end = len(args)
+		}
+		for i := s.NumKeys; i < end; i++ {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
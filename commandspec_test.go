@@ -0,0 +1,101 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCommandSpec_CheckArity(t *testing.T) {
+	spec, ok := LookupCommandSpec("get")
+	assert.True(t, ok)
+	assert.Equal(t, 2, spec.Arity)
+	assert.True(t, spec.ReadOnly)
+	assert.Nil(t, spec.CheckArity(1))
+	assert.NotNil(t, spec.CheckArity(0))
+	assert.NotNil(t, spec.CheckArity(2))
+
+	spec, _ = LookupCommandSpec("MSET")
+	assert.Nil(t, spec.CheckArity(2))
+	assert.Nil(t, spec.CheckArity(4))
+	assert.Equal(t, "ERR wrong number of arguments for 'mset' command", spec.CheckArity(1).Error())
+
+	_, ok = LookupCommandSpec("NOTACOMMAND")
+	assert.False(t, ok)
+
+	//malformed calls fail before they are sent,so the unreachable redis is never dialed
+	redis := NewRedis(&Option{Host: "localhost", Port: 6380})
+	defer redis.Close()
+	_, err := redis.MGet()
+	assert.Equal(t, "ERR wrong number of arguments for 'mget' command", err.Error())
+	_, err = redis.Del()
+	assert.IsType(t, &DataError{}, err)
+	err = redis.SendByStr("get")
+	assert.IsType(t, &DataError{}, err)
+	err = redis.SendByStr("get", []byte("godis"))
+	assert.IsType(t, &ConnectError{}, err)
+}
+
+func TestCommandSpec_Keys(t *testing.T) {
+	keys := func(command string, args ...string) []string {
+		spec, ok := LookupCommandSpec(command)
+		assert.True(t, ok)
+		return spec.Keys(args...)
+	}
+	assert.Equal(t, []string{"a"}, keys("SET", "a", "1", "EX", "10"))
+	assert.Equal(t, []string{"a", "b"}, keys("MSET", "a", "1", "b", "2"))
+	assert.Equal(t, []string{"a", "b"}, keys("DEL", "a", "b"))
+	assert.Equal(t, []string{"a", "b"}, keys("BLPOP", "a", "b", "0"))
+	assert.Equal(t, []string{"a", "b"}, keys("RPOPLPUSH", "a", "b"))
+	assert.Equal(t, []string{"dest", "a", "b"}, keys("BITOP", "AND", "dest", "a", "b"))
+	assert.Equal(t, []string{"a", "b"}, keys("EVAL", "return 1", "2", "a", "b", "arg"))
+	assert.Equal(t, []string{"dest", "a", "b"}, keys("ZUNIONSTORE", "dest", "2", "a", "b", "WEIGHTS", "1", "2"))
+	assert.Equal(t, []string{"a", "b"}, keys("ZDIFF", "2", "a", "b"))
+	assert.Equal(t, []string{"a", "b"}, keys("XREADGROUP", "GROUP", "g", "c", "COUNT", "1", "STREAMS", "a", "b", ">", ">"))
+	assert.Equal(t, []string{}, keys("PING"))
+	assert.Equal(t, []string{}, keys("EVAL", "return 1", "x"))
+	assert.True(t, IsReadOnlyCommand("xrange"))
+	assert.False(t, IsReadOnlyCommand("xadd"))
+	assert.False(t, IsReadOnlyCommand("notacommand"))
+}
+
+func TestCommandSpec_Idempotent(t *testing.T) {
+	assert.True(t, IsIdempotentCommand("get"))
+	assert.True(t, IsIdempotentCommand("LRANGE"))
+	assert.True(t, IsIdempotentCommand("SET"))
+	assert.True(t, IsIdempotentCommand("DEL"))
+	assert.False(t, IsIdempotentCommand("INCR"))
+	assert.False(t, IsIdempotentCommand("LPUSH"))
+	assert.False(t, IsIdempotentCommand("EXPIRE"))
+	assert.False(t, IsIdempotentCommand("notacommand"))
+
+	//the fake server never replies,so the commands fail after they are sent
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{})
+	defer closeServer()
+	fakeOption.SoTimeout = 100 * time.Millisecond
+	redis := NewRedis(fakeOption)
+	_, err := redis.Incr("godis")
+	assert.IsType(t, &ConnectError{}, err)
+	assert.True(t, redis.client.ambiguous())
+	redis.Close()
+
+	redis = NewRedis(fakeOption)
+	_, err = redis.Get("godis")
+	assert.IsType(t, &ConnectError{}, err)
+	assert.False(t, redis.client.ambiguous())
+	redis.Close()
+
+	//the command is never sent if the connection can't be established
+	redis = NewRedis(&Option{Host: "localhost", Port: 6380})
+	_, err = redis.Incr("godis")
+	assert.NotNil(t, err)
+	assert.False(t, redis.client.ambiguous())
+	redis.Close()
+
+	flushAll()
+	redis = NewRedis(option)
+	defer redis.Close()
+	_, err = redis.Incr("godis")
+	assert.Nil(t, err)
+	assert.False(t, redis.client.ambiguous())
+}
@@ -0,0 +1,100 @@
+package godis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+//ConfigSnapshot the config of a redis instance at a moment
+type ConfigSnapshot struct {
+	Name   string            //instance name,host:port of the host queried by default
+	Time   time.Time         //when the snapshot was taken
+	Config map[string]string //parameter -> value
+}
+
+//TakeConfigSnapshot capture CONFIG GET * of the instance
+func TakeConfigSnapshot(redis *Redis) (*ConfigSnapshot, error) {
+	config, err := redis.ConfigGetMap("*")
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigSnapshot{
+		Name:   redis.client.addr(),
+		Time:   time.Now(),
+		Config: config,
+	}, nil
+}
+
+//ConfigValue value of a parameter in one snapshot
+type ConfigValue struct {
+	Value  string
+	Exists bool //false if the snapshot has no such parameter
+}
+
+//ConfigDiff a parameter whose value is not the same in all snapshots
+type ConfigDiff struct {
+	Parameter string
+	Values    []ConfigValue //in the same order as ConfigDiffReport.Snapshots
+}
+
+//ConfigDiffReport the config drift between snapshots
+type ConfigDiffReport struct {
+	Snapshots []*ConfigSnapshot
+	Diffs     []ConfigDiff //sorted by parameter
+}
+
+//DiffConfigSnapshots compare snapshots of multiple instances,or of one instance over time
+func DiffConfigSnapshots(snapshots ...*ConfigSnapshot) *ConfigDiffReport {
+	params := make(map[string]struct{})
+	for _, s := range snapshots {
+		for k := range s.Config {
+			params[k] = struct{}{}
+		}
+	}
+	names := make([]string, 0, len(params))
+	for k := range params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	report := &ConfigDiffReport{Snapshots: snapshots, Diffs: make([]ConfigDiff, 0)}
+	for _, name := range names {
+		values := make([]ConfigValue, 0, len(snapshots))
+		drift := false
+		for i, s := range snapshots {
+			v, ok := s.Config[name]
+			values = append(values, ConfigValue{Value: v, Exists: ok})
+			if i > 0 && values[i] != values[0] {
+				drift = true
+			}
+		}
+		if drift {
+			report.Diffs = append(report.Diffs, ConfigDiff{Parameter: name, Values: values})
+		}
+	}
+	return report
+}
+
+//HasDrift whether any parameter differs
+func (r *ConfigDiffReport) HasDrift() bool {
+	return len(r.Diffs) > 0
+}
+
+//String one line per parameter,such as: maxmemory: 127.0.0.1:6379=0 127.0.0.1:6380=(absent)
+func (r *ConfigDiffReport) String() string {
+	var b strings.Builder
+	for _, d := range r.Diffs {
+		b.WriteString(d.Parameter)
+		b.WriteString(":")
+		for i, v := range d.Values {
+			value := "(absent)"
+			if v.Exists {
+				value = fmt.Sprintf("%q", v.Value)
+			}
+			fmt.Fprintf(&b, " %s=%s", r.Snapshots[i].Name, value)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffConfigSnapshots(t *testing.T) {
+	s1 := &ConfigSnapshot{Name: "a", Config: map[string]string{"maxmemory": "0", "appendonly": "no", "port": "6379"}}
+	s2 := &ConfigSnapshot{Name: "b", Config: map[string]string{"maxmemory": "1gb", "appendonly": "no"}}
+	s3 := &ConfigSnapshot{Name: "c", Config: map[string]string{"maxmemory": "0", "appendonly": "no", "port": "6379"}}
+
+	report := DiffConfigSnapshots(s1, s3)
+	assert.False(t, report.HasDrift())
+	assert.Equal(t, "", report.String())
+
+	report = DiffConfigSnapshots(s1, s2, s3)
+	assert.True(t, report.HasDrift())
+	assert.Equal(t, []ConfigDiff{
+		{Parameter: "maxmemory", Values: []ConfigValue{{"0", true}, {"1gb", true}, {"0", true}}},
+		{Parameter: "port", Values: []ConfigValue{{"6379", true}, {"", false}, {"6379", true}}},
+	}, report.Diffs)
+	assert.Equal(t, "maxmemory: a=\"0\" b=\"1gb\" c=\"0\"\nport: a=\"6379\" b=(absent) c=\"6379\"\n", report.String())
+}
+
+func TestTakeConfigSnapshot(t *testing.T) {
+	redis := NewRedis(option)
+	defer redis.Close()
+	snapshot, err := TakeConfigSnapshot(redis)
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:6379", snapshot.Name)
+	assert.NotEmpty(t, snapshot.Config)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = TakeConfigSnapshot(redisBroken)
+	assert.NotNil(t, err)
+}
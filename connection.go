@@ -2,21 +2,125 @@ package godis
 
 import (
 	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+//connStats counters of a connection,updated atomically so they can be read while the connection is used
+type connStats struct {
+	commands     int64
+	replies      int64
+	flushes      int64
+	bytesWritten int64
+	bytesRead    int64
+}
+
+//countingReader count the bytes read from the socket
+type countingReader struct {
+	reader io.Reader
+	count  *int64
+}
+
+func (r countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
+
+//DisconnectPolicy decides what happens to a command when redis is unreachable
+type DisconnectPolicy int
+
+const (
+	//FailFast return ErrDisconnected immediately, this is the default
+	FailFast DisconnectPolicy = iota
+	//QueueUntilReconnect queue the commands in order while redis is unreachable,at most MaxQueueSize of them,
+	//they are sent on reconnect when a reply is read,a command queued longer than QueueTTL is dropped,
+	//the commands dropped or not queued because the queue is full fail with ErrQueueFull
+	QueueUntilReconnect
+	//BlockUntilReconnect block the command until reconnected or BlockTimeout expires
+	BlockUntilReconnect
+)
+
+const (
+	defaultMaxQueueSize = 1000
+	reconnectInterval   = 100 * time.Millisecond
+)
+
 type connection struct {
 	host              string
 	port              int
 	connectionTimeout time.Duration
 	soTimeout         time.Duration
+	writeTimeout      time.Duration
+	keepAlive         time.Duration
+	disableTCPNoDelay bool
+	network           string
+	tlsConfig         *tls.Config
+	dialFunc          DialFunc //dial instead of net.Dialer if not nil
+	breakOnReadOnly   bool     //mark the connection broken when redis replies READONLY
 
 	socket            net.Conn
+	socketMu          sync.Mutex //guards socket against closeSocket called by another goroutine
+	socketClosed      bool       //closeSocket was called,the connection is never dialed again
+	addr              string //address of the host dialed by the socket,one of the host list,or the unix socket path
 	protocol          *protocol
 	broken            bool
 	pipelinedCommands int
+
+	disconnectPolicy DisconnectPolicy
+	maxQueueSize     int
+	queueTTL         time.Duration
+	blockTimeout     time.Duration
+	queue            []*queuedCommand //commands waiting for reconnect,oldest first
+	queueExpired     int              //count of the commands expired in the queue,moved to expiredReplies once reconnected
+	expiredReplies   int              //count of the next replies which fail since their commands expired in the queue
+	expiredRead      bool             //the reply being read failed since its command expired in the queue
+	dialMu           sync.Mutex
+
+	handshake func() error //run on every new socket,such as AUTH and SELECT
+
+	returnErrNil bool //return ErrNil for nil replies
+
+	infiniteBlockingRead bool          //read the replies of blocking commands without deadline
+	blockingRead         time.Duration //extra read time of the running blocking command,negative means no deadline
+	subscribeTimeout     time.Duration //read timeout since the last reply in subscribe mode,negative means no deadline,0 means not subscribed
+	commandTimeout       time.Duration //time from sending a command to reading its reply,0 means no limit
+	commandDeadline      time.Time     //deadline of the commands waiting for replies,zero means none
+
+	profiler *KeyProfiler //sample the commands for hot keys,nil means disabled
+	samples  []keySample  //sampled commands waiting for replies,in the order of sending
+	sent     int64        //count of commands sent by the socket
+	replied  int64        //count of replies read from the socket
+	written  int64        //size of the commands encoded,to sync pipelines by size
+	stats    connStats    //counters since the connection is created,see Redis.Stats
+
+	unsafeSent int64 //sequence of the last command sent which isn't idempotent,0 if its reply was read
+
+	breaker *CircuitBreaker //fail fast instead of dialing while redis is down,nil means disabled
+
+	detectConcurrentUse bool  //fail the commands overlapping with the ones of another goroutine
+	users               int32 //count of the sends and reads in progress,more than 1 means concurrent use
+
+	mirror        func(spec *CommandSpec, args [][]byte) //called with every command flushed,used by MirroredRedis
+	mirrorPending []mirroredCommand                      //commands encoded but not flushed yet,passed to the mirror once flushed
+
+	keyMapper KeyMapper //transform the keys before encoding,nil means disabled
+
+	onClose       func() //called after an established socket is closed,see Option.OnConnectionClosed
+	closeNotified bool   //onClose was called for the current socket
+
+	slowThreshold time.Duration          //report the commands slower than it,0 means disabled
+	onSlowCommand func(slow SlowCommand) //called with the slow commands
+	poolWait      time.Duration          //time waited to borrow the connection from the pool,reported with the next command
 }
 
 func newConnection(host string, port int, connectionTimeout, soTimeout time.Duration) *connection {
@@ -38,6 +142,160 @@ func newConnection(host string, port int, connectionTimeout, soTimeout time.Dura
 		connectionTimeout: connectionTimeout,
 		soTimeout:         soTimeout,
 		broken:            false,
+		maxQueueSize:      defaultMaxQueueSize,
+		queueTTL:          defaultTimeout,
+		blockTimeout:      defaultTimeout,
+	}
+}
+
+//setTCPOptions set write timeout,keep-alive period and whether TCP_NODELAY is cleared,
+//0 write timeout means same as soTimeout,0 keep-alive means the default period,negative means disabled
+func (c *connection) setTCPOptions(writeTimeout, keepAlive time.Duration, disableTCPNoDelay bool) {
+	c.writeTimeout = writeTimeout
+	c.keepAlive = keepAlive
+	c.disableTCPNoDelay = disableTCPNoDelay
+}
+
+//setTransport dial by unix socket if network is unix,host is the socket path then,
+//use tls if tlsConfig is not nil
+func (c *connection) setTransport(network string, tlsConfig *tls.Config) {
+	c.network = network
+	c.tlsConfig = tlsConfig
+}
+
+//setIODeadline reads time out after soTimeout plus the block timeout of the running blocking command,
+//writes time out after writeTimeout
+func (c *connection) setIODeadline() error {
+	now := time.Now()
+	writeTimeout := c.writeTimeout
+	if writeTimeout <= 0 {
+		writeTimeout = c.soTimeout
+	}
+	writeDeadline := now.Add(writeTimeout)
+	if !c.commandDeadline.IsZero() && c.commandDeadline.Before(writeDeadline) {
+		writeDeadline = c.commandDeadline
+	}
+	//in subscribe mode the read deadline only moves when a reply is read,so pings can't hide a dead connection
+	if c.subscribeTimeout == 0 {
+		if err := c.socket.SetReadDeadline(c.replyDeadline(now)); err != nil {
+			return err
+		}
+	}
+	return c.socket.SetWriteDeadline(writeDeadline)
+}
+
+//startCommand set the deadline of the command about to be sent by the command timeout,
+//the commands sent before the earlier ones are replied,such as a pipeline,share the deadline of the first one
+func (c *connection) startCommand() {
+	if c.commandTimeout <= 0 {
+		c.commandDeadline = time.Time{}
+		return
+	}
+	if c.sent == c.replied || c.commandDeadline.IsZero() {
+		c.commandDeadline = time.Now().Add(c.commandTimeout)
+	}
+}
+
+//readDeadline the deadline of reading a reply from now,zero means no deadline
+func (c *connection) readDeadline(now time.Time) time.Time {
+	if c.subscribeTimeout > 0 {
+		return now.Add(c.subscribeTimeout)
+	}
+	if c.blockingRead < 0 || c.subscribeTimeout < 0 {
+		return time.Time{}
+	}
+	return now.Add(c.soTimeout + c.blockingRead)
+}
+
+//replyDeadline the deadline of reading a reply from now,capped by the deadline of the command,
+//a blocking command may wait for its block timeout beyond the command timeout
+func (c *connection) replyDeadline(now time.Time) time.Time {
+	readDeadline := c.readDeadline(now)
+	if c.subscribeTimeout == 0 && !c.commandDeadline.IsZero() && c.blockingRead >= 0 {
+		if deadline := c.commandDeadline.Add(c.blockingRead); deadline.Before(readDeadline) {
+			readDeadline = deadline
+		}
+	}
+	return readDeadline
+}
+
+//setReplyDeadline set the read deadline of the next reply,it is set once per reply instead of once per read of the socket
+func (c *connection) setReplyDeadline() error {
+	if c.socket == nil {
+		return nil
+	}
+	if err := c.socket.SetReadDeadline(c.replyDeadline(time.Now())); err != nil {
+		return wrapConnectError(err)
+	}
+	return nil
+}
+
+//interruptOnDone break the running read or write of the socket once ctx is done,
+//so the connection is broken instead of waiting out the timeout,call stop when the commands are done
+func (c *connection) interruptOnDone(ctx context.Context) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	socket := c.socket
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			socket.SetDeadline(time.Now())
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+	}
+}
+
+//setBlockingTimeout extend the read deadline by the block timeout of a blocking command until clearBlockingTimeout,
+//0 block timeout means blocking forever,so the replies are read without deadline
+func (c *connection) setBlockingTimeout(block time.Duration) {
+	if block <= 0 || c.infiniteBlockingRead {
+		c.blockingRead = -1
+		return
+	}
+	c.blockingRead = block
+}
+
+func (c *connection) clearBlockingTimeout() {
+	c.blockingRead = 0
+}
+
+//setSubscribeTimeout read the replies in subscribe mode with timeout since the last reply until clearSubscribeTimeout,
+//0 timeout means no deadline,so an idle subscription never times out
+func (c *connection) setSubscribeTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = -1
+	}
+	c.subscribeTimeout = timeout
+	if c.socket == nil {
+		return newConnectError("socket is closed")
+	}
+	if err := c.socket.SetReadDeadline(c.readDeadline(time.Now())); err != nil {
+		c.broken = true
+		return wrapConnectError(err)
+	}
+	return nil
+}
+
+func (c *connection) clearSubscribeTimeout() {
+	c.subscribeTimeout = 0
+}
+
+//setDisconnectPolicy set the behaviour of commands when redis is unreachable
+func (c *connection) setDisconnectPolicy(policy DisconnectPolicy, maxQueueSize int, queueTTL, blockTimeout time.Duration) {
+	c.disconnectPolicy = policy
+	if maxQueueSize > 0 {
+		c.maxQueueSize = maxQueueSize
+	}
+	if queueTTL > 0 {
+		c.queueTTL = queueTTL
+	}
+	if blockTimeout > 0 {
+		c.blockTimeout = blockTimeout
 	}
 }
 
@@ -51,7 +309,7 @@ func (c *connection) setTimeoutInfinite() error {
 	err := c.socket.SetDeadline(time.Time{})
 	if err != nil {
 		c.broken = true
-		return newConnectError(err.Error())
+		return wrapConnectError(err)
 	}
 	return nil
 }
@@ -64,21 +322,38 @@ func (c *connection) rollbackTimeout() error {
 	err := c.socket.SetDeadline(time.Now().Add(c.connectionTimeout))
 	if err != nil {
 		c.broken = true
-		return newConnectError(err.Error())
+		return wrapConnectError(err)
 	}
 	return nil
 }
 
 func (c *connection) resetPipelinedCount() {
 	c.pipelinedCommands = 0
+	c.queue = nil
+	c.queueExpired = 0
+	c.expiredReplies = 0
+	c.expiredRead = false
+	c.resetSamples()
 }
 
 func (c *connection) sendCommand(cmd protocolCommand, args ...[]byte) error {
-	err := c.connect()
+	if err := cmd.checkArity(len(args)); err != nil {
+		return err
+	}
+	queue, err := c.connectOrQueue()
 	if err != nil {
 		return err
 	}
-	if err := c.protocol.sendCommand(cmd.getRaw(), args...); err != nil {
+	if queue {
+		return c.queueCommand(cmd.name, cmd.spec, args)
+	}
+	//used after connecting,the handshake sends and reads its own commands
+	if err := c.beginUse(); err != nil {
+		c.endUse()
+		return err
+	}
+	defer c.endUse()
+	if err := c.encodeCommand(cmd.name, cmd.spec, args); err != nil {
 		return err
 	}
 	c.pipelinedCommands++
@@ -86,14 +361,72 @@ func (c *connection) sendCommand(cmd protocolCommand, args ...[]byte) error {
 }
 
 func (c *connection) sendCommandByStr(cmd string, args ...[]byte) error {
-	err := c.connect()
+	spec, _ := LookupCommandSpec(cmd)
+	queue, err := c.connectOrQueue()
+	if err != nil {
+		return err
+	}
+	if queue {
+		return c.queueCommand(cmd, spec, args)
+	}
+	//used after connecting,the handshake sends and reads its own commands
+	if err := c.beginUse(); err != nil {
+		c.endUse()
+		return err
+	}
+	defer c.endUse()
+	if err := c.encodeCommand(cmd, spec, args); err != nil {
+		return err
+	}
+	c.pipelinedCommands++
+	return nil
+}
+
+//encodeCommand transform the keys by the key mapper and encode the command into the output buffer
+func (c *connection) encodeCommand(name string, spec *CommandSpec, args [][]byte) error {
+	args = c.mapKeys(spec, args)
+	c.startCommand()
+	//held for the mirror before encoding,a command with large arguments is flushed while it is encoded
+	c.mirrorSent(spec, args)
+	if err := c.protocol.sendCommand(name, args...); err != nil {
+		return err
+	}
+	if profiled, tracked := c.countSent(spec); tracked {
+		c.addSample(name, spec, ByteArrArrToStrArr(args), profiled)
+	}
+	return nil
+}
+
+//sendCommandStr send command whose arguments are strings,they are encoded without conversion
+func (c *connection) sendCommandStr(cmd protocolCommand, args ...string) error {
+	if err := cmd.checkArity(len(args)); err != nil {
+		return err
+	}
+	queue, err := c.connectOrQueue()
 	if err != nil {
 		return err
 	}
-	if err := c.protocol.sendCommand([]byte(cmd), args...); err != nil {
+	if queue {
+		return c.queueCommand(cmd.name, cmd.spec, StrArrToByteArrArr(args))
+	}
+	//used after connecting,the handshake sends and reads its own commands
+	if err := c.beginUse(); err != nil {
+		c.endUse()
+		return err
+	}
+	defer c.endUse()
+	args = c.mapStrKeys(cmd.spec, args)
+	c.startCommand()
+	if c.mirror != nil {
+		c.mirrorSent(cmd.spec, StrArrToByteArrArr(args))
+	}
+	if err := c.protocol.sendStrCommand(cmd.name, args...); err != nil {
 		return err
 	}
 	c.pipelinedCommands++
+	if profiled, tracked := c.countSent(cmd.spec); tracked {
+		c.addSample(cmd.name, cmd.spec, args, profiled)
+	}
 	return nil
 }
 
@@ -101,105 +434,278 @@ func (c *connection) readProtocolWithCheckingBroken() (interface{}, error) {
 	if c.broken {
 		return nil, newConnectError("attempting to read from a broken connection")
 	}
+	if err := c.setReplyDeadline(); err != nil {
+		return nil, c.readError(err)
+	}
 	read, err := c.protocol.read()
 	if err == nil {
 		return read, nil
 	}
+	return nil, c.readError(err)
+}
+
+//beginRead use the connection to read a reply until finishReply,
+//if it is used by another goroutine,the use is ended at once and finishReply must not be called,
+//the reply being read isn't ours to count,
+//the queued commands are reconnected for before the use,since the handshake uses the connection too
+func (c *connection) beginRead() error {
+	if err := c.reconnectQueue(); err != nil {
+		return err
+	}
+	if err := c.beginUse(); err != nil {
+		c.endUse()
+		return err
+	}
+	return nil
+}
+
+//nextReply flush the pending commands and take the reply of the next one,like getOne
+func (c *connection) nextReply() error {
+	if err := c.flush(); err != nil {
+		return err
+	}
+	c.pipelinedCommands--
+	if err := c.expiredReply(); err != nil {
+		return err
+	}
+	if c.broken {
+		return newConnectError("attempting to read from a broken connection")
+	}
+	if err := c.setReplyDeadline(); err != nil {
+		return c.readError(err)
+	}
+	return nil
+}
+
+//readError mark the connection broken if reading the reply failed by err,
+//or redis replied READONLY with BreakOnReadOnly
+func (c *connection) readError(err error) error {
 	switch err.(type) {
 	case *ConnectError:
 		c.broken = true
+	case *ReadOnlyError:
+		if c.breakOnReadOnly {
+			c.broken = true
+		}
 	}
-	return nil, err
+	return err
 }
 
-func (c *connection) getStatusCodeReply() (string, error) {
-	reply, err := c.getOne()
+//getSyncPayload read the reply of PSYNC and the snapshot after it into w,
+//return the replication id and offset of the snapshot and the size of the snapshot
+func (c *connection) getSyncPayload(w io.Writer) (string, int64, int64, error) {
+	if err := c.beginRead(); err != nil {
+		return "", 0, 0, err
+	}
+	defer c.finishReply()
+	if err := c.nextReply(); err != nil {
+		return "", 0, 0, err
+	}
+	replID, offset, err := c.protocol.readFullResync()
+	if err != nil {
+		return "", 0, 0, c.readError(err)
+	}
+	n, err := c.protocol.readSyncPayload(w)
+	if err != nil {
+		return "", 0, n, c.readError(err)
+	}
+	return replID, offset, n, nil
+}
+
+//readReplicationCommand read the next command of the replication stream after the snapshot,
+//return the command and its size in the stream,which advances the replication offset
+func (c *connection) readReplicationCommand() ([]string, int64, error) {
+	if err := c.setReplyDeadline(); err != nil {
+		return nil, 0, c.readError(err)
+	}
+	start := c.protocol.is.position()
+	reply, err := c.protocol.read()
 	if err != nil {
+		return nil, 0, c.readError(err)
+	}
+	arr, ok := reply.([]interface{})
+	if !ok || len(arr) == 0 {
+		c.broken = true
+		return nil, 0, newProtocolError(fmt.Sprintf("unexpected replication stream:%v", reply))
+	}
+	command := make([]string, 0, len(arr))
+	for _, item := range arr {
+		b, _ := item.([]byte)
+		command = append(command, string(b))
+	}
+	return command, c.protocol.is.position() - start, nil
+}
+
+//sendReplicationAck acknowledge the replication offset to the master,it has no reply
+func (c *connection) sendReplicationAck(offset int64) error {
+	if err := c.protocol.sendCommand(cmdReplConf.name, []byte("ACK"), Int64ToByteArr(offset)); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+func (c *connection) getStatusCodeReply() (string, error) {
+	if err := c.beginRead(); err != nil {
 		return "", err
 	}
+	defer c.finishReply()
+	if err := c.nextReply(); err != nil {
+		return "", err
+	}
+	s, reply, err := c.protocol.readString()
+	if err != nil {
+		return "", c.readError(err)
+	}
 	if reply == nil {
-		return "", nil
+		return s, nil
 	}
-	switch t := reply.(type) {
-	case string:
-		return t, nil
-	case []byte:
-		return string(t), nil
-	default:
-		return "", newDataError(fmt.Sprintf("data error:%v", reply))
+	if isNilReply(reply) {
+		return "", c.nilReplyError()
 	}
+	return "", newDataError(fmt.Sprintf("data error:%v", reply))
 }
 
 func (c *connection) getBulkReply() (string, error) {
-	result, err := c.getBinaryBulkReply()
-	if err != nil {
+	if err := c.beginRead(); err != nil {
+		return "", err
+	}
+	defer c.finishReply()
+	if err := c.nextReply(); err != nil {
 		return "", err
 	}
-	return string(result), nil
+	s, reply, err := c.protocol.readString()
+	if err != nil {
+		return "", c.readError(err)
+	}
+	if reply == nil {
+		return s, nil
+	}
+	result, err := c.binaryBulkReply(reply)
+	return string(result), err
 }
 
 func (c *connection) getBinaryBulkReply() ([]byte, error) {
-	reply, err := c.getOne()
-	if err != nil {
+	if err := c.beginRead(); err != nil {
 		return nil, err
 	}
+	defer c.finishReply()
+	if err := c.nextReply(); err != nil {
+		return nil, err
+	}
+	b, reply, err := c.protocol.readBytes()
+	if err != nil {
+		return nil, c.readError(err)
+	}
 	if reply == nil {
-		return []byte{}, nil
+		return b, nil
 	}
-	switch reply.(type) {
+	return c.binaryBulkReply(reply)
+}
+
+//binaryBulkReply convert the reply which isn't a status or bulk reply
+func (c *connection) binaryBulkReply(reply interface{}) ([]byte, error) {
+	if isNilReply(reply) && c.returnErrNil {
+		return []byte{}, ErrNil
+	}
+	switch t := reply.(type) {
 	case []byte:
-		return reply.([]byte), nil
+		return t, nil
 	case []interface{}:
 		arr := make([]byte, 0)
-		for _, i := range reply.([]interface{}) {
-			arr = append(arr, i.(byte))
+		for _, i := range t {
+			b, ok := i.(byte)
+			if !ok {
+				return nil, newDataError(fmt.Sprintf("data error:%v", reply))
+			}
+			arr = append(arr, b)
 		}
 		return arr, nil
 	}
-	return reply.([]byte), nil
+	return nil, newDataError(fmt.Sprintf("data error:%v", reply))
 }
 
 func (c *connection) getIntegerReply() (int64, error) {
-	reply, err := c.getOne()
-	if err != nil {
+	if err := c.beginRead(); err != nil {
+		return 0, err
+	}
+	defer c.finishReply()
+	if err := c.nextReply(); err != nil {
 		return 0, err
 	}
+	n, reply, err := c.protocol.readInteger()
+	if err != nil {
+		return 0, c.readError(err)
+	}
 	if reply == nil {
-		return 0, nil
+		return n, nil
 	}
-	switch reply.(type) {
-	case int64:
-		return reply.(int64), nil
+	if isNilReply(reply) {
+		return -1, c.nilReplyError()
 	}
 	return -1, nil
 }
 
 func (c *connection) getMultiBulkReply() ([]string, error) {
-	reply, err := c.getBinaryMultiBulkReply()
-	if err != nil {
+	if err := c.beginRead(); err != nil {
+		return nil, err
+	}
+	defer c.finishReply()
+	if err := c.nextReply(); err != nil {
 		return nil, err
 	}
-	resp := make([]string, 0)
-	for _, r := range reply {
-		resp = append(resp, string(r))
+	arr, reply, err := c.protocol.readStringArray()
+	if err != nil {
+		return nil, c.readError(err)
+	}
+	if reply == nil {
+		return arr, nil
+	}
+	if _, err := c.binaryMultiBulkReply(reply); err != nil {
+		return nil, err
 	}
-	return resp, nil
+	return []string{}, nil
 }
 
-func (c *connection) getBinaryMultiBulkReply() ([][]byte, error) {
+func (c *connection) getMultiBulkSetReply() (map[string]struct{}, error) {
 	reply, err := c.getOne()
 	if err != nil {
 		return nil, err
 	}
 	if reply == nil {
-		return [][]byte{}, nil
+		return map[string]struct{}{}, nil
 	}
 	resp := reply.([]interface{})
-	arr := make([][]byte, 0)
+	set := make(map[string]struct{}, len(resp))
 	for _, res := range resp {
-		arr = append(arr, res.([]byte))
+		set[string(res.([]byte))] = struct{}{}
+	}
+	return set, nil
+}
+
+func (c *connection) getBinaryMultiBulkReply() ([][]byte, error) {
+	if err := c.beginRead(); err != nil {
+		return nil, err
+	}
+	defer c.finishReply()
+	if err := c.nextReply(); err != nil {
+		return nil, err
+	}
+	arr, reply, err := c.protocol.readBytesArray()
+	if err != nil {
+		return nil, c.readError(err)
 	}
-	return arr, nil
+	if reply == nil {
+		return arr, nil
+	}
+	return c.binaryMultiBulkReply(reply)
+}
+
+//binaryMultiBulkReply convert the reply which isn't a multi bulk reply
+func (c *connection) binaryMultiBulkReply(reply interface{}) ([][]byte, error) {
+	if isNilReply(reply) {
+		return [][]byte{}, c.nilReplyError()
+	}
+	return nil, newDataError(fmt.Sprintf("data error:%v", reply))
 }
 
 func (c *connection) getUnflushedObjectMultiBulkReply() ([]interface{}, error) {
@@ -218,10 +724,17 @@ func (c *connection) getRawObjectMultiBulkReply() ([]interface{}, error) {
 }
 
 func (c *connection) getObjectMultiBulkReply() ([]interface{}, error) {
+	if err := c.beginRead(); err != nil {
+		return nil, err
+	}
+	defer c.finishReply()
 	if err := c.flush(); err != nil {
 		return nil, err
 	}
 	c.pipelinedCommands--
+	if err := c.expiredReply(); err != nil {
+		return nil, err
+	}
 	return c.getRawObjectMultiBulkReply()
 }
 
@@ -245,11 +758,51 @@ func (c *connection) getIntegerMultiBulkReply() ([]int64, error) {
 	}
 }
 
+//getScanReply convert the reply into dest,return ErrNil if the reply is nil
+func (c *connection) getScanReply(dest interface{}) error {
+	reply, err := c.getOne()
+	if err != nil {
+		return err
+	}
+	if isNilReply(reply) {
+		return ErrNil
+	}
+	return ScanReply(reply, dest)
+}
+
+//isNilReply whether the reply is a nil bulk or nil multi bulk reply
+func isNilReply(reply interface{}) bool {
+	switch r := reply.(type) {
+	case nil:
+		return true
+	case []byte:
+		return r == nil
+	case []interface{}:
+		return r == nil
+	}
+	return false
+}
+
+//nilReplyError ErrNil if ReturnErrNil is enabled,otherwise nil
+func (c *connection) nilReplyError() error {
+	if c.returnErrNil {
+		return ErrNil
+	}
+	return nil
+}
+
 func (c *connection) getOne() (interface{}, error) {
+	if err := c.beginRead(); err != nil {
+		return nil, err
+	}
+	defer c.finishReply()
 	if err := c.flush(); err != nil {
 		return "", err
 	}
 	c.pipelinedCommands--
+	if err := c.expiredReply(); err != nil {
+		return nil, err
+	}
 	return c.readProtocolWithCheckingBroken()
 }
 
@@ -258,27 +811,228 @@ func (c *connection) getAll(expect ...int) (interface{}, error) {
 	if len(expect) > 0 {
 		num = expect[0]
 	}
-	if err := c.flush(); err != nil {
+	err := c.reconnectQueue()
+	if err == nil {
+		err = c.beginUse()
+		if err == nil {
+			err = c.flush()
+		}
+		c.endUse()
+	}
+	if err != nil {
 		return nil, err
 	}
 	all := make([]interface{}, 0)
 	for c.pipelinedCommands > num {
-		obj, err := c.readProtocolWithCheckingBroken()
+		if err := c.beginRead(); err != nil {
+			return nil, err
+		}
+		var obj interface{}
+		err := c.expiredReply()
+		if err == nil {
+			obj, err = c.readProtocolWithCheckingBroken()
+		}
 		if err != nil {
 			all = append(all, err)
 		} else {
 			all = append(all, obj)
 		}
 		c.pipelinedCommands--
+		c.finishReply()
 	}
 	return all, nil
 }
 
+//countSent count the command just sent,remember it until its reply is read if it isn't idempotent,
+//profiled is true if it is sampled by the profiler,tracked is true if its latency is measured
+func (c *connection) countSent(spec *CommandSpec) (profiled, tracked bool) {
+	c.sent++
+	if spec == nil || !spec.Idempotent {
+		c.unsafeSent = c.sent
+	}
+	profiled = c.profiler != nil && c.profiler.sample()
+	return profiled, profiled || c.slowThreshold > 0
+}
+
+//addSample remember the keys of the command just sent,the latency is measured when its reply is read
+func (c *connection) addSample(name string, spec *CommandSpec, args []string, profiled bool) {
+	var keys []string
+	if spec != nil {
+		name = spec.Name
+		keys = spec.Keys(args...)
+	}
+	profiled = profiled && len(keys) > 0
+	if !profiled && c.slowThreshold <= 0 {
+		return
+	}
+	c.samples = append(c.samples, keySample{seq: c.sent, command: name, keys: keys, start: time.Now(), profiled: profiled, poolWait: c.poolWait})
+	c.poolWait = 0
+}
+
+//finishReply count the reply just read,record the latency if its command is sampled,
+//report the command if it is slow
+func (c *connection) finishReply() {
+	//ended first,OnSlowCommand may send commands
+	c.endUse()
+	if c.expiredRead {
+		c.expiredRead = false
+		return
+	}
+	c.replied++
+	if !c.broken {
+		atomic.AddInt64(&c.stats.replies, 1)
+	}
+	if !c.broken && c.unsafeSent <= c.replied {
+		c.unsafeSent = 0
+	}
+	for len(c.samples) > 0 && c.samples[0].seq <= c.replied {
+		sample := c.samples[0]
+		c.samples = c.samples[1:]
+		if sample.seq != c.replied {
+			continue
+		}
+		latency := time.Since(sample.start)
+		if sample.profiled {
+			c.profiler.record(sample.command, sample.keys, latency)
+		}
+		if c.slowThreshold > 0 && latency+sample.poolWait >= c.slowThreshold {
+			c.onSlowCommand(SlowCommand{Command: sample.command, Keys: sample.keys, Duration: latency, PoolWait: sample.poolWait})
+		}
+	}
+}
+
+//beginUse mark the connection used by a send or read until endUse,
+//return ErrConcurrentUse if another goroutine is using it,endUse must be called anyway
+func (c *connection) beginUse() error {
+	if !c.detectConcurrentUse {
+		return nil
+	}
+	if atomic.AddInt32(&c.users, 1) != 1 {
+		return ErrConcurrentUse
+	}
+	return nil
+}
+
+//endUse end the use of beginUse
+func (c *connection) endUse() {
+	if c.detectConcurrentUse {
+		atomic.AddInt32(&c.users, -1)
+	}
+}
+
+//resetSamples forget the sampled commands,their replies will never be read
+func (c *connection) resetSamples() {
+	c.samples = nil
+	c.replied = c.sent
+	c.unsafeSent = 0
+}
+
+//discardPending drop the commands not flushed yet,they are never sent,return the count of them
+func (c *connection) discardPending() int {
+	if c.protocol == nil || c.protocol.os.buf == nil {
+		return 0
+	}
+	n := c.protocol.os.count
+	c.written -= int64(c.protocol.os.size())
+	c.protocol.os.release()
+	c.mirrorPending = nil
+	c.pipelinedCommands -= n
+	c.sent -= int64(n)
+	for len(c.samples) > 0 && c.samples[len(c.samples)-1].seq > c.sent {
+		c.samples = c.samples[:len(c.samples)-1]
+	}
+	if c.unsafeSent > c.sent {
+		//the earlier commands may be unsafe too,keep it ambiguous until their replies are read
+		c.unsafeSent = c.sent
+		if c.unsafeSent <= c.replied {
+			c.unsafeSent = 0
+		}
+	}
+	return n
+}
+
+//mapKeys transform the keys in args by the key mapper,args are copied if any key is transformed
+func (c *connection) mapKeys(spec *CommandSpec, args [][]byte) [][]byte {
+	if c.keyMapper == nil || spec == nil {
+		return args
+	}
+	indexes := spec.keyIndexes(ByteArrArrToStrArr(args))
+	if len(indexes) == 0 {
+		return args
+	}
+	mapped := make([][]byte, len(args))
+	copy(mapped, args)
+	for _, i := range indexes {
+		mapped[i] = []byte(c.keyMapper(string(args[i])))
+	}
+	return mapped
+}
+
+//mapStrKeys transform the keys in args by the key mapper,like mapKeys
+func (c *connection) mapStrKeys(spec *CommandSpec, args []string) []string {
+	if c.keyMapper == nil || spec == nil {
+		return args
+	}
+	indexes := spec.keyIndexes(args)
+	if len(indexes) == 0 {
+		return args
+	}
+	mapped := make([]string, len(args))
+	copy(mapped, args)
+	for _, i := range indexes {
+		mapped[i] = c.keyMapper(args[i])
+	}
+	return mapped
+}
+
+//mirroredCommand a command encoded but not flushed yet,its arguments are copied
+type mirroredCommand struct {
+	spec *CommandSpec
+	args [][]byte
+}
+
+//mirrorSent hold the command just encoded for the mirror,if it is set,
+//it is passed to the mirror once flushed,so the commands discarded before are never mirrored
+func (c *connection) mirrorSent(spec *CommandSpec, args [][]byte) {
+	if c.mirror == nil || spec == nil {
+		return
+	}
+	//the arguments may be reused by the caller after the command is sent
+	copied := make([][]byte, len(args))
+	for i, arg := range args {
+		copied[i] = append([]byte(nil), arg...)
+	}
+	c.mirrorPending = append(c.mirrorPending, mirroredCommand{spec: spec, args: copied})
+}
+
+//mirrorFlushed pass the commands just flushed to the mirror
+func (c *connection) mirrorFlushed() {
+	for _, command := range c.mirrorPending {
+		c.mirror(command.spec, command.args)
+	}
+	c.mirrorPending = nil
+}
+
+//ambiguous whether a command which isn't idempotent was sent but its reply wasn't read,
+//if the connection failed,redis may or may not have executed it
+func (c *connection) ambiguous() bool {
+	return c.unsafeSent > 0
+}
+
 func (c *connection) flush() error {
+	if len(c.queue) > 0 || c.expiredReplies > 0 {
+		if err := c.sendQueue(); err != nil {
+			return err
+		}
+		if !c.isConnected() {
+			//all the queued commands expired,nothing is written
+			return nil
+		}
+	}
 	err := c.protocol.os.flush()
 	if err != nil {
 		c.broken = true
-		return newConnectError(err.Error())
+		return err
 	}
 	return nil
 }
@@ -287,21 +1041,295 @@ func (c *connection) connect() error {
 	if c.isConnected() {
 		return nil
 	}
-	conn, err := net.DialTimeout("tcp", fmt.Sprint(c.host, ":", c.port), c.connectionTimeout)
+	//dialed under dialMu like reconnect,so a goroutine reconnecting doesn't dial at the same time
+	c.dialMu.Lock()
+	err := c.dialIfNotConnected(c.connectionTimeout)
+	c.dialMu.Unlock()
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*ConnectError); !ok {
+		//the handshake is rejected by redis,retry won't help
+		return err
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		//redis is down,fail fast instead of waiting for reconnect
+		return err
+	}
+	if c.disconnectPolicy == BlockUntilReconnect {
+		return c.reconnect(c.blockTimeout)
+	}
+	return newDisconnectedError(err.Error(), ErrDisconnected)
+}
+
+//queuedCommand command queued while redis is unreachable
+type queuedCommand struct {
+	name   string
+	spec   *CommandSpec
+	args   [][]byte
+	queued time.Time
+}
+
+//connectOrQueue connect to redis,return true if the command should be queued instead,
+//it is queued if redis is unreachable and the policy is QueueUntilReconnect,
+//or if earlier commands are queued,so the commands are sent in order
+func (c *connection) connectOrQueue() (bool, error) {
+	if len(c.queue) > 0 {
+		return true, nil
+	}
+	err := c.connect()
+	if err != nil && c.disconnectPolicy == QueueUntilReconnect && errors.Is(err, ErrDisconnected) {
+		return true, nil
+	}
+	return false, err
+}
+
+//queueCommand queue the command until reconnect,the args are copied,
+//it fails with ErrQueueFull if MaxQueueSize commands are queued
+func (c *connection) queueCommand(name string, spec *CommandSpec, args [][]byte) error {
+	if err := c.beginUse(); err != nil {
+		c.endUse()
+		return err
+	}
+	defer c.endUse()
+	if len(c.queue) >= c.maxQueueSize {
+		return newDisconnectedError(ErrQueueFull.Error(), ErrQueueFull)
+	}
+	copied := make([][]byte, len(args))
+	for i, arg := range args {
+		copied[i] = append([]byte(nil), arg...)
+	}
+	c.queue = append(c.queue, &queuedCommand{name: name, spec: spec, args: copied, queued: time.Now()})
+	c.pipelinedCommands++
+	return nil
+}
+
+//reconnectQueue reconnect for the queued commands,the commands queued longer than QueueTTL are dropped meanwhile,
+//their replies fail with ErrQueueFull once reconnected,
+//it runs before the connection is used,since the handshake sends and reads its own commands
+func (c *connection) reconnectQueue() error {
+	for len(c.queue) > 0 {
+		for len(c.queue) > 0 && time.Since(c.queue[0].queued) >= c.queueTTL {
+			c.queue[0] = nil
+			c.queue = c.queue[1:]
+			c.queueExpired++
+		}
+		if len(c.queue) == 0 || c.isConnected() {
+			break
+		}
+		//taken out while reconnecting,so the handshake neither queues its commands nor takes the expired replies
+		queue, queueExpired, expiredReplies := c.queue, c.queueExpired, c.expiredReplies
+		c.queue, c.queueExpired, c.expiredReplies = nil, 0, 0
+		err := c.reconnect(c.queueTTL - time.Since(queue[0].queued))
+		c.queue, c.queueExpired, c.expiredReplies = queue, queueExpired, expiredReplies
+		if err != nil {
+			if _, ok := err.(*ConnectError); !ok {
+				//the handshake is rejected by redis,keep the commands queued
+				return err
+			}
+		}
+	}
+	//the replies of the handshake are read,the expired replies precede the ones of the queued commands
+	c.expiredReplies += c.queueExpired
+	c.queueExpired = 0
+	return nil
+}
+
+//sendQueue encode the queued commands in order once reconnected
+func (c *connection) sendQueue() error {
+	if err := c.reconnectQueue(); err != nil {
+		return err
+	}
+	if !c.isConnected() {
+		return nil
+	}
+	queue := c.queue
+	c.queue = nil
+	for _, command := range queue {
+		if err := c.encodeCommand(command.name, command.spec, command.args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//expiredReply the error of the next reply if its command expired in the queue,the expired commands precede the others,
+//the reply isn't read from the socket,so it isn't counted by finishReply
+func (c *connection) expiredReply() error {
+	if c.expiredReplies == 0 {
+		return nil
+	}
+	c.expiredReplies--
+	c.expiredRead = true
+	return newDisconnectedError("the command expired in the disconnected queue", ErrQueueFull)
+}
+
+//reconnect keep dialing until success or timeout
+func (c *connection) reconnect(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return newDisconnectedError(fmt.Sprintf("reconnect timeout after %v", timeout), ErrDisconnected)
+		}
+		if remaining > reconnectInterval {
+			time.Sleep(reconnectInterval)
+		} else {
+			time.Sleep(remaining)
+		}
+		dialTimeout := time.Until(deadline)
+		if dialTimeout > c.connectionTimeout {
+			dialTimeout = c.connectionTimeout
+		}
+		c.dialMu.Lock()
+		err := c.dialIfNotConnected(dialTimeout)
+		c.dialMu.Unlock()
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*ConnectError); !ok {
+			return err
+		}
+	}
+}
+
+func (c *connection) dialIfNotConnected(timeout time.Duration) error {
+	if c.isConnected() {
+		return nil
+	}
+	if timeout <= 0 {
+		return newDisconnectedError("reconnect timeout", ErrDisconnected)
+	}
+	return c.dial(timeout)
+}
+
+//dial connect to redis,guarded by the circuit breaker if it is set
+func (c *connection) dial(timeout time.Duration) error {
+	if c.breaker == nil {
+		return c.dialSocket(timeout)
+	}
+	return c.breaker.Do(func() error {
+		return c.dialSocket(timeout)
+	})
+}
+
+func (c *connection) dialSocket(timeout time.Duration) error {
+	conn, addr, err := c.dialHosts(timeout)
 	if err != nil {
-		return newConnectError(err.Error())
+		return wrapConnectError(err)
+	}
+	//go sets TCP_NODELAY on tcp connections,it is only cleared on demand
+	if tcpConn, ok := conn.(*net.TCPConn); ok && c.disableTCPNoDelay {
+		if err := tcpConn.SetNoDelay(false); err != nil {
+			conn.Close()
+			return wrapConnectError(err)
+		}
+	}
+	if c.tlsConfig != nil {
+		host := addr
+		if h, _, err := net.SplitHostPort(addr); err == nil {
+			host = h
+		}
+		conn, err = c.tlsHandshake(conn, host, timeout)
+		if err != nil {
+			return err
+		}
+	}
+	if err := c.setSocket(conn); err != nil {
+		return err
 	}
-	err = conn.SetDeadline(time.Now().Add(c.soTimeout))
+	c.addr = addr
+	c.commandDeadline = time.Time{}
+	err = c.setIODeadline()
 	if err != nil {
-		return newConnectError(err.Error())
+		c.setSocket(nil)
+		conn.Close()
+		return wrapConnectError(err)
 	}
-	c.socket = conn
-	os := newRedisOutputStream(bufio.NewWriter(c.socket), c)
-	is := newRedisInputStream(bufio.NewReader(c.socket), c)
+	c.resetSamples()
+	os := newRedisOutputStream(c)
+	is := newRedisInputStream(bufio.NewReader(countingReader{reader: c.socket, count: &c.stats.bytesRead}), c)
 	c.protocol = newProtocol(os, is)
+	if c.handshake != nil {
+		if err := c.handshake(); err != nil {
+			//the connection was never established,so onClose isn't called
+			conn.Close()
+			c.setSocket(nil)
+			return err
+		}
+	}
+	c.closeNotified = false
 	return nil
 }
 
+//dialHosts dial the hosts in order until one succeeds,every one within timeout,return the connection and the address dialed,
+//the names are resolved again by every dial,so a DNS name is dialed at its new address after a failover
+func (c *connection) dialHosts(timeout time.Duration) (net.Conn, string, error) {
+	if c.network == "unix" {
+		conn, err := c.dialTimeout("unix", c.host, timeout)
+		return conn, c.host, err
+	}
+	var lastErr error
+	for _, addr := range parseHostList(c.host, c.port) {
+		conn, err := c.dialTimeout("tcp", addr, timeout)
+		if err == nil {
+			return conn, addr, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no address in host %q", c.host)
+	}
+	return nil, "", lastErr
+}
+
+//dialTimeout dial by Option.Dialer if it is set,otherwise by net.Dialer with the keep-alive period
+func (c *connection) dialTimeout(network, addr string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if c.dialFunc != nil {
+		return c.dialFunc(ctx, network, addr)
+	}
+	dialer := &net.Dialer{KeepAlive: c.keepAlive}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+//parseHostList split the comma separated hosts into addresses,such as redis-a:6379,redis-b,
+//a host without port is dialed at port
+func parseHostList(hosts string, port int) []string {
+	addrs := make([]string, 0)
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+		if h, p, err := net.SplitHostPort(host); err == nil {
+			addrs = append(addrs, net.JoinHostPort(h, p))
+			continue
+		}
+		addrs = append(addrs, net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port)))
+	}
+	return addrs
+}
+
+func (c *connection) tlsHandshake(conn net.Conn, host string, timeout time.Duration) (net.Conn, error) {
+	config := c.tlsConfig
+	if config.ServerName == "" && !config.InsecureSkipVerify {
+		config = config.Clone()
+		config.ServerName = host
+	}
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		conn.Close()
+		return nil, wrapConnectError(err)
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, wrapConnectError(err)
+	}
+	return tlsConn, nil
+}
+
 func (c *connection) isConnected() bool {
 	if c.socket == nil {
 		return false
@@ -314,6 +1342,49 @@ func (c *connection) close() error {
 		return nil
 	}
 	err := c.socket.Close()
-	c.socket = nil
+	c.setSocket(nil)
+	c.notifyClosed()
 	return err
 }
+
+//setSocket replace the socket,a new socket is closed with an error if closeSocket was called
+func (c *connection) setSocket(conn net.Conn) error {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+	if conn != nil && c.socketClosed {
+		conn.Close()
+		return newConnectError("connection is closed")
+	}
+	c.socket = conn
+	return nil
+}
+
+//closeSocket close the socket from another goroutine than the one using the connection,
+//which sees the error of the closed socket,the connection isn't dialed again afterwards
+func (c *connection) closeSocket() {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+	c.socketClosed = true
+	if c.socket != nil {
+		c.socket.Close()
+	}
+}
+
+//release close the socket of a connection destroyed by the pool,unlike close the socket is kept,
+//so the connection stays broken instead of reconnecting by the next command
+func (c *connection) release() {
+	if c.socket == nil {
+		return
+	}
+	c.socket.Close()
+	c.broken = true
+	c.notifyClosed()
+}
+
+//notifyClosed call onClose once for the socket
+func (c *connection) notifyClosed() {
+	if c.onClose != nil && !c.closeNotified {
+		c.closeNotified = true
+		c.onClose()
+	}
+}
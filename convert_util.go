@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
+	"time"
 )
 
 //BoolToByteArr convert bool to byte array
@@ -85,6 +87,15 @@ func StrArrToByteArrArr(arr []string) [][]byte {
 	return newArr
 }
 
+//ByteArrArrToStrArr convert byte array list to string array
+func ByteArrArrToStrArr(arr [][]byte) []string {
+	newArr := make([]string, 0, len(arr))
+	for _, a := range arr {
+		newArr = append(newArr, string(a))
+	}
+	return newArr
+}
+
 //StrToFloat64Reply convert string reply to float64 reply
 func StrToFloat64Reply(reply string, err error) (float64, error) {
 	if err != nil {
@@ -141,6 +152,97 @@ func StrArrToTupleReply(reply []string, err error) ([]Tuple, error) {
 	return newArr, err
 }
 
+//StrArrToKeyedTupleReply convert string array reply to keyed tuple reply
+func StrArrToKeyedTupleReply(reply []string, err error) (*KeyedTuple, error) {
+	if err != nil || len(reply) < 3 {
+		return nil, err
+	}
+	f, err := strconv.ParseFloat(reply[2], 64)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyedTuple{Key: reply[0], Tuple: Tuple{element: reply[1], score: f}}, nil
+}
+
+//ObjArrToKeyedElementsReply convert the reply of BLMPOP,nil if the timeout expired
+func ObjArrToKeyedElementsReply(reply []interface{}, err error) (*KeyedElements, error) {
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	key, elements, err := parseMPopReply(reply)
+	if err != nil {
+		return nil, err
+	}
+	arr := make([]string, 0, len(elements))
+	for _, e := range elements {
+		b, ok := e.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unexpected mpop element:%v", e)
+		}
+		arr = append(arr, string(b))
+	}
+	return &KeyedElements{Key: key, Elements: arr}, nil
+}
+
+//ObjArrToKeyedTuplesReply convert the reply of BZMPOP,nil if the timeout expired
+func ObjArrToKeyedTuplesReply(reply []interface{}, err error) (*KeyedTuples, error) {
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	key, elements, err := parseMPopReply(reply)
+	if err != nil {
+		return nil, err
+	}
+	tuples := make([]Tuple, 0, len(elements))
+	for _, e := range elements {
+		pair, ok := e.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("unexpected mpop tuple:%v", e)
+		}
+		member, ok := pair[0].([]byte)
+		score, ok2 := pair[1].([]byte)
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("unexpected mpop tuple:%v", e)
+		}
+		f, err := strconv.ParseFloat(string(score), 64)
+		if err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, Tuple{element: string(member), score: f})
+	}
+	return &KeyedTuples{Key: key, Tuples: tuples}, nil
+}
+
+//parseMPopReply split the reply of BLMPOP and BZMPOP into the key and the popped elements
+func parseMPopReply(reply []interface{}) (string, []interface{}, error) {
+	if len(reply) != 2 {
+		return "", nil, fmt.Errorf("unexpected mpop reply:%v", reply)
+	}
+	key, ok := reply[0].([]byte)
+	elements, ok2 := reply[1].([]interface{})
+	if !ok || !ok2 {
+		return "", nil, fmt.Errorf("unexpected mpop reply:%v", reply)
+	}
+	return string(key), elements, nil
+}
+
+//ObjArrToNumSubReply convert object array reply of PUBSUB NUMSUB to channel and subscriber count map
+func ObjArrToNumSubReply(reply []interface{}, err error) (map[string]int64, error) {
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]int64, len(reply)/2)
+	for i := 0; i+1 < len(reply); i += 2 {
+		channel, ok1 := reply[i].([]byte)
+		count, ok2 := reply[i+1].(int64)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("unexpected numsub reply:%v", reply)
+		}
+		m[string(channel)] = count
+	}
+	return m, nil
+}
+
 //ObjArrToScanResultReply convert object array reply to scanresult reply
 func ObjArrToScanResultReply(reply []interface{}, err error) (*ScanResult, error) {
 	if err != nil || len(reply) == 0 {
@@ -218,6 +320,32 @@ func ObjArrToGeoRadiusResponseReply(reply []interface{}, err error) ([]GeoRadius
 	return arr, err
 }
 
+//ObjArrToNullableInt64Reply convert object array reply to int64 array reply,a nil element is kept as nil
+func ObjArrToNullableInt64Reply(reply []interface{}, err error) ([]*int64, error) {
+	if err != nil {
+		return nil, err
+	}
+	arr := make([]*int64, 0, len(reply))
+	for _, item := range reply {
+		switch v := item.(type) {
+		case int64:
+			arr = append(arr, &v)
+		case []byte:
+			if v != nil {
+				return nil, fmt.Errorf("unexpected reply:%v", item)
+			}
+			arr = append(arr, nil)
+		case nil:
+			arr = append(arr, nil)
+		case error:
+			return nil, v
+		default:
+			return nil, fmt.Errorf("unexpected reply:%v", item)
+		}
+	}
+	return arr, nil
+}
+
 //ObjArrToMapArrayReply convert object array reply to map array reply
 func ObjArrToMapArrayReply(reply []interface{}, err error) ([]map[string]string, error) {
 	if err != nil || len(reply) == 0 {
@@ -226,9 +354,20 @@ func ObjArrToMapArrayReply(reply []interface{}, err error) ([]map[string]string,
 	masters := make([]map[string]string, 0)
 	for _, re := range reply {
 		m := make(map[string]string)
-		arr := re.([][]byte)
-		for i := 0; i < len(arr); i += 2 {
-			m[string(arr[i])] = string(arr[i+1])
+		switch arr := re.(type) {
+		case [][]byte:
+			for i := 0; i+1 < len(arr); i += 2 {
+				m[string(arr[i])] = string(arr[i+1])
+			}
+		case []interface{}:
+			//the fields read from redis are bulk strings
+			for i := 0; i+1 < len(arr); i += 2 {
+				key, _ := arr[i].([]byte)
+				value, _ := arr[i+1].([]byte)
+				m[string(key)] = string(value)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected reply:%v", re)
 		}
 		masters = append(masters, m)
 	}
@@ -260,6 +399,14 @@ func ObjToEvalResult(reply interface{}, err error) (interface{}, error) {
 
 //<editor-fold desc="cluster reply convert">
 
+//ToByteArrReply convert object reply to byte array reply
+func ToByteArrReply(reply interface{}, err error) ([]byte, error) {
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	return reply.([]byte), nil
+}
+
 //ToStrReply convert object reply to string reply
 func ToStrReply(reply interface{}, err error) (string, error) {
 	if err != nil {
@@ -336,6 +483,18 @@ func ToMapReply(reply interface{}, err error) (map[string]string, error) {
 	return reply.(map[string]string), nil
 }
 
+//ToStrSetReply convert object reply to string set reply
+func ToStrSetReply(reply interface{}, err error) (map[string]struct{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	set, ok := reply.(map[string]struct{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected type:%T", reply)
+	}
+	return set, nil
+}
+
 //ToTupleArrReply convert object reply to tuple array reply
 func ToTupleArrReply(reply interface{}, err error) ([]Tuple, error) {
 	if err != nil {
@@ -344,6 +503,30 @@ func ToTupleArrReply(reply interface{}, err error) ([]Tuple, error) {
 	return reply.([]Tuple), nil
 }
 
+//ToKeyedTupleReply convert object reply to keyed tuple reply
+func ToKeyedTupleReply(reply interface{}, err error) (*KeyedTuple, error) {
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	return reply.(*KeyedTuple), nil
+}
+
+//ToKeyedElementsReply convert object reply to keyed elements reply
+func ToKeyedElementsReply(reply interface{}, err error) (*KeyedElements, error) {
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	return reply.(*KeyedElements), nil
+}
+
+//ToKeyedTuplesReply convert object reply to keyed tuples reply
+func ToKeyedTuplesReply(reply interface{}, err error) (*KeyedTuples, error) {
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	return reply.(*KeyedTuples), nil
+}
+
 //ToGeoCoordArrReply convert object reply to geocoordinate array reply
 func ToGeoCoordArrReply(reply interface{}, err error) ([]*GeoCoordinate, error) {
 	if err != nil {
@@ -360,6 +543,30 @@ func ToGeoRespArrReply(reply interface{}, err error) ([]GeoRadiusResponse, error
 	return reply.([]GeoRadiusResponse), nil
 }
 
+//ToStreamEntriesArrReply convert object reply to stream entries array reply
+func ToStreamEntriesArrReply(reply interface{}, err error) ([]StreamEntries, error) {
+	if err != nil {
+		return nil, err
+	}
+	return reply.([]StreamEntries), nil
+}
+
+//ToStreamPendingEntryArrReply convert object reply to stream pending entry array reply
+func ToStreamPendingEntryArrReply(reply interface{}, err error) ([]StreamPendingEntry, error) {
+	if err != nil {
+		return nil, err
+	}
+	return reply.([]StreamPendingEntry), nil
+}
+
+//ToStreamClaimResultReply convert object reply to stream claim result reply
+func ToStreamClaimResultReply(reply interface{}, err error) (*StreamClaimResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	return reply.(*StreamClaimResult), nil
+}
+
 //</editor-fold>
 
 //Builder convert pipeline|transaction response data
@@ -374,6 +581,14 @@ var (
 	Int64Builder = newInt64Builder()
 	//StrArrBuilder convert interface to string array
 	StrArrBuilder = newStringArrayBuilder()
+	//TupleArrBuilder convert interface to tuple array
+	TupleArrBuilder = newTupleArrBuilder()
+	//KeyedTupleBuilder convert interface to keyed tuple
+	KeyedTupleBuilder = newKeyedTupleBuilder()
+	//KeyedElementsBuilder convert interface to keyed elements,the reply of BLMPOP
+	KeyedElementsBuilder = &keyedElementsBuilder{}
+	//KeyedTuplesBuilder convert interface to keyed tuples,the reply of BZMPOP
+	KeyedTuplesBuilder = &keyedTuplesBuilder{}
 )
 
 type strBuilder struct {
@@ -439,3 +654,714 @@ func (b *strArrBuilder) build(data interface{}) (interface{}, error) {
 	}
 	return nil, fmt.Errorf("unexpected type:%T", data)
 }
+
+type tupleArrBuilder struct {
+}
+
+func newTupleArrBuilder() *tupleArrBuilder {
+	return &tupleArrBuilder{}
+}
+
+func (b *tupleArrBuilder) build(data interface{}) (interface{}, error) {
+	arr, err := StrArrBuilder.build(data)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToTupleReply(arr.([]string), nil)
+}
+
+type keyedTupleBuilder struct {
+}
+
+func newKeyedTupleBuilder() *keyedTupleBuilder {
+	return &keyedTupleBuilder{}
+}
+
+func (b *keyedTupleBuilder) build(data interface{}) (interface{}, error) {
+	arr, err := StrArrBuilder.build(data)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToKeyedTupleReply(arr.([]string), nil)
+}
+
+type keyedElementsBuilder struct {
+}
+
+func (b *keyedElementsBuilder) build(data interface{}) (interface{}, error) {
+	if data == nil {
+		return (*KeyedElements)(nil), nil
+	}
+	switch data.(type) {
+	case []interface{}:
+		return ObjArrToKeyedElementsReply(data.([]interface{}), nil)
+	}
+	return nil, fmt.Errorf("unexpected type:%T", data)
+}
+
+type keyedTuplesBuilder struct {
+}
+
+func (b *keyedTuplesBuilder) build(data interface{}) (interface{}, error) {
+	if data == nil {
+		return (*KeyedTuples)(nil), nil
+	}
+	switch data.(type) {
+	case []interface{}:
+		return ObjArrToKeyedTuplesReply(data.([]interface{}), nil)
+	}
+	return nil, fmt.Errorf("unexpected type:%T", data)
+}
+
+//ParseClusterSlots parse the reply of CLUSTER SLOTS
+func ParseClusterSlots(reply []interface{}, err error) ([]ClusterSlotRange, error) {
+	if err != nil {
+		return nil, err
+	}
+	ranges := make([]ClusterSlotRange, 0, len(reply))
+	for _, r := range reply {
+		info, ok := r.([]interface{})
+		if !ok || len(info) < 3 {
+			return nil, fmt.Errorf("unexpected cluster slots reply:%v", r)
+		}
+		start, ok1 := info[0].(int64)
+		end, ok2 := info[1].(int64)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("unexpected cluster slots range:%v", info[:2])
+		}
+		slotRange := ClusterSlotRange{Start: int(start), End: int(end), Replicas: make([]ClusterSlotNode, 0)}
+		for i, n := range info[2:] {
+			node, err := parseClusterSlotNode(n)
+			if err != nil {
+				return nil, err
+			}
+			if i == 0 {
+				slotRange.Master = node
+			} else {
+				slotRange.Replicas = append(slotRange.Replicas, node)
+			}
+		}
+		ranges = append(ranges, slotRange)
+	}
+	return ranges, nil
+}
+
+func parseClusterSlotNode(n interface{}) (ClusterSlotNode, error) {
+	info, ok := n.([]interface{})
+	if !ok || len(info) < 2 {
+		return ClusterSlotNode{}, fmt.Errorf("unexpected cluster slots node:%v", n)
+	}
+	host, ok1 := info[0].([]byte)
+	port, ok2 := info[1].(int64)
+	if !ok1 || !ok2 {
+		return ClusterSlotNode{}, fmt.Errorf("unexpected cluster slots node:%v", n)
+	}
+	node := ClusterSlotNode{Host: string(host), Port: int(port)}
+	if len(info) > 2 {
+		if id, ok := info[2].([]byte); ok {
+			node.ID = string(id)
+		}
+	}
+	return node, nil
+}
+
+//ParseClusterShards parse the reply of CLUSTER SHARDS
+func ParseClusterShards(reply []interface{}, err error) ([]ClusterShard, error) {
+	if err != nil {
+		return nil, err
+	}
+	shards := make([]ClusterShard, 0, len(reply))
+	for _, r := range reply {
+		fields, ok := r.([]interface{})
+		if !ok || len(fields)%2 != 0 {
+			return nil, fmt.Errorf("unexpected cluster shards reply:%v", r)
+		}
+		shard := ClusterShard{Slots: make([][2]int, 0), Nodes: make([]ClusterShardNode, 0)}
+		for i := 0; i < len(fields); i += 2 {
+			name, _ := fields[i].([]byte)
+			value, _ := fields[i+1].([]interface{})
+			switch string(name) {
+			case "slots":
+				for j := 0; j+1 < len(value); j += 2 {
+					start, err := parseClusterInt(value[j])
+					if err != nil {
+						return nil, err
+					}
+					end, err := parseClusterInt(value[j+1])
+					if err != nil {
+						return nil, err
+					}
+					shard.Slots = append(shard.Slots, [2]int{int(start), int(end)})
+				}
+			case "nodes":
+				for _, n := range value {
+					node, err := parseClusterShardNode(n)
+					if err != nil {
+						return nil, err
+					}
+					shard.Nodes = append(shard.Nodes, node)
+				}
+			}
+		}
+		shards = append(shards, shard)
+	}
+	return shards, nil
+}
+
+func parseClusterShardNode(n interface{}) (ClusterShardNode, error) {
+	fields, ok := n.([]interface{})
+	if !ok || len(fields)%2 != 0 {
+		return ClusterShardNode{}, fmt.Errorf("unexpected cluster shards node:%v", n)
+	}
+	node := ClusterShardNode{}
+	for i := 0; i < len(fields); i += 2 {
+		name, _ := fields[i].([]byte)
+		value := fields[i+1]
+		str, _ := value.([]byte)
+		var err error
+		var num int64
+		switch string(name) {
+		case "id":
+			node.ID = string(str)
+		case "port":
+			num, err = parseClusterInt(value)
+			node.Port = int(num)
+		case "tls-port":
+			num, err = parseClusterInt(value)
+			node.TLSPort = int(num)
+		case "ip":
+			node.IP = string(str)
+		case "endpoint":
+			node.Endpoint = string(str)
+		case "hostname":
+			node.Hostname = string(str)
+		case "role":
+			node.Role = string(str)
+		case "replication-offset":
+			node.ReplicationOffset, err = parseClusterInt(value)
+		case "health":
+			node.Health = string(str)
+		}
+		if err != nil {
+			return ClusterShardNode{}, err
+		}
+	}
+	return node, nil
+}
+
+//parseClusterInt parse integer or bulk string number
+//ParseClusterLinks parse the reply of CLUSTER LINKS
+func ParseClusterLinks(reply []interface{}, err error) ([]ClusterLink, error) {
+	if err != nil {
+		return nil, err
+	}
+	links := make([]ClusterLink, 0, len(reply))
+	for _, r := range reply {
+		fields, ok := r.([]interface{})
+		if !ok || len(fields)%2 != 0 {
+			return nil, fmt.Errorf("unexpected cluster links reply:%v", r)
+		}
+		link := ClusterLink{}
+		for i := 0; i < len(fields); i += 2 {
+			name, _ := fields[i].([]byte)
+			value := fields[i+1]
+			str, _ := value.([]byte)
+			var err error
+			switch string(name) {
+			case "direction":
+				link.Direction = string(str)
+			case "node":
+				link.Node = string(str)
+			case "create-time":
+				link.CreateTime, err = parseClusterInt(value)
+			case "events":
+				link.Events = string(str)
+			case "send-buffer-allocated":
+				link.SendBufferAllocated, err = parseClusterInt(value)
+			case "send-buffer-used":
+				link.SendBufferUsed, err = parseClusterInt(value)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+func parseClusterInt(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	}
+	return 0, fmt.Errorf("unexpected type:%T", value)
+}
+
+//ParseClusterNodes parse the reply of CLUSTER NODES,
+//every line is: id ip:port@cport[,hostname] flags master ping-sent pong-recv config-epoch link-state slot...
+func ParseClusterNodes(reply string, err error) ([]ClusterNode, error) {
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]ClusterNode, 0)
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		node, err := parseClusterNode(line)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+func parseClusterNode(line string) (ClusterNode, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 8 {
+		return ClusterNode{}, fmt.Errorf("unexpected cluster nodes line:%s", line)
+	}
+	node := ClusterNode{
+		ID:        fields[0],
+		Flags:     strings.Split(fields[2], ","),
+		LinkState: fields[7],
+		Slots:     make([][2]int, 0),
+		Migrating: make(map[int]string),
+		Importing: make(map[int]string),
+	}
+	addr := fields[1]
+	if i := strings.Index(addr, ","); i >= 0 {
+		node.Hostname = addr[i+1:]
+		addr = addr[:i]
+	}
+	if i := strings.Index(addr, "@"); i >= 0 {
+		busPort, err := strconv.Atoi(addr[i+1:])
+		if err != nil {
+			return ClusterNode{}, fmt.Errorf("unexpected cluster nodes address:%s", fields[1])
+		}
+		node.BusPort = busPort
+		addr = addr[:i]
+	}
+	node.Addr = addr
+	if i := strings.LastIndex(addr, ":"); i >= 0 {
+		node.Host = addr[:i]
+		port, err := strconv.Atoi(addr[i+1:])
+		if err != nil {
+			return ClusterNode{}, fmt.Errorf("unexpected cluster nodes address:%s", fields[1])
+		}
+		node.Port = port
+	}
+	if fields[3] != "-" {
+		node.MasterID = fields[3]
+	}
+	var err error
+	if node.PingSent, err = strconv.ParseInt(fields[4], 10, 64); err != nil {
+		return ClusterNode{}, fmt.Errorf("unexpected cluster nodes ping-sent:%s", fields[4])
+	}
+	if node.PongRecv, err = strconv.ParseInt(fields[5], 10, 64); err != nil {
+		return ClusterNode{}, fmt.Errorf("unexpected cluster nodes pong-recv:%s", fields[5])
+	}
+	if node.ConfigEpoch, err = strconv.ParseInt(fields[6], 10, 64); err != nil {
+		return ClusterNode{}, fmt.Errorf("unexpected cluster nodes config-epoch:%s", fields[6])
+	}
+	for _, slot := range fields[8:] {
+		if err := parseClusterNodeSlot(&node, slot); err != nil {
+			return ClusterNode{}, err
+		}
+	}
+	return node, nil
+}
+
+//parseClusterNodeSlot parse 0-5460,5461,[5462->-id] or [5462-<-id]
+func parseClusterNodeSlot(node *ClusterNode, slot string) error {
+	if strings.HasPrefix(slot, "[") && strings.HasSuffix(slot, "]") {
+		slot = slot[1 : len(slot)-1]
+		target := node.Migrating
+		parts := strings.SplitN(slot, "->-", 2)
+		if len(parts) != 2 {
+			target = node.Importing
+			parts = strings.SplitN(slot, "-<-", 2)
+		}
+		if len(parts) != 2 {
+			return fmt.Errorf("unexpected cluster nodes slot:%s", slot)
+		}
+		n, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return fmt.Errorf("unexpected cluster nodes slot:%s", slot)
+		}
+		target[n] = parts[1]
+		return nil
+	}
+	parts := strings.SplitN(slot, "-", 2)
+	start, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return fmt.Errorf("unexpected cluster nodes slot:%s", slot)
+	}
+	end := start
+	if len(parts) == 2 {
+		if end, err = strconv.Atoi(parts[1]); err != nil {
+			return fmt.Errorf("unexpected cluster nodes slot:%s", slot)
+		}
+	}
+	node.Slots = append(node.Slots, [2]int{start, end})
+	return nil
+}
+
+//ParseStreamEntriesArr parse the reply of XREAD and XREADGROUP
+func ParseStreamEntriesArr(reply []interface{}, err error) ([]StreamEntries, error) {
+	if err != nil {
+		return nil, err
+	}
+	streams := make([]StreamEntries, 0, len(reply))
+	for _, r := range reply {
+		info, ok := r.([]interface{})
+		if !ok || len(info) < 2 {
+			return nil, fmt.Errorf("unexpected stream reply:%v", r)
+		}
+		name, ok := info[0].([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unexpected stream name:%v", info[0])
+		}
+		entries, err := parseStreamEntries(info[1])
+		if err != nil {
+			return nil, err
+		}
+		streams = append(streams, StreamEntries{Stream: string(name), Entries: entries})
+	}
+	return streams, nil
+}
+
+func parseStreamEntries(reply interface{}) ([]StreamEntry, error) {
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected stream entries:%v", reply)
+	}
+	entries := make([]StreamEntry, 0, len(arr))
+	for _, e := range arr {
+		if isNilReply(e) {
+			//redis 6.2 returns nil for claimed entries deleted from the stream
+			continue
+		}
+		entry, err := parseStreamEntry(e)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseStreamEntry(reply interface{}) (StreamEntry, error) {
+	info, ok := reply.([]interface{})
+	if !ok || len(info) < 2 {
+		return StreamEntry{}, fmt.Errorf("unexpected stream entry:%v", reply)
+	}
+	id, ok := info[0].([]byte)
+	if !ok {
+		return StreamEntry{}, fmt.Errorf("unexpected stream entry id:%v", info[0])
+	}
+	entry := StreamEntry{ID: string(id)}
+	if isNilReply(info[1]) {
+		//fields of a deleted entry are nil
+		return entry, nil
+	}
+	fields, ok := info[1].([]interface{})
+	if !ok {
+		return StreamEntry{}, fmt.Errorf("unexpected stream entry fields:%v", info[1])
+	}
+	entry.Fields = make(map[string]string, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		k, ok1 := fields[i].([]byte)
+		v, ok2 := fields[i+1].([]byte)
+		if !ok1 || !ok2 {
+			return StreamEntry{}, fmt.Errorf("unexpected stream entry fields:%v", fields)
+		}
+		entry.Fields[string(k)] = string(v)
+	}
+	return entry, nil
+}
+
+//ParseStreamPendingEntries parse the reply of XPENDING with start and end
+func ParseStreamPendingEntries(reply []interface{}, err error) ([]StreamPendingEntry, error) {
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]StreamPendingEntry, 0, len(reply))
+	for _, r := range reply {
+		info, ok := r.([]interface{})
+		if !ok || len(info) < 4 {
+			return nil, fmt.Errorf("unexpected stream pending entry:%v", r)
+		}
+		id, ok1 := info[0].([]byte)
+		consumer, ok2 := info[1].([]byte)
+		idle, ok3 := info[2].(int64)
+		deliveries, ok4 := info[3].(int64)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return nil, fmt.Errorf("unexpected stream pending entry:%v", r)
+		}
+		entries = append(entries, StreamPendingEntry{
+			ID:         string(id),
+			Consumer:   string(consumer),
+			Idle:       time.Duration(idle) * time.Millisecond,
+			Deliveries: deliveries,
+		})
+	}
+	return entries, nil
+}
+
+//ParseStreamClaimResult parse the reply of XAUTOCLAIM
+func ParseStreamClaimResult(reply []interface{}, err error) (*StreamClaimResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(reply) < 2 {
+		return nil, fmt.Errorf("unexpected xautoclaim reply:%v", reply)
+	}
+	next, ok := reply[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected xautoclaim next id:%v", reply[0])
+	}
+	entries, err := parseStreamEntries(reply[1])
+	if err != nil {
+		return nil, err
+	}
+	result := &StreamClaimResult{Next: string(next), Entries: entries, Deleted: make([]string, 0)}
+	if len(reply) > 2 {
+		deleted, _ := reply[2].([]interface{})
+		for _, d := range deleted {
+			if id, ok := d.([]byte); ok {
+				result.Deleted = append(result.Deleted, string(id))
+			}
+		}
+	}
+	return result, nil
+}
+
+//ParseInfo parse the reply of INFO,every section starts with "# Section" followed by field:value lines
+func ParseInfo(reply string, err error) (*ServerInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	info := &ServerInfo{
+		Sections: make(map[string]map[string]string),
+		Keyspace: make(map[int]KeyspaceInfo),
+	}
+	section := ""
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			section = strings.ToLower(strings.TrimSpace(line[1:]))
+			info.Sections[section] = make(map[string]string)
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		if info.Sections[section] == nil {
+			info.Sections[section] = make(map[string]string)
+		}
+		info.Sections[section][line[:i]] = line[i+1:]
+	}
+	parseMemoryInfo(info)
+	parseClientsInfo(info)
+	parseReplicationInfo(info)
+	parseKeyspaceInfo(info)
+	return info, nil
+}
+
+//ParseSentinelMasterInfo parse the reply of SENTINEL MASTER
+func ParseSentinelMasterInfo(fields []interface{}, err error) (*SentinelMasterInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	maps, err := ObjArrToMapArrayReply([]interface{}{fields}, nil)
+	if err != nil {
+		return nil, err
+	}
+	reply := maps[0]
+	if reply["name"] == "" {
+		return nil, fmt.Errorf("unexpected sentinel master reply:%v", fields)
+	}
+	info := &SentinelMasterInfo{
+		Name:                  reply["name"],
+		IP:                    reply["ip"],
+		Port:                  int(infoInt(reply, "port")),
+		RunID:                 reply["runid"],
+		Flags:                 strings.Split(reply["flags"], ","),
+		LinkPendingCommands:   infoInt(reply, "link-pending-commands"),
+		LastPingSent:          infoInt(reply, "last-ping-sent"),
+		LastOkPingReply:       infoInt(reply, "last-ok-ping-reply"),
+		LastPingReply:         infoInt(reply, "last-ping-reply"),
+		DownAfterMilliseconds: infoInt(reply, "down-after-milliseconds"),
+		InfoRefresh:           infoInt(reply, "info-refresh"),
+		RoleReported:          reply["role-reported"],
+		ConfigEpoch:           infoInt(reply, "config-epoch"),
+		NumSlaves:             infoInt(reply, "num-slaves"),
+		NumOtherSentinels:     infoInt(reply, "num-other-sentinels"),
+		Quorum:                infoInt(reply, "quorum"),
+		FailoverTimeout:       infoInt(reply, "failover-timeout"),
+		ParallelSyncs:         infoInt(reply, "parallel-syncs"),
+		Fields:                reply,
+	}
+	return info, nil
+}
+
+//infoInt parse the integer field,0 if it is absent or malformed
+func infoInt(fields map[string]string, field string) int64 {
+	n, _ := strconv.ParseInt(fields[field], 10, 64)
+	return n
+}
+
+func parseMemoryInfo(info *ServerInfo) {
+	fields := info.Sections["memory"]
+	ratio, _ := strconv.ParseFloat(fields["mem_fragmentation_ratio"], 64)
+	info.Memory = MemoryInfo{
+		UsedMemory:         infoInt(fields, "used_memory"),
+		UsedMemoryRss:      infoInt(fields, "used_memory_rss"),
+		UsedMemoryPeak:     infoInt(fields, "used_memory_peak"),
+		MaxMemory:          infoInt(fields, "maxmemory"),
+		MaxMemoryPolicy:    fields["maxmemory_policy"],
+		FragmentationRatio: ratio,
+	}
+}
+
+func parseClientsInfo(info *ServerInfo) {
+	fields := info.Sections["clients"]
+	info.Clients = ClientsInfo{
+		ConnectedClients: infoInt(fields, "connected_clients"),
+		BlockedClients:   infoInt(fields, "blocked_clients"),
+		MaxClients:       infoInt(fields, "maxclients"),
+	}
+}
+
+func parseReplicationInfo(info *ServerInfo) {
+	fields := info.Sections["replication"]
+	info.Replication = ReplicationInfo{
+		Role:             fields["role"],
+		ConnectedSlaves:  infoInt(fields, "connected_slaves"),
+		MasterHost:       fields["master_host"],
+		MasterPort:       int(infoInt(fields, "master_port")),
+		MasterLinkStatus: fields["master_link_status"],
+		MasterReplOffset: infoInt(fields, "master_repl_offset"),
+		Slaves:           make([]SlaveInfo, 0),
+	}
+	for i := int64(0); i < info.Replication.ConnectedSlaves; i++ {
+		value, ok := fields["slave"+strconv.FormatInt(i, 10)]
+		if !ok {
+			continue
+		}
+		//ip=127.0.0.1,port=6380,state=online,offset=100,lag=0
+		slave := parseInfoPairs(value)
+		info.Replication.Slaves = append(info.Replication.Slaves, SlaveInfo{
+			IP:     slave["ip"],
+			Port:   int(infoInt(slave, "port")),
+			State:  slave["state"],
+			Offset: infoInt(slave, "offset"),
+			Lag:    infoInt(slave, "lag"),
+		})
+	}
+}
+
+func parseKeyspaceInfo(info *ServerInfo) {
+	//db0:keys=1,expires=0,avg_ttl=0
+	for field, value := range info.Sections["keyspace"] {
+		if !strings.HasPrefix(field, "db") {
+			continue
+		}
+		db, err := strconv.Atoi(field[2:])
+		if err != nil {
+			continue
+		}
+		pairs := parseInfoPairs(value)
+		info.Keyspace[db] = KeyspaceInfo{
+			Keys:    infoInt(pairs, "keys"),
+			Expires: infoInt(pairs, "expires"),
+			AvgTTL:  infoInt(pairs, "avg_ttl"),
+		}
+	}
+}
+
+//parseInfoPairs parse the value of the form k1=v1,k2=v2
+func parseInfoPairs(value string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if i := strings.Index(pair, "="); i >= 0 {
+			pairs[pair[:i]] = pair[i+1:]
+		}
+	}
+	return pairs
+}
+
+//ParseDebugObject parse the reply of DEBUG OBJECT,
+//such as: Value at:0x7f refcount:1 encoding:embstr serializedlength:5 lru:123 lru_seconds_idle:10
+func ParseDebugObject(reply string, err error) (*DebugObjectInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	info := &DebugObjectInfo{Fields: make(map[string]string)}
+	for _, field := range strings.Fields(reply) {
+		i := strings.Index(field, ":")
+		if i < 0 {
+			continue
+		}
+		info.Fields[field[:i]] = field[i+1:]
+	}
+	info.Encoding = info.Fields["encoding"]
+	info.RefCount = infoInt(info.Fields, "refcount")
+	info.SerializedLength = infoInt(info.Fields, "serializedlength")
+	info.LRU = infoInt(info.Fields, "lru")
+	info.LRUSecondsIdle = infoInt(info.Fields, "lru_seconds_idle")
+	return info, nil
+}
+
+//ParseClientList parse the reply of CLIENT LIST,a line for every client,
+//such as: id=3 addr=127.0.0.1:52555 laddr=127.0.0.1:6379 fd=8 name= age=10 idle=0 flags=N db=0 ... cmd=client|list user=default
+func ParseClientList(reply string, err error) ([]ClientInfo, error) {
+	if err != nil {
+		return nil, err
+	}
+	clients := make([]ClientInfo, 0)
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		clients = append(clients, parseClientInfo(line))
+	}
+	return clients, nil
+}
+
+func parseClientInfo(line string) ClientInfo {
+	fields := make(map[string]string)
+	for _, field := range strings.Fields(line) {
+		if i := strings.Index(field, "="); i >= 0 {
+			fields[field[:i]] = field[i+1:]
+		}
+	}
+	return ClientInfo{
+		ID:     infoInt(fields, "id"),
+		Addr:   fields["addr"],
+		LAddr:  fields["laddr"],
+		FD:     infoInt(fields, "fd"),
+		Name:   fields["name"],
+		Age:    infoInt(fields, "age"),
+		Idle:   infoInt(fields, "idle"),
+		Flags:  fields["flags"],
+		DB:     int(infoInt(fields, "db")),
+		Sub:    infoInt(fields, "sub"),
+		PSub:   infoInt(fields, "psub"),
+		Multi:  infoInt(fields, "multi"),
+		Cmd:    fields["cmd"],
+		User:   fields["user"],
+		Fields: fields,
+	}
+}
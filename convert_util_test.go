@@ -26,6 +26,12 @@ func TestByteArrToStringReply(t *testing.T) {
 	assert.Equal(t, "", s)
 }
 
+func TestByteArrArrToStrArr(t *testing.T) {
+	arr := ByteArrArrToStrArr([][]byte{[]byte("a"), []byte("good")})
+	assert.Equal(t, []string{"a", "good"}, arr)
+	assert.Equal(t, []string{}, ByteArrArrToStrArr(nil))
+}
+
 func TestByteArrayToFloat64(t *testing.T) {
 	arr := []byte("1.1")
 	f := ByteArrToFloat64(arr)
@@ -123,6 +129,15 @@ func TestObjectArrToMapArrayReply(t *testing.T) {
 	assert.Len(t, arr, 4)
 }
 
+func TestParseSentinelMasterInfo(t *testing.T) {
+	_, err := ParseSentinelMasterInfo(nil, newConnectError("broken"))
+	assert.NotNil(t, err)
+	_, err = ParseSentinelMasterInfo([]interface{}{[]byte("ip"), []byte("127.0.0.1")}, nil)
+	assert.NotNil(t, err)
+	_, err = ObjArrToMapArrayReply([]interface{}{"a"}, nil)
+	assert.NotNil(t, err)
+}
+
 func TestObjectArrToScanResultReply(t *testing.T) {
 	result, e := ObjArrToScanResultReply(nil, newNoReachableClusterNodeError("no reachable server"))
 	assert.NotNil(t, e, e.Error())
@@ -292,3 +307,240 @@ func Test_stringBuilder_build(t *testing.T) {
 	assert.NotNil(t, e)
 	assert.Equal(t, "", r)
 }
+
+func TestParseClusterSlots(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{int64(0), int64(5460),
+			[]interface{}{[]byte("127.0.0.1"), int64(7000), []byte("id0")},
+			[]interface{}{[]byte("127.0.0.1"), int64(7003), []byte("id3")}},
+		[]interface{}{int64(5461), int64(10922),
+			[]interface{}{[]byte("127.0.0.1"), int64(7001)}},
+	}
+	ranges, err := ParseClusterSlots(reply, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []ClusterSlotRange{
+		{Start: 0, End: 5460, Master: ClusterSlotNode{"127.0.0.1", 7000, "id0"}, Replicas: []ClusterSlotNode{{"127.0.0.1", 7003, "id3"}}},
+		{Start: 5461, End: 10922, Master: ClusterSlotNode{"127.0.0.1", 7001, ""}, Replicas: []ClusterSlotNode{}},
+	}, ranges)
+
+	_, err = ParseClusterSlots(nil, newConnectError("broken"))
+	assert.NotNil(t, err)
+	_, err = ParseClusterSlots([]interface{}{[]interface{}{int64(0)}}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseClusterSlots([]interface{}{[]interface{}{[]byte("0"), int64(1), []interface{}{}}}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseClusterSlots([]interface{}{[]interface{}{int64(0), int64(1), []interface{}{int64(1), int64(1)}}}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseClusterShards(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("slots"), []interface{}{int64(0), int64(5460), int64(10923), int64(10999)},
+			[]byte("nodes"), []interface{}{
+				[]interface{}{
+					[]byte("id"), []byte("id0"),
+					[]byte("port"), int64(7000),
+					[]byte("ip"), []byte("127.0.0.1"),
+					[]byte("endpoint"), []byte("127.0.0.1"),
+					[]byte("hostname"), []byte(""),
+					[]byte("role"), []byte("master"),
+					[]byte("replication-offset"), int64(72156),
+					[]byte("health"), []byte("online"),
+				},
+				[]interface{}{
+					[]byte("id"), []byte("id3"),
+					[]byte("port"), int64(7003),
+					[]byte("tls-port"), []byte("7103"),
+					[]byte("role"), []byte("replica"),
+					[]byte("health"), []byte("loading"),
+				},
+			},
+		},
+	}
+	shards, err := ParseClusterShards(reply, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []ClusterShard{{
+		Slots: [][2]int{{0, 5460}, {10923, 10999}},
+		Nodes: []ClusterShardNode{
+			{ID: "id0", Port: 7000, IP: "127.0.0.1", Endpoint: "127.0.0.1", Role: "master", ReplicationOffset: 72156, Health: "online"},
+			{ID: "id3", Port: 7003, TLSPort: 7103, Role: "replica", Health: "loading"},
+		},
+	}}, shards)
+
+	_, err = ParseClusterShards(nil, newConnectError("broken"))
+	assert.NotNil(t, err)
+	_, err = ParseClusterShards([]interface{}{[]interface{}{[]byte("slots")}}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseClusterShards([]interface{}{[]interface{}{[]byte("slots"), []interface{}{1.1, int64(1)}}}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseClusterShards([]interface{}{[]interface{}{[]byte("nodes"), []interface{}{[]interface{}{[]byte("port"), []byte("a")}}}}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseClusterLinks(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("direction"), []byte("to"),
+			[]byte("node"), []byte("id1"),
+			[]byte("create-time"), int64(1639442739375),
+			[]byte("events"), []byte("rw"),
+			[]byte("send-buffer-allocated"), int64(4512),
+			[]byte("send-buffer-used"), int64(0),
+		},
+		[]interface{}{
+			[]byte("direction"), []byte("from"),
+			[]byte("node"), []byte("id1"),
+			[]byte("create-time"), []byte("1639442739411"),
+			[]byte("events"), []byte("r"),
+		},
+	}
+	links, err := ParseClusterLinks(reply, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []ClusterLink{
+		{Direction: "to", Node: "id1", CreateTime: 1639442739375, Events: "rw", SendBufferAllocated: 4512},
+		{Direction: "from", Node: "id1", CreateTime: 1639442739411, Events: "r"},
+	}, links)
+
+	_, err = ParseClusterLinks(nil, newConnectError("broken"))
+	assert.NotNil(t, err)
+	_, err = ParseClusterLinks([]interface{}{[]interface{}{[]byte("direction")}}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseClusterLinks([]interface{}{[]interface{}{[]byte("create-time"), []byte("a")}}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseInfo(t *testing.T) {
+	reply := "# Server\r\nredis_version:7.2.4\r\n\r\n" +
+		"# Clients\r\nconnected_clients:3\r\nblocked_clients:1\r\nmaxclients:10000\r\n\r\n" +
+		"# Memory\r\nused_memory:1048576\r\nused_memory_rss:2097152\r\nused_memory_peak:3145728\r\n" +
+		"maxmemory:0\r\nmaxmemory_policy:noeviction\r\nmem_fragmentation_ratio:2.00\r\n\r\n" +
+		"# Replication\r\nrole:master\r\nconnected_slaves:2\r\n" +
+		"slave0:ip=127.0.0.1,port=6380,state=online,offset=100,lag=0\r\n" +
+		"slave1:ip=127.0.0.2,port=6381,state=wait_bgsave,offset=0,lag=1\r\nmaster_repl_offset:100\r\n\r\n" +
+		"# Keyspace\r\ndb0:keys=10,expires=2,avg_ttl=5000\r\ndb3:keys=1,expires=0,avg_ttl=0\r\n"
+	info, err := ParseInfo(reply, nil)
+	assert.Nil(t, err)
+	version, ok := info.Get("Server", "redis_version")
+	assert.True(t, ok)
+	assert.Equal(t, "7.2.4", version)
+	_, ok = info.Get("server", "no_such_field")
+	assert.False(t, ok)
+	assert.Equal(t, ClientsInfo{ConnectedClients: 3, BlockedClients: 1, MaxClients: 10000}, info.Clients)
+	assert.Equal(t, MemoryInfo{
+		UsedMemory:         1048576,
+		UsedMemoryRss:      2097152,
+		UsedMemoryPeak:     3145728,
+		MaxMemoryPolicy:    "noeviction",
+		FragmentationRatio: 2,
+	}, info.Memory)
+	assert.Equal(t, "master", info.Replication.Role)
+	assert.Equal(t, int64(100), info.Replication.MasterReplOffset)
+	assert.Equal(t, []SlaveInfo{
+		{IP: "127.0.0.1", Port: 6380, State: "online", Offset: 100, Lag: 0},
+		{IP: "127.0.0.2", Port: 6381, State: "wait_bgsave", Offset: 0, Lag: 1},
+	}, info.Replication.Slaves)
+	assert.Equal(t, map[int]KeyspaceInfo{
+		0: {Keys: 10, Expires: 2, AvgTTL: 5000},
+		3: {Keys: 1},
+	}, info.Keyspace)
+
+	info, err = ParseInfo("# Replication\r\nrole:slave\r\nmaster_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:down\r\n", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ReplicationInfo{Role: "slave", MasterHost: "10.0.0.1", MasterPort: 6379, MasterLinkStatus: "down", Slaves: []SlaveInfo{}}, info.Replication)
+	assert.Len(t, info.Keyspace, 0)
+
+	_, err = ParseInfo("", newConnectError("broken"))
+	assert.NotNil(t, err)
+}
+
+func TestParseClusterNodes(t *testing.T) {
+	reply := "07c37dfeb235213a872192d90877d0cd55635b91 127.0.0.1:30004@31004,host4 slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238317239 4 connected\n" +
+		"e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460 5462 [5463->-67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1] [5464-<-292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f]\n" +
+		"\n"
+	nodes, err := ParseClusterNodes(reply, nil)
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, ClusterNode{
+		ID:          "07c37dfeb235213a872192d90877d0cd55635b91",
+		Addr:        "127.0.0.1:30004",
+		Host:        "127.0.0.1",
+		Port:        30004,
+		BusPort:     31004,
+		Hostname:    "host4",
+		Flags:       []string{"slave"},
+		MasterID:    "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca",
+		PongRecv:    1426238317239,
+		ConfigEpoch: 4,
+		LinkState:   "connected",
+		Slots:       [][2]int{},
+		Migrating:   map[int]string{},
+		Importing:   map[int]string{},
+	}, nodes[0])
+	assert.True(t, nodes[1].HasFlag("master"))
+	assert.True(t, nodes[1].HasFlag("myself"))
+	assert.False(t, nodes[1].HasFlag("slave"))
+	assert.Equal(t, "", nodes[1].MasterID)
+	assert.Equal(t, [][2]int{{0, 5460}, {5462, 5462}}, nodes[1].Slots)
+	assert.Equal(t, map[int]string{5463: "67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1"}, nodes[1].Migrating)
+	assert.Equal(t, map[int]string{5464: "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f"}, nodes[1].Importing)
+
+	_, err = ParseClusterNodes("", newConnectError("broken"))
+	assert.NotNil(t, err)
+	for _, line := range []string{
+		"id 127.0.0.1:30001 master - 0 0 1",
+		"id 127.0.0.1:30001@bus master - 0 0 1 connected",
+		"id 127.0.0.1:port master - 0 0 1 connected",
+		"id 127.0.0.1:30001 master - a 0 1 connected",
+		"id 127.0.0.1:30001 master - 0 a 1 connected",
+		"id 127.0.0.1:30001 master - 0 0 a connected",
+		"id 127.0.0.1:30001 master - 0 0 1 connected a-1",
+		"id 127.0.0.1:30001 master - 0 0 1 connected 0-a",
+		"id 127.0.0.1:30001 master - 0 0 1 connected [1]",
+		"id 127.0.0.1:30001 master - 0 0 1 connected [a->-id]",
+	} {
+		_, err = ParseClusterNodes(line, nil)
+		assert.NotNil(t, err, line)
+	}
+}
+
+func TestParseStreamClaimResult(t *testing.T) {
+	reply := []interface{}{
+		[]byte("0-0"),
+		[]interface{}{
+			[]interface{}{[]byte("1-0"), []interface{}{[]byte("name"), []byte("a")}},
+			[]interface{}(nil),
+			[]interface{}{[]byte("2-0"), []interface{}(nil)},
+		},
+		[]interface{}{[]byte("3-0")},
+	}
+	result, err := ParseStreamClaimResult(reply, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &StreamClaimResult{
+		Next:    "0-0",
+		Entries: []StreamEntry{{ID: "1-0", Fields: map[string]string{"name": "a"}}, {ID: "2-0"}},
+		Deleted: []string{"3-0"},
+	}, result)
+
+	_, err = ParseStreamClaimResult(nil, newConnectError("broken"))
+	assert.NotNil(t, err)
+	_, err = ParseStreamClaimResult([]interface{}{[]byte("0-0")}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseStreamClaimResult([]interface{}{[]byte("0-0"), []interface{}{[]interface{}{int64(1)}}}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseStreamEntriesArr([]interface{}{[]interface{}{[]byte("stream")}}, nil)
+	assert.NotNil(t, err)
+	_, err = ParseStreamPendingEntries([]interface{}{[]interface{}{[]byte("1-0"), []byte("c1"), []byte("idle"), int64(1)}}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestToStrSetReply(t *testing.T) {
+	set, err := ToStrSetReply(map[string]struct{}{"a": {}}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]struct{}{"a": {}}, set)
+
+	_, err = ToStrSetReply(nil, newConnectError("broken"))
+	assert.NotNil(t, err)
+	_, err = ToStrSetReply([]string{"a"}, nil)
+	assert.EqualError(t, err, "unexpected type:[]string")
+}
@@ -0,0 +1,263 @@
+package godis
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDelayedPollInterval = time.Second
+	defaultDelayedBatchSize    = 100
+)
+
+//delayedScheduleScript store the job and schedule its id at the due time
+const delayedScheduleScript = `redis.call("hset", KEYS[2], ARGV[1], ARGV[3])
+return redis.call("zadd", KEYS[1], ARGV[2], ARGV[1])`
+
+//delayedPopScript pop at most ARGV[2] jobs due before ARGV[1],return their ids,jobs and due times
+const delayedPopScript = `local items = redis.call("zrangebyscore", KEYS[1], "-inf", ARGV[1], "withscores", "limit", 0, ARGV[2])
+local jobs = {}
+for i = 1, #items, 2 do
+	local id = items[i]
+	redis.call("zrem", KEYS[1], id)
+	local job = redis.call("hget", KEYS[2], id)
+	redis.call("hdel", KEYS[2], id)
+	if job then
+		jobs[#jobs + 1] = id
+		jobs[#jobs + 1] = job
+		jobs[#jobs + 1] = items[i + 1]
+	end
+end
+return jobs`
+
+//delayedCancelScript remove the scheduled job
+const delayedCancelScript = `redis.call("hdel", KEYS[2], ARGV[1])
+return redis.call("zrem", KEYS[1], ARGV[1])`
+
+//DelayedQueueOption delayed queue options
+type DelayedQueueOption struct {
+	Name         string                           //the sorted set of the job ids scored by due time,the jobs are stored in the hash Name+":jobs"
+	PollInterval time.Duration                    //interval to pop the due jobs,default 1s
+	BatchSize    int                              //max count of jobs popped by a poll,default 100
+	RetryDelay   time.Duration                    //a failed job is scheduled again after RetryDelay,0 means it is dropped
+	OnError      func(job *DelayedJob, err error) //called when a job fails or has no handler,it must not block
+}
+
+//DelayedJob a job scheduled to run at a time
+type DelayedJob struct {
+	ID      string
+	Topic   string //the handler registered for the topic runs the job
+	Payload string
+	DueAt   time.Time
+}
+
+//DelayedQueue schedule jobs to run at a time,built on a sorted set of due timestamps.
+//
+//the due jobs are popped atomically by a lua script,so every job is handed to one dispatcher
+//even if many processes poll the same queue,then the handler registered for the topic of the job runs.
+//a job is removed before it runs,it is lost if the process crashes while handling it.
+//the keys aren't hash tagged,use a name such as {jobs} in cluster mode.
+//
+//DelayedQueue is safe for concurrent use
+type DelayedQueue struct {
+	pool   *Pool
+	option DelayedQueueOption
+
+	mu       sync.Mutex
+	handlers map[string]func(job *DelayedJob) error
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+//NewDelayedQueue create new delayed queue
+func NewDelayedQueue(pool *Pool, option *DelayedQueueOption) *DelayedQueue {
+	opt := *option
+	if opt.PollInterval <= 0 {
+		opt.PollInterval = defaultDelayedPollInterval
+	}
+	if opt.BatchSize <= 0 {
+		opt.BatchSize = defaultDelayedBatchSize
+	}
+	return &DelayedQueue{pool: pool, option: opt, handlers: make(map[string]func(job *DelayedJob) error)}
+}
+
+//Handle register the handler of the jobs of topic,it replaces the handler registered before
+func (q *DelayedQueue) Handle(topic string, handler func(job *DelayedJob) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[topic] = handler
+}
+
+//Schedule schedule a job to run after delay,return the id of the job,
+//the topic must not contain a line break
+func (q *DelayedQueue) Schedule(topic, payload string, delay time.Duration) (string, error) {
+	return q.ScheduleAt(topic, payload, time.Now().Add(delay))
+}
+
+//ScheduleAt schedule a job to run at the time,return the id of the job
+func (q *DelayedQueue) ScheduleAt(topic, payload string, at time.Time) (string, error) {
+	job := &DelayedJob{ID: randomKeySuffix(), Topic: topic, Payload: payload, DueAt: at}
+	return job.ID, q.schedule(job)
+}
+
+func (q *DelayedQueue) schedule(job *DelayedJob) error {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	due := strconv.FormatInt(job.DueAt.UnixNano()/int64(time.Millisecond), 10)
+	_, err = redis.Eval(delayedScheduleScript, 2, q.option.Name, q.jobsKey(), job.ID, due, job.Topic+"\n"+job.Payload)
+	return err
+}
+
+//Cancel remove the job if it hasn't been popped,return false if it isn't scheduled
+func (q *DelayedQueue) Cancel(id string) (bool, error) {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return false, err
+	}
+	defer redis.Close()
+	reply, err := redis.Eval(delayedCancelScript, 2, q.option.Name, q.jobsKey(), id)
+	if err != nil {
+		return false, err
+	}
+	removed, _ := reply.(int64)
+	return removed > 0, nil
+}
+
+//Len return the count of scheduled jobs
+func (q *DelayedQueue) Len() (int64, error) {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZCard(q.option.Name)
+}
+
+//Poll pop the due jobs and run their handlers in order,return the count of jobs popped,
+//it is called every PollInterval after Start
+func (q *DelayedQueue) Poll() (int, error) {
+	jobs, err := q.pop()
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range jobs {
+		q.run(job)
+	}
+	return len(jobs), nil
+}
+
+//Start run the dispatcher goroutine which polls the due jobs until Close,
+//it polls again without waiting while a poll pops a full batch
+func (q *DelayedQueue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.done != nil {
+		return
+	}
+	q.done = make(chan struct{})
+	q.stopped = make(chan struct{})
+	go q.dispatch(q.done, q.stopped)
+}
+
+//Close stop the dispatcher and wait until the running handlers return
+func (q *DelayedQueue) Close() {
+	q.mu.Lock()
+	done, stopped := q.done, q.stopped
+	q.done, q.stopped = nil, nil
+	q.mu.Unlock()
+	if done == nil {
+		return
+	}
+	close(done)
+	<-stopped
+}
+
+func (q *DelayedQueue) dispatch(done, stopped chan struct{}) {
+	defer close(stopped)
+	for {
+		n, err := q.Poll()
+		if err != nil && q.option.OnError != nil {
+			q.option.OnError(nil, err)
+		}
+		if n == q.option.BatchSize {
+			select {
+			case <-done:
+				return
+			default:
+				continue
+			}
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(q.option.PollInterval):
+		}
+	}
+}
+
+//pop pop the due jobs atomically
+func (q *DelayedQueue) pop() ([]*DelayedJob, error) {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	now := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	reply, err := redis.Eval(delayedPopScript, 2, q.option.Name, q.jobsKey(), now, strconv.Itoa(q.option.BatchSize))
+	if err != nil {
+		return nil, err
+	}
+	fields, _ := reply.([]interface{})
+	jobs := make([]*DelayedJob, 0, len(fields)/3)
+	for i := 0; i+2 < len(fields); i += 3 {
+		id, _ := fields[i].(string)
+		value, _ := fields[i+1].(string)
+		score, _ := fields[i+2].(string)
+		due, _ := strconv.ParseInt(score, 10, 64)
+		job := &DelayedJob{ID: id, DueAt: time.Unix(0, due*int64(time.Millisecond))}
+		if i := strings.IndexByte(value, '\n'); i >= 0 {
+			job.Topic, job.Payload = value[:i], value[i+1:]
+		} else {
+			job.Topic = value
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+//run run the handler of the job,schedule it again after RetryDelay if it fails
+func (q *DelayedQueue) run(job *DelayedJob) {
+	q.mu.Lock()
+	handler := q.handlers[job.Topic]
+	q.mu.Unlock()
+	if handler == nil {
+		q.fail(job, newDataError("no handler for topic "+job.Topic))
+		return
+	}
+	err := handler(job)
+	if err == nil {
+		return
+	}
+	q.fail(job, err)
+	if q.option.RetryDelay > 0 {
+		job.DueAt = time.Now().Add(q.option.RetryDelay)
+		if err := q.schedule(job); err != nil {
+			q.fail(job, err)
+		}
+	}
+}
+
+func (q *DelayedQueue) fail(job *DelayedJob, err error) {
+	if q.option.OnError != nil {
+		q.option.OnError(job, err)
+	}
+}
+
+func (q *DelayedQueue) jobsKey() string {
+	return q.option.Name + ":jobs"
+}
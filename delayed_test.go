@@ -0,0 +1,104 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDelayedQueue(t *testing.T) {
+	flushAll()
+	pool := NewPool(nil, option)
+	defer pool.Destroy()
+	failed := make([]string, 0)
+	queue := NewDelayedQueue(pool, &DelayedQueueOption{
+		Name: "delayed",
+		OnError: func(job *DelayedJob, err error) {
+			failed = append(failed, job.Topic+" "+err.Error())
+		},
+	})
+	handled := make([]*DelayedJob, 0)
+	queue.Handle("mail", func(job *DelayedJob) error {
+		handled = append(handled, job)
+		return nil
+	})
+	at := time.Now().Add(-time.Second).Truncate(time.Millisecond)
+	id, err := queue.ScheduleAt("mail", "a\nb", at)
+	assert.Nil(t, err)
+	_, err = queue.Schedule("mail", "later", time.Hour)
+	assert.Nil(t, err)
+	cancelled, err := queue.Schedule("mail", "cancelled", 0)
+	assert.Nil(t, err)
+	_, err = queue.Schedule("sms", "no handler", 0)
+	assert.Nil(t, err)
+	ok, err := queue.Cancel(cancelled)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = queue.Cancel(cancelled)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	l, err := queue.Len()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), l)
+
+	n, err := queue.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []*DelayedJob{{ID: id, Topic: "mail", Payload: "a\nb", DueAt: at}}, handled)
+	assert.Equal(t, []string{"sms no handler for topic sms"}, failed)
+	n, err = queue.Poll()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+	l, _ = queue.Len()
+	assert.Equal(t, int64(1), l)
+}
+
+func TestDelayedQueue_Start(t *testing.T) {
+	flushAll()
+	pool := NewPool(nil, option)
+	defer pool.Destroy()
+	queue := NewDelayedQueue(pool, &DelayedQueueOption{
+		Name:         "delayed",
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    2,
+		RetryDelay:   10 * time.Millisecond,
+	})
+	var mu sync.Mutex
+	payloads := make([]string, 0)
+	attempts := 0
+	done := make(chan struct{})
+	queue.Handle("job", func(job *DelayedJob) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if job.Payload == "retry" && attempts == 0 {
+			attempts++
+			return errors.New("failed")
+		}
+		payloads = append(payloads, job.Payload)
+		if len(payloads) == 4 {
+			close(done)
+		}
+		return nil
+	})
+	for _, payload := range []string{"1", "2", "retry"} {
+		_, err := queue.Schedule("job", payload, 0)
+		assert.Nil(t, err)
+	}
+	_, err := queue.Schedule("job", "delayed", 50*time.Millisecond)
+	assert.Nil(t, err)
+	queue.Start()
+	queue.Start()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("jobs are not dispatched")
+	}
+	queue.Close()
+	queue.Close()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"1", "2", "retry", "delayed"}, payloads)
+	assert.Equal(t, 1, attempts)
+}
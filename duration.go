@@ -0,0 +1,100 @@
+package godis
+
+import "time"
+
+//durationToMillis the milliseconds of d,a positive part of a millisecond is rounded up,
+//so a short ttl doesn't become 0 which deletes the key or fails the command
+func durationToMillis(d time.Duration) int64 {
+	if d > 0 && d%time.Millisecond != 0 {
+		return int64(d/time.Millisecond) + 1
+	}
+	return int64(d / time.Millisecond)
+}
+
+//timeToMillis the unix time of t in milliseconds
+func timeToMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+//<editor-fold desc="durationcommands">
+
+//ExpireDuration set the ttl of key to d by PEXPIRE,unlike Expire the unit is part of the argument,
+//such as ExpireDuration(key, 10*time.Minute)
+//
+//1 if the timeout was set.
+//0 if key does not exist.
+func (r *Redis) ExpireDuration(key string, d time.Duration) (int64, error) {
+	return r.PExpire(key, durationToMillis(d))
+}
+
+//ExpireAtTime set key to expire at t by PEXPIREAT,a time in the past deletes the key
+//
+//1 if the timeout was set.
+//0 if key does not exist.
+func (r *Redis) ExpireAtTime(key string, t time.Time) (int64, error) {
+	return r.PExpireAt(key, timeToMillis(t))
+}
+
+//SetWithTTL set key to value which expires after ttl by PSETEX,ttl must be positive
+func (r *Redis) SetWithTTL(key, value string, ttl time.Duration) (string, error) {
+	return r.PSetEx(key, durationToMillis(ttl), value)
+}
+
+//TTLDuration the remaining ttl of key by PTTL,TTLMissing if key doesn't exist and TTLNoExpiry if key has no expire
+func (r *Redis) TTLDuration(key string) (time.Duration, error) {
+	ttl, err := r.PTTL(key)
+	if err != nil {
+		return 0, err
+	}
+	return pttlToDuration(ttl), nil
+}
+
+//ExpireDuration see comment in duration.go
+func (r *RedisCluster) ExpireDuration(key string, d time.Duration) (int64, error) {
+	return r.PExpire(key, durationToMillis(d))
+}
+
+//ExpireAtTime see comment in duration.go
+func (r *RedisCluster) ExpireAtTime(key string, t time.Time) (int64, error) {
+	return r.PExpireAt(key, timeToMillis(t))
+}
+
+//SetWithTTL see comment in duration.go
+func (r *RedisCluster) SetWithTTL(key, value string, ttl time.Duration) (string, error) {
+	return r.PSetEx(key, durationToMillis(ttl), value)
+}
+
+//TTLDuration see comment in duration.go
+func (r *RedisCluster) TTLDuration(key string) (time.Duration, error) {
+	ttl, err := r.PTTL(key)
+	if err != nil {
+		return 0, err
+	}
+	return pttlToDuration(ttl), nil
+}
+
+//ExpireDuration  see comment in duration.go
+func (p *PooledRedis) ExpireDuration(key string, d time.Duration) (int64, error) {
+	return p.PExpire(key, durationToMillis(d))
+}
+
+//ExpireAtTime  see comment in duration.go
+func (p *PooledRedis) ExpireAtTime(key string, t time.Time) (int64, error) {
+	return p.PExpireAt(key, timeToMillis(t))
+}
+
+//SetWithTTL  see comment in duration.go
+func (p *PooledRedis) SetWithTTL(key, value string, ttl time.Duration) (string, error) {
+	return p.PSetEx(key, durationToMillis(ttl), value)
+}
+
+//TTLDuration  see comment in duration.go
+func (p *PooledRedis) TTLDuration(key string) (time.Duration, error) {
+	ttl, err := p.PTTL(key)
+	if err != nil {
+		return 0, err
+	}
+	return pttlToDuration(ttl), nil
+}
+
+//</editor-fold>
@@ -0,0 +1,56 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRedis_ExpireDuration(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	s, err := redis.SetWithTTL("godis", "good", time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	ttl, err := redis.TTLDuration("godis")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+
+	c, err := redis.ExpireDuration("godis", 90*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	ttl, _ = redis.TTLDuration("godis")
+	assert.True(t, ttl > 89*time.Second && ttl <= 90*time.Second)
+
+	c, err = redis.ExpireAtTime("godis", time.Now().Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	ttl, _ = redis.TTLDuration("godis")
+	assert.True(t, ttl > 59*time.Minute && ttl <= time.Hour)
+
+	c, _ = redis.ExpireDuration("godis1", time.Minute)
+	assert.Equal(t, int64(0), c)
+	ttl, _ = redis.TTLDuration("godis1")
+	assert.Equal(t, TTLMissing, ttl)
+	redis.Set("godis1", "good")
+	ttl, _ = redis.TTLDuration("godis1")
+	assert.Equal(t, TTLNoExpiry, ttl)
+
+	pooled := NewPooledRedis(option, &PoolConfig{MaxTotal: 1})
+	defer pooled.Close()
+	c, err = pooled.ExpireAtTime("godis1", time.Now().Add(-time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	ttl, _ = pooled.TTLDuration("godis1")
+	assert.Equal(t, TTLMissing, ttl)
+}
+
+func TestDurationToMillis(t *testing.T) {
+	assert.Equal(t, int64(1500), durationToMillis(1500*time.Millisecond))
+	assert.Equal(t, int64(1), durationToMillis(time.Microsecond))
+	assert.Equal(t, int64(2), durationToMillis(1001*time.Microsecond))
+	assert.Equal(t, int64(0), durationToMillis(0))
+	assert.Equal(t, int64(-1000), durationToMillis(-time.Second))
+	assert.Equal(t, int64(1500000000000), timeToMillis(time.Unix(1500000000, 0)))
+}
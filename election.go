@@ -0,0 +1,180 @@
+package godis
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultElectionTTL = 10 * time.Second
+
+//electionRenewScript extend the ttl of the key only if it is still held by the candidate
+const electionRenewScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+
+//ElectionOption leader election options
+type ElectionOption struct {
+	Key           string        //the key held by the leader
+	ID            string        //identity of the candidate stored in the key,default a random string
+	TTL           time.Duration //the leadership expires after TTL without renewal,default 10 seconds
+	RenewInterval time.Duration //interval to renew the leadership or campaign again,default TTL/3
+	OnElected     func()        //called when the candidate becomes the leader
+	OnRevoked     func()        //called when the candidate loses the leadership or resigns
+}
+
+//Election elect one leader among the candidates campaigning for the same key,for singleton workers.
+//
+//a candidate becomes the leader by SET NX PX,then renews the ttl every RenewInterval while it is the leader.
+//the leadership is revoked as soon as a renewal fails,even by a connection error,
+//so there is at most one leader as long as the renewal returns within TTL,
+//the others take over after TTL when the leader stops renewing.
+//
+//the callbacks are called in order by the campaign goroutine.
+//Election is safe for concurrent use
+type Election struct {
+	pool   *Pool
+	option ElectionOption
+
+	mu      sync.Mutex
+	leader  bool
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+//NewElection create new election
+func NewElection(pool *Pool, option *ElectionOption) *Election {
+	opt := *option
+	if opt.ID == "" {
+		opt.ID = randomKeySuffix()
+	}
+	if opt.TTL < time.Millisecond {
+		opt.TTL = defaultElectionTTL
+	}
+	if opt.RenewInterval <= 0 || opt.RenewInterval >= opt.TTL {
+		opt.RenewInterval = opt.TTL / 3
+	}
+	return &Election{pool: pool, option: opt}
+}
+
+//ID the identity of the candidate
+func (e *Election) ID() string {
+	return e.option.ID
+}
+
+//Campaign start campaigning in background until Resign,it returns immediately
+func (e *Election) Campaign() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.done != nil {
+		return
+	}
+	e.done = make(chan struct{})
+	e.stopped = make(chan struct{})
+	go e.campaign(e.done, e.stopped)
+}
+
+//IsLeader return true if the candidate is the leader
+func (e *Election) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+//Leader return the identity of the current leader,empty if there is none
+func (e *Election) Leader() (string, error) {
+	redis, err := e.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	leader, err := redis.Get(e.option.Key)
+	if errors.Is(err, ErrNil) {
+		return "", nil
+	}
+	return leader, err
+}
+
+//Resign stop campaigning,give up the leadership if the candidate is the leader,
+//so another candidate is elected without waiting for TTL
+func (e *Election) Resign() error {
+	e.mu.Lock()
+	done, stopped := e.done, e.stopped
+	e.done, e.stopped = nil, nil
+	e.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	close(done)
+	<-stopped
+	if !e.IsLeader() {
+		return nil
+	}
+	e.revoke()
+	redis, err := e.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	_, err = redis.Eval(cacheUnlockScript, 1, e.option.Key, e.option.ID)
+	return err
+}
+
+func (e *Election) campaign(done, stopped chan struct{}) {
+	defer close(stopped)
+	for {
+		if e.IsLeader() {
+			if !e.renew() {
+				e.revoke()
+			}
+		} else if e.acquire() {
+			e.mu.Lock()
+			e.leader = true
+			e.mu.Unlock()
+			if e.option.OnElected != nil {
+				e.option.OnElected()
+			}
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(e.option.RenewInterval):
+		}
+	}
+}
+
+//acquire take the key if it is free
+func (e *Election) acquire() bool {
+	redis, err := e.pool.GetResource()
+	if err != nil {
+		return false
+	}
+	defer redis.Close()
+	status, err := redis.SetWithParamsAndTime(e.option.Key, e.option.ID, "nx", "px", e.ttl())
+	return err == nil && status == keywordOk.name
+}
+
+//renew extend the leadership,return false if the key isn't held by the candidate any more or it can't be renewed
+func (e *Election) renew() bool {
+	redis, err := e.pool.GetResource()
+	if err != nil {
+		return false
+	}
+	defer redis.Close()
+	reply, err := redis.Eval(electionRenewScript, 1, e.option.Key, e.option.ID, strconv.FormatInt(e.ttl(), 10))
+	renewed, _ := reply.(int64)
+	return err == nil && renewed == 1
+}
+
+func (e *Election) revoke() {
+	e.mu.Lock()
+	leader := e.leader
+	e.leader = false
+	e.mu.Unlock()
+	if leader && e.option.OnRevoked != nil {
+		e.option.OnRevoked()
+	}
+}
+
+func (e *Election) ttl() int64 {
+	return int64(e.option.TTL / time.Millisecond)
+}
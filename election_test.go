@@ -0,0 +1,65 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestElection(t *testing.T) {
+	flushAll()
+	pool := NewPool(nil, option)
+	defer pool.Destroy()
+	events := make(chan string, 10)
+	newCandidate := func(id string) *Election {
+		return NewElection(pool, &ElectionOption{
+			Key:           "leader",
+			ID:            id,
+			TTL:           time.Second,
+			RenewInterval: 20 * time.Millisecond,
+			OnElected: func() {
+				events <- id + " elected"
+			},
+			OnRevoked: func() {
+				events <- id + " revoked"
+			},
+		})
+	}
+	a := newCandidate("a")
+	b := newCandidate("b")
+	a.Campaign()
+	a.Campaign()
+	assert.Equal(t, "a elected", <-events)
+	assert.True(t, a.IsLeader())
+	b.Campaign()
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, b.IsLeader())
+	leader, err := b.Leader()
+	assert.Nil(t, err)
+	assert.Equal(t, "a", leader)
+
+	//the leadership is renewed beyond the ttl
+	redis := NewRedis(option)
+	defer redis.Close()
+	ttl, err := redis.PTTL("leader")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 900)
+
+	//b takes over once a resigns
+	assert.Nil(t, a.Resign())
+	assert.Equal(t, "a revoked", <-events)
+	assert.False(t, a.IsLeader())
+	assert.Equal(t, "b elected", <-events)
+	assert.Nil(t, a.Resign())
+
+	//b loses the key,it is revoked on the next renewal
+	_, err = redis.Set("leader", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, "b revoked", <-events)
+	assert.Nil(t, b.Resign())
+	leader, err = b.Leader()
+	assert.Nil(t, err)
+	assert.Equal(t, "c", leader)
+
+	assert.NotEmpty(t, NewElection(pool, &ElectionOption{Key: "leader"}).ID())
+}
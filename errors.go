@@ -1,5 +1,50 @@
 package godis
 
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+//the kinds of errors,check them by errors.Is,they match the errors of all the clients whatever the error type is
+var (
+	//ErrConnect the connection failed,such as dial,read and write errors,every ConnectError is ErrConnect,
+	//the command may or may not have been executed
+	ErrConnect = errors.New("redis connection error")
+	//ErrTimeout the dial,read or write timed out,it is also ErrConnect
+	ErrTimeout = errors.New("redis i/o timeout")
+	//ErrProtocol the reply doesn't follow the redis protocol,the connection is broken,it is also ErrConnect
+	ErrProtocol = errors.New("redis protocol error")
+	//ErrAuth the authentication failed or is required,such as WRONGPASS and NOAUTH
+	ErrAuth = errors.New("redis authentication error")
+	//ErrPoolClosed the pool is closed or drained,the redis instance was not borrowed
+	ErrPoolClosed = errors.New("pool is closed")
+)
+
+var (
+	//ErrDisconnected redis is unreachable, the command was not sent
+	ErrDisconnected = errors.New("redis is disconnected")
+	//ErrQueueFull the command was not queued because the disconnected queue is full,or it expired in the queue,it was not sent
+	ErrQueueFull = errors.New("disconnected command queue is full")
+	//ErrCircuitOpen redis is considered down by the circuit breaker,the command was not sent
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+	//ErrMirrorQueueFull too many writes are already waiting to be mirrored,the write is not mirrored
+	ErrMirrorQueueFull = errors.New("mirror queue is full")
+	//ErrBulkLoaderClosed the bulk loader is closed,the command was not queued
+	ErrBulkLoaderClosed = errors.New("bulk loader is closed")
+	//ErrPingTimeout nothing is received by a subscription with keep-alive in time,the connection is considered dead
+	ErrPingTimeout = errors.New("no reply received for ping in subscribe mode")
+	//ErrUnsupportedVersion the command is newer than the server,it was not sent
+	ErrUnsupportedVersion = errors.New("command is not supported by the server version")
+	//ErrCommandDenied the command is blocked by a command filter of the option,it was not sent
+	ErrCommandDenied = errors.New("command is denied")
+	//ErrConcurrentUse the connection is used by another goroutine,the command was not sent or received,see Option.DetectConcurrentUse
+	ErrConcurrentUse = errors.New("redis is used by multiple goroutines concurrently,Redis is not safe for concurrent use,use Pool,PooledRedis or RedisCluster instead")
+	//ErrNil the reply is nil,such as Get on a missing key,only returned when Option.ReturnErrNil is true
+	ErrNil = errors.New("redis: nil reply")
+)
+
 //RedisError basic redis error
 type RedisError struct {
 	Message string
@@ -52,32 +97,40 @@ func (e *NoReachableClusterNodeError) Error() string {
 	return e.Message
 }
 
-//MovedDataError cluster move data error
+//MovedDataError cluster move data error,the slot is served by the node Addr permanently
 type MovedDataError struct {
 	Message string
 	Host    string
 	Port    int
 	Slot    int
+	Addr    string //host:port
 }
 
+//MovedError alias of MovedDataError
+type MovedError = MovedDataError
+
 func newMovedDataError(message string, host string, port int, slot int) *MovedDataError {
-	return &MovedDataError{Message: message, Host: host, Port: port, Slot: slot}
+	return &MovedDataError{Message: message, Host: host, Port: port, Slot: slot, Addr: host + ":" + strconv.Itoa(port)}
 }
 
 func (e *MovedDataError) Error() string {
 	return e.Message
 }
 
-//AskDataError ask data error
+//AskDataError ask data error,the slot is migrating,the next command should be sent to Addr after ASKING
 type AskDataError struct {
 	Message string
 	Host    string
 	Port    int
 	Slot    int
+	Addr    string //host:port
 }
 
+//AskError alias of AskDataError
+type AskError = AskDataError
+
 func newAskDataError(message string, host string, port int, slot int) *AskDataError {
-	return &AskDataError{Message: message, Host: host, Port: port, Slot: slot}
+	return &AskDataError{Message: message, Host: host, Port: port, Slot: slot, Addr: host + ":" + strconv.Itoa(port)}
 }
 
 func (e *AskDataError) Error() string {
@@ -97,6 +150,25 @@ func (e *ClusterError) Error() string {
 	return e.Message
 }
 
+//ClusterDownError the cluster is down,such as some slots are not served,
+//it is a ClusterError too,errors.As finds the ClusterError it wraps
+type ClusterDownError struct {
+	ClusterError
+}
+
+func newClusterDownError(message string) *ClusterDownError {
+	return &ClusterDownError{ClusterError{Message: message}}
+}
+
+func (e *ClusterDownError) Error() string {
+	return e.Message
+}
+
+//Unwrap the ClusterError,CLUSTERDOWN was a plain ClusterError before
+func (e *ClusterDownError) Unwrap() error {
+	return &e.ClusterError
+}
+
 //BusyError operation is busy error
 type BusyError struct {
 	Message string
@@ -123,32 +195,117 @@ func (e *NoScriptError) Error() string {
 	return e.Message
 }
 
+//ReadOnlyError write command against a read only replica
+type ReadOnlyError struct {
+	Message string
+}
+
+func newReadOnlyError(message string) *ReadOnlyError {
+	return &ReadOnlyError{Message: message}
+}
+
+func (e *ReadOnlyError) Error() string {
+	return e.Message
+}
+
+//LoadingError redis is loading the dataset in memory
+type LoadingError struct {
+	Message string
+}
+
+func newLoadingError(message string) *LoadingError {
+	return &LoadingError{Message: message}
+}
+
+func (e *LoadingError) Error() string {
+	return e.Message
+}
+
 //DataError data error
 type DataError struct {
 	Message string
+	cause   error
 }
 
 func newDataError(message string) *DataError {
 	return &DataError{Message: message}
 }
 
+//wrapDataError data error caused by err,such as the error of an element of a multi bulk reply
+func wrapDataError(err error) *DataError {
+	return &DataError{Message: err.Error(), cause: err}
+}
+
+//newAuthError data error of a failed authentication,it is ErrAuth
+func newAuthError(message string) *DataError {
+	return &DataError{Message: message, cause: ErrAuth}
+}
+
 func (e *DataError) Error() string {
 	return e.Message
 }
 
+//Unwrap return the error causing it,ErrAuth if the authentication failed
+func (e *DataError) Unwrap() error {
+	return e.cause
+}
+
 //ConnectError redis connection error,such as io timeout
 type ConnectError struct {
 	Message string
+	cause   error
 }
 
 func newConnectError(message string) *ConnectError {
 	return &ConnectError{Message: message}
 }
 
+//wrapConnectError connection error caused by err,such as a net.Error of the socket
+func wrapConnectError(err error) *ConnectError {
+	return &ConnectError{Message: err.Error(), cause: err}
+}
+
+//asConnectError err as a connection error,it is wrapped unless it is one already
+func asConnectError(err error) *ConnectError {
+	if e, ok := err.(*ConnectError); ok {
+		return e
+	}
+	return wrapConnectError(err)
+}
+
+//newProtocolError connection error of a reply breaking the protocol,it is ErrProtocol
+func newProtocolError(message string) *ConnectError {
+	return &ConnectError{Message: message, cause: ErrProtocol}
+}
+
+//newDisconnectedError connection error caused by ErrDisconnected,ErrQueueFull,ErrCircuitOpen or ErrPingTimeout
+func newDisconnectedError(message string, cause error) *ConnectError {
+	return &ConnectError{Message: message, cause: cause}
+}
+
 func (e *ConnectError) Error() string {
 	return e.Message
 }
 
+//Unwrap return the error causing it,such as the net.Error of the socket or ErrProtocol,
+//ErrDisconnected or ErrQueueFull if the command was not sent because redis is unreachable,
+//ErrPingTimeout if a subscription lost the connection
+func (e *ConnectError) Unwrap() error {
+	return e.cause
+}
+
+//Is every connection error is ErrConnect,it is ErrTimeout if it is caused by a timeout
+func (e *ConnectError) Is(target error) bool {
+	switch target {
+	case ErrConnect:
+		return true
+	case ErrTimeout:
+		var netErr net.Error
+		return errors.As(e.cause, &netErr) && netErr.Timeout()
+	}
+	return false
+}
+
 //ClusterOperationError cluster operation error
 type ClusterOperationError struct {
 	Message string
@@ -161,3 +318,86 @@ func newClusterOperationError(message string) *ClusterOperationError {
 func (e *ClusterOperationError) Error() string {
 	return e.Message
 }
+
+//ClusterPipelineError some commands of the cluster pipeline failed,
+//Errors maps the submission index of every failed command to its error
+type ClusterPipelineError struct {
+	Message string
+	Errors  map[int]error
+}
+
+func newClusterPipelineError(errs map[int]error) *ClusterPipelineError {
+	return &ClusterPipelineError{Message: strconv.Itoa(len(errs)) + " commands of the cluster pipeline failed", Errors: errs}
+}
+
+func (e *ClusterPipelineError) Error() string {
+	return e.Message
+}
+
+//UnsupportedVersionError the command requires a server version newer than the connected server,it was not sent
+type UnsupportedVersionError struct {
+	Message  string
+	Command  string
+	Required ServerVersion //the version introducing the command
+	Server   ServerVersion
+}
+
+func newUnsupportedVersionError(command string, required, server ServerVersion) *UnsupportedVersionError {
+	return &UnsupportedVersionError{
+		Message:  command + " requires redis " + required.String() + ",the server is " + server.String(),
+		Command:  command,
+		Required: required,
+		Server:   server,
+	}
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return e.Message
+}
+
+//Unwrap return ErrUnsupportedVersion
+func (e *UnsupportedVersionError) Unwrap() error {
+	return ErrUnsupportedVersion
+}
+
+//CommandDeniedError the command is blocked by DenyCommands or AllowCommands,it was not sent
+type CommandDeniedError struct {
+	Message string
+	Command string //the upper case name,with the subcommand if it is denied by the subcommand,such as CONFIG|SET
+}
+
+func newCommandDeniedError(command string) *CommandDeniedError {
+	return &CommandDeniedError{Message: "command " + command + " is denied", Command: command}
+}
+
+func (e *CommandDeniedError) Error() string {
+	return e.Message
+}
+
+//Unwrap return ErrCommandDenied
+func (e *CommandDeniedError) Unwrap() error {
+	return ErrCommandDenied
+}
+
+//IsRedirectError whether err is a MOVED or ASK redirection of the cluster
+func IsRedirectError(err error) bool {
+	var moved *MovedDataError
+	var ask *AskDataError
+	return errors.As(err, &moved) || errors.As(err, &ask)
+}
+
+//IsUnknownCommandError whether the server doesn't support the command,such as a command newer than the server
+func IsUnknownCommandError(err error) bool {
+	var dataErr *DataError
+	return errors.As(err, &dataErr) && strings.HasPrefix(dataErr.Message, "ERR unknown command")
+}
+
+//IsRetryableError whether the command may succeed if it is retried later,
+//such as connection errors, CLUSTERDOWN, LOADING and BUSY
+func IsRetryableError(err error) bool {
+	var connectErr *ConnectError
+	var clusterDown *ClusterDownError
+	var loading *LoadingError
+	var busy *BusyError
+	return errors.As(err, &connectErr) || errors.As(err, &clusterDown) || errors.As(err, &loading) || errors.As(err, &busy)
+}
@@ -1,7 +1,15 @@
 module github.com/piaohao/godis
 
+go 1.27.1
+
 require (
-	github.com/fortytw2/leaktest v1.3.0 // indirect
 	github.com/jolestar/go-commons-pool v2.0.0+incompatible
 	github.com/stretchr/testify v1.3.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/fortytw2/leaktest v1.3.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.1.0 // indirect
+)
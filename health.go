@@ -0,0 +1,114 @@
+package godis
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+//Health the result of Redis.HealthCheck
+type Health struct {
+	Latency          time.Duration //round trip of PING
+	Role             string        //master or slave
+	MasterLinkStatus string        //up or down,empty for a master
+	//seconds since the last interaction with the master for a replica,
+	//the max lag of the connected replicas for a master
+	ReplicationLag int64
+	Loading        bool   //the dataset is being loaded from disk,commands are rejected
+	Ready          bool   //redis can serve commands
+	Reason         string //why redis isn't ready,empty if it is ready
+}
+
+//HealthCheck ping redis,parse the role,replication and loading state from INFO,
+//the connection is broken if ctx is done before the replies are read.
+//
+//redis is ready if it isn't loading,and its link to the master is up if it is a replica
+func (r *Redis) HealthCheck(ctx context.Context) (*Health, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.Connect(); err != nil {
+		return nil, err
+	}
+	stop := r.client.interruptOnDone(ctx)
+	defer stop()
+	start := time.Now()
+	if _, err := r.Ping(); err != nil {
+		return nil, healthError(ctx, err)
+	}
+	health := &Health{Latency: time.Since(start)}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	info, err := r.InfoParsed("replication")
+	if err != nil {
+		return nil, healthError(ctx, err)
+	}
+	persistence, err := r.InfoParsed("persistence")
+	if err != nil {
+		return nil, healthError(ctx, err)
+	}
+	health.Role = info.Replication.Role
+	health.MasterLinkStatus = info.Replication.MasterLinkStatus
+	if health.Role == "master" {
+		for _, slave := range info.Replication.Slaves {
+			if slave.Lag > health.ReplicationLag {
+				health.ReplicationLag = slave.Lag
+			}
+		}
+	} else {
+		health.ReplicationLag = infoInt(info.Sections["replication"], "master_last_io_seconds_ago")
+	}
+	loading, _ := persistence.Get("persistence", "loading")
+	health.Loading = loading == "1"
+	switch {
+	case health.Loading:
+		health.Reason = "redis is loading the dataset"
+	case health.Role != "master" && health.MasterLinkStatus != "up":
+		health.Reason = "link to the master is " + health.MasterLinkStatus
+	default:
+		health.Ready = true
+	}
+	return health, nil
+}
+
+//healthError the error of ctx if it is done,otherwise err
+func healthError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+//HealthHandler http handler of a readiness probe,such as of kubernetes,
+//it replies 200 if redis is ready,otherwise 503 with the reason,
+//every probe borrows a connection from the pool and fails after timeout
+func HealthHandler(pool *Pool, timeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		health, err := checkPoolHealth(ctx, pool)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if !health.Ready {
+			http.Error(w, health.Reason, http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+}
+
+func checkPoolHealth(ctx context.Context, pool *Pool) (*Health, error) {
+	redis, err := pool.GetResourceContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HealthCheck(ctx)
+}
@@ -0,0 +1,84 @@
+package godis
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func fakeBulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+func TestRedis_HealthCheck(t *testing.T) {
+	masterOption, closeMaster := newFakeServer(t, map[string][]string{
+		"PING":             {"+PONG\r\n"},
+		"INFO replication": {fakeBulk("# Replication\r\nrole:master\r\nconnected_slaves:2\r\nslave0:ip=127.0.0.1,port=6380,state=online,offset=100,lag=1\r\nslave1:ip=127.0.0.1,port=6381,state=online,offset=90,lag=3\r\n")},
+		"INFO persistence": {fakeBulk("# Persistence\r\nloading:0\r\n")},
+	})
+	defer closeMaster()
+	redis := NewRedis(masterOption)
+	health, err := redis.HealthCheck(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, health.Ready)
+	assert.Equal(t, "master", health.Role)
+	assert.Equal(t, int64(3), health.ReplicationLag)
+	assert.True(t, health.Latency > 0)
+	redis.Close()
+
+	replicaOption, closeReplica := newFakeServer(t, map[string][]string{
+		"PING":             {"+PONG\r\n"},
+		"INFO replication": {fakeBulk("# Replication\r\nrole:slave\r\nmaster_host:127.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:down\r\nmaster_last_io_seconds_ago:-1\r\n")},
+		"INFO persistence": {fakeBulk("# Persistence\r\nloading:1\r\n")},
+	})
+	defer closeReplica()
+	redis = NewRedis(replicaOption)
+	health, err = redis.HealthCheck(context.Background())
+	assert.Nil(t, err)
+	assert.False(t, health.Ready)
+	assert.True(t, health.Loading)
+	assert.Equal(t, "redis is loading the dataset", health.Reason)
+	assert.Equal(t, "down", health.MasterLinkStatus)
+	assert.Equal(t, int64(-1), health.ReplicationLag)
+	redis.Close()
+
+	//INFO is never replied,the check fails when ctx is done
+	hangOption, closeHang := newFakeServer(t, map[string][]string{"PING": {"+PONG\r\n"}})
+	defer closeHang()
+	redis = NewRedis(hangOption)
+	defer redis.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = redis.HealthCheck(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+	_, err = redis.HealthCheck(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestHealthHandler(t *testing.T) {
+	masterOption, closeMaster := newFakeServer(t, map[string][]string{
+		"PING":             {"+PONG\r\n"},
+		"INFO replication": {fakeBulk("# Replication\r\nrole:master\r\nconnected_slaves:0\r\n")},
+		"INFO persistence": {fakeBulk("# Persistence\r\nloading:0\r\n")},
+		"QUIT":             {"+OK\r\n"},
+	})
+	defer closeMaster()
+	pool := NewPool(nil, masterOption)
+	defer pool.Destroy()
+	recorder := httptest.NewRecorder()
+	HealthHandler(pool, time.Second).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ok", recorder.Body.String())
+
+	broken := NewPool(nil, &Option{Host: "localhost", Port: 6380})
+	defer broken.Destroy()
+	recorder = httptest.NewRecorder()
+	HealthHandler(broken, time.Second).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
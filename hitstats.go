@@ -0,0 +1,152 @@
+package godis
+
+import (
+	"strings"
+	"sync"
+)
+
+//HitStats hits and misses of the reads of HitStatsClient
+type HitStats struct {
+	Hits   int64
+	Misses int64
+}
+
+//Ratio hits divided by all the reads,0 if there are no reads
+func (s HitStats) Ratio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+//HitStatsClient count the hits and misses of GET,MGET,HGET and HMGET per key prefix,
+//to validate the effectiveness of a cache without instrumenting the server.
+//
+//a nil reply is a miss,an empty value is also counted as a miss since nil and empty are the same in the replies,
+//a read failed with an error other than ErrNil is not counted.
+//every key of MGET and every field of HMGET is counted as one read.
+//the other commands are passed to the client as is.
+//
+//HitStatsClient is safe for concurrent use if the client is
+//
+//	stats := godis.NewHitStatsClient(cluster, nil)
+//	stats.Get("user:1")
+//	stats.PrefixStats()["user:"].Ratio()
+type HitStatsClient struct {
+	UniversalClient
+	keyPrefix func(key string) string
+
+	mu    sync.Mutex
+	stats map[string]*HitStats //key prefix -> stats
+}
+
+var _ UniversalClient = (*HitStatsClient)(nil)
+
+//NewHitStatsClient create new client counting the hits of client,
+//keyPrefix maps a key to the prefix its reads are counted under,
+//the prefixes should be few,such as the namespaces of the keys.
+//default is the key up to and including the first colon,or empty if there is no colon
+func NewHitStatsClient(client UniversalClient, keyPrefix func(key string) string) *HitStatsClient {
+	if keyPrefix == nil {
+		keyPrefix = defaultHitStatsKeyPrefix
+	}
+	return &HitStatsClient{UniversalClient: client, keyPrefix: keyPrefix, stats: make(map[string]*HitStats)}
+}
+
+func defaultHitStatsKeyPrefix(key string) string {
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i+1]
+	}
+	return ""
+}
+
+//Stats the aggregate hits and misses of all the prefixes
+func (c *HitStatsClient) Stats() HitStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := HitStats{}
+	for _, stats := range c.stats {
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+	}
+	return total
+}
+
+//PrefixStats the hits and misses of every prefix
+func (c *HitStatsClient) PrefixStats() map[string]HitStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := make(map[string]HitStats, len(c.stats))
+	for prefix, stats := range c.stats {
+		m[prefix] = *stats
+	}
+	return m
+}
+
+//Reset forget all the counts
+func (c *HitStatsClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = make(map[string]*HitStats)
+}
+
+//record count the reads of key,a read of an empty value is a miss
+func (c *HitStatsClient) record(key string, values ...string) {
+	prefix := c.keyPrefix(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats, ok := c.stats[prefix]
+	if !ok {
+		stats = &HitStats{}
+		c.stats[prefix] = stats
+	}
+	for _, value := range values {
+		if value == "" {
+			stats.Misses++
+		} else {
+			stats.Hits++
+		}
+	}
+}
+
+//Get  see comment in redis.go
+func (c *HitStatsClient) Get(key string) (string, error) {
+	value, err := c.UniversalClient.Get(key)
+	if err == nil || err == ErrNil {
+		c.record(key, value)
+	}
+	return value, err
+}
+
+//MGet  see comment in redis.go
+func (c *HitStatsClient) MGet(keys ...string) ([]string, error) {
+	values, err := c.UniversalClient.MGet(keys...)
+	if err != nil {
+		return values, err
+	}
+	for i, key := range keys {
+		if i < len(values) {
+			c.record(key, values[i])
+		}
+	}
+	return values, err
+}
+
+//HGet  see comment in redis.go
+func (c *HitStatsClient) HGet(key, field string) (string, error) {
+	value, err := c.UniversalClient.HGet(key, field)
+	if err == nil || err == ErrNil {
+		c.record(key, value)
+	}
+	return value, err
+}
+
+//HMGet  see comment in redis.go
+func (c *HitStatsClient) HMGet(key string, fields ...string) ([]string, error) {
+	values, err := c.UniversalClient.HMGet(key, fields...)
+	if err != nil {
+		return values, err
+	}
+	c.record(key, values...)
+	return values, err
+}
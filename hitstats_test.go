@@ -0,0 +1,48 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHitStatsClient(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.Set("user:1", "godis")
+	redis.Set("user:2", "good")
+	redis.HSet("session:1", "name", "godis")
+	stats := NewHitStatsClient(redis, nil)
+	s, err := stats.Get("user:1")
+	assert.Nil(t, err)
+	assert.Equal(t, "godis", s)
+	stats.Get("user:3")
+	stats.MGet("user:1", "user:2", "user:4", "other")
+	stats.HGet("session:1", "name")
+	stats.HMGet("session:1", "name", "age")
+	//not counted
+	stats.Set("user:5", "godis")
+	stats.Exists("user:5")
+
+	assert.Equal(t, map[string]HitStats{
+		"user:":    {Hits: 3, Misses: 2},
+		"":         {Hits: 0, Misses: 1},
+		"session:": {Hits: 2, Misses: 1},
+	}, stats.PrefixStats())
+	total := stats.Stats()
+	assert.Equal(t, HitStats{Hits: 5, Misses: 4}, total)
+	assert.InDelta(t, 5.0/9, total.Ratio(), 1e-9)
+
+	stats.Reset()
+	assert.Equal(t, HitStats{}, stats.Stats())
+	assert.Equal(t, float64(0), stats.Stats().Ratio())
+
+	errNilRedis := NewRedis(&Option{Host: "localhost", Port: 6379, ReturnErrNil: true})
+	defer errNilRedis.Close()
+	stats = NewHitStatsClient(errNilRedis, func(key string) string {
+		return "all"
+	})
+	_, err = stats.Get("user:3")
+	assert.Equal(t, ErrNil, err)
+	assert.Equal(t, map[string]HitStats{"all": {Misses: 1}}, stats.PrefixStats())
+}
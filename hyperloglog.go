@@ -0,0 +1,67 @@
+package godis
+
+//HyperLogLogClient the commands HyperLogLog needs,Redis,RedisCluster and PooledRedis implement it
+type HyperLogLogClient interface {
+	PfAdd(key string, elements ...string) (int64, error)
+	PfCount(keys ...string) (int64, error)
+	PfMerge(destKey string, srcKeys ...string) (string, error)
+}
+
+var (
+	_ HyperLogLogClient = (*Redis)(nil)
+	_ HyperLogLogClient = (*RedisCluster)(nil)
+	_ HyperLogLogClient = (*PooledRedis)(nil)
+)
+
+//HyperLogLog approximate count of unique elements stored at a key,such as unique visitors of a page,
+//it takes at most 12k bytes whatever the count is,with a standard error of 0.81%.
+//
+//	visitors := godis.NewHyperLogLog(redis, "visitors:2020-06-01")
+//	visitors.Add(userID)
+//	count, err := visitors.Count()
+type HyperLogLog struct {
+	Key string
+
+	client HyperLogLogClient
+}
+
+//NewHyperLogLog create new HyperLogLog stored at key
+func NewHyperLogLog(client HyperLogLogClient, key string) *HyperLogLog {
+	return &HyperLogLog{Key: key, client: client}
+}
+
+//Add add the elements,return true if the approximated count is changed
+func (h *HyperLogLog) Add(elements ...string) (bool, error) {
+	c, err := h.client.PfAdd(h.Key, elements...)
+	if err != nil {
+		return false, err
+	}
+	return c == 1, nil
+}
+
+//Count return the approximated count of unique elements,0 if the key doesn't exist
+func (h *HyperLogLog) Count() (int64, error) {
+	return h.client.PfCount(h.Key)
+}
+
+//CountWith return the approximated count of the union of h and others without storing it,
+//the keys must be in the same slot in cluster mode
+func (h *HyperLogLog) CountWith(others ...*HyperLogLog) (int64, error) {
+	return h.client.PfCount(hyperLogLogKeys(h, others)...)
+}
+
+//MergeInto merge h and others into dest,the elements already in dest are kept,
+//the keys must be in the same slot in cluster mode
+func (h *HyperLogLog) MergeInto(dest *HyperLogLog, others ...*HyperLogLog) error {
+	_, err := h.client.PfMerge(dest.Key, hyperLogLogKeys(h, others)...)
+	return err
+}
+
+func hyperLogLogKeys(h *HyperLogLog, others []*HyperLogLog) []string {
+	keys := make([]string, 0, len(others)+1)
+	keys = append(keys, h.Key)
+	for _, other := range others {
+		keys = append(keys, other.Key)
+	}
+	return keys
+}
@@ -0,0 +1,95 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	monday := NewHyperLogLog(redis, "visitors:monday")
+	tuesday := NewHyperLogLog(redis, "visitors:tuesday")
+	c, err := monday.Count()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+	changed, err := monday.Add("a", "b", "c")
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	changed, err = monday.Add("a")
+	assert.Nil(t, err)
+	assert.False(t, changed)
+	tuesday.Add("d", "e")
+	c, err = monday.Count()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+	c, err = monday.CountWith(tuesday)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), c)
+
+	week := NewHyperLogLog(redis, "visitors:week")
+	week.Add("f")
+	assert.Nil(t, monday.MergeInto(week, tuesday))
+	c, err = week.Count()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), c)
+
+	redis.Set("godis", "a")
+	_, err = NewHyperLogLog(redis, "godis").Add("a")
+	assert.NotNil(t, err)
+}
+
+func TestClusterPipeline_PfAdd(t *testing.T) {
+	flushAll()
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(8191), []interface{}{[]byte("localhost"), int64(6379)}},
+		[]interface{}{int64(8192), int64(16383), []interface{}{[]byte("127.0.0.1"), int64(6379)}},
+	})
+	defer cluster.Close()
+	p := cluster.Pipelined()
+	added, err := p.PfAdd("godis", "a", "b")
+	assert.Nil(t, err)
+	count, err := p.PfCount("godis")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Sync())
+	c, err := ToInt64Reply(added.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	c, err = ToInt64Reply(count.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+}
+
+func TestRedis_PfDebug(t *testing.T) {
+	//PFDEBUG is served by a fake server
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"PFDEBUG ENCODING godis": {"+sparse\r\n"},
+		"PFDEBUG GETREG godis":   {"*3\r\n:0\r\n:2\r\n:1\r\n"},
+		"QUIT":                   {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	s, err := redis.PfDebugEncoding("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "sparse", s)
+	regs, err := redis.PfDebugGetReg("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0, 2, 1}, regs)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.PfDebugEncoding("godis")
+	assert.NotNil(t, err)
+	_, err = redisBroken.PfDebugGetReg("godis")
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.PfDebugEncoding("godis")
+	assert.NotNil(t, err)
+	_, err = redisBroken.PfDebugGetReg("godis")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,190 @@
+package godis
+
+import (
+	"time"
+)
+
+const (
+	defaultDedupeTTL  = time.Hour
+	defaultPopTimeout = 1
+)
+
+//ListConsumerOption list consumer options
+type ListConsumerOption struct {
+	Queue       string                   //the list used as queue,producers LPush items into it
+	Processing  string                   //the list holding items being handled,default Queue+":processing"
+	Processed   string                   //the set recording ids of handled items,default Queue+":processed"
+	Retries     string                   //the hash recording retry times of failed items,default Queue+":retries"
+	DedupeTTL   time.Duration            //the processed set expires after DedupeTTL without new items,default 1 hour
+	PopTimeout  int                      //seconds to block waiting for an item,default 1
+	MaxRetries  int64                    //times a failed item is pushed back to the queue,0 means drop it on the first failure
+	AtLeastOnce bool                     //keep the item in the processing list until it is handled,so Recover can re-queue it after a crash
+	ItemID      func(item string) string //extract the dedupe id of an item,default the item itself
+}
+
+//ListConsumer consume a plain redis list as a queue,
+//skip items whose id was handled within DedupeTTL,and retry failed items up to MaxRetries times.
+//
+//without AtLeastOnce,an item is lost if the consumer crashes while handling it,
+//with AtLeastOnce,the item is moved to the processing list atomically and removed after it is handled,
+//call Recover on startup to re-queue items left by a crashed consumer,so the handler may see an item more than once.
+//
+//a consumer is bound to one redis connection and must not be shared between goroutines
+type ListConsumer struct {
+	redis  *Redis
+	option ListConsumerOption
+}
+
+//NewListConsumer create new list consumer
+func NewListConsumer(redis *Redis, option *ListConsumerOption) *ListConsumer {
+	opt := *option
+	if opt.Processing == "" {
+		opt.Processing = opt.Queue + ":processing"
+	}
+	if opt.Processed == "" {
+		opt.Processed = opt.Queue + ":processed"
+	}
+	if opt.Retries == "" {
+		opt.Retries = opt.Queue + ":retries"
+	}
+	if opt.DedupeTTL < time.Second {
+		opt.DedupeTTL = defaultDedupeTTL
+	}
+	if opt.PopTimeout <= 0 {
+		opt.PopTimeout = defaultPopTimeout
+	}
+	if opt.ItemID == nil {
+		opt.ItemID = func(item string) string {
+			return item
+		}
+	}
+	return &ListConsumer{redis: redis, option: opt}
+}
+
+//Consume pop one item and handle it,
+//return false if no item arrives within PopTimeout,otherwise return true and the error of handler
+func (c *ListConsumer) Consume(handler func(item string) error) (bool, error) {
+	item, ok, err := c.pop()
+	if err != nil || !ok {
+		return false, err
+	}
+	id := c.option.ItemID(item)
+	handled, err := c.redis.SIsMember(c.option.Processed, id)
+	if err != nil {
+		return true, err
+	}
+	if handled {
+		return true, c.ack(item)
+	}
+	handleErr := handler(item)
+	if handleErr == nil {
+		return true, c.done(item, id)
+	}
+	if err := c.retry(item, id); err != nil {
+		return true, err
+	}
+	return true, handleErr
+}
+
+//Recover push the items left in the processing list back to the queue,
+//an item already waiting in the queue is not pushed again
+//
+//return the count of items pushed back
+func (c *ListConsumer) Recover() (int64, error) {
+	items, err := c.redis.LRange(c.option.Processing, 0, -1)
+	if err != nil {
+		return 0, err
+	}
+	count := int64(0)
+	for _, item := range items {
+		pushed, err := c.requeue(item)
+		if err != nil {
+			return count, err
+		}
+		if pushed {
+			count++
+		}
+		if _, err := c.redis.LRem(c.option.Processing, 1, item); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func (c *ListConsumer) pop() (string, bool, error) {
+	if c.option.AtLeastOnce {
+		c.redis.client.setBlockingTimeout(time.Duration(c.option.PopTimeout) * time.Second)
+		defer c.redis.client.clearBlockingTimeout()
+		if err := c.redis.client.brpoplpush(c.option.Queue, c.option.Processing, c.option.PopTimeout); err != nil {
+			return "", false, err
+		}
+		//read the raw reply,so an empty item can be told from timeout
+		reply, err := c.redis.client.getOne()
+		if err != nil {
+			return "", false, err
+		}
+		item, _ := reply.([]byte)
+		if item == nil {
+			return "", false, nil
+		}
+		return string(item), true, nil
+	}
+	arr, err := c.redis.BRPopTimeout(c.option.PopTimeout, c.option.Queue)
+	if err != nil || len(arr) < 2 {
+		return "", false, err
+	}
+	return arr[1], true, nil
+}
+
+//done record the id of a handled item
+func (c *ListConsumer) done(item, id string) error {
+	if _, err := c.redis.SAdd(c.option.Processed, id); err != nil {
+		return err
+	}
+	if _, err := c.redis.Expire(c.option.Processed, int(c.option.DedupeTTL/time.Second)); err != nil {
+		return err
+	}
+	if c.option.MaxRetries > 0 {
+		if _, err := c.redis.HDel(c.option.Retries, id); err != nil {
+			return err
+		}
+	}
+	return c.ack(item)
+}
+
+//retry push a failed item back to the queue until MaxRetries is exceeded
+func (c *ListConsumer) retry(item, id string) error {
+	if c.option.MaxRetries > 0 {
+		times, err := c.redis.HIncrBy(c.option.Retries, id, 1)
+		if err != nil {
+			return err
+		}
+		if times <= c.option.MaxRetries {
+			if _, err := c.requeue(item); err != nil {
+				return err
+			}
+		} else if _, err := c.redis.HDel(c.option.Retries, id); err != nil {
+			return err
+		}
+	}
+	return c.ack(item)
+}
+
+//requeue push item to the queue if it is not already there
+func (c *ListConsumer) requeue(item string) (bool, error) {
+	pos, err := c.redis.LPos(c.option.Queue, item)
+	if err != nil || pos >= 0 {
+		return false, err
+	}
+	_, err = c.redis.LPush(c.option.Queue, item)
+	return err == nil, err
+}
+
+//ack remove the item from the processing list
+func (c *ListConsumer) ack(item string) error {
+	if !c.option.AtLeastOnce {
+		return nil
+	}
+	_, err := c.redis.LRem(c.option.Processing, 1, item)
+	return err
+}
@@ -0,0 +1,93 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestListConsumer(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.LPush("queue", "a", "b", "a", "c")
+
+	consumer := NewListConsumer(redis, &ListConsumerOption{Queue: "queue", MaxRetries: 1, AtLeastOnce: true})
+	handled := make([]string, 0)
+	failed := 0
+	handler := func(item string) error {
+		if item == "c" && failed < 2 {
+			failed++
+			return errors.New("handle failed")
+		}
+		handled = append(handled, item)
+		return nil
+	}
+	for {
+		ok, err := consumer.Consume(handler)
+		if !ok {
+			assert.Nil(t, err)
+			break
+		}
+	}
+	//a is handled once,c is dropped after one retry
+	assert.Equal(t, []string{"a", "b"}, handled)
+	assert.Equal(t, 2, failed)
+
+	arr, err := redis.SMembers("queue:processed")
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, arr)
+	ttl, err := redis.TTL("queue:processed")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0)
+	c, err := redis.LLen("queue:processing")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+
+	redis.RPush("queue:processing", "d", "e")
+	redis.LPush("queue", "e")
+	c, err = consumer.Recover()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	arr, err = redis.LRange("queue", 0, -1)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"d", "e"}, arr)
+
+	consumer = NewListConsumer(redis, &ListConsumerOption{Queue: "queue"})
+	ok, err := consumer.Consume(func(item string) error {
+		return errors.New("handle failed")
+	})
+	assert.True(t, ok)
+	assert.NotNil(t, err)
+	c, err = redis.LLen("queue")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	consumer = NewListConsumer(redisBroken, &ListConsumerOption{Queue: "queue", AtLeastOnce: true})
+	ok, err = consumer.Consume(handler)
+	assert.False(t, ok)
+	assert.NotNil(t, err)
+	_, err = consumer.Recover()
+	assert.NotNil(t, err)
+}
+
+func TestListConsumer_PopTimeoutBeyondSoTimeout(t *testing.T) {
+	flushAll()
+	redis := NewRedis(&Option{Host: "localhost", Port: 6379, SoTimeout: 200 * time.Millisecond})
+	defer redis.Close()
+	consumer := NewListConsumer(redis, &ListConsumerOption{Queue: "queue", AtLeastOnce: true, PopTimeout: 1})
+	//the pop waits for PopTimeout instead of timing out the read at SoTimeout
+	ok, err := consumer.Consume(func(item string) error {
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	s, err := redis.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+}
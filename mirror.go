@@ -0,0 +1,219 @@
+package godis
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultMirrorQueueSize = 1000
+	mirrorBatchSize        = 100
+)
+
+//MirrorOption option of MirroredRedis
+type MirrorOption struct {
+	QueueSize int                             //max count of writes waiting to be mirrored,default 1000,more writes are dropped
+	OnError   func(command string, err error) //called when a write is dropped or fails on the secondary,it must not block
+}
+
+//MirrorStats counters of MirroredRedis
+type MirrorStats struct {
+	Mirrored int64 //writes executed by the secondary
+	Dropped  int64 //writes dropped because the queue is full
+	Failed   int64 //writes failed on the secondary
+}
+
+//MirroredRedis execute the commands on the primary and mirror the writes to the secondary asynchronously,
+//to migrate data to a new redis without changing the application.
+//
+//all the methods of Redis are available,their replies always come from the primary.
+//the writes,commands with keys which aren't read only,are queued and sent to the secondary in order by a background goroutine,
+//a transaction is mirrored as a whole after EXEC,a discarded one is not mirrored,
+//neither are the commands dropped by Pipeline.Discard before they are sent.
+//mirroring is best effort:writes are dropped when the queue is full and writes failed on the secondary are not retried,
+//blocking commands and SELECT are not mirrored,the secondary always uses the db of its option.
+//
+//like Redis,MirroredRedis is not safe for concurrent use
+type MirroredRedis struct {
+	*Redis
+	option    MirrorOption
+	secondary Option
+	queue     chan []mirrorCommand
+	stopped   chan struct{}
+
+	mu     sync.Mutex
+	tx     []mirrorCommand //writes of the running transaction
+	inTx   bool
+	closed bool
+
+	mirrored int64
+	dropped  int64
+	failed   int64
+}
+
+//mirrorCommand a write waiting to be mirrored
+type mirrorCommand struct {
+	name string
+	args [][]byte
+}
+
+//NewMirroredRedis create new mirrored redis,the commands are executed by the primary and the writes are mirrored to the secondary
+func NewMirroredRedis(primary, secondary *Option, option *MirrorOption) *MirroredRedis {
+	opt := MirrorOption{}
+	if option != nil {
+		opt = *option
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = defaultMirrorQueueSize
+	}
+	m := &MirroredRedis{
+		Redis:     NewRedis(primary),
+		option:    opt,
+		secondary: *secondary,
+		queue:     make(chan []mirrorCommand, opt.QueueSize),
+		stopped:   make(chan struct{}),
+	}
+	m.client.mirror = m.record
+	go m.run()
+	return m
+}
+
+//Stats the counters of mirrored,dropped and failed writes
+func (m *MirroredRedis) Stats() MirrorStats {
+	return MirrorStats{
+		Mirrored: atomic.LoadInt64(&m.mirrored),
+		Dropped:  atomic.LoadInt64(&m.dropped),
+		Failed:   atomic.LoadInt64(&m.failed),
+	}
+}
+
+//Close close the primary,wait until the queued writes are mirrored,then close the secondary
+func (m *MirroredRedis) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	close(m.queue)
+	m.mu.Unlock()
+	err := m.Redis.Close()
+	<-m.stopped
+	return err
+}
+
+//record queue the command flushed by the primary if it should be mirrored,its arguments are already copied
+func (m *MirroredRedis) record(spec *CommandSpec, args [][]byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	switch spec.Name {
+	case "MULTI":
+		m.inTx, m.tx = true, nil
+		return
+	case "EXEC":
+		if m.inTx && len(m.tx) > 0 {
+			batch := make([]mirrorCommand, 0, len(m.tx)+2)
+			batch = append(batch, mirrorCommand{name: "MULTI"})
+			batch = append(batch, m.tx...)
+			m.enqueue(append(batch, mirrorCommand{name: "EXEC"}))
+		}
+		m.inTx, m.tx = false, nil
+		return
+	case "DISCARD":
+		m.inTx, m.tx = false, nil
+		return
+	}
+	if spec.ReadOnly || (spec.FirstKey == 0 && spec.NumKeys == 0) {
+		return
+	}
+	if _, ok := blockingTimeout(spec.Name, ByteArrArrToStrArr(args)); ok {
+		return
+	}
+	command := mirrorCommand{name: spec.Name, args: args}
+	if m.inTx {
+		m.tx = append(m.tx, command)
+		return
+	}
+	m.enqueue([]mirrorCommand{command})
+}
+
+//enqueue queue the writes without blocking the primary,drop them if the queue is full
+func (m *MirroredRedis) enqueue(batch []mirrorCommand) {
+	select {
+	case m.queue <- batch:
+	default:
+		atomic.AddInt64(&m.dropped, int64(len(batch)))
+		for _, command := range batch {
+			m.report(command.name, ErrMirrorQueueFull)
+		}
+	}
+}
+
+//run send the queued writes to the secondary until the queue is closed,
+//the writes waiting in the queue are sent as a pipeline
+func (m *MirroredRedis) run() {
+	defer close(m.stopped)
+	redis := NewRedis(&m.secondary)
+	defer func() {
+		redis.Close()
+	}()
+	for batch := range m.queue {
+	drain:
+		for i := 0; i < mirrorBatchSize; i++ {
+			select {
+			case more, ok := <-m.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, more...)
+			default:
+				break drain
+			}
+		}
+		if !m.mirror(redis, batch) {
+			//the connection is broken,dial again for the next writes
+			redis.Close()
+			redis = NewRedis(&m.secondary)
+		}
+	}
+}
+
+//mirror send the writes to the secondary,return false if the connection is broken
+func (m *MirroredRedis) mirror(redis *Redis, batch []mirrorCommand) bool {
+	for _, command := range batch {
+		if err := redis.client.sendCommandByStr(command.name, command.args...); err != nil {
+			m.fail(batch, err)
+			return false
+		}
+	}
+	replies, err := redis.client.getAll()
+	if err != nil {
+		m.fail(batch, err)
+		return false
+	}
+	for i, reply := range replies.([]interface{}) {
+		if err, ok := reply.(error); ok {
+			atomic.AddInt64(&m.failed, 1)
+			m.report(batch[i].name, err)
+			continue
+		}
+		atomic.AddInt64(&m.mirrored, 1)
+	}
+	return !redis.client.broken
+}
+
+func (m *MirroredRedis) fail(batch []mirrorCommand, err error) {
+	atomic.AddInt64(&m.failed, int64(len(batch)))
+	for _, command := range batch {
+		m.report(command.name, err)
+	}
+}
+
+func (m *MirroredRedis) report(command string, err error) {
+	if m.option.OnError != nil {
+		m.option.OnError(command, err)
+	}
+}
@@ -0,0 +1,135 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMirroredRedis(t *testing.T) {
+	flushAll()
+	secondary := *option
+	secondary.Db = 1
+	mirrored := NewMirroredRedis(option, &secondary, nil)
+	_, err := mirrored.Set("godis", "good")
+	assert.Nil(t, err)
+	_, err = mirrored.Incr("counter")
+	assert.Nil(t, err)
+	_, err = mirrored.HSet("hash", "field", "value")
+	assert.Nil(t, err)
+	value, err := mirrored.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", value)
+
+	tx, err := mirrored.Multi()
+	assert.Nil(t, err)
+	tx.MSet("tx", "good")
+	_, err = tx.Exec()
+	assert.Nil(t, err)
+	tx, err = mirrored.Multi()
+	assert.Nil(t, err)
+	tx.MSet("discarded", "good")
+	_, err = tx.Discard()
+	assert.Nil(t, err)
+	assert.Nil(t, mirrored.Close())
+	assert.Equal(t, MirrorStats{Mirrored: 6}, mirrored.Stats())
+
+	redis := NewRedis(&secondary)
+	defer redis.Close()
+	value, _ = redis.Get("godis")
+	assert.Equal(t, "good", value)
+	value, _ = redis.Get("counter")
+	assert.Equal(t, "1", value)
+	value, _ = redis.Get("tx")
+	assert.Equal(t, "good", value)
+	value, _ = redis.HGet("hash", "field")
+	assert.Equal(t, "value", value)
+	exists, _ := redis.Exists("discarded")
+	assert.Equal(t, int64(0), exists)
+}
+
+func TestMirroredRedis_QueueFull(t *testing.T) {
+	flushAll()
+	//the fake server never replies,so the first write holds the secondary
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{})
+	defer closeServer()
+	fakeOption.SoTimeout = 200 * time.Millisecond
+	var mu sync.Mutex
+	errs := make(map[string]error)
+	mirrored := NewMirroredRedis(option, fakeOption, &MirrorOption{
+		QueueSize: 1,
+		OnError: func(command string, err error) {
+			mu.Lock()
+			errs[command] = err
+			mu.Unlock()
+		},
+	})
+	for i := 0; i < 5; i++ {
+		_, err := mirrored.Incr("godis")
+		assert.Nil(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Nil(t, mirrored.Close())
+	stats := mirrored.Stats()
+	assert.True(t, stats.Dropped > 0)
+	assert.Equal(t, int64(5), stats.Dropped+stats.Failed)
+	assert.Equal(t, int64(0), stats.Mirrored)
+	mu.Lock()
+	assert.NotNil(t, errs["INCR"])
+	mu.Unlock()
+
+	redis := NewRedis(option)
+	defer redis.Close()
+	value, _ := redis.Get("godis")
+	assert.Equal(t, "5", value)
+}
+
+func TestMirroredRedis_PipelineDiscard(t *testing.T) {
+	replies := map[string][]string{
+		"MSET kept good":    {"+OK\r\n"},
+		"MSET dropped good": {"+OK\r\n"},
+		"QUIT":              {"+OK\r\n"},
+	}
+	primary, closePrimary := newFakeServer(t, replies)
+	defer closePrimary()
+	secondary, closeSecondary := newFakeServer(t, replies)
+	defer closeSecondary()
+	mirrored := NewMirroredRedis(primary, secondary, nil)
+	p := mirrored.Pipelined()
+	_, err := p.MSet("kept", "good")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Sync())
+	//the discarded command is never sent,so it is not mirrored
+	_, err = p.MSet("dropped", "good")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, p.Discard())
+	assert.Nil(t, mirrored.Close())
+	assert.Equal(t, MirrorStats{Mirrored: 1}, mirrored.Stats())
+}
+
+func TestMirroredRedis_LargeArg(t *testing.T) {
+	value := strings.Repeat("x", largeArgSize)
+	replies := map[string][]string{
+		"SET godis " + value: {"+OK\r\n"},
+		"MSET dropped good":  {"+OK\r\n"},
+		"QUIT":               {"+OK\r\n"},
+	}
+	primary, closePrimary := newFakeServer(t, replies)
+	defer closePrimary()
+	secondary, closeSecondary := newFakeServer(t, replies)
+	defer closeSecondary()
+	mirrored := NewMirroredRedis(primary, secondary, nil)
+	//the command is flushed while it is encoded,it is mirrored all the same
+	assert.Nil(t, mirrored.SendByStr("SET", []byte("godis"), []byte(value)))
+	_, err := mirrored.Receive()
+	assert.Nil(t, err)
+	//discarding the commands encoded later doesn't drop it
+	p := mirrored.Pipelined()
+	_, err = p.MSet("dropped", "good")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, p.Discard())
+	assert.Nil(t, mirrored.Close())
+	assert.Equal(t, MirrorStats{Mirrored: 1}, mirrored.Stats())
+}
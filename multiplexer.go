@@ -0,0 +1,371 @@
+package godis
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//statefulCommands change the state of the connection,they can't be sent through the shared connection of Multiplexer
+var statefulCommands = map[string]bool{
+	"AUTH": true, "HELLO": true, "SELECT": true, "RESET": true, "QUIT": true, "MONITOR": true,
+	"MULTI": true, "EXEC": true, "DISCARD": true, "WATCH": true, "UNWATCH": true,
+	"SUBSCRIBE": true, "PSUBSCRIBE": true, "SSUBSCRIBE": true,
+	"UNSUBSCRIBE": true, "PUNSUBSCRIBE": true, "SUNSUBSCRIBE": true,
+	"READONLY": true, "READWRITE": true,
+}
+
+//statefulSubcommands the subcommands changing the state of the connection,keyed by their command
+var statefulSubcommands = map[string]map[string]bool{
+	"CLIENT": {"REPLY": true},
+}
+
+//Multiplexer many goroutines share one connection,the commands are written as a pipeline
+//and the replies are matched to the commands in order,so small commands don't contend for pooled connections.
+//
+//blocking commands,such as BLPOP and XREAD BLOCK,are sent by dedicated connections borrowed from a pool,
+//so they don't hold up the other commands,Subscribe and Dedicated borrow dedicated connections too.
+//
+//if the shared connection breaks,the commands waiting for replies fail with the error,
+//and the connection is dialed again for the next command.
+//
+//the commands are sent like the ones of Redis,with the command filters,the key mapper,the command timeout,
+//the profiler and the mirror of the option,except Option.DetectConcurrentUse,
+//the shared connection is used by a writer and a reader goroutine which synchronize by themselves.
+//
+//Multiplexer is safe for concurrent use
+type Multiplexer struct {
+	option   Option
+	pool     *Pool
+	requests chan *muxCall
+	stop     chan struct{}
+	stopped  chan struct{}
+	once     sync.Once
+}
+
+//muxCall a command waiting for its reply
+type muxCall struct {
+	command string
+	args    []string
+	reply   interface{}
+	err     error
+	done    chan struct{}
+}
+
+func (c *muxCall) finish(reply interface{}, err error) {
+	c.reply, c.err = reply, err
+	close(c.done)
+}
+
+//muxMaxPendingCommands the encoded commands are flushed once so many are pending,even if more are waiting
+const muxMaxPendingCommands = 128
+
+//muxSession the shared connection and the commands written to it,the replies are read in the order of inflight
+type muxSession struct {
+	client   *client
+	conn     net.Conn
+	inflight chan *muxCall
+	dead     chan struct{}
+	once     sync.Once
+	err      error
+	//mu guards the bookkeeping of the connection shared by the writer and the reader,
+	//such as the counters and the command deadline,it is never held while the socket is read or written
+	mu sync.Mutex
+}
+
+//fail close the connection,the commands waiting for replies fail with err
+func (s *muxSession) fail(err error) {
+	s.once.Do(func() {
+		s.err = err
+		//close the socket only,the writer and the reader still hold the connection
+		s.conn.Close()
+		close(s.dead)
+	})
+}
+
+//NewMultiplexer create new multiplexer,poolConfig is the config of the pool of dedicated connections
+func NewMultiplexer(option *Option, poolConfig *PoolConfig) *Multiplexer {
+	m := &Multiplexer{
+		option:   *option,
+		pool:     NewPool(poolConfig, option),
+		requests: make(chan *muxCall, defaultMaxQueueSize),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+//Do send the command and wait for its reply,the reply is the same as Redis.Receive,
+//convert it by ScanReply or the ToXxxReply functions.
+//
+//blocking commands are sent by a dedicated connection,
+//commands changing the state of the connection,such as MULTI and SELECT,are rejected,use Dedicated for them
+func (m *Multiplexer) Do(command string, args ...string) (interface{}, error) {
+	name := strings.ToUpper(command)
+	if statefulCommands[name] {
+		return nil, newDataError("command " + name + " changes the state of the connection,use Dedicated instead")
+	}
+	if len(args) > 0 && statefulSubcommands[name][strings.ToUpper(args[0])] {
+		return nil, newDataError("command " + name + " " + strings.ToUpper(args[0]) + " changes the state of the connection,use Dedicated instead")
+	}
+	if spec, ok := commandSpecs[name]; ok {
+		if err := spec.CheckArity(len(args)); err != nil {
+			return nil, err
+		}
+	}
+	if timeout, ok := blockingTimeout(name, args); ok {
+		return m.doBlocking(command, timeout, args)
+	}
+	call := &muxCall{command: command, args: args, done: make(chan struct{})}
+	select {
+	case m.requests <- call:
+	case <-m.stop:
+		return nil, ErrClosed
+	}
+	select {
+	case <-call.done:
+		return call.reply, call.err
+	case <-m.stopped:
+		select {
+		case <-call.done:
+			return call.reply, call.err
+		default:
+			return nil, ErrClosed
+		}
+	}
+}
+
+//Dedicated run fn with a connection borrowed from the pool,for transactions and other stateful commands
+func (m *Multiplexer) Dedicated(fn func(redis *Redis) error) error {
+	redis, err := m.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return fn(redis)
+}
+
+//Subscribe subscribe the channels with a dedicated connection,block until all of them are unsubscribed
+func (m *Multiplexer) Subscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	return m.Dedicated(func(redis *Redis) error {
+		return redis.Subscribe(redisPubSub, channels...)
+	})
+}
+
+//PSubscribe subscribe the patterns with a dedicated connection,block until all of them are unsubscribed
+func (m *Multiplexer) PSubscribe(redisPubSub *RedisPubSub, patterns ...string) error {
+	return m.Dedicated(func(redis *Redis) error {
+		return redis.PSubscribe(redisPubSub, patterns...)
+	})
+}
+
+//Close close the shared connection and the pool,the commands not replied yet fail with ErrClosed
+func (m *Multiplexer) Close() error {
+	m.once.Do(func() {
+		close(m.stop)
+		<-m.stopped
+		m.pool.Destroy()
+	})
+	return nil
+}
+
+func (m *Multiplexer) doBlocking(command string, timeout time.Duration, args []string) (interface{}, error) {
+	var reply interface{}
+	err := m.Dedicated(func(redis *Redis) error {
+		redis.client.setBlockingTimeout(timeout)
+		defer redis.client.clearBlockingTimeout()
+		err := redis.SendByStr(command, StrArrToByteArrArr(args)...)
+		if err != nil {
+			return err
+		}
+		reply, err = redis.Receive()
+		return err
+	})
+	return reply, err
+}
+
+//run dial the shared connection and serve the commands until the multiplexer is closed
+func (m *Multiplexer) run() {
+	defer close(m.stopped)
+	for {
+		client := newClient(&m.option)
+		client.connection.detectConcurrentUse = false
+		if err := client.connect(); err != nil {
+			//fail the next command,so the callers see the error instead of waiting
+			select {
+			case <-m.stop:
+				return
+			case call := <-m.requests:
+				call.finish(nil, err)
+			}
+			continue
+		}
+		s := &muxSession{client: client, conn: client.socket, inflight: make(chan *muxCall, defaultMaxQueueSize), dead: make(chan struct{})}
+		go m.read(s)
+		if !m.write(s) {
+			m.failRequests()
+			return
+		}
+	}
+}
+
+//write encode the commands to the shared connection,flush them when no more commands are waiting,
+//when muxMaxPendingCommands are pending or before waiting for the reader,
+//return false if the multiplexer is closed
+func (m *Multiplexer) write(s *muxSession) bool {
+	defer close(s.inflight)
+	out := s.client.protocol.os
+	for {
+		select {
+		case <-m.stop:
+			s.fail(ErrClosed)
+			return false
+		case <-s.dead:
+			return true
+		case call := <-m.requests:
+			if err := s.filter(call); err != nil {
+				call.finish(nil, err)
+				continue
+			}
+			if err := s.encode(call); err != nil {
+				s.fail(err)
+				call.finish(nil, err)
+				return true
+			}
+			select {
+			case s.inflight <- call:
+			default:
+				//the reader may be waiting for the replies of the pending commands
+				if err := out.flush(); err != nil {
+					s.fail(err)
+					call.finish(nil, err)
+					return true
+				}
+				select {
+				case s.inflight <- call:
+				case <-s.dead:
+					call.finish(nil, s.err)
+					return true
+				}
+			}
+			if len(m.requests) > 0 && out.count < muxMaxPendingCommands {
+				continue
+			}
+			if err := out.flush(); err != nil {
+				s.fail(err)
+				return true
+			}
+		}
+	}
+}
+
+//filter run the command filters of the option
+func (s *muxSession) filter(call *muxCall) error {
+	if len(s.client.filters) == 0 {
+		return nil
+	}
+	spec, _ := LookupCommandSpec(call.command)
+	return s.client.filterCommand(strings.ToUpper(call.command), spec, call.args)
+}
+
+//encode map the keys and encode the command like connection.sendCommandStr,
+//the command is counted before it is encoded,since its reply may be read once it is flushed
+func (s *muxSession) encode(call *muxCall) error {
+	c := s.client.connection
+	spec, _ := LookupCommandSpec(call.command)
+	args := c.mapStrKeys(spec, call.args)
+	s.mu.Lock()
+	c.startCommand()
+	if profiled, tracked := c.countSent(spec); tracked {
+		c.addSample(call.command, spec, args, profiled)
+	}
+	s.mu.Unlock()
+	if c.mirror != nil {
+		c.mirrorSent(spec, StrArrToByteArrArr(args))
+	}
+	return c.protocol.sendStrCommand(call.command, args...)
+}
+
+//read match the replies to the commands in order,
+//once the connection fails,the remaining commands fail with the same error
+func (m *Multiplexer) read(s *muxSession) {
+	c := s.client.connection
+	for call := range s.inflight {
+		select {
+		case <-s.dead:
+			call.finish(nil, s.err)
+			continue
+		default:
+		}
+		s.mu.Lock()
+		err := c.setReplyDeadline()
+		s.mu.Unlock()
+		var reply interface{}
+		if err == nil {
+			reply, err = c.protocol.read()
+		}
+		s.mu.Lock()
+		if err != nil {
+			err = c.readError(err)
+		}
+		c.finishReply()
+		s.mu.Unlock()
+		if _, ok := err.(*ConnectError); ok {
+			s.fail(err)
+		}
+		call.finish(reply, err)
+	}
+}
+
+//failRequests fail the commands not written yet after the multiplexer is closed
+func (m *Multiplexer) failRequests() {
+	for {
+		select {
+		case call := <-m.requests:
+			call.finish(nil, ErrClosed)
+		default:
+			return
+		}
+	}
+}
+
+//blockingTimeout the block timeout of a blocking command,ok is false if the command doesn't block
+func blockingTimeout(name string, args []string) (time.Duration, bool) {
+	switch name {
+	case "BLPOP", "BRPOP", "BRPOPLPUSH", "BLMOVE", "BZPOPMIN", "BZPOPMAX":
+		return parseBlockTimeout(args), true
+	case "BLMPOP", "BZMPOP":
+		return parseBlockTimeout(args[:minInt(len(args), 1)]), true
+	case "WAIT":
+		return parseMillisTimeout(args, len(args)-1), true
+	case "XREAD", "XREADGROUP":
+		for i, arg := range args {
+			if strings.EqualFold(arg, keywordBlock.name) {
+				return parseMillisTimeout(args, i+1), true
+			}
+		}
+	}
+	return 0, false
+}
+
+//parseMillisTimeout the timeout in milliseconds at index i of args
+func parseMillisTimeout(args []string, i int) time.Duration {
+	if i < 0 || i >= len(args) {
+		return 0
+	}
+	ms, err := strconv.ParseInt(args[i], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,163 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMultiplexer_Do(t *testing.T) {
+	flushAll()
+	m := NewMultiplexer(option, nil)
+	defer m.Close()
+
+	s, err := ToStrReply(m.Do("SET", "godis", "good"))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = ToStrReply(m.Do("get", "godis"))
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	_, err = m.Do("INCR", "godis")
+	assert.NotNil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				key := "godis" + strconv.Itoa(i)
+				n, err := ToInt64Reply(m.Do("INCR", key))
+				assert.Nil(t, err)
+				assert.Equal(t, int64(j+1), n)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	_, err = m.Do("MULTI")
+	assert.NotNil(t, err)
+	err = m.Dedicated(func(redis *Redis) error {
+		_, err := redis.Select(1)
+		return err
+	})
+	assert.Nil(t, err)
+	s, err = ToStrReply(m.Do("GET", "godis"))
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+}
+
+func TestMultiplexer_Blocking(t *testing.T) {
+	flushAll()
+	m := NewMultiplexer(option, nil)
+	defer m.Close()
+
+	done := make(chan []string)
+	go func() {
+		reply, err := m.Do("BLPOP", "godis", "2")
+		assert.Nil(t, err)
+		var arr []string
+		assert.Nil(t, ScanReply(reply, &arr))
+		done <- arr
+	}()
+	time.Sleep(100 * time.Millisecond)
+	//the shared connection isn't held up by the blocking command
+	start := time.Now()
+	s, err := ToStrReply(m.Do("PING"))
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+	assert.True(t, time.Since(start) < time.Second)
+	_, err = m.Do("RPUSH", "godis", "good")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"godis", "good"}, <-done)
+
+	timeout, ok := blockingTimeout("XREADGROUP", []string{"GROUP", "g", "c", "block", "1500", "STREAMS", "s", ">"})
+	assert.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, timeout)
+	timeout, ok = blockingTimeout("BLMPOP", []string{"0.5", "1", "s", "LEFT"})
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, timeout)
+	_, ok = blockingTimeout("XREAD", []string{"STREAMS", "s", "0"})
+	assert.False(t, ok)
+}
+
+func TestMultiplexer_Close(t *testing.T) {
+	m := NewMultiplexer(&Option{Host: "localhost", Port: 6380, ConnectionTimeout: 100 * time.Millisecond}, nil)
+	_, err := m.Do("PING")
+	assert.NotNil(t, err)
+	m.Close()
+	_, err = m.Do("PING")
+	assert.Equal(t, ErrClosed, err)
+
+	m = NewMultiplexer(option, nil)
+	_, err = m.Do("PING")
+	assert.Nil(t, err)
+	assert.Nil(t, m.Close())
+	assert.Nil(t, m.Close())
+	_, err = m.Do("PING")
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestMultiplexer_Stateful(t *testing.T) {
+	m := NewMultiplexer(&Option{Host: "localhost", Port: 6380, ConnectionTimeout: 100 * time.Millisecond}, nil)
+	defer m.Close()
+	//they are rejected before being sent,the shared connection is never used
+	for _, command := range [][]string{{"MULTI"}, {"select", "1"}, {"READONLY"}, {"readwrite"}, {"CLIENT", "reply", "OFF"}} {
+		_, err := m.Do(command[0], command[1:]...)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "changes the state of the connection")
+		}
+	}
+}
+
+func TestMultiplexer_ManyCallers(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"PING": {"+PONG\r\n"},
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.SoTimeout = time.Second
+	m := NewMultiplexer(fakeOption, nil)
+	defer m.Close()
+	//more callers than the commands the writer and the reader buffer,the writer must flush before waiting for the reader
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3*defaultMaxQueueSize; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				s, err := ToStrReply(m.Do("PING"))
+				assert.Nil(t, err)
+				assert.Equal(t, "PONG", s)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.True(t, time.Since(start) < fakeOption.SoTimeout)
+}
+
+func TestMultiplexer_Option(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"GET app:godis": {"$4\r\ngood\r\n"},
+		"QUIT":          {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.KeyMapper = func(key string) string {
+		return "app:" + key
+	}
+	fakeOption.CommandFilters = []CommandFilter{DenyCommands("FLUSHALL")}
+	m := NewMultiplexer(fakeOption, nil)
+	defer m.Close()
+	//the shared connection honors the key mapper and the command filters like Redis
+	s, err := ToStrReply(m.Do("GET", "godis"))
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	_, err = m.Do("flushall")
+	assert.NotNil(t, err)
+	s, err = ToStrReply(m.Do("GET", "godis"))
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+}
@@ -5,7 +5,7 @@ import "sync"
 //Response pipeline and transaction response,include replies from redis
 type Response struct {
 	response  interface{} //store replies
-	exception *DataError
+	exception error
 
 	building bool //whether response is building
 	built    bool //whether response is build done
@@ -13,6 +13,7 @@ type Response struct {
 
 	builder    Builder     //response data convert rule
 	data       interface{} //real data
+	raw        interface{} //the reply as it is read,kept for Scan after build
 	dependency *Response   //response cycle dependency
 }
 
@@ -26,6 +27,7 @@ func newResponse() *Response {
 
 func (r *Response) set(data interface{}) {
 	r.data = data
+	r.raw = data
 	r.isSet = true
 }
 
@@ -52,6 +54,20 @@ func (r *Response) Get() (interface{}, error) {
 	return r.response, nil
 }
 
+//Scan convert the reply into dest,see ScanReply,return ErrNil if the reply is nil
+func (r *Response) Scan(dest interface{}) error {
+	if !r.isSet {
+		return newDataError("please close pipeline or multi block before calling this method")
+	}
+	if err, ok := r.raw.(error); ok {
+		return err
+	}
+	if isNilReply(r.raw) {
+		return ErrNil
+	}
+	return ScanReply(r.raw, dest)
+}
+
 func (r *Response) setDependency(dependency *Response) {
 	r.dependency = dependency
 }
@@ -70,6 +86,9 @@ func (r *Response) build() error {
 		case *DataError:
 			r.exception = r.data.(*DataError)
 			return nil
+		case error:
+			r.exception = r.data.(error)
+			return nil
 		}
 		result, err := r.builder.build(r.data)
 		if err != nil {
@@ -81,7 +100,136 @@ func (r *Response) build() error {
 	return nil
 }
 
-//Transaction redis transaction struct
+//PipelineCommands commands which can be queued in pipeline or transaction,
+// every command only queue the request and returns a *Response,
+// the real reply can be got by Response.Get after Pipeline.Sync or Transaction.Exec.
+//
+//PipelineCommands,Pipeliner and Transactioner are meant to be mocked,
+//methods are added to them as more commands are supported but the signatures of the existing ones never change,
+//so embed the interface in the mock and implement only the methods it uses.
+type PipelineCommands interface {
+	BgRewriteAof() (*Response, error)
+	BgSave() (*Response, error)
+	ConfigGet(pattern string) (*Response, error)
+	ConfigSet(parameter, value string) (*Response, error)
+	ConfigResetStat() (*Response, error)
+	Save() (*Response, error)
+	LastSave() (*Response, error)
+	FlushDB() (*Response, error)
+	FlushDBWithMode(mode *FlushMode) (*Response, error)
+	FlushAll() (*Response, error)
+	FlushAllWithMode(mode *FlushMode) (*Response, error)
+	Info() (*Response, error)
+	Time() (*Response, error)
+	DbSize() (*Response, error)
+	Shutdown() (*Response, error)
+	ShutdownWithParams(params *ShutdownParams) (*Response, error)
+	Ping() (*Response, error)
+	Select(index int) (*Response, error)
+	Get(key string) (*Response, error)
+	Set(key, value string) (*Response, error)
+	SetEx(key string, seconds int, value string) (*Response, error)
+	SetNx(key, value string) (*Response, error)
+	Incr(key string) (*Response, error)
+	IncrBy(key string, increment int64) (*Response, error)
+	Decr(key string) (*Response, error)
+	DecrBy(key string, decrement int64) (*Response, error)
+	Expire(key string, seconds int) (*Response, error)
+	TTL(key string) (*Response, error)
+	Type(key string) (*Response, error)
+	HSet(key, field, value string) (*Response, error)
+	HGet(key, field string) (*Response, error)
+	HMSet(key string, hash map[string]string) (*Response, error)
+	HMGet(key string, fields ...string) (*Response, error)
+	HGetAll(key string) (*Response, error)
+	HDel(key string, fields ...string) (*Response, error)
+	HIncrBy(key, field string, increment int64) (*Response, error)
+	LPush(key string, values ...string) (*Response, error)
+	RPush(key string, values ...string) (*Response, error)
+	LPop(key string) (*Response, error)
+	RPop(key string) (*Response, error)
+	LLen(key string) (*Response, error)
+	LRange(key string, start, stop int64) (*Response, error)
+	SAdd(key string, members ...string) (*Response, error)
+	SRem(key string, members ...string) (*Response, error)
+	SMembers(key string) (*Response, error)
+	SIsMember(key, member string) (*Response, error)
+	ZAdd(key string, score float64, member string, params ...*ZAddParams) (*Response, error)
+	ZRem(key string, members ...string) (*Response, error)
+	ZRange(key string, start, stop int64) (*Response, error)
+	ZCard(key string) (*Response, error)
+	Del(keys ...string) (*Response, error)
+	Exists(keys ...string) (*Response, error)
+	BLPopTimeout(timeout int, keys ...string) (*Response, error)
+	BRPopTimeout(timeout int, keys ...string) (*Response, error)
+	BLPop(args ...string) (*Response, error)
+	BRPop(args ...string) (*Response, error)
+	Keys(pattern string) (*Response, error)
+	MGet(keys ...string) (*Response, error)
+	MSet(kvs ...string) (*Response, error)
+	MSetNx(kvs ...string) (*Response, error)
+	Rename(oldkey, newkey string) (*Response, error)
+	RenameNx(oldkey, newkey string) (*Response, error)
+	RPopLPush(srcKey, destKey string) (*Response, error)
+	SDiff(keys ...string) (*Response, error)
+	SDiffStore(destKey string, keys ...string) (*Response, error)
+	SInter(keys ...string) (*Response, error)
+	SInterStore(destKey string, keys ...string) (*Response, error)
+	SMove(srcKey, destKey, member string) (*Response, error)
+	SortStore(key string, destKey string, params ...*SortParams) (*Response, error)
+	SUnion(keys ...string) (*Response, error)
+	SUnionStore(destKey string, keys ...string) (*Response, error)
+	Watch(keys ...string) (*Response, error)
+	ZInterStore(destKey string, sets ...string) (*Response, error)
+	ZInterStoreWithParams(destKey string, params *ZParams, sets ...string) (*Response, error)
+	ZUnionStore(destKey string, sets ...string) (*Response, error)
+	ZUnionStoreWithParams(destKey string, params *ZParams, sets ...string) (*Response, error)
+	BRPopLPush(source, destination string, timeout int) (*Response, error)
+	Publish(channel, message string) (*Response, error)
+	RandomKey() (*Response, error)
+	BitOp(op BitOP, destKey string, srcKeys ...string) (*Response, error)
+	PfMerge(destKey string, srcKeys ...string) (*Response, error)
+	PfCount(keys ...string) (*Response, error)
+	ClusterNodes() (*Response, error)
+	ClusterMeet(ip string, port int) (*Response, error)
+	ClusterAddSlots(slots ...int) (*Response, error)
+	ClusterDelSlots(slots ...int) (*Response, error)
+	ClusterInfo() (*Response, error)
+	ClusterGetKeysInSlot(slot int, count int) (*Response, error)
+	ClusterSetSlotNode(slot int, nodeID string) (*Response, error)
+	ClusterSetSlotMigrating(slot int, nodeID string) (*Response, error)
+	ClusterSetSlotImporting(slot int, nodeID string) (*Response, error)
+	Eval(script string, keyCount int, params ...string) (*Response, error)
+	EvalSha(sha1 string, keyCount int, params ...string) (*Response, error)
+}
+
+//Pipeliner the public interface of Pipeline, it's useful when you want to store pipeline in struct field or mock it,
+//it grows like PipelineCommands
+type Pipeliner interface {
+	PipelineCommands
+	Sync() error
+	SyncAll() ([]interface{}, []error, error)
+	Discard() int
+}
+
+//Transactioner the public interface of Transaction, it's useful when you want to store transaction in struct field or mock it,
+//it grows like PipelineCommands
+type Transactioner interface {
+	PipelineCommands
+	Exec() ([]interface{}, error)
+	ExecGetResponse() ([]*Response, error)
+	Discard() (string, error)
+	Clear() (string, error)
+}
+
+var (
+	_ Pipeliner     = (*Pipeline)(nil)
+	_ Transactioner = (*Transaction)(nil)
+)
+
+//Transaction redis transaction struct, get it by Redis.Multi.
+//the lifecycle is: Redis.Multi -> queue commands -> Exec|ExecGetResponse|Discard,
+//after Exec or Discard, the transaction is finished and cannot be used again.
 type Transaction struct {
 	*multiKeyPipelineBase
 	inTransaction bool
@@ -166,9 +314,36 @@ func (t *Transaction) clean() {
 	t.pipelinedResponses = make([]*Response, 0)
 }
 
-//Pipeline redis pipeline struct
+//Pipeline redis pipeline struct, get it by Redis.Pipelined.
+//the lifecycle is: Redis.Pipelined -> queue commands -> Sync,
+//after Sync, the replies are filled into the queued responses and the pipeline can be reused.
 type Pipeline struct {
 	*multiKeyPipelineBase
+
+	option PipelineOption
+	synced int64 //size of the commands written by the connection at the last sync
+	err    error //error of the last automatic sync,returned by the next Sync
+}
+
+//PipelineErrorPolicy how a pipeline handles the errors replied to its commands
+type PipelineErrorPolicy int
+
+const (
+	//PipelineCollectErrors keep going,the error of every command is returned by its response,default
+	PipelineCollectErrors PipelineErrorPolicy = iota
+	//PipelineFailFast Sync returns the first error,once an automatic sync sees an error,
+	//the commands queued later are discarded without being sent
+	PipelineFailFast
+)
+
+//PipelineOption option of a pipeline syncing automatically,
+//so a bulk load doesn't hold millions of replies in memory until Sync
+type PipelineOption struct {
+	MaxPendingCommands int                      //sync when so many commands are waiting for replies,0 means no limit
+	MaxBufferBytes     int                      //sync when the commands waiting for replies are so large,0 means no limit
+	OnResponse         func(response *Response) //called with every response once its reply is read,in the order of the commands
+
+	ErrorPolicy PipelineErrorPolicy
 }
 
 func newPipeline(c *client) *Pipeline {
@@ -179,24 +354,220 @@ func newPipeline(c *client) *Pipeline {
 	return &Pipeline{multiKeyPipelineBase: base}
 }
 
+func newPipelineWithOption(c *client, option *PipelineOption) *Pipeline {
+	p := newPipeline(c)
+	if option != nil {
+		p.option = *option
+	}
+	p.synced = c.written
+	p.onQueued = p.autoSync
+	return p
+}
+
 //Sync  see redis command
 func (p *Pipeline) Sync() error {
+	err := p.sync()
+	if p.err != nil {
+		err, p.err = p.err, nil
+	}
+	return err
+}
+
+//SyncAll sync the pipeline,return the replies and the errors of the commands queued after the last sync,
+//errs[i] is the error of the i-th command or nil,the reply of a failed command is nil.
+//err is the error of the connection,or the first error of the commands if the policy is PipelineFailFast
+func (p *Pipeline) SyncAll() ([]interface{}, []error, error) {
+	p.mu.Lock()
+	responses := append([]*Response(nil), p.pipelinedResponses...)
+	p.mu.Unlock()
+	err := p.Sync()
+	replies := make([]interface{}, len(responses))
+	errs := make([]error, len(responses))
+	for i, r := range responses {
+		if !r.isSet && err != nil {
+			errs[i] = err
+			continue
+		}
+		replies[i], errs[i] = r.Get()
+	}
+	return replies, errs, err
+}
+
+//Discard drop the queued commands not sent yet,their responses fail,return the count of them.
+//commands are sent when the pipeline is synced,or earlier if they are large
+func (p *Pipeline) Discard() int {
+	return p.discard(newDataError("command is discarded from the pipeline"))
+}
+
+func (p *Pipeline) discard(err error) int {
+	n := p.client.discardPending()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if n > len(p.pipelinedResponses) {
+		n = len(p.pipelinedResponses)
+	}
+	keep := len(p.pipelinedResponses) - n
+	for _, r := range p.pipelinedResponses[keep:] {
+		r.set(err)
+		if b, ok := r.builder.(*queuedBuilder); ok {
+			b.response.set(err)
+		}
+	}
+	p.pipelinedResponses = p.pipelinedResponses[:keep]
+	return n
+}
+
+func (p *Pipeline) sync() error {
 	if len(p.pipelinedResponses) == 0 {
 		return nil
 	}
+	p.synced = p.client.written
 	all, err := p.client.connection.getAll()
 	if err != nil {
 		return err
 	}
 	for _, a := range all.([]interface{}) {
-		p.generateResponse(a)
+		r := p.generateResponse(a)
+		if e, ok := a.(error); ok && err == nil && p.option.ErrorPolicy == PipelineFailFast {
+			err = e
+		}
+		if r == nil {
+			continue
+		}
+		switch b := r.builder.(type) {
+		case *queuedBuilder:
+			//a command rejected by MULTI fails at once,EXEC will be aborted
+			if e, ok := a.(error); ok {
+				b.response.set(e)
+			}
+			continue
+		case *transactionBuilder:
+			b.unpack(a, p.option.OnResponse)
+		}
+		if p.option.OnResponse != nil {
+			p.option.OnResponse(r)
+		}
+	}
+	return err
+}
+
+//Multi start a transaction in the pipeline,the commands queued until Exec are executed atomically
+//in the same round trip as the other commands,their responses are filled by the reply of EXEC
+func (p *Pipeline) Multi() (*Response, error) {
+	p.mu.Lock()
+	inMulti := p.inMulti
+	p.mu.Unlock()
+	if inMulti {
+		return nil, newDataError("MULTI calls can not be nested")
+	}
+	err := p.client.multi()
+	if err != nil {
+		return nil, err
+	}
+	r := p.getResponse(StrBuilder)
+	p.mu.Lock()
+	p.inMulti, p.multi = true, make([]*Response, 0)
+	p.mu.Unlock()
+	return r, nil
+}
+
+//Exec execute the transaction started by Multi,the response is the replies of the commands in the transaction,
+//the response of every command gets its own reply too
+func (p *Pipeline) Exec() (*Response, error) {
+	p.mu.Lock()
+	inMulti := p.inMulti
+	p.mu.Unlock()
+	if !inMulti {
+		return nil, newDataError("EXEC without MULTI")
+	}
+	err := p.client.exec()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	responses := p.multi
+	p.inMulti, p.multi = false, nil
+	p.mu.Unlock()
+	return p.getResponse(&transactionBuilder{responses: responses}), nil
+}
+
+//queuedBuilder the QUEUED reply of a command in the transaction of a pipeline
+type queuedBuilder struct {
+	response *Response
+}
+
+func (b *queuedBuilder) build(data interface{}) (interface{}, error) {
+	return StrBuilder.build(data)
+}
+
+//transactionBuilder the reply of EXEC of the transaction of a pipeline
+type transactionBuilder struct {
+	responses []*Response
+}
+
+func (b *transactionBuilder) build(data interface{}) (interface{}, error) {
+	result := make([]interface{}, 0, len(b.responses))
+	for _, r := range b.responses {
+		reply, err := r.Get()
+		if err != nil {
+			result = append(result, err)
+			continue
+		}
+		result = append(result, reply)
+	}
+	return result, nil
+}
+
+//unpack fill the responses of the commands by the reply of EXEC,
+//they fail if the transaction is aborted
+func (b *transactionBuilder) unpack(data interface{}, onResponse func(response *Response)) {
+	var err error
+	replies, ok := data.([]interface{})
+	if !ok {
+		if err, ok = data.(error); !ok {
+			err = newDataError("transaction is aborted,a watched key is modified")
+		}
+	}
+	for i, r := range b.responses {
+		if r.isSet {
+			continue
+		}
+		if err != nil || i >= len(replies) {
+			r.set(err)
+		} else {
+			r.set(replies[i])
+		}
+		if onResponse != nil {
+			onResponse(r)
+		}
+	}
+}
+
+//autoSync sync the pipeline once the commands waiting for replies reach the limits of the option
+func (p *Pipeline) autoSync() {
+	if p.err != nil {
+		if p.option.ErrorPolicy == PipelineFailFast {
+			p.discard(p.err)
+		}
+		return
+	}
+	full := p.option.MaxPendingCommands > 0 && p.getPipelinedResponseLength() >= p.option.MaxPendingCommands
+	if p.option.MaxBufferBytes > 0 && p.client.written-p.synced >= int64(p.option.MaxBufferBytes) {
+		full = true
+	}
+	if full {
+		p.err = p.sync()
 	}
-	return nil
 }
 
 type queue struct {
 	pipelinedResponses []*Response
 	mu                 sync.Mutex
+
+	onQueued func() //called after a response is queued,nil means nothing to do
+
+	inMulti bool        //a transaction is started by Pipeline.Multi
+	multi   []*Response //responses of the commands in the transaction
 }
 
 func newQueue() *queue {
@@ -228,10 +599,21 @@ func (q *queue) generateResponse(data interface{}) *Response {
 
 func (q *queue) getResponse(builder Builder) *Response {
 	q.mu.Lock()
-	defer q.mu.Unlock()
 	response := newResponse()
 	response.builder = builder
-	q.pipelinedResponses = append(q.pipelinedResponses, response)
+	if q.inMulti {
+		//the reply of the command is QUEUED,the real reply comes with EXEC
+		q.multi = append(q.multi, response)
+		queued := newResponse()
+		queued.builder = &queuedBuilder{response: response}
+		q.pipelinedResponses = append(q.pipelinedResponses, queued)
+	} else {
+		q.pipelinedResponses = append(q.pipelinedResponses, response)
+	}
+	q.mu.Unlock()
+	if q.onQueued != nil {
+		q.onQueued()
+	}
 	return response
 }
 
@@ -330,6 +712,15 @@ func (p *multiKeyPipelineBase) FlushDB() (*Response, error) {
 	return p.getResponse(StrBuilder), nil
 }
 
+//FlushDBWithMode  see redis command
+func (p *multiKeyPipelineBase) FlushDBWithMode(mode *FlushMode) (*Response, error) {
+	err := p.client.flushDB(mode)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
 //FlushAll  see redis command
 func (p *multiKeyPipelineBase) FlushAll() (*Response, error) {
 	err := p.client.flushAll()
@@ -339,6 +730,15 @@ func (p *multiKeyPipelineBase) FlushAll() (*Response, error) {
 	return p.getResponse(StrBuilder), nil
 }
 
+//FlushAllWithMode  see redis command
+func (p *multiKeyPipelineBase) FlushAllWithMode(mode *FlushMode) (*Response, error) {
+	err := p.client.flushAll(mode)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
 //Info  see redis command
 func (p *multiKeyPipelineBase) Info() (*Response, error) {
 	err := p.client.info()
@@ -375,6 +775,15 @@ func (p *multiKeyPipelineBase) Shutdown() (*Response, error) {
 	return p.getResponse(StrBuilder), nil
 }
 
+//ShutdownWithParams  see redis command
+func (p *multiKeyPipelineBase) ShutdownWithParams(params *ShutdownParams) (*Response, error) {
+	err := p.client.shutdown(params)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
 //Ping  see redis command
 func (p *multiKeyPipelineBase) Ping() (*Response, error) {
 	err := p.client.ping()
@@ -395,6 +804,298 @@ func (p *multiKeyPipelineBase) Select(index int) (*Response, error) {
 
 //</editor-fold>
 
+//<editor-fold desc="singlekeypipeline">
+
+//Get  see redis command
+func (p *multiKeyPipelineBase) Get(key string) (*Response, error) {
+	err := p.getClient(key).get(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//Set  see redis command
+func (p *multiKeyPipelineBase) Set(key, value string) (*Response, error) {
+	err := p.getClient(key).set(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//SetEx  see redis command
+func (p *multiKeyPipelineBase) SetEx(key string, seconds int, value string) (*Response, error) {
+	err := p.getClient(key).setex(key, seconds, value)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//SetNx  see redis command
+func (p *multiKeyPipelineBase) SetNx(key, value string) (*Response, error) {
+	err := p.getClient(key).setnx(key, value)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//Incr  see redis command
+func (p *multiKeyPipelineBase) Incr(key string) (*Response, error) {
+	err := p.getClient(key).incr(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//IncrBy  see redis command
+func (p *multiKeyPipelineBase) IncrBy(key string, increment int64) (*Response, error) {
+	err := p.getClient(key).incrBy(key, increment)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//Decr  see redis command
+func (p *multiKeyPipelineBase) Decr(key string) (*Response, error) {
+	err := p.getClient(key).decr(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//DecrBy  see redis command
+func (p *multiKeyPipelineBase) DecrBy(key string, decrement int64) (*Response, error) {
+	err := p.getClient(key).decrBy(key, decrement)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//Expire  see redis command
+func (p *multiKeyPipelineBase) Expire(key string, seconds int) (*Response, error) {
+	err := p.getClient(key).expire(key, seconds)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//TTL  see redis command
+func (p *multiKeyPipelineBase) TTL(key string) (*Response, error) {
+	err := p.getClient(key).ttl(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//Type  see redis command
+func (p *multiKeyPipelineBase) Type(key string) (*Response, error) {
+	err := p.getClient(key).typeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//HSet  see redis command
+func (p *multiKeyPipelineBase) HSet(key, field, value string) (*Response, error) {
+	err := p.getClient(key).hset(key, field, value)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//HGet  see redis command
+func (p *multiKeyPipelineBase) HGet(key, field string) (*Response, error) {
+	err := p.getClient(key).hget(key, field)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//HMSet  see redis command
+func (p *multiKeyPipelineBase) HMSet(key string, hash map[string]string) (*Response, error) {
+	err := p.getClient(key).hmset(key, hash)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//HMGet  see redis command
+func (p *multiKeyPipelineBase) HMGet(key string, fields ...string) (*Response, error) {
+	err := p.getClient(key).hmget(key, fields...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//HGetAll  see redis command,the reply is the fields and values in turn
+func (p *multiKeyPipelineBase) HGetAll(key string) (*Response, error) {
+	err := p.getClient(key).hgetAll(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//HDel  see redis command
+func (p *multiKeyPipelineBase) HDel(key string, fields ...string) (*Response, error) {
+	err := p.getClient(key).hdel(key, fields...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//HIncrBy  see redis command
+func (p *multiKeyPipelineBase) HIncrBy(key, field string, increment int64) (*Response, error) {
+	err := p.getClient(key).hincrBy(key, field, increment)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//LPush  see redis command
+func (p *multiKeyPipelineBase) LPush(key string, values ...string) (*Response, error) {
+	err := p.getClient(key).lpush(key, values...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//RPush  see redis command
+func (p *multiKeyPipelineBase) RPush(key string, values ...string) (*Response, error) {
+	err := p.getClient(key).rpush(key, values...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//LPop  see redis command
+func (p *multiKeyPipelineBase) LPop(key string) (*Response, error) {
+	err := p.getClient(key).lpop(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//RPop  see redis command
+func (p *multiKeyPipelineBase) RPop(key string) (*Response, error) {
+	err := p.getClient(key).rPop(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrBuilder), nil
+}
+
+//LLen  see redis command
+func (p *multiKeyPipelineBase) LLen(key string) (*Response, error) {
+	err := p.getClient(key).llen(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//LRange  see redis command
+func (p *multiKeyPipelineBase) LRange(key string, start, stop int64) (*Response, error) {
+	err := p.getClient(key).lrange(key, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//SAdd  see redis command
+func (p *multiKeyPipelineBase) SAdd(key string, members ...string) (*Response, error) {
+	err := p.getClient(key).sAdd(key, members...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//SRem  see redis command
+func (p *multiKeyPipelineBase) SRem(key string, members ...string) (*Response, error) {
+	err := p.getClient(key).sRem(key, members...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//SMembers  see redis command
+func (p *multiKeyPipelineBase) SMembers(key string) (*Response, error) {
+	err := p.getClient(key).sMembers(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//SIsMember  see redis command,the reply is 1 if it is a member
+func (p *multiKeyPipelineBase) SIsMember(key, member string) (*Response, error) {
+	err := p.getClient(key).sIsMember(key, member)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//ZAdd  see redis command
+func (p *multiKeyPipelineBase) ZAdd(key string, score float64, member string, params ...*ZAddParams) (*Response, error) {
+	err := p.getClient(key).zAdd(key, score, member, params...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//ZRem  see redis command
+func (p *multiKeyPipelineBase) ZRem(key string, members ...string) (*Response, error) {
+	err := p.getClient(key).zRem(key, members...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//ZRange  see redis command
+func (p *multiKeyPipelineBase) ZRange(key string, start, stop int64) (*Response, error) {
+	err := p.getClient(key).zRange(key, start, stop)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//ZCard  see redis command
+func (p *multiKeyPipelineBase) ZCard(key string) (*Response, error) {
+	err := p.getClient(key).zCard(key)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//</editor-fold>
+
 //<editor-fold desc="multikeypipeline">
 
 //Del see redis command
@@ -631,6 +1332,168 @@ func (p *multiKeyPipelineBase) ZUnionStoreWithParams(destKey string, params *ZPa
 	return p.getResponse(Int64Builder), nil
 }
 
+//ZDiff  see redis command
+func (p *multiKeyPipelineBase) ZDiff(keys ...string) (*Response, error) {
+	err := p.client.zdiff(false, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//ZDiffWithScores  see redis command
+func (p *multiKeyPipelineBase) ZDiffWithScores(keys ...string) (*Response, error) {
+	err := p.client.zdiff(true, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(TupleArrBuilder), nil
+}
+
+//ZDiffStore  see redis command
+func (p *multiKeyPipelineBase) ZDiffStore(destKey string, keys ...string) (*Response, error) {
+	err := p.client.zdiffstore(destKey, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//ZUnion  see redis command
+func (p *multiKeyPipelineBase) ZUnion(params *ZParams, keys ...string) (*Response, error) {
+	err := p.client.zunion(params, false, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//ZUnionWithScores  see redis command
+func (p *multiKeyPipelineBase) ZUnionWithScores(params *ZParams, keys ...string) (*Response, error) {
+	err := p.client.zunion(params, true, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(TupleArrBuilder), nil
+}
+
+//ZInter  see redis command
+func (p *multiKeyPipelineBase) ZInter(params *ZParams, keys ...string) (*Response, error) {
+	err := p.client.zinter(params, false, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//ZInterWithScores  see redis command
+func (p *multiKeyPipelineBase) ZInterWithScores(params *ZParams, keys ...string) (*Response, error) {
+	err := p.client.zinter(params, true, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(TupleArrBuilder), nil
+}
+
+//ZInterCard  see redis command
+func (p *multiKeyPipelineBase) ZInterCard(limit int64, keys ...string) (*Response, error) {
+	err := p.client.zintercard(limit, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
+//ZPopMin  see redis command
+func (p *multiKeyPipelineBase) ZPopMin(key string, count ...int64) (*Response, error) {
+	err := p.getClient(key).zPopMin(key, count...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(TupleArrBuilder), nil
+}
+
+//ZPopMax  see redis command
+func (p *multiKeyPipelineBase) ZPopMax(key string, count ...int64) (*Response, error) {
+	err := p.getClient(key).zPopMax(key, count...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(TupleArrBuilder), nil
+}
+
+//BZPopMin  see redis command
+func (p *multiKeyPipelineBase) BZPopMin(timeout int, keys ...string) (*Response, error) {
+	err := p.client.bzPopMin(timeout, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(KeyedTupleBuilder), nil
+}
+
+//BZPopMax  see redis command
+func (p *multiKeyPipelineBase) BZPopMax(timeout int, keys ...string) (*Response, error) {
+	err := p.client.bzPopMax(timeout, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(KeyedTupleBuilder), nil
+}
+
+//BZMPop  see redis command
+func (p *multiKeyPipelineBase) BZMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*Response, error) {
+	err := p.client.bzmPop(timeout, direction, count, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(KeyedTuplesBuilder), nil
+}
+
+//BLMPop  see redis command
+func (p *multiKeyPipelineBase) BLMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*Response, error) {
+	err := p.client.blmPop(timeout, direction, count, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(KeyedElementsBuilder), nil
+}
+
+//SPopBatch  see redis command
+func (p *multiKeyPipelineBase) SPopBatch(key string, count int64) (*Response, error) {
+	err := p.getClient(key).sPopBatch(key, count)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//LPopCount  see redis command
+func (p *multiKeyPipelineBase) LPopCount(key string, count int64) (*Response, error) {
+	err := p.getClient(key).lpopCount(key, count)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//RPopCount  see redis command
+func (p *multiKeyPipelineBase) RPopCount(key string, count int64) (*Response, error) {
+	err := p.getClient(key).rpopCount(key, count)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
+//SRandMemberBatch  see redis command,a negative count may return the same element multiple times
+func (p *multiKeyPipelineBase) SRandMemberBatch(key string, count int) (*Response, error) {
+	err := p.getClient(key).sRandMemberBatch(key, count)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(StrArrBuilder), nil
+}
+
 //BRPopLPush  see redis command
 func (p *multiKeyPipelineBase) BRPopLPush(source, destination string, timeout int) (*Response, error) {
 	err := p.client.brpoplpush(source, destination, timeout)
@@ -667,6 +1530,15 @@ func (p *multiKeyPipelineBase) BitOp(op BitOP, destKey string, srcKeys ...string
 	return p.getResponse(Int64Builder), nil
 }
 
+//PfAdd  see redis command
+func (p *multiKeyPipelineBase) PfAdd(key string, elements ...string) (*Response, error) {
+	err := p.client.pfadd(key, elements...)
+	if err != nil {
+		return nil, err
+	}
+	return p.getResponse(Int64Builder), nil
+}
+
 //PfMerge  see redis command
 func (p *multiKeyPipelineBase) PfMerge(destKey string, srcKeys ...string) (*Response, error) {
 	err := p.client.pfmerge(destKey, srcKeys...)
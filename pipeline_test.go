@@ -448,7 +448,7 @@ func Test_multiKeyPipelineBase_ConfigSet(t *testing.T) {
 	assert.Equal(t, []string{"timeout", "30"}, resp2)
 	resp3, err := ToStrReply(reply2.Get())
 	assert.Nil(t, err)
-	assert.Equal(t, "\x00\x00", resp3)
+	assert.Equal(t, "OK", resp3)
 	resp4, err := ToStrArrReply(reply3.Get())
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"timeout", "0"}, resp4)
@@ -526,7 +526,7 @@ func Test_multiKeyPipelineBase_Eval(t *testing.T) {
 	resp1, _ := ToStrReply(s1.Get())
 	assert.Equal(t, "good", resp1)
 	resp2, _ := ToStrReply(s2.Get())
-	assert.Equal(t, "\x00\x00", resp2)
+	assert.Equal(t, "OK", resp2)
 	resp3, _ := ToStrReply(s3.Get())
 	assert.Equal(t, "godis", resp3)
 
@@ -760,6 +760,37 @@ func Test_multiKeyPipelineBase_Mget(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func Test_multiKeyPipelineBase_Pfadd(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	p := redis.Pipelined()
+	reply1, err := p.PfAdd("godis", "a", "b", "c")
+	assert.Nil(t, err)
+	reply2, err := p.PfAdd("godis", "a")
+	assert.Nil(t, err)
+	reply3, err := p.PfCount("godis")
+	assert.Nil(t, err)
+	p.Sync()
+	resp1, err := ToInt64Reply(reply1.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), resp1)
+	resp2, err := ToInt64Reply(reply2.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), resp2)
+	resp3, err := ToInt64Reply(reply3.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), resp3)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	brokenPipe := redisBroken.Pipelined()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = brokenPipe.PfAdd("godis", "a")
+	assert.NotNil(t, err)
+}
+
 func Test_multiKeyPipelineBase_Pfcount(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -794,7 +825,7 @@ func Test_multiKeyPipelineBase_Pfcount(t *testing.T) {
 	assert.Equal(t, int64(4), resp2)
 	resp3, err := ToStrReply(reply3.Get())
 	assert.Nil(t, err)
-	assert.Equal(t, "\x00\x00", resp3)
+	assert.Equal(t, "OK", resp3)
 	resp4, err := ToInt64Reply(reply4.Get())
 	assert.Nil(t, err)
 	assert.Equal(t, int64(4), resp4)
@@ -1203,6 +1234,139 @@ func Test_multiKeyPipelineBase_Zinterstore(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func Test_multiKeyPipelineBase_ZDiff(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	p := redis.Pipelined()
+	c, err := redis.ZAddByMap("godis1", map[string]float64{"a": 1, "b": 2, "c": 3})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+
+	c, err = redis.ZAddByMap("godis2", map[string]float64{"a": 1, "b": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+
+	r1, err := p.ZDiff("godis1", "godis2")
+	assert.Nil(t, err)
+	r2, err := p.ZDiffWithScores("godis1", "godis2")
+	assert.Nil(t, err)
+	r3, err := p.ZInter(nil, "godis1", "godis2")
+	assert.Nil(t, err)
+	r4, err := p.ZInterCard(0, "godis1", "godis2")
+	assert.Nil(t, err)
+
+	p.Sync()
+	arr, err := ToStrArrReply(r1.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c"}, arr)
+	tuples, err := ToTupleArrReply(r2.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "c", score: 3}}, tuples)
+	arr, err = ToStrArrReply(r3.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, arr)
+	c, err = ToInt64Reply(r4.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	brokenPipe := redisBroken.Pipelined()
+	_, err = brokenPipe.ZDiff("godis1", "godis2")
+	assert.NotNil(t, err)
+	_, err = brokenPipe.ZUnion(nil, "godis1", "godis2")
+	assert.NotNil(t, err)
+}
+
+func Test_multiKeyPipelineBase_ZPopMin(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	p := redis.Pipelined()
+	c, err := redis.ZAddByMap("godis", map[string]float64{"a": 1, "b": 2, "c": 3})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+
+	r1, err := p.ZPopMin("godis")
+	assert.Nil(t, err)
+	r2, err := p.ZPopMax("godis")
+	assert.Nil(t, err)
+	r3, err := p.BZPopMin(1, "godis")
+	assert.Nil(t, err)
+
+	p.Sync()
+	tuples, err := ToTupleArrReply(r1.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "a", score: 1}}, tuples)
+	tuples, err = ToTupleArrReply(r2.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "c", score: 3}}, tuples)
+	tuple, err := ToKeyedTupleReply(r3.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyedTuple{Key: "godis", Tuple: Tuple{element: "b", score: 2}}, tuple)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	brokenPipe := redisBroken.Pipelined()
+	_, err = brokenPipe.ZPopMin("godis")
+	assert.NotNil(t, err)
+	_, err = brokenPipe.BZPopMax(1, "godis")
+	assert.NotNil(t, err)
+}
+
+func Test_multiKeyPipelineBase_SPopBatch(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	c, err := redis.SAdd("godis", "1", "2", "3")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+
+	p := redis.Pipelined()
+	r1, err := p.SRandMemberBatch("godis", -5)
+	assert.Nil(t, err)
+	r2, err := p.SRandMemberBatch("godis", 5)
+	assert.Nil(t, err)
+	r3, err := p.SPopBatch("godis", 2)
+	assert.Nil(t, err)
+	p.Sync()
+
+	arr, err := ToStrArrReply(r1.Get())
+	assert.Nil(t, err)
+	assert.Len(t, arr, 5)
+	arr, err = ToStrArrReply(r2.Get())
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, arr)
+	arr, err = ToStrArrReply(r3.Get())
+	assert.Nil(t, err)
+	assert.Len(t, arr, 2)
+
+	tx, err := redis.Multi()
+	assert.Nil(t, err)
+	r4, err := tx.SPopBatch("godis", 2)
+	assert.Nil(t, err)
+	_, err = tx.Exec()
+	assert.Nil(t, err)
+	arr, err = ToStrArrReply(r4.Get())
+	assert.Nil(t, err)
+	assert.Len(t, arr, 1)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	brokenPipe := redisBroken.Pipelined()
+	_, err = brokenPipe.SPopBatch("godis", 2)
+	assert.NotNil(t, err)
+	_, err = brokenPipe.SRandMemberBatch("godis", 2)
+	assert.NotNil(t, err)
+}
+
 func Test_Transaction(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -1249,9 +1413,229 @@ func Test_Transaction2(t *testing.T) {
 
 	s, err := p.Discard()
 	assert.Nil(t, err)
-	assert.Equal(t, "\x00\x00", s)
+	assert.Equal(t, "OK", s)
 
 	s, err = p.Clear()
 	assert.Nil(t, err)
 	assert.Equal(t, "", s)
 }
+
+func Test_Pipeliner(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.Set("godis", "good")
+
+	var p Pipeliner = redis.Pipelined()
+	del, err := p.Del("godis")
+	assert.Nil(t, err)
+	err = p.Sync()
+	assert.Nil(t, err)
+	c, err := ToInt64Reply(del.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	var m Transactioner
+	m, err = redis.Multi()
+	assert.Nil(t, err)
+	exists, err := m.Exists("godis")
+	assert.Nil(t, err)
+	_, err = m.Exec()
+	assert.Nil(t, err)
+	c, err = ToInt64Reply(exists.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+}
+
+func Test_Pipeline_AutoSync(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	var synced []int64
+	p := redis.PipelinedWithOption(&PipelineOption{
+		MaxPendingCommands: 3,
+		OnResponse: func(response *Response) {
+			c, err := ToInt64Reply(response.Get())
+			assert.Nil(t, err)
+			synced = append(synced, c)
+		},
+	})
+	for i := 0; i < 7; i++ {
+		_, err := p.Exists("godis")
+		assert.Nil(t, err)
+		assert.Equal(t, (i+1)/3*3, len(synced))
+		assert.True(t, p.getPipelinedResponseLength() < 3)
+	}
+	assert.Nil(t, p.Sync())
+	assert.Equal(t, 7, len(synced))
+
+	//a large value syncs the pipeline by size
+	synced = nil
+	p = redis.PipelinedWithOption(&PipelineOption{MaxBufferBytes: 1024})
+	del, err := p.Del("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, p.getPipelinedResponseLength())
+	_, err = p.MSet("godis", strings.Repeat("a", 1024))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, p.getPipelinedResponseLength())
+	c, err := ToInt64Reply(del.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+	assert.Nil(t, p.Sync())
+	s, err := redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, 1024, len(s))
+}
+
+func Test_Pipeline_ErrorPolicy(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	p := redis.Pipelined()
+	p.MSet("godis", "good")
+	p.Del("godis", "a")
+	p.MSet("godis", "good")
+	p.BitOp(*BitOpNot, "dest", "godis", "a")
+	p.Exists("godis")
+	replies, errs, err := p.SyncAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(replies))
+	assert.Equal(t, []error{nil, nil, nil, errs[3], nil}, errs)
+	assert.IsType(t, &DataError{}, errs[3])
+	assert.Equal(t, "OK", replies[0])
+	assert.Equal(t, int64(1), replies[4])
+
+	//the commands queued after an error are never sent
+	p = redis.PipelinedWithOption(&PipelineOption{MaxPendingCommands: 2, ErrorPolicy: PipelineFailFast})
+	p.BitOp(*BitOpNot, "dest", "godis", "a")
+	p.Exists("godis")
+	r, err := p.Del("godis")
+	assert.Nil(t, err)
+	_, err = r.Get()
+	assert.NotNil(t, err)
+	err = p.Sync()
+	assert.IsType(t, &DataError{}, err)
+	c, err := redis.Exists("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	assert.Nil(t, p.Sync())
+
+	p = redis.Pipelined()
+	del, _ := p.Del("godis")
+	p.MSet("godis2", "good")
+	assert.Equal(t, 2, p.Discard())
+	assert.Equal(t, 0, p.Discard())
+	_, err = del.Get()
+	assert.NotNil(t, err)
+	assert.Nil(t, p.Sync())
+	c, err = redis.Exists("godis", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+}
+
+func Test_Pipeline_Multi(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	p := redis.Pipelined()
+	before, _ := p.MSet("godis", "good")
+	multi, err := p.Multi()
+	assert.Nil(t, err)
+	_, err = p.Multi()
+	assert.NotNil(t, err)
+	del, _ := p.Del("godis2")
+	set, _ := p.MSet("godis2", "good")
+	bitop, _ := p.BitOp(*BitOpAnd, "godis3", "godis", "godis2")
+	exists, _ := p.Exists("godis", "godis2")
+	exec, err := p.Exec()
+	assert.Nil(t, err)
+	after, _ := p.Exists("godis3")
+	_, err = p.Exec()
+	assert.NotNil(t, err)
+	assert.Nil(t, p.Sync())
+
+	s, err := ToStrReply(before.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = ToStrReply(multi.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	c, err := ToInt64Reply(del.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+	s, err = ToStrReply(set.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	c, err = ToInt64Reply(bitop.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), c)
+	c, err = ToInt64Reply(exists.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+	replies, err := exec.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{int64(0), "OK", int64(4), int64(2)}, replies)
+	c, err = ToInt64Reply(after.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	assert.False(t, redis.client.isInMulti)
+}
+
+func Test_multiKeyPipelineBase_SingleKey(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SET godis good":        {"+OK\r\n"},
+		"GET godis":             {"$4\r\ngood\r\n"},
+		"INCR counter":          {":1\r\n"},
+		"HSET hash field value": {":1\r\n"},
+		"HGETALL hash":          {"*2\r\n$5\r\nfield\r\n$5\r\nvalue\r\n"},
+		"QUIT":                  {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	p := redis.Pipelined()
+	set, err := p.Set("godis", "good")
+	assert.Nil(t, err)
+	get, err := p.Get("godis")
+	assert.Nil(t, err)
+	incr, err := p.Incr("counter")
+	assert.Nil(t, err)
+	hset, err := p.HSet("hash", "field", "value")
+	assert.Nil(t, err)
+	hgetAll, err := p.HGetAll("hash")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Sync())
+	s, err := ToStrReply(set.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = ToStrReply(get.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	n, err := ToInt64Reply(incr.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = ToInt64Reply(hset.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	arr, err := ToStrArrReply(hgetAll.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"field", "value"}, arr)
+}
+
+//getOnlyPipeliner a mock implementing only the commands it uses,the other methods come from the embedded interface
+type getOnlyPipeliner struct {
+	Pipeliner
+	keys []string
+}
+
+func (p *getOnlyPipeliner) Get(key string) (*Response, error) {
+	p.keys = append(p.keys, key)
+	return newResponse(), nil
+}
+
+func TestPipeliner_Mock(t *testing.T) {
+	var p Pipeliner = &getOnlyPipeliner{}
+	_, err := p.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"godis"}, p.(*getOnlyPipeliner).keys)
+}
@@ -4,18 +4,31 @@ import (
 	"context"
 	"errors"
 	"github.com/jolestar/go-commons-pool"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
-	//ErrClosed when pool is closed,continue operate pool will return this error
-	ErrClosed = errors.New("pool is closed")
+	//ErrClosed when pool is closed,continue operate pool will return this error,the same as ErrPoolClosed
+	ErrClosed = ErrPoolClosed
+	//ErrPoolExhausted when no redis instance is available before MaxWaitMillis or the deadline of the context elapses
+	ErrPoolExhausted = errors.New("pool exhausted")
+
+	errConnExpired = errors.New("connection exceeds max lifetime")
 )
 
-//Pool redis pool
+//Pool redis pool,the connections are on the db of the option,see WithDb to use other dbs
 type Pool struct {
 	internalPool *pool.ObjectPool
 	ctx          context.Context
+	maxWait      time.Duration
+	draining     int32
+	option       *Option //option of the view,its Db is selected on the borrowed connections
+	base         *Pool   //the pool created by NewPool,the views share its connections,draining state and subscribers
+
+	subMu       sync.Mutex
+	subscribers map[*Redis]struct{} //dedicated connections of the running subscriptions
 }
 
 //PoolConfig redis pool config, see go-commons-pool ObjectPoolConfig
@@ -24,6 +37,8 @@ type PoolConfig struct {
 	MaxIdle  int //The cap on the number of "idle" instances in the pool
 	MinIdle  int //The minimum number of idle objects to maintain in the pool
 
+	MaxWaitMillis int64 //The maximum milliseconds GetResource blocks when the pool is exhausted,0 means block until the context is done
+
 	LIFO               bool //Whether the pool has LIFO (last in, first out) behaviour
 	TestOnBorrow       bool //Whether objects borrowed from the pool will be validated before being returned from the ObjectPool.BorrowObject() method
 	TestWhileIdle      bool //Whether objects sitting idle in the pool will be validated by the idle object evictor (if any - see TimeBetweenEvictionRuns )
@@ -36,6 +51,8 @@ type PoolConfig struct {
 	TimeBetweenEvictionRuns  time.Duration //The amount of time sleep between runs of the idle object evictor goroutine.
 	EvictionPolicyName       string        //The name of the EvictionPolicy implementation
 	NumTestsPerEvictionRun   int           //The maximum number of objects to examine during each run
+
+	MaxConnLifetime time.Duration //The maximum time a connection is used since it is dialed,an older one is closed when borrowed or returned,even if healthy,0 means no limit
 }
 
 //NewPool create new pool
@@ -56,26 +73,118 @@ func NewPool(config *PoolConfig, option *Option) *Pool {
 	if config != nil && config.TestOnBorrow != false {
 		poolConfig.TestOnBorrow = config.TestOnBorrow
 	}
+	var maxWait time.Duration
+	if config != nil && config.MaxWaitMillis > 0 {
+		maxWait = time.Duration(config.MaxWaitMillis) * time.Millisecond
+	}
+	factory := newFactory(option)
+	if config != nil && config.MaxConnLifetime > 0 {
+		factory.maxLifetime = config.MaxConnLifetime
+	}
 	ctx := context.Background()
-	internalPool := pool.NewObjectPool(ctx, newFactory(option), poolConfig)
+	internalPool := pool.NewObjectPool(ctx, factory, poolConfig)
 	internalPool.PreparePool(ctx)
-	return &Pool{
+	p := &Pool{
 		ctx:          ctx,
 		internalPool: internalPool,
+		maxWait:      maxWait,
+		option:       option,
+		subscribers:  make(map[*Redis]struct{}),
+	}
+	p.base = p
+	return p
+}
+
+//WithDb a view of the pool on db,the connections borrowed from it select db,
+//they are shared with the pool and select the db of the pool again when returned,
+//so one pool can serve several dbs without mixing them up.
+//draining or destroying a view drains or destroys the whole pool
+//
+//	sessions := pool.WithDb(1)
+//	redis, err := sessions.GetResource()
+func (p *Pool) WithDb(db int) *Pool {
+	option := *p.option
+	option.Db = db
+	return &Pool{
+		ctx:          p.ctx,
+		internalPool: p.internalPool,
+		maxWait:      p.maxWait,
+		option:       &option,
+		base:         p.base,
 	}
 }
 
-//GetResource get redis instance from pool
+//Db the db selected on the connections borrowed from the pool
+func (p *Pool) Db() int {
+	return p.option.Db
+}
+
+//GetResource get redis instance from pool,
+//return ErrPoolExhausted if no instance is available within MaxWaitMillis
 func (p *Pool) GetResource() (*Redis, error) {
-	obj, err := p.internalPool.BorrowObject(p.ctx)
+	return p.GetResourceContext(p.ctx)
+}
+
+//GetResourceContext get redis instance from pool,
+//wait until an instance is available,MaxWaitMillis elapses or ctx is done,
+//return ErrPoolExhausted when the wait times out and the error of ctx when ctx is canceled
+func (p *Pool) GetResourceContext(ctx context.Context) (*Redis, error) {
+	if atomic.LoadInt32(&p.base.draining) == 1 {
+		return nil, ErrClosed
+	}
+	if p.maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.maxWait)
+		defer cancel()
+	}
+	start := time.Now()
+	obj, err := p.internalPool.BorrowObject(ctx)
 	if err != nil {
-		return nil, newConnectError(err.Error())
+		if ctx.Err() == context.Canceled {
+			return nil, ctx.Err()
+		}
+		if isPoolExhausted(err) {
+			return nil, ErrPoolExhausted
+		}
+		if isPoolClosed(err) {
+			return nil, ErrClosed
+		}
+		return nil, asConnectError(err)
 	}
 	redis := obj.(*Redis)
+	if atomic.LoadInt32(&p.base.draining) == 1 {
+		//the pool started draining while borrowing
+		p.internalPool.ReturnObject(p.ctx, redis)
+		return nil, ErrClosed
+	}
+	if err := selectPoolDb(redis, p.option.Db); err != nil {
+		p.internalPool.InvalidateObject(p.ctx, redis)
+		return nil, err
+	}
+	redis.client.poolWait = time.Since(start)
 	redis.setDataSource(p)
 	return redis, nil
 }
 
+//isPoolExhausted whether the borrow failed because no object is available in time
+func isPoolExhausted(err error) bool {
+	if err == context.DeadlineExceeded {
+		return true
+	}
+	if e, ok := err.(*pool.NoSuchElementErr); ok {
+		return e.Error() == "Pool exhausted" || e.Error() == "Timeout waiting for idle object"
+	}
+	return false
+}
+
+//isPoolClosed whether the borrow failed because the pool is destroyed
+func isPoolClosed(err error) bool {
+	if e, ok := err.(*pool.IllegalStateErr); ok {
+		return e.Error() == "Pool not open"
+	}
+	return false
+}
+
 func (p *Pool) returnBrokenResourceObject(resource *Redis) error {
 	if resource != nil {
 		return p.internalPool.InvalidateObject(p.ctx, resource)
@@ -90,14 +199,80 @@ func (p *Pool) returnResourceObject(resource *Redis) error {
 	return p.internalPool.ReturnObject(p.ctx, resource)
 }
 
-//Destroy destroy pool
+//Destroy destroy pool,the connections of the running subscriptions are closed too
 func (p *Pool) Destroy() {
 	p.internalPool.Close(p.ctx)
+	base := p.base
+	base.subMu.Lock()
+	defer base.subMu.Unlock()
+	for redis := range base.subscribers {
+		//the socket is closed instead of the connection,it is still used by the subscription
+		redis.client.closeSocket()
+	}
+}
+
+//NumSubscribers the count of running subscriptions,every one has a dedicated connection outside of the pool
+func (p *Pool) NumSubscribers() int {
+	base := p.base
+	base.subMu.Lock()
+	defer base.subMu.Unlock()
+	return len(base.subscribers)
+}
+
+//subscribe run the subscription fn with a dedicated connection,
+//it isn't borrowed from the pool,so a long-lived subscription doesn't starve the pool,
+//the connection is closed when the subscription ends
+func (p *Pool) subscribe(fn func(redis *Redis) error) error {
+	base := p.base
+	if atomic.LoadInt32(&base.draining) == 1 {
+		return ErrClosed
+	}
+	redis := NewRedis(p.option)
+	defer redis.Close()
+	if err := redis.Connect(); err != nil {
+		return err
+	}
+	base.subMu.Lock()
+	base.subscribers[redis] = struct{}{}
+	base.subMu.Unlock()
+	defer func() {
+		base.subMu.Lock()
+		delete(base.subscribers, redis)
+		base.subMu.Unlock()
+	}()
+	return fn(redis)
+}
+
+//Drain stop handing out redis instances,GetResource returns ErrClosed afterwards,
+//then wait until all the instances in use are returned or ctx is done,
+//return the error of ctx if some instances are still in use
+func (p *Pool) Drain(ctx context.Context) error {
+	atomic.StoreInt32(&p.base.draining, 1)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for p.internalPool.GetNumActive() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+//Shutdown drain the pool and then destroy it,
+//the pool is destroyed even if ctx is done before all the instances are returned,
+//the instances returned later are closed
+func (p *Pool) Shutdown(ctx context.Context) error {
+	err := p.Drain(ctx)
+	p.Destroy()
+	return err
 }
 
 //Factory redis pool factory
 type factory struct {
-	option *Option
+	option      *Option
+	maxLifetime time.Duration //retire the connections older than it,0 means no limit
 }
 
 //NewFactory create new redis pool factory
@@ -113,6 +288,9 @@ func (f factory) MakeObject(ctx context.Context) (*pool.PooledObject, error) {
 			redis.Close()
 		}
 	}()
+	if f.option.LazyConnect {
+		return pool.NewPooledObject(redis), nil
+	}
 	err := redis.Connect()
 	if err != nil {
 		return nil, err
@@ -123,6 +301,11 @@ func (f factory) MakeObject(ctx context.Context) (*pool.PooledObject, error) {
 //DestroyObject destroy object of pool
 func (f factory) DestroyObject(ctx context.Context, object *pool.PooledObject) error {
 	redis := object.Object.(*Redis)
+	defer redis.client.connection.release()
+	//a lazy connection never used has nothing to quit,QUIT would dial it
+	if !redis.client.connection.isConnected() {
+		return nil
+	}
 	_, err := redis.Quit()
 	if err != nil {
 		return err
@@ -146,21 +329,39 @@ func (f factory) ValidateObject(ctx context.Context, object *pool.PooledObject)
 	return reply == "PONG"
 }
 
-//ActivateObject active object
+//expired whether the connection is older than the max lifetime,
+//such as to be dropped silently by a load balancer or proxy in front of redis
+func (f factory) expired(object *pool.PooledObject) bool {
+	return f.maxLifetime > 0 && time.Since(object.CreateTime) >= f.maxLifetime
+}
+
+//ActivateObject active object,an expired idle object is destroyed by the pool and another one is borrowed instead
 func (f factory) ActivateObject(ctx context.Context, object *pool.PooledObject) error {
-	redis := object.Object.(*Redis)
-	if redis.client.Db == f.option.Db {
+	if f.expired(object) {
+		return errConnExpired
+	}
+	return selectPoolDb(object.Object.(*Redis), f.option.Db)
+}
+
+//selectPoolDb make sure the connection is on db,a connection not dialed yet selects it when it connects
+func selectPoolDb(redis *Redis, db int) error {
+	if redis.client.Db == db {
 		return nil
 	}
-	_, err := redis.Select(f.option.Db)
-	if err != nil {
-		return err
+	if !redis.client.isConnected() {
+		redis.client.Db = db
+		return nil
 	}
-	return nil
+	_, err := redis.Select(db)
+	return err
 }
 
-//PassivateObject passivate object
+//PassivateObject clear the state left by the borrower,
+//the object is destroyed by the pool if its state can't be cleared or it exceeds the max lifetime
 func (f factory) PassivateObject(ctx context.Context, object *pool.PooledObject) error {
-	//todo how to passivate redis object
-	return nil
+	if f.expired(object) {
+		return errConnExpired
+	}
+	redis := object.Object.(*Redis)
+	return redis.resetState(f.option.Db)
 }
@@ -1,7 +1,11 @@
 package godis
 
 import (
+	"context"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"net"
+	"sync"
 	"testing"
 	"time"
 )
@@ -90,3 +94,285 @@ func TestPool_Basic3(t *testing.T) {
 	_, e := pool.GetResource()
 	assert.NotNil(t, e) //auth error
 }
+
+func TestPool_GetResourceTimeout(t *testing.T) {
+	pool := NewPool(&PoolConfig{
+		MaxTotal:      1,
+		MaxWaitMillis: 100,
+	}, option)
+	defer pool.Destroy()
+	redis, e := pool.GetResource()
+	assert.Nil(t, e)
+
+	start := time.Now()
+	_, e = pool.GetResource()
+	assert.Equal(t, ErrPoolExhausted, e)
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, e = pool.GetResourceContext(ctx)
+	assert.Equal(t, ErrPoolExhausted, e)
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, e = pool.GetResourceContext(ctx)
+	assert.Equal(t, context.Canceled, e)
+
+	redis.Close()
+	redis, e = pool.GetResourceContext(context.Background())
+	assert.Nil(t, e)
+	s, e := redis.Echo("godis")
+	assert.Nil(t, e)
+	assert.Equal(t, "godis", s)
+	redis.Close()
+}
+
+func TestPool_MaxConnLifetime(t *testing.T) {
+	pool := NewPool(&PoolConfig{
+		MaxTotal:        1,
+		MaxConnLifetime: 200 * time.Millisecond,
+	}, option)
+	defer pool.Destroy()
+	redis, e := pool.GetResource()
+	assert.Nil(t, e)
+	redis.Close()
+	redis1, e := pool.GetResource()
+	assert.Nil(t, e)
+	assert.True(t, redis == redis1)
+	redis1.Close()
+
+	//the idle connection is retired when borrowed
+	time.Sleep(250 * time.Millisecond)
+	redis2, e := pool.GetResource()
+	assert.Nil(t, e)
+	assert.False(t, redis == redis2)
+	assert.Equal(t, 1, pool.internalPool.GetDestroyedCount())
+	s, e := redis2.Echo("godis")
+	assert.Nil(t, e)
+	assert.Equal(t, "godis", s)
+
+	//the borrowed connection is retired when returned
+	time.Sleep(250 * time.Millisecond)
+	redis2.Close()
+	assert.Equal(t, 0, pool.internalPool.GetNumIdle())
+	assert.Equal(t, 2, pool.internalPool.GetDestroyedCount())
+}
+
+func TestPool_Shutdown(t *testing.T) {
+	pool := NewPool(&PoolConfig{MaxTotal: 2}, option)
+	redis, e := pool.GetResource()
+	assert.Nil(t, e)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s, e := redis.Echo("godis")
+		assert.Nil(t, e)
+		assert.Equal(t, "godis", s)
+		redis.Close()
+	}()
+	start := time.Now()
+	e = pool.Shutdown(context.Background())
+	assert.Nil(t, e)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	assert.True(t, pool.internalPool.IsClosed())
+	_, e = pool.GetResource()
+	assert.Equal(t, ErrClosed, e)
+
+	pool = NewPool(&PoolConfig{MaxTotal: 2}, option)
+	redis, e = pool.GetResource()
+	assert.Nil(t, e)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	e = pool.Drain(ctx)
+	assert.Equal(t, context.DeadlineExceeded, e)
+	assert.False(t, pool.internalPool.IsClosed())
+	_, e = pool.GetResource()
+	assert.Equal(t, ErrClosed, e)
+	e = pool.Shutdown(ctx)
+	assert.Equal(t, context.DeadlineExceeded, e)
+	assert.True(t, pool.internalPool.IsClosed())
+	assert.Nil(t, redis.Close())
+}
+
+func TestPool_ResetState(t *testing.T) {
+	flushAll()
+	pool := NewPool(&PoolConfig{MaxTotal: 1}, option)
+	defer pool.Destroy()
+
+	redis, e := pool.GetResource()
+	assert.Nil(t, e)
+	_, e = redis.Multi()
+	assert.Nil(t, e)
+	redis.Close()
+	redis, e = pool.GetResource()
+	assert.Nil(t, e)
+	assert.False(t, redis.client.isInMulti)
+	s, e := redis.Set("godis", "good")
+	assert.Nil(t, e)
+	assert.Equal(t, "OK", s)
+
+	_, e = redis.Watch("godis")
+	assert.Nil(t, e)
+	assert.True(t, redis.client.isInWatch)
+	redis.Close()
+	redis, e = pool.GetResource()
+	assert.Nil(t, e)
+	assert.False(t, redis.client.isInWatch)
+
+	p := redis.Pipelined()
+	_, e = p.Exists("godis")
+	assert.Nil(t, e)
+	redis.Close()
+	redis, e = pool.GetResource()
+	assert.Nil(t, e)
+	assert.Equal(t, 0, redis.client.pipelinedCommands)
+	s, e = redis.Get("godis")
+	assert.Nil(t, e)
+	assert.Equal(t, "good", s)
+
+	_, e = redis.Select(2)
+	assert.Nil(t, e)
+	redis.Close()
+	redis, e = pool.GetResource()
+	assert.Nil(t, e)
+	assert.Equal(t, 0, redis.client.Db)
+	s, e = redis.Get("godis")
+	assert.Nil(t, e)
+	assert.Equal(t, "good", s)
+	redis.Close()
+}
+
+func TestPool_WithDb(t *testing.T) {
+	flushAll()
+	pool := NewPool(&PoolConfig{MaxTotal: 1}, option)
+	defer pool.Destroy()
+	sessions := pool.WithDb(1)
+	assert.Equal(t, 1, sessions.Db())
+	assert.Equal(t, 0, pool.Db())
+
+	//the views share the only connection of the pool
+	redis, e := sessions.GetResource()
+	assert.Nil(t, e)
+	redis.Set("godis", "db1")
+	redis.Close()
+	redis, e = pool.GetResource()
+	assert.Nil(t, e)
+	assert.Equal(t, 0, redis.client.Db)
+	s, _ := redis.Get("godis")
+	assert.Equal(t, "", s)
+	redis.Set("godis", "db0")
+	redis.Close()
+	assert.Equal(t, 0, pool.internalPool.GetDestroyedCount())
+
+	pooled := NewPooledRedis(option, &PoolConfig{MaxTotal: 1}).WithDb(2)
+	defer pooled.Close()
+	pooled.Set("godis", "db2")
+	for db, want := range []string{"db0", "db1", "db2"} {
+		redis, e = pool.WithDb(db).GetResource()
+		assert.Nil(t, e)
+		s, _ = redis.Get("godis")
+		assert.Equal(t, want, s)
+		redis.Close()
+	}
+
+	pool.WithDb(1).Destroy()
+	_, e = pool.GetResource()
+	assert.NotNil(t, e)
+}
+
+func TestPool_Subscribe(t *testing.T) {
+	pooled := NewPooledRedis(option, &PoolConfig{MaxTotal: 1, MaxWaitMillis: 100})
+	pool := pooled.Pool()
+	defer pool.Destroy()
+	messages := make(chan string, 1)
+	pubsub := &RedisPubSub{
+		OnMessage: func(channel, message string) {
+			messages <- message
+		},
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- pooled.Subscribe(pubsub, "godis")
+	}()
+	for i := 0; i < 50 && pool.NumSubscribers() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, pool.NumSubscribers())
+	time.Sleep(50 * time.Millisecond)
+	//the only connection of the pool is still available
+	c, err := pooled.Publish("godis", "good")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	assert.Equal(t, "good", <-messages)
+	assert.Nil(t, pubsub.UnSubscribe("godis"))
+	assert.Nil(t, <-done)
+	assert.Equal(t, 0, pool.NumSubscribers())
+
+	//a borrowed redis subscribes by a dedicated connection too
+	redis, err := pool.GetResource()
+	assert.Nil(t, err)
+	go func() {
+		done <- redis.PSubscribe(pubsub, "god*")
+	}()
+	for i := 0; i < 50 && pool.NumSubscribers() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, pool.NumSubscribers())
+	s, err := redis.Echo("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "godis", s)
+	redis.Close()
+	//the subscription ends when the pool is destroyed
+	pool.Destroy()
+	assert.NotNil(t, <-done)
+	assert.Equal(t, 0, pool.NumSubscribers())
+}
+
+func TestPool_DestroySubscribers(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SUBSCRIBE godis": {"*3\r\n$9\r\nsubscribe\r\n$5\r\ngodis\r\n:1\r\n"},
+	})
+	defer closeServer()
+	fakeOption.SoTimeout = 5 * time.Second
+	pooled := NewPooledRedis(fakeOption, &PoolConfig{MaxTotal: 1})
+	pool := pooled.Pool()
+	done := make(chan error, 1)
+	go func() {
+		done <- pooled.Subscribe(&RedisPubSub{}, "godis")
+	}()
+	for i := 0; i < 50 && pool.NumSubscribers() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, pool.NumSubscribers())
+	//a subscriber whose socket is gone,such as after a failed dial,doesn't panic the destroy
+	pool.subMu.Lock()
+	pool.subscribers[NewRedis(fakeOption)] = struct{}{}
+	pool.subMu.Unlock()
+	pool.Destroy()
+	select {
+	case err := <-done:
+		assert.NotNil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the subscription is still running after the pool is destroyed")
+	}
+}
+
+func TestPool_DestroyLazy(t *testing.T) {
+	var mu sync.Mutex
+	dials := 0
+	lazyOption := &Option{Host: "localhost", Port: 6379, LazyConnect: true,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			mu.Lock()
+			dials++
+			mu.Unlock()
+			return nil, errors.New("proxy refused")
+		}}
+	//the idle connection prepared by the pool is never used,destroying it doesn't dial to send QUIT
+	pool := NewPool(&PoolConfig{MinIdle: 1}, lazyOption)
+	pool.Destroy()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 0, dials)
+}
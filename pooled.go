@@ -0,0 +1,2020 @@
+package godis
+
+import "time"
+
+//PooledRedis redis client borrowing a connection from the pool for every command,
+//unlike Redis,it is safe for concurrent use,so it can be shared like RedisCluster
+type PooledRedis struct {
+	pool *Pool
+}
+
+//NewPooledRedis create new pooled redis
+func NewPooledRedis(option *Option, poolConfig *PoolConfig) *PooledRedis {
+	return &PooledRedis{pool: NewPool(poolConfig, option)}
+}
+
+//Pool the pool of the connections
+func (p *PooledRedis) Pool() *Pool {
+	return p.pool
+}
+
+//WithDb the pooled redis on db,it shares the connections of p,see Pool.WithDb
+func (p *PooledRedis) WithDb(db int) *PooledRedis {
+	return &PooledRedis{pool: p.pool.WithDb(db)}
+}
+
+//Close destroy the pool
+func (p *PooledRedis) Close() {
+	p.pool.Destroy()
+}
+
+//WithTimeout run fn with a connection borrowed from the pool and the command timeout,see comment in redis.go
+func (p *PooledRedis) WithTimeout(timeout time.Duration, fn func(redis *Redis) error) error {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return redis.WithTimeout(timeout, fn)
+}
+
+//Del  see comment in redis.go
+func (p *PooledRedis) Del(keys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Del(keys...)
+}
+
+//Exists  see comment in redis.go
+func (p *PooledRedis) Exists(keys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Exists(keys...)
+}
+
+//Expire  see comment in redis.go
+func (p *PooledRedis) Expire(key string, seconds int) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Expire(key, seconds)
+}
+
+//ExpireAt  see comment in redis.go
+func (p *PooledRedis) ExpireAt(key string, unixTimeSeconds int64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ExpireAt(key, unixTimeSeconds)
+}
+
+//PExpire  see comment in redis.go
+func (p *PooledRedis) PExpire(key string, milliseconds int64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.PExpire(key, milliseconds)
+}
+
+//PExpireAt  see comment in redis.go
+func (p *PooledRedis) PExpireAt(key string, millisecondsTimestamp int64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.PExpireAt(key, millisecondsTimestamp)
+}
+
+//Persist  see comment in redis.go
+func (p *PooledRedis) Persist(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Persist(key)
+}
+
+//TTL  see comment in redis.go
+func (p *PooledRedis) TTL(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.TTL(key)
+}
+
+//PTTL  see comment in redis.go
+func (p *PooledRedis) PTTL(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.PTTL(key)
+}
+
+//Type  see comment in redis.go
+func (p *PooledRedis) Type(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.Type(key)
+}
+
+//Rename  see comment in redis.go
+func (p *PooledRedis) Rename(oldKey, newKey string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.Rename(oldKey, newKey)
+}
+
+//RenameNx  see comment in redis.go
+func (p *PooledRedis) RenameNx(oldKey, newKey string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.RenameNx(oldKey, newKey)
+}
+
+//Dump  see comment in redis.go
+func (p *PooledRedis) Dump(key string) ([]byte, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.Dump(key)
+}
+
+//Restore  see comment in redis.go
+func (p *PooledRedis) Restore(key string, ttl int, serializedValue []byte) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.Restore(key, ttl, serializedValue)
+}
+
+//RestoreReplace  see comment in redis.go
+func (p *PooledRedis) RestoreReplace(key string, ttl int, serializedValue []byte) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.RestoreReplace(key, ttl, serializedValue)
+}
+
+//Scan  see comment in redis.go
+func (p *PooledRedis) Scan(cursor string, params ...*ScanParams) (*ScanResult, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.Scan(cursor, params...)
+}
+
+//Sort  see comment in redis.go
+func (p *PooledRedis) Sort(key string, params ...*SortParams) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.Sort(key, params...)
+}
+
+//SortStore  see comment in redis.go
+func (p *PooledRedis) SortStore(srcKey, destKey string, params ...*SortParams) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SortStore(srcKey, destKey, params...)
+}
+
+//Append  see comment in redis.go
+func (p *PooledRedis) Append(key, value string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Append(key, value)
+}
+
+//Decr  see comment in redis.go
+func (p *PooledRedis) Decr(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Decr(key)
+}
+
+//DecrBy  see comment in redis.go
+func (p *PooledRedis) DecrBy(key string, decrement int64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.DecrBy(key, decrement)
+}
+
+//Get  see comment in redis.go
+func (p *PooledRedis) Get(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.Get(key)
+}
+
+//GetRange  see comment in redis.go
+func (p *PooledRedis) GetRange(key string, start, end int64) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.GetRange(key, start, end)
+}
+
+//GetScan  see comment in redis.go
+func (p *PooledRedis) GetScan(key string, dest interface{}) error {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return redis.GetScan(key, dest)
+}
+
+//GetSet  see comment in redis.go
+func (p *PooledRedis) GetSet(key, value string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.GetSet(key, value)
+}
+
+//GetDel  see comment in redis.go
+func (p *PooledRedis) GetDel(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.GetDel(key)
+}
+
+//Incr  see comment in redis.go
+func (p *PooledRedis) Incr(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Incr(key)
+}
+
+//IncrBy  see comment in redis.go
+func (p *PooledRedis) IncrBy(key string, increment int64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.IncrBy(key, increment)
+}
+
+//IncrByFloat  see comment in redis.go
+func (p *PooledRedis) IncrByFloat(key string, increment float64) (float64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.IncrByFloat(key, increment)
+}
+
+//MGet  see comment in redis.go
+func (p *PooledRedis) MGet(keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.MGet(keys...)
+}
+
+//MSet  see comment in redis.go
+func (p *PooledRedis) MSet(kvs ...string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.MSet(kvs...)
+}
+
+//MSetNx  see comment in redis.go
+func (p *PooledRedis) MSetNx(kvs ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.MSetNx(kvs...)
+}
+
+//PSetEx  see comment in redis.go
+func (p *PooledRedis) PSetEx(key string, milliseconds int64, value string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.PSetEx(key, milliseconds, value)
+}
+
+//Set  see comment in redis.go
+func (p *PooledRedis) Set(key, value string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.Set(key, value)
+}
+
+//SetEx  see comment in redis.go
+func (p *PooledRedis) SetEx(key string, seconds int, value string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.SetEx(key, seconds, value)
+}
+
+//SetNx  see comment in redis.go
+func (p *PooledRedis) SetNx(key, value string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SetNx(key, value)
+}
+
+//SetRange  see comment in redis.go
+func (p *PooledRedis) SetRange(key string, offset int64, value string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SetRange(key, offset, value)
+}
+
+//SetWithParams  see comment in redis.go
+func (p *PooledRedis) SetWithParams(key, value, nxxx string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.SetWithParams(key, value, nxxx)
+}
+
+//SetWithParamsAndTime  see comment in redis.go
+func (p *PooledRedis) SetWithParamsAndTime(key, value, nxxx, expx string, time int64) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.SetWithParamsAndTime(key, value, nxxx, expx, time)
+}
+
+//StrLen  see comment in redis.go
+func (p *PooledRedis) StrLen(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.StrLen(key)
+}
+
+//SubStr  see comment in redis.go
+func (p *PooledRedis) SubStr(key string, start, end int) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.SubStr(key, start, end)
+}
+
+//BitCount  see comment in redis.go
+func (p *PooledRedis) BitCount(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.BitCount(key)
+}
+
+//BitCountRange  see comment in redis.go
+func (p *PooledRedis) BitCountRange(key string, start, end int64, unit ...*BitUnit) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.BitCountRange(key, start, end, unit...)
+}
+
+//BitField  see comment in redis.go
+func (p *PooledRedis) BitField(key string, arguments ...string) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BitField(key, arguments...)
+}
+
+//BitFieldWithArgs  see comment in redis.go
+func (p *PooledRedis) BitFieldWithArgs(key string, args *BitFieldArgs) ([]*int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BitFieldWithArgs(key, args)
+}
+
+//BitFieldRo  see comment in redis.go
+func (p *PooledRedis) BitFieldRo(key string, args *BitFieldArgs) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BitFieldRo(key, args)
+}
+
+//BitOp  see comment in redis.go
+func (p *PooledRedis) BitOp(op BitOP, destKey string, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.BitOp(op, destKey, srcKeys...)
+}
+
+//BitPos  see comment in redis.go
+func (p *PooledRedis) BitPos(key string, value bool, params ...*BitPosParams) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.BitPos(key, value, params...)
+}
+
+//GetBit  see comment in redis.go
+func (p *PooledRedis) GetBit(key string, offset int64) (bool, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return false, err
+	}
+	defer redis.Close()
+	return redis.GetBit(key, offset)
+}
+
+//SetBit  see comment in redis.go
+func (p *PooledRedis) SetBit(key string, offset int64, value string) (bool, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return false, err
+	}
+	defer redis.Close()
+	return redis.SetBit(key, offset, value)
+}
+
+//SetBitWithBool  see comment in redis.go
+func (p *PooledRedis) SetBitWithBool(key string, offset int64, value bool) (bool, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return false, err
+	}
+	defer redis.Close()
+	return redis.SetBitWithBool(key, offset, value)
+}
+
+//HDel  see comment in redis.go
+func (p *PooledRedis) HDel(key string, fields ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.HDel(key, fields...)
+}
+
+//HExists  see comment in redis.go
+func (p *PooledRedis) HExists(key, field string) (bool, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return false, err
+	}
+	defer redis.Close()
+	return redis.HExists(key, field)
+}
+
+//HExpire  see comment in redis.go
+func (p *PooledRedis) HExpire(key string, seconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HExpire(key, seconds, fields, condition...)
+}
+
+//HExpireAt  see comment in redis.go
+func (p *PooledRedis) HExpireAt(key string, unixTime int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HExpireAt(key, unixTime, fields, condition...)
+}
+
+//HGet  see comment in redis.go
+func (p *PooledRedis) HGet(key, field string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.HGet(key, field)
+}
+
+//HGetAll  see comment in redis.go
+func (p *PooledRedis) HGetAll(key string) (map[string]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HGetAll(key)
+}
+
+//HGetAllScan  see comment in redis.go
+func (p *PooledRedis) HGetAllScan(key string, dest interface{}) error {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return redis.HGetAllScan(key, dest)
+}
+
+//HGetScan  see comment in redis.go
+func (p *PooledRedis) HGetScan(key, field string, dest interface{}) error {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return redis.HGetScan(key, field, dest)
+}
+
+//HIncrBy  see comment in redis.go
+func (p *PooledRedis) HIncrBy(key, field string, value int64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.HIncrBy(key, field, value)
+}
+
+//HIncrByFloat  see comment in redis.go
+func (p *PooledRedis) HIncrByFloat(key, field string, increment float64) (float64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.HIncrByFloat(key, field, increment)
+}
+
+//HKeys  see comment in redis.go
+func (p *PooledRedis) HKeys(key string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HKeys(key)
+}
+
+//HLen  see comment in redis.go
+func (p *PooledRedis) HLen(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.HLen(key)
+}
+
+//HMGet  see comment in redis.go
+func (p *PooledRedis) HMGet(key string, fields ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HMGet(key, fields...)
+}
+
+//HMGetScan  see comment in redis.go
+func (p *PooledRedis) HMGetScan(key string, dest interface{}, fields ...string) error {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return redis.HMGetScan(key, dest, fields...)
+}
+
+//HMSet  see comment in redis.go
+func (p *PooledRedis) HMSet(key string, hash map[string]string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.HMSet(key, hash)
+}
+
+//HPExpire  see comment in redis.go
+func (p *PooledRedis) HPExpire(key string, milliseconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HPExpire(key, milliseconds, fields, condition...)
+}
+
+//HPTtl  see comment in redis.go
+func (p *PooledRedis) HPTtl(key string, fields ...string) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HPTtl(key, fields...)
+}
+
+//HPersist  see comment in redis.go
+func (p *PooledRedis) HPersist(key string, fields ...string) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HPersist(key, fields...)
+}
+
+//HScan  see comment in redis.go
+func (p *PooledRedis) HScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HScan(key, cursor, params...)
+}
+
+//HSet  see comment in redis.go
+func (p *PooledRedis) HSet(key, field, value string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.HSet(key, field, value)
+}
+
+//HSetNx  see comment in redis.go
+func (p *PooledRedis) HSetNx(key, field, value string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.HSetNx(key, field, value)
+}
+
+//HTtl  see comment in redis.go
+func (p *PooledRedis) HTtl(key string, fields ...string) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HTtl(key, fields...)
+}
+
+//HVals  see comment in redis.go
+func (p *PooledRedis) HVals(key string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.HVals(key)
+}
+
+//BLPop  see comment in redis.go
+func (p *PooledRedis) BLPop(args ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BLPop(args...)
+}
+
+//BLPopTimeout  see comment in redis.go
+func (p *PooledRedis) BLPopTimeout(timeout int, keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BLPopTimeout(timeout, keys...)
+}
+
+//BRPop  see comment in redis.go
+func (p *PooledRedis) BRPop(args ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BRPop(args...)
+}
+
+//BRPopTimeout  see comment in redis.go
+func (p *PooledRedis) BRPopTimeout(timeout int, keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BRPopTimeout(timeout, keys...)
+}
+
+//BRPopLPush  see comment in redis.go
+func (p *PooledRedis) BRPopLPush(srcKey, destKey string, timeout int) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.BRPopLPush(srcKey, destKey, timeout)
+}
+
+//LIndex  see comment in redis.go
+func (p *PooledRedis) LIndex(key string, index int64) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.LIndex(key, index)
+}
+
+//LInsert  see comment in redis.go
+func (p *PooledRedis) LInsert(key string, where *ListOption, pivot, value string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.LInsert(key, where, pivot, value)
+}
+
+//LLen  see comment in redis.go
+func (p *PooledRedis) LLen(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.LLen(key)
+}
+
+//LPop  see comment in redis.go
+func (p *PooledRedis) LPop(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.LPop(key)
+}
+
+//LPos  see comment in redis.go
+func (p *PooledRedis) LPos(key, element string, params ...*LPosParams) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.LPos(key, element, params...)
+}
+
+//LPosCount  see comment in redis.go
+func (p *PooledRedis) LPosCount(key, element string, count int64, params ...*LPosParams) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.LPosCount(key, element, count, params...)
+}
+
+//LPush  see comment in redis.go
+func (p *PooledRedis) LPush(key string, members ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.LPush(key, members...)
+}
+
+//LPushX  see comment in redis.go
+func (p *PooledRedis) LPushX(key string, members ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.LPushX(key, members...)
+}
+
+//LRange  see comment in redis.go
+func (p *PooledRedis) LRange(key string, start, stop int64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.LRange(key, start, stop)
+}
+
+//LRangeScan  see comment in redis.go
+func (p *PooledRedis) LRangeScan(key string, start, stop int64, dest interface{}) error {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return redis.LRangeScan(key, start, stop, dest)
+}
+
+//LRem  see comment in redis.go
+func (p *PooledRedis) LRem(key string, count int64, value string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.LRem(key, count, value)
+}
+
+//LSet  see comment in redis.go
+func (p *PooledRedis) LSet(key string, index int64, value string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.LSet(key, index, value)
+}
+
+//LTrim  see comment in redis.go
+func (p *PooledRedis) LTrim(key string, start, stop int64) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.LTrim(key, start, stop)
+}
+
+//RPop  see comment in redis.go
+func (p *PooledRedis) RPop(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.RPop(key)
+}
+
+//LPopCount  see comment in redis.go
+func (p *PooledRedis) LPopCount(key string, count int64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.LPopCount(key, count)
+}
+
+//RPopCount  see comment in redis.go
+func (p *PooledRedis) RPopCount(key string, count int64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.RPopCount(key, count)
+}
+
+//RPopLPush  see comment in redis.go
+func (p *PooledRedis) RPopLPush(srcKey, destKey string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.RPopLPush(srcKey, destKey)
+}
+
+//RPush  see comment in redis.go
+func (p *PooledRedis) RPush(key string, members ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.RPush(key, members...)
+}
+
+//RPushX  see comment in redis.go
+func (p *PooledRedis) RPushX(key string, members ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.RPushX(key, members...)
+}
+
+//SAdd  see comment in redis.go
+func (p *PooledRedis) SAdd(key string, members ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SAdd(key, members...)
+}
+
+//SCard  see comment in redis.go
+func (p *PooledRedis) SCard(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SCard(key)
+}
+
+//SDiff  see comment in redis.go
+func (p *PooledRedis) SDiff(keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SDiff(keys...)
+}
+
+//SDiffStore  see comment in redis.go
+func (p *PooledRedis) SDiffStore(destKey string, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SDiffStore(destKey, srcKeys...)
+}
+
+//SInter  see comment in redis.go
+func (p *PooledRedis) SInter(keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SInter(keys...)
+}
+
+//SInterStore  see comment in redis.go
+func (p *PooledRedis) SInterStore(destKey string, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SInterStore(destKey, srcKeys...)
+}
+
+//SInterCard  see comment in redis.go
+func (p *PooledRedis) SInterCard(limit int64, keys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SInterCard(limit, keys...)
+}
+
+//EstimateIntersection  see comment in redis.go
+func (p *PooledRedis) EstimateIntersection(keys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.EstimateIntersection(keys...)
+}
+
+//SIsMember  see comment in redis.go
+func (p *PooledRedis) SIsMember(key, member string) (bool, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return false, err
+	}
+	defer redis.Close()
+	return redis.SIsMember(key, member)
+}
+
+//SMembers  see comment in redis.go
+func (p *PooledRedis) SMembers(key string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SMembers(key)
+}
+
+//SMembersMap  see comment in redis.go
+func (p *PooledRedis) SMembersMap(key string) (map[string]struct{}, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SMembersMap(key)
+}
+
+//SMembersScan  see comment in redis.go
+func (p *PooledRedis) SMembersScan(key string, dest interface{}) error {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return redis.SMembersScan(key, dest)
+}
+
+//SMove  see comment in redis.go
+func (p *PooledRedis) SMove(srcKey, destKey, member string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SMove(srcKey, destKey, member)
+}
+
+//SPop  see comment in redis.go
+func (p *PooledRedis) SPop(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.SPop(key)
+}
+
+//SPopBatch  see comment in redis.go
+func (p *PooledRedis) SPopBatch(key string, count int64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SPopBatch(key, count)
+}
+
+//SRandMember  see comment in redis.go
+func (p *PooledRedis) SRandMember(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.SRandMember(key)
+}
+
+//SRandMemberBatch  see comment in redis.go
+func (p *PooledRedis) SRandMemberBatch(key string, count int) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SRandMemberBatch(key, count)
+}
+
+//SRem  see comment in redis.go
+func (p *PooledRedis) SRem(key string, members ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SRem(key, members...)
+}
+
+//SScan  see comment in redis.go
+func (p *PooledRedis) SScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SScan(key, cursor, params...)
+}
+
+//SUnion  see comment in redis.go
+func (p *PooledRedis) SUnion(keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.SUnion(keys...)
+}
+
+//SUnionStore  see comment in redis.go
+func (p *PooledRedis) SUnionStore(destKey string, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SUnionStore(destKey, srcKeys...)
+}
+
+//BZPopMax  see comment in redis.go
+func (p *PooledRedis) BZPopMax(timeout int, keys ...string) (*KeyedTuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BZPopMax(timeout, keys...)
+}
+
+//BZPopMin  see comment in redis.go
+func (p *PooledRedis) BZPopMin(timeout int, keys ...string) (*KeyedTuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BZPopMin(timeout, keys...)
+}
+
+//BLMPop  see comment in redis.go
+func (p *PooledRedis) BLMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedElements, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BLMPop(timeout, direction, count, keys...)
+}
+
+//BZMPop  see comment in redis.go
+func (p *PooledRedis) BZMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedTuples, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.BZMPop(timeout, direction, count, keys...)
+}
+
+//ZAdd  see comment in redis.go
+func (p *PooledRedis) ZAdd(key string, score float64, member string, params ...*ZAddParams) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZAdd(key, score, member, params...)
+}
+
+//ZAddByMap  see comment in redis.go
+func (p *PooledRedis) ZAddByMap(key string, scoreMembers map[string]float64, params ...*ZAddParams) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZAddByMap(key, scoreMembers, params...)
+}
+
+//ZCard  see comment in redis.go
+func (p *PooledRedis) ZCard(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZCard(key)
+}
+
+//ZCount  see comment in redis.go
+func (p *PooledRedis) ZCount(key string, min, max float64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZCount(key, min, max)
+}
+
+//ZDiff  see comment in redis.go
+func (p *PooledRedis) ZDiff(keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZDiff(keys...)
+}
+
+//ZDiffStore  see comment in redis.go
+func (p *PooledRedis) ZDiffStore(destKey string, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZDiffStore(destKey, srcKeys...)
+}
+
+//ZDiffWithScores  see comment in redis.go
+func (p *PooledRedis) ZDiffWithScores(keys ...string) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZDiffWithScores(keys...)
+}
+
+//ZIncrBy  see comment in redis.go
+func (p *PooledRedis) ZIncrBy(key string, increment float64, member string, params ...*ZAddParams) (float64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZIncrBy(key, increment, member, params...)
+}
+
+//ZInter  see comment in redis.go
+func (p *PooledRedis) ZInter(params *ZParams, keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZInter(params, keys...)
+}
+
+//ZInterCard  see comment in redis.go
+func (p *PooledRedis) ZInterCard(limit int64, keys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZInterCard(limit, keys...)
+}
+
+//ZInterStore  see comment in redis.go
+func (p *PooledRedis) ZInterStore(destKey string, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZInterStore(destKey, srcKeys...)
+}
+
+//ZInterStoreWithParams  see comment in redis.go
+func (p *PooledRedis) ZInterStoreWithParams(destKey string, params *ZParams, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZInterStoreWithParams(destKey, params, srcKeys...)
+}
+
+//ZInterWithScores  see comment in redis.go
+func (p *PooledRedis) ZInterWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZInterWithScores(params, keys...)
+}
+
+//ZLexCount  see comment in redis.go
+func (p *PooledRedis) ZLexCount(key, min, max string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZLexCount(key, min, max)
+}
+
+//ZPopMax  see comment in redis.go
+func (p *PooledRedis) ZPopMax(key string, count ...int64) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZPopMax(key, count...)
+}
+
+//ZPopMin  see comment in redis.go
+func (p *PooledRedis) ZPopMin(key string, count ...int64) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZPopMin(key, count...)
+}
+
+//ZRange  see comment in redis.go
+func (p *PooledRedis) ZRange(key string, start, stop int64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRange(key, start, stop)
+}
+
+//ZRangeByLex  see comment in redis.go
+func (p *PooledRedis) ZRangeByLex(key, min, max string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRangeByLex(key, min, max)
+}
+
+//ZRangeByLexBatch  see comment in redis.go
+func (p *PooledRedis) ZRangeByLexBatch(key, min, max string, offset, count int) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRangeByLexBatch(key, min, max, offset, count)
+}
+
+//ZRangeByScore  see comment in redis.go
+func (p *PooledRedis) ZRangeByScore(key string, min, max float64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRangeByScore(key, min, max)
+}
+
+//ZRangeByScoreBatch  see comment in redis.go
+func (p *PooledRedis) ZRangeByScoreBatch(key string, min, max float64, offset, count int) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRangeByScoreBatch(key, min, max, offset, count)
+}
+
+//ZRangeByScoreWithScores  see comment in redis.go
+func (p *PooledRedis) ZRangeByScoreWithScores(key string, min, max float64) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRangeByScoreWithScores(key, min, max)
+}
+
+//ZRangeByScoreWithScoresBatch  see comment in redis.go
+func (p *PooledRedis) ZRangeByScoreWithScoresBatch(key string, min, max float64, offset, count int) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRangeByScoreWithScoresBatch(key, min, max, offset, count)
+}
+
+//ZRangeWithScores  see comment in redis.go
+func (p *PooledRedis) ZRangeWithScores(key string, start, end int64) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRangeWithScores(key, start, end)
+}
+
+//ZRank  see comment in redis.go
+func (p *PooledRedis) ZRank(key, member string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZRank(key, member)
+}
+
+//ZRem  see comment in redis.go
+func (p *PooledRedis) ZRem(key string, members ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZRem(key, members...)
+}
+
+//ZRemRangeByLex  see comment in redis.go
+func (p *PooledRedis) ZRemRangeByLex(key, min, max string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZRemRangeByLex(key, min, max)
+}
+
+//ZRemRangeByRank  see comment in redis.go
+func (p *PooledRedis) ZRemRangeByRank(key string, start, stop int64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZRemRangeByRank(key, start, stop)
+}
+
+//ZRemRangeByScore  see comment in redis.go
+func (p *PooledRedis) ZRemRangeByScore(key string, min, max float64) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZRemRangeByScore(key, min, max)
+}
+
+//ZRevRange  see comment in redis.go
+func (p *PooledRedis) ZRevRange(key string, start, stop int64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRevRange(key, start, stop)
+}
+
+//ZRevRangeByLex  see comment in redis.go
+func (p *PooledRedis) ZRevRangeByLex(key, max, min string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRevRangeByLex(key, max, min)
+}
+
+//ZRevRangeByLexBatch  see comment in redis.go
+func (p *PooledRedis) ZRevRangeByLexBatch(key, max, min string, offset, count int) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRevRangeByLexBatch(key, max, min, offset, count)
+}
+
+//ZRevRangeByScore  see comment in redis.go
+func (p *PooledRedis) ZRevRangeByScore(key string, max, min float64) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRevRangeByScore(key, max, min)
+}
+
+//ZRevRangeByScoreWithScores  see comment in redis.go
+func (p *PooledRedis) ZRevRangeByScoreWithScores(key string, max, min float64) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRevRangeByScoreWithScores(key, max, min)
+}
+
+//ZRevRangeByScoreWithScoresBatch  see comment in redis.go
+func (p *PooledRedis) ZRevRangeByScoreWithScoresBatch(key string, max, min float64, offset, count int) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRevRangeByScoreWithScoresBatch(key, max, min, offset, count)
+}
+
+//ZRevRangeWithScores  see comment in redis.go
+func (p *PooledRedis) ZRevRangeWithScores(key string, start, end int64) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZRevRangeWithScores(key, start, end)
+}
+
+//ZRevRank  see comment in redis.go
+func (p *PooledRedis) ZRevRank(key, member string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZRevRank(key, member)
+}
+
+//ZScan  see comment in redis.go
+func (p *PooledRedis) ZScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZScan(key, cursor, params...)
+}
+
+//ZScore  see comment in redis.go
+func (p *PooledRedis) ZScore(key, member string) (float64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZScore(key, member)
+}
+
+//ZUnion  see comment in redis.go
+func (p *PooledRedis) ZUnion(params *ZParams, keys ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZUnion(params, keys...)
+}
+
+//ZUnionStore  see comment in redis.go
+func (p *PooledRedis) ZUnionStore(destKey string, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZUnionStore(destKey, srcKeys...)
+}
+
+//ZUnionStoreWithParams  see comment in redis.go
+func (p *PooledRedis) ZUnionStoreWithParams(destKey string, params *ZParams, srcKeys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.ZUnionStoreWithParams(destKey, params, srcKeys...)
+}
+
+//ZUnionWithScores  see comment in redis.go
+func (p *PooledRedis) ZUnionWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.ZUnionWithScores(params, keys...)
+}
+
+//GeoAdd  see comment in redis.go
+func (p *PooledRedis) GeoAdd(key string, longitude, latitude float64, member string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.GeoAdd(key, longitude, latitude, member)
+}
+
+//GeoAddByMap  see comment in redis.go
+func (p *PooledRedis) GeoAddByMap(key string, memberCoordinateMap map[string]GeoCoordinate) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.GeoAddByMap(key, memberCoordinateMap)
+}
+
+//GeoDist  see comment in redis.go
+func (p *PooledRedis) GeoDist(key, member1, member2 string, unit ...*GeoUnit) (float64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.GeoDist(key, member1, member2, unit...)
+}
+
+//GeoHash  see comment in redis.go
+func (p *PooledRedis) GeoHash(key string, members ...string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.GeoHash(key, members...)
+}
+
+//GeoPos  see comment in redis.go
+func (p *PooledRedis) GeoPos(key string, members ...string) ([]*GeoCoordinate, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.GeoPos(key, members...)
+}
+
+//GeoRadius  see comment in redis.go
+func (p *PooledRedis) GeoRadius(key string, longitude, latitude, radius float64, unit *GeoUnit, param ...*GeoRadiusParams) ([]GeoRadiusResponse, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.GeoRadius(key, longitude, latitude, radius, unit, param...)
+}
+
+//GeoRadiusByMember  see comment in redis.go
+func (p *PooledRedis) GeoRadiusByMember(key, member string, radius float64, unit *GeoUnit, param ...*GeoRadiusParams) ([]GeoRadiusResponse, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.GeoRadiusByMember(key, member, radius, unit, param...)
+}
+
+//PfAdd  see comment in redis.go
+func (p *PooledRedis) PfAdd(key string, elements ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.PfAdd(key, elements...)
+}
+
+//PfCount  see comment in redis.go
+func (p *PooledRedis) PfCount(keys ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.PfCount(keys...)
+}
+
+//PfMerge  see comment in redis.go
+func (p *PooledRedis) PfMerge(destKey string, srcKeys ...string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.PfMerge(destKey, srcKeys...)
+}
+
+//PfDebugGetReg  see comment in redis.go
+func (p *PooledRedis) PfDebugGetReg(key string) ([]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.PfDebugGetReg(key)
+}
+
+//PfDebugEncoding  see comment in redis.go
+func (p *PooledRedis) PfDebugEncoding(key string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.PfDebugEncoding(key)
+}
+
+//XAck  see comment in redis.go
+func (p *PooledRedis) XAck(key, group string, ids ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.XAck(key, group, ids...)
+}
+
+//XAdd  see comment in redis.go
+func (p *PooledRedis) XAdd(key, id string, hash map[string]string) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.XAdd(key, id, hash)
+}
+
+//XAutoClaim  see comment in redis.go
+func (p *PooledRedis) XAutoClaim(key, group, consumer string, minIdle time.Duration, start string, count int) (*StreamClaimResult, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.XAutoClaim(key, group, consumer, minIdle, start, count)
+}
+
+//XDel  see comment in redis.go
+func (p *PooledRedis) XDel(key string, ids ...string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.XDel(key, ids...)
+}
+
+//XGroupCreate  see comment in redis.go
+func (p *PooledRedis) XGroupCreate(key, group, id string, mkStream bool) (string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return "", err
+	}
+	defer redis.Close()
+	return redis.XGroupCreate(key, group, id, mkStream)
+}
+
+//XLen  see comment in redis.go
+func (p *PooledRedis) XLen(key string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.XLen(key)
+}
+
+//XPending  see comment in redis.go
+func (p *PooledRedis) XPending(key, group, start, end string, count int64, params ...*XPendingParams) ([]StreamPendingEntry, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.XPending(key, group, start, end, count, params...)
+}
+
+//XReadGroup  see comment in redis.go
+func (p *PooledRedis) XReadGroup(group, consumer string, count int, block time.Duration, streams ...string) ([]StreamEntries, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.XReadGroup(group, consumer, count, block, streams...)
+}
+
+//Eval  see comment in redis.go
+func (p *PooledRedis) Eval(script string, keyCount int, params ...string) (interface{}, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.Eval(script, keyCount, params...)
+}
+
+//EvalSha  see comment in redis.go
+func (p *PooledRedis) EvalSha(sha1 string, keyCount int, params ...string) (interface{}, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.EvalSha(sha1, keyCount, params...)
+}
+
+//EvalTimeout  see comment in redis.go
+func (p *PooledRedis) EvalTimeout(timeout time.Duration, script string, keyCount int, params ...string) (interface{}, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.EvalTimeout(timeout, script, keyCount, params...)
+}
+
+//EvalShaTimeout  see comment in redis.go
+func (p *PooledRedis) EvalShaTimeout(timeout time.Duration, sha1 string, keyCount int, params ...string) (interface{}, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.EvalShaTimeout(timeout, sha1, keyCount, params...)
+}
+
+//EvalRo  see comment in redis.go
+func (p *PooledRedis) EvalRo(script string, keyCount int, params ...string) (interface{}, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.EvalRo(script, keyCount, params...)
+}
+
+//EvalShaRo  see comment in redis.go
+func (p *PooledRedis) EvalShaRo(sha1 string, keyCount int, params ...string) (interface{}, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.EvalShaRo(sha1, keyCount, params...)
+}
+
+//Publish  see comment in redis.go
+func (p *PooledRedis) Publish(channel, message string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.Publish(channel, message)
+}
+
+//Subscribe  see comment in redis.go
+func (p *PooledRedis) Subscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	return p.pool.subscribe(func(redis *Redis) error {
+		return redis.Subscribe(redisPubSub, channels...)
+	})
+}
+
+//PSubscribe  see comment in redis.go
+func (p *PooledRedis) PSubscribe(redisPubSub *RedisPubSub, patterns ...string) error {
+	return p.pool.subscribe(func(redis *Redis) error {
+		return redis.PSubscribe(redisPubSub, patterns...)
+	})
+}
+
+//SPublish  see comment in redis.go
+func (p *PooledRedis) SPublish(channel, message string) (int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	return redis.SPublish(channel, message)
+}
+
+//SSubscribe  see comment in redis.go
+func (p *PooledRedis) SSubscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	return p.pool.subscribe(func(redis *Redis) error {
+		return redis.SSubscribe(redisPubSub, channels...)
+	})
+}
+
+//PubSubShardChannels  see comment in redis.go
+func (p *PooledRedis) PubSubShardChannels(pattern string) ([]string, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.PubSubShardChannels(pattern)
+}
+
+//PubSubShardNumSub  see comment in redis.go
+func (p *PooledRedis) PubSubShardNumSub(channels ...string) (map[string]int64, error) {
+	redis, err := p.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.PubSubShardNumSub(channels...)
+}
@@ -0,0 +1,1179 @@
+package godis
+
+import (
+	"strings"
+	"time"
+)
+
+//PrefixedClient prepend a namespace to every key,so multiple applications can share one redis database.
+//
+//the keys of multi-key commands,rename and store destinations,the keys of Eval,
+//the BY and GET patterns of Sort and the stream keys of XReadGroup are prefixed,
+//the keys in replies,such as of Scan,BLPop and BZPopMin,are returned without the prefix.
+//Scan only returns the keys of the namespace,its MATCH pattern is matched against the keys without the prefix.
+//channels aren't keys,they are not prefixed.
+//
+//the prefix must not contain a hash tag in cluster mode,the keys with the same hash tag stay in the same slot.
+//
+//	app := redis.WithPrefix("app1:")
+//	app.Set("user:1", "godis") //SET app1:user:1 godis
+type PrefixedClient struct {
+	client UniversalClient
+	prefix string
+}
+
+var _ UniversalClient = (*PrefixedClient)(nil)
+
+//NewPrefixedClient create new client prepending prefix to every key of client
+func NewPrefixedClient(client UniversalClient, prefix string) *PrefixedClient {
+	return &PrefixedClient{client: client, prefix: prefix}
+}
+
+//WithPrefix return a client prepending prefix to every key,see PrefixedClient
+func (r *Redis) WithPrefix(prefix string) *PrefixedClient {
+	return NewPrefixedClient(r, prefix)
+}
+
+//WithPrefix  see comment in prefix.go
+func (r *RedisCluster) WithPrefix(prefix string) *PrefixedClient {
+	return NewPrefixedClient(r, prefix)
+}
+
+//WithPrefix return a client of the nested namespace,prefix is appended to the prefix of p
+func (p *PrefixedClient) WithPrefix(prefix string) *PrefixedClient {
+	return NewPrefixedClient(p.client, p.prefix+prefix)
+}
+
+//Prefix the prefix of the keys
+func (p *PrefixedClient) Prefix() string {
+	return p.prefix
+}
+
+func (p *PrefixedClient) key(key string) string {
+	return p.prefix + key
+}
+
+func (p *PrefixedClient) unkey(key string) string {
+	return strings.TrimPrefix(key, p.prefix)
+}
+
+func (p *PrefixedClient) keys(keys []string) []string {
+	arr := make([]string, 0, len(keys))
+	for _, key := range keys {
+		arr = append(arr, p.key(key))
+	}
+	return arr
+}
+
+//kvs prefix the keys of key/value pairs
+func (p *PrefixedClient) kvs(kvs []string) []string {
+	arr := make([]string, len(kvs))
+	for i, s := range kvs {
+		if i%2 == 0 {
+			s = p.key(s)
+		}
+		arr[i] = s
+	}
+	return arr
+}
+
+//scriptParams prefix the first keyCount params,the keys of the script
+func (p *PrefixedClient) scriptParams(keyCount int, params []string) []string {
+	arr := make([]string, len(params))
+	copy(arr, params)
+	for i := 0; i < keyCount && i < len(arr); i++ {
+		arr[i] = p.key(arr[i])
+	}
+	return arr
+}
+
+func (p *PrefixedClient) sortParams(params []*SortParams) []*SortParams {
+	arr := make([]*SortParams, 0, len(params))
+	for _, param := range params {
+		arr = append(arr, param.withPrefix(p.prefix))
+	}
+	return arr
+}
+
+//popReply remove the prefix of the key popped by BLPOP or BRPOP
+func (p *PrefixedClient) popReply(reply []string, err error) ([]string, error) {
+	if len(reply) > 0 {
+		reply[0] = p.unkey(reply[0])
+	}
+	return reply, err
+}
+
+//<editor-fold desc="keycommands">
+
+//Del  see comment in redis.go
+func (p *PrefixedClient) Del(keys ...string) (int64, error) {
+	return p.client.Del(p.keys(keys)...)
+}
+
+//Exists  see comment in redis.go
+func (p *PrefixedClient) Exists(keys ...string) (int64, error) {
+	return p.client.Exists(p.keys(keys)...)
+}
+
+//Expire  see comment in redis.go
+func (p *PrefixedClient) Expire(key string, seconds int) (int64, error) {
+	return p.client.Expire(p.key(key), seconds)
+}
+
+//ExpireAt  see comment in redis.go
+func (p *PrefixedClient) ExpireAt(key string, unixTimeSeconds int64) (int64, error) {
+	return p.client.ExpireAt(p.key(key), unixTimeSeconds)
+}
+
+//PExpire  see comment in redis.go
+func (p *PrefixedClient) PExpire(key string, milliseconds int64) (int64, error) {
+	return p.client.PExpire(p.key(key), milliseconds)
+}
+
+//PExpireAt  see comment in redis.go
+func (p *PrefixedClient) PExpireAt(key string, millisecondsTimestamp int64) (int64, error) {
+	return p.client.PExpireAt(p.key(key), millisecondsTimestamp)
+}
+
+//Persist  see comment in redis.go
+func (p *PrefixedClient) Persist(key string) (int64, error) {
+	return p.client.Persist(p.key(key))
+}
+
+//TTL  see comment in redis.go
+func (p *PrefixedClient) TTL(key string) (int64, error) {
+	return p.client.TTL(p.key(key))
+}
+
+//PTTL  see comment in redis.go
+func (p *PrefixedClient) PTTL(key string) (int64, error) {
+	return p.client.PTTL(p.key(key))
+}
+
+//Type  see comment in redis.go
+func (p *PrefixedClient) Type(key string) (string, error) {
+	return p.client.Type(p.key(key))
+}
+
+//Rename  see comment in redis.go
+func (p *PrefixedClient) Rename(oldKey, newKey string) (string, error) {
+	return p.client.Rename(p.key(oldKey), p.key(newKey))
+}
+
+//RenameNx  see comment in redis.go
+func (p *PrefixedClient) RenameNx(oldKey, newKey string) (int64, error) {
+	return p.client.RenameNx(p.key(oldKey), p.key(newKey))
+}
+
+//Dump  see comment in redis.go
+func (p *PrefixedClient) Dump(key string) ([]byte, error) {
+	return p.client.Dump(p.key(key))
+}
+
+//Restore  see comment in redis.go
+func (p *PrefixedClient) Restore(key string, ttl int, serializedValue []byte) (string, error) {
+	return p.client.Restore(p.key(key), ttl, serializedValue)
+}
+
+//RestoreReplace  see comment in redis.go
+func (p *PrefixedClient) RestoreReplace(key string, ttl int, serializedValue []byte) (string, error) {
+	return p.client.RestoreReplace(p.key(key), ttl, serializedValue)
+}
+
+//Scan scan the keys of the namespace,the MATCH pattern is matched against the keys without the prefix
+func (p *PrefixedClient) Scan(cursor string, params ...*ScanParams) (*ScanResult, error) {
+	param := NewScanParams()
+	if len(params) > 0 {
+		param = params[0]
+	}
+	result, err := p.client.Scan(cursor, param.withPrefix(p.prefix))
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range result.Results {
+		result.Results[i] = p.unkey(key)
+	}
+	return result, nil
+}
+
+//Sort  see comment in redis.go
+func (p *PrefixedClient) Sort(key string, params ...*SortParams) ([]string, error) {
+	return p.client.Sort(p.key(key), p.sortParams(params)...)
+}
+
+//SortStore  see comment in redis.go
+func (p *PrefixedClient) SortStore(srcKey, destKey string, params ...*SortParams) (int64, error) {
+	return p.client.SortStore(p.key(srcKey), p.key(destKey), p.sortParams(params)...)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="stringcommands">
+
+//Append  see comment in redis.go
+func (p *PrefixedClient) Append(key, value string) (int64, error) {
+	return p.client.Append(p.key(key), value)
+}
+
+//Decr  see comment in redis.go
+func (p *PrefixedClient) Decr(key string) (int64, error) {
+	return p.client.Decr(p.key(key))
+}
+
+//DecrBy  see comment in redis.go
+func (p *PrefixedClient) DecrBy(key string, decrement int64) (int64, error) {
+	return p.client.DecrBy(p.key(key), decrement)
+}
+
+//Get  see comment in redis.go
+func (p *PrefixedClient) Get(key string) (string, error) {
+	return p.client.Get(p.key(key))
+}
+
+//GetRange  see comment in redis.go
+func (p *PrefixedClient) GetRange(key string, start, end int64) (string, error) {
+	return p.client.GetRange(p.key(key), start, end)
+}
+
+//GetScan  see comment in redis.go
+func (p *PrefixedClient) GetScan(key string, dest interface{}) error {
+	return p.client.GetScan(p.key(key), dest)
+}
+
+//GetSet  see comment in redis.go
+func (p *PrefixedClient) GetSet(key, value string) (string, error) {
+	return p.client.GetSet(p.key(key), value)
+}
+
+//GetDel  see comment in redis.go
+func (p *PrefixedClient) GetDel(key string) (string, error) {
+	return p.client.GetDel(p.key(key))
+}
+
+//Incr  see comment in redis.go
+func (p *PrefixedClient) Incr(key string) (int64, error) {
+	return p.client.Incr(p.key(key))
+}
+
+//IncrBy  see comment in redis.go
+func (p *PrefixedClient) IncrBy(key string, increment int64) (int64, error) {
+	return p.client.IncrBy(p.key(key), increment)
+}
+
+//IncrByFloat  see comment in redis.go
+func (p *PrefixedClient) IncrByFloat(key string, increment float64) (float64, error) {
+	return p.client.IncrByFloat(p.key(key), increment)
+}
+
+//MGet  see comment in redis.go
+func (p *PrefixedClient) MGet(keys ...string) ([]string, error) {
+	return p.client.MGet(p.keys(keys)...)
+}
+
+//MSet  see comment in redis.go
+func (p *PrefixedClient) MSet(kvs ...string) (string, error) {
+	return p.client.MSet(p.kvs(kvs)...)
+}
+
+//MSetNx  see comment in redis.go
+func (p *PrefixedClient) MSetNx(kvs ...string) (int64, error) {
+	return p.client.MSetNx(p.kvs(kvs)...)
+}
+
+//PSetEx  see comment in redis.go
+func (p *PrefixedClient) PSetEx(key string, milliseconds int64, value string) (string, error) {
+	return p.client.PSetEx(p.key(key), milliseconds, value)
+}
+
+//Set  see comment in redis.go
+func (p *PrefixedClient) Set(key, value string) (string, error) {
+	return p.client.Set(p.key(key), value)
+}
+
+//SetEx  see comment in redis.go
+func (p *PrefixedClient) SetEx(key string, seconds int, value string) (string, error) {
+	return p.client.SetEx(p.key(key), seconds, value)
+}
+
+//SetNx  see comment in redis.go
+func (p *PrefixedClient) SetNx(key, value string) (int64, error) {
+	return p.client.SetNx(p.key(key), value)
+}
+
+//SetRange  see comment in redis.go
+func (p *PrefixedClient) SetRange(key string, offset int64, value string) (int64, error) {
+	return p.client.SetRange(p.key(key), offset, value)
+}
+
+//SetWithParams  see comment in redis.go
+func (p *PrefixedClient) SetWithParams(key, value, nxxx string) (string, error) {
+	return p.client.SetWithParams(p.key(key), value, nxxx)
+}
+
+//SetWithParamsAndTime  see comment in redis.go
+func (p *PrefixedClient) SetWithParamsAndTime(key, value, nxxx, expx string, time int64) (string, error) {
+	return p.client.SetWithParamsAndTime(p.key(key), value, nxxx, expx, time)
+}
+
+//StrLen  see comment in redis.go
+func (p *PrefixedClient) StrLen(key string) (int64, error) {
+	return p.client.StrLen(p.key(key))
+}
+
+//SubStr  see comment in redis.go
+func (p *PrefixedClient) SubStr(key string, start, end int) (string, error) {
+	return p.client.SubStr(p.key(key), start, end)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="bitcommands">
+
+//BitCount  see comment in redis.go
+func (p *PrefixedClient) BitCount(key string) (int64, error) {
+	return p.client.BitCount(p.key(key))
+}
+
+//BitCountRange  see comment in redis.go
+func (p *PrefixedClient) BitCountRange(key string, start, end int64, unit ...*BitUnit) (int64, error) {
+	return p.client.BitCountRange(p.key(key), start, end, unit...)
+}
+
+//BitField  see comment in redis.go
+func (p *PrefixedClient) BitField(key string, arguments ...string) ([]int64, error) {
+	return p.client.BitField(p.key(key), arguments...)
+}
+
+//BitFieldWithArgs  see comment in redis.go
+func (p *PrefixedClient) BitFieldWithArgs(key string, args *BitFieldArgs) ([]*int64, error) {
+	return p.client.BitFieldWithArgs(p.key(key), args)
+}
+
+//BitFieldRo  see comment in redis.go
+func (p *PrefixedClient) BitFieldRo(key string, args *BitFieldArgs) ([]int64, error) {
+	return p.client.BitFieldRo(p.key(key), args)
+}
+
+//BitOp  see comment in redis.go
+func (p *PrefixedClient) BitOp(op BitOP, destKey string, srcKeys ...string) (int64, error) {
+	return p.client.BitOp(op, p.key(destKey), p.keys(srcKeys)...)
+}
+
+//BitPos  see comment in redis.go
+func (p *PrefixedClient) BitPos(key string, value bool, params ...*BitPosParams) (int64, error) {
+	return p.client.BitPos(p.key(key), value, params...)
+}
+
+//GetBit  see comment in redis.go
+func (p *PrefixedClient) GetBit(key string, offset int64) (bool, error) {
+	return p.client.GetBit(p.key(key), offset)
+}
+
+//SetBit  see comment in redis.go
+func (p *PrefixedClient) SetBit(key string, offset int64, value string) (bool, error) {
+	return p.client.SetBit(p.key(key), offset, value)
+}
+
+//SetBitWithBool  see comment in redis.go
+func (p *PrefixedClient) SetBitWithBool(key string, offset int64, value bool) (bool, error) {
+	return p.client.SetBitWithBool(p.key(key), offset, value)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="hashcommands">
+
+//HDel  see comment in redis.go
+func (p *PrefixedClient) HDel(key string, fields ...string) (int64, error) {
+	return p.client.HDel(p.key(key), fields...)
+}
+
+//HExists  see comment in redis.go
+func (p *PrefixedClient) HExists(key, field string) (bool, error) {
+	return p.client.HExists(p.key(key), field)
+}
+
+//HExpire  see comment in redis.go
+func (p *PrefixedClient) HExpire(key string, seconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	return p.client.HExpire(p.key(key), seconds, fields, condition...)
+}
+
+//HExpireAt  see comment in redis.go
+func (p *PrefixedClient) HExpireAt(key string, unixTime int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	return p.client.HExpireAt(p.key(key), unixTime, fields, condition...)
+}
+
+//HGet  see comment in redis.go
+func (p *PrefixedClient) HGet(key, field string) (string, error) {
+	return p.client.HGet(p.key(key), field)
+}
+
+//HGetAll  see comment in redis.go
+func (p *PrefixedClient) HGetAll(key string) (map[string]string, error) {
+	return p.client.HGetAll(p.key(key))
+}
+
+//HGetAllScan  see comment in redis.go
+func (p *PrefixedClient) HGetAllScan(key string, dest interface{}) error {
+	return p.client.HGetAllScan(p.key(key), dest)
+}
+
+//HGetScan  see comment in redis.go
+func (p *PrefixedClient) HGetScan(key, field string, dest interface{}) error {
+	return p.client.HGetScan(p.key(key), field, dest)
+}
+
+//HIncrBy  see comment in redis.go
+func (p *PrefixedClient) HIncrBy(key, field string, value int64) (int64, error) {
+	return p.client.HIncrBy(p.key(key), field, value)
+}
+
+//HIncrByFloat  see comment in redis.go
+func (p *PrefixedClient) HIncrByFloat(key, field string, increment float64) (float64, error) {
+	return p.client.HIncrByFloat(p.key(key), field, increment)
+}
+
+//HKeys  see comment in redis.go
+func (p *PrefixedClient) HKeys(key string) ([]string, error) {
+	return p.client.HKeys(p.key(key))
+}
+
+//HLen  see comment in redis.go
+func (p *PrefixedClient) HLen(key string) (int64, error) {
+	return p.client.HLen(p.key(key))
+}
+
+//HMGet  see comment in redis.go
+func (p *PrefixedClient) HMGet(key string, fields ...string) ([]string, error) {
+	return p.client.HMGet(p.key(key), fields...)
+}
+
+//HMGetScan  see comment in redis.go
+func (p *PrefixedClient) HMGetScan(key string, dest interface{}, fields ...string) error {
+	return p.client.HMGetScan(p.key(key), dest, fields...)
+}
+
+//HMSet  see comment in redis.go
+func (p *PrefixedClient) HMSet(key string, hash map[string]string) (string, error) {
+	return p.client.HMSet(p.key(key), hash)
+}
+
+//HPExpire  see comment in redis.go
+func (p *PrefixedClient) HPExpire(key string, milliseconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	return p.client.HPExpire(p.key(key), milliseconds, fields, condition...)
+}
+
+//HPTtl  see comment in redis.go
+func (p *PrefixedClient) HPTtl(key string, fields ...string) ([]int64, error) {
+	return p.client.HPTtl(p.key(key), fields...)
+}
+
+//HPersist  see comment in redis.go
+func (p *PrefixedClient) HPersist(key string, fields ...string) ([]int64, error) {
+	return p.client.HPersist(p.key(key), fields...)
+}
+
+//HScan  see comment in redis.go
+func (p *PrefixedClient) HScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
+	return p.client.HScan(p.key(key), cursor, params...)
+}
+
+//HSet  see comment in redis.go
+func (p *PrefixedClient) HSet(key, field, value string) (int64, error) {
+	return p.client.HSet(p.key(key), field, value)
+}
+
+//HSetNx  see comment in redis.go
+func (p *PrefixedClient) HSetNx(key, field, value string) (int64, error) {
+	return p.client.HSetNx(p.key(key), field, value)
+}
+
+//HTtl  see comment in redis.go
+func (p *PrefixedClient) HTtl(key string, fields ...string) ([]int64, error) {
+	return p.client.HTtl(p.key(key), fields...)
+}
+
+//HVals  see comment in redis.go
+func (p *PrefixedClient) HVals(key string) ([]string, error) {
+	return p.client.HVals(p.key(key))
+}
+
+//</editor-fold>
+
+//<editor-fold desc="listcommands">
+
+//BLPop  see comment in redis.go
+func (p *PrefixedClient) BLPop(args ...string) ([]string, error) {
+	//the last argument is the timeout
+	if len(args) > 0 {
+		keys := p.keys(args[:len(args)-1])
+		args = append(keys, args[len(args)-1])
+	}
+	return p.popReply(p.client.BLPop(args...))
+}
+
+//BLPopTimeout  see comment in redis.go
+func (p *PrefixedClient) BLPopTimeout(timeout int, keys ...string) ([]string, error) {
+	return p.popReply(p.client.BLPopTimeout(timeout, p.keys(keys)...))
+}
+
+//BRPop  see comment in redis.go
+func (p *PrefixedClient) BRPop(args ...string) ([]string, error) {
+	//the last argument is the timeout
+	if len(args) > 0 {
+		keys := p.keys(args[:len(args)-1])
+		args = append(keys, args[len(args)-1])
+	}
+	return p.popReply(p.client.BRPop(args...))
+}
+
+//BRPopTimeout  see comment in redis.go
+func (p *PrefixedClient) BRPopTimeout(timeout int, keys ...string) ([]string, error) {
+	return p.popReply(p.client.BRPopTimeout(timeout, p.keys(keys)...))
+}
+
+//BRPopLPush  see comment in redis.go
+func (p *PrefixedClient) BRPopLPush(srcKey, destKey string, timeout int) (string, error) {
+	return p.client.BRPopLPush(p.key(srcKey), p.key(destKey), timeout)
+}
+
+//LIndex  see comment in redis.go
+func (p *PrefixedClient) LIndex(key string, index int64) (string, error) {
+	return p.client.LIndex(p.key(key), index)
+}
+
+//LInsert  see comment in redis.go
+func (p *PrefixedClient) LInsert(key string, where *ListOption, pivot, value string) (int64, error) {
+	return p.client.LInsert(p.key(key), where, pivot, value)
+}
+
+//LLen  see comment in redis.go
+func (p *PrefixedClient) LLen(key string) (int64, error) {
+	return p.client.LLen(p.key(key))
+}
+
+//LPop  see comment in redis.go
+func (p *PrefixedClient) LPop(key string) (string, error) {
+	return p.client.LPop(p.key(key))
+}
+
+//LPos  see comment in redis.go
+func (p *PrefixedClient) LPos(key, element string, params ...*LPosParams) (int64, error) {
+	return p.client.LPos(p.key(key), element, params...)
+}
+
+//LPosCount  see comment in redis.go
+func (p *PrefixedClient) LPosCount(key, element string, count int64, params ...*LPosParams) ([]int64, error) {
+	return p.client.LPosCount(p.key(key), element, count, params...)
+}
+
+//LPush  see comment in redis.go
+func (p *PrefixedClient) LPush(key string, members ...string) (int64, error) {
+	return p.client.LPush(p.key(key), members...)
+}
+
+//LPushX  see comment in redis.go
+func (p *PrefixedClient) LPushX(key string, members ...string) (int64, error) {
+	return p.client.LPushX(p.key(key), members...)
+}
+
+//LRange  see comment in redis.go
+func (p *PrefixedClient) LRange(key string, start, stop int64) ([]string, error) {
+	return p.client.LRange(p.key(key), start, stop)
+}
+
+//LRangeScan  see comment in redis.go
+func (p *PrefixedClient) LRangeScan(key string, start, stop int64, dest interface{}) error {
+	return p.client.LRangeScan(p.key(key), start, stop, dest)
+}
+
+//LRem  see comment in redis.go
+func (p *PrefixedClient) LRem(key string, count int64, value string) (int64, error) {
+	return p.client.LRem(p.key(key), count, value)
+}
+
+//LSet  see comment in redis.go
+func (p *PrefixedClient) LSet(key string, index int64, value string) (string, error) {
+	return p.client.LSet(p.key(key), index, value)
+}
+
+//LTrim  see comment in redis.go
+func (p *PrefixedClient) LTrim(key string, start, stop int64) (string, error) {
+	return p.client.LTrim(p.key(key), start, stop)
+}
+
+//RPop  see comment in redis.go
+func (p *PrefixedClient) RPop(key string) (string, error) {
+	return p.client.RPop(p.key(key))
+}
+
+//LPopCount  see comment in redis.go
+func (p *PrefixedClient) LPopCount(key string, count int64) ([]string, error) {
+	return p.client.LPopCount(p.key(key), count)
+}
+
+//RPopCount  see comment in redis.go
+func (p *PrefixedClient) RPopCount(key string, count int64) ([]string, error) {
+	return p.client.RPopCount(p.key(key), count)
+}
+
+//RPopLPush  see comment in redis.go
+func (p *PrefixedClient) RPopLPush(srcKey, destKey string) (string, error) {
+	return p.client.RPopLPush(p.key(srcKey), p.key(destKey))
+}
+
+//RPush  see comment in redis.go
+func (p *PrefixedClient) RPush(key string, members ...string) (int64, error) {
+	return p.client.RPush(p.key(key), members...)
+}
+
+//RPushX  see comment in redis.go
+func (p *PrefixedClient) RPushX(key string, members ...string) (int64, error) {
+	return p.client.RPushX(p.key(key), members...)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="setcommands">
+
+//SAdd  see comment in redis.go
+func (p *PrefixedClient) SAdd(key string, members ...string) (int64, error) {
+	return p.client.SAdd(p.key(key), members...)
+}
+
+//SCard  see comment in redis.go
+func (p *PrefixedClient) SCard(key string) (int64, error) {
+	return p.client.SCard(p.key(key))
+}
+
+//SDiff  see comment in redis.go
+func (p *PrefixedClient) SDiff(keys ...string) ([]string, error) {
+	return p.client.SDiff(p.keys(keys)...)
+}
+
+//SDiffStore  see comment in redis.go
+func (p *PrefixedClient) SDiffStore(destKey string, srcKeys ...string) (int64, error) {
+	return p.client.SDiffStore(p.key(destKey), p.keys(srcKeys)...)
+}
+
+//SInter  see comment in redis.go
+func (p *PrefixedClient) SInter(keys ...string) ([]string, error) {
+	return p.client.SInter(p.keys(keys)...)
+}
+
+//SInterStore  see comment in redis.go
+func (p *PrefixedClient) SInterStore(destKey string, srcKeys ...string) (int64, error) {
+	return p.client.SInterStore(p.key(destKey), p.keys(srcKeys)...)
+}
+
+//SInterCard  see comment in redis.go
+func (p *PrefixedClient) SInterCard(limit int64, keys ...string) (int64, error) {
+	return p.client.SInterCard(limit, p.keys(keys)...)
+}
+
+//SIsMember  see comment in redis.go
+func (p *PrefixedClient) SIsMember(key, member string) (bool, error) {
+	return p.client.SIsMember(p.key(key), member)
+}
+
+//SMembers  see comment in redis.go
+func (p *PrefixedClient) SMembers(key string) ([]string, error) {
+	return p.client.SMembers(p.key(key))
+}
+
+//SMembersMap  see comment in redis.go
+func (p *PrefixedClient) SMembersMap(key string) (map[string]struct{}, error) {
+	return p.client.SMembersMap(p.key(key))
+}
+
+//SMembersScan  see comment in redis.go
+func (p *PrefixedClient) SMembersScan(key string, dest interface{}) error {
+	return p.client.SMembersScan(p.key(key), dest)
+}
+
+//SMove  see comment in redis.go
+func (p *PrefixedClient) SMove(srcKey, destKey, member string) (int64, error) {
+	return p.client.SMove(p.key(srcKey), p.key(destKey), member)
+}
+
+//SPop  see comment in redis.go
+func (p *PrefixedClient) SPop(key string) (string, error) {
+	return p.client.SPop(p.key(key))
+}
+
+//SPopBatch  see comment in redis.go
+func (p *PrefixedClient) SPopBatch(key string, count int64) ([]string, error) {
+	return p.client.SPopBatch(p.key(key), count)
+}
+
+//SRandMember  see comment in redis.go
+func (p *PrefixedClient) SRandMember(key string) (string, error) {
+	return p.client.SRandMember(p.key(key))
+}
+
+//SRandMemberBatch  see comment in redis.go
+func (p *PrefixedClient) SRandMemberBatch(key string, count int) ([]string, error) {
+	return p.client.SRandMemberBatch(p.key(key), count)
+}
+
+//SRem  see comment in redis.go
+func (p *PrefixedClient) SRem(key string, members ...string) (int64, error) {
+	return p.client.SRem(p.key(key), members...)
+}
+
+//SScan  see comment in redis.go
+func (p *PrefixedClient) SScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
+	return p.client.SScan(p.key(key), cursor, params...)
+}
+
+//SUnion  see comment in redis.go
+func (p *PrefixedClient) SUnion(keys ...string) ([]string, error) {
+	return p.client.SUnion(p.keys(keys)...)
+}
+
+//SUnionStore  see comment in redis.go
+func (p *PrefixedClient) SUnionStore(destKey string, srcKeys ...string) (int64, error) {
+	return p.client.SUnionStore(p.key(destKey), p.keys(srcKeys)...)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="sortedsetcommands">
+
+//BZPopMax  see comment in redis.go
+func (p *PrefixedClient) BZPopMax(timeout int, keys ...string) (*KeyedTuple, error) {
+	tuple, err := p.client.BZPopMax(timeout, p.keys(keys)...)
+	if tuple != nil {
+		tuple.Key = p.unkey(tuple.Key)
+	}
+	return tuple, err
+}
+
+//BZPopMin  see comment in redis.go
+func (p *PrefixedClient) BZPopMin(timeout int, keys ...string) (*KeyedTuple, error) {
+	tuple, err := p.client.BZPopMin(timeout, p.keys(keys)...)
+	if tuple != nil {
+		tuple.Key = p.unkey(tuple.Key)
+	}
+	return tuple, err
+}
+
+//BLMPop  see comment in redis.go
+func (p *PrefixedClient) BLMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedElements, error) {
+	elements, err := p.client.BLMPop(timeout, direction, count, p.keys(keys)...)
+	if elements != nil {
+		elements.Key = p.unkey(elements.Key)
+	}
+	return elements, err
+}
+
+//BZMPop  see comment in redis.go
+func (p *PrefixedClient) BZMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedTuples, error) {
+	tuples, err := p.client.BZMPop(timeout, direction, count, p.keys(keys)...)
+	if tuples != nil {
+		tuples.Key = p.unkey(tuples.Key)
+	}
+	return tuples, err
+}
+
+//ZAdd  see comment in redis.go
+func (p *PrefixedClient) ZAdd(key string, score float64, member string, params ...*ZAddParams) (int64, error) {
+	return p.client.ZAdd(p.key(key), score, member, params...)
+}
+
+//ZAddByMap  see comment in redis.go
+func (p *PrefixedClient) ZAddByMap(key string, scoreMembers map[string]float64, params ...*ZAddParams) (int64, error) {
+	return p.client.ZAddByMap(p.key(key), scoreMembers, params...)
+}
+
+//ZCard  see comment in redis.go
+func (p *PrefixedClient) ZCard(key string) (int64, error) {
+	return p.client.ZCard(p.key(key))
+}
+
+//ZCount  see comment in redis.go
+func (p *PrefixedClient) ZCount(key string, min, max float64) (int64, error) {
+	return p.client.ZCount(p.key(key), min, max)
+}
+
+//ZDiff  see comment in redis.go
+func (p *PrefixedClient) ZDiff(keys ...string) ([]string, error) {
+	return p.client.ZDiff(p.keys(keys)...)
+}
+
+//ZDiffStore  see comment in redis.go
+func (p *PrefixedClient) ZDiffStore(destKey string, srcKeys ...string) (int64, error) {
+	return p.client.ZDiffStore(p.key(destKey), p.keys(srcKeys)...)
+}
+
+//ZDiffWithScores  see comment in redis.go
+func (p *PrefixedClient) ZDiffWithScores(keys ...string) ([]Tuple, error) {
+	return p.client.ZDiffWithScores(p.keys(keys)...)
+}
+
+//ZIncrBy  see comment in redis.go
+func (p *PrefixedClient) ZIncrBy(key string, increment float64, member string, params ...*ZAddParams) (float64, error) {
+	return p.client.ZIncrBy(p.key(key), increment, member, params...)
+}
+
+//ZInter  see comment in redis.go
+func (p *PrefixedClient) ZInter(params *ZParams, keys ...string) ([]string, error) {
+	return p.client.ZInter(params, p.keys(keys)...)
+}
+
+//ZInterCard  see comment in redis.go
+func (p *PrefixedClient) ZInterCard(limit int64, keys ...string) (int64, error) {
+	return p.client.ZInterCard(limit, p.keys(keys)...)
+}
+
+//ZInterStore  see comment in redis.go
+func (p *PrefixedClient) ZInterStore(destKey string, srcKeys ...string) (int64, error) {
+	return p.client.ZInterStore(p.key(destKey), p.keys(srcKeys)...)
+}
+
+//ZInterStoreWithParams  see comment in redis.go
+func (p *PrefixedClient) ZInterStoreWithParams(destKey string, params *ZParams, srcKeys ...string) (int64, error) {
+	return p.client.ZInterStoreWithParams(p.key(destKey), params, p.keys(srcKeys)...)
+}
+
+//ZInterWithScores  see comment in redis.go
+func (p *PrefixedClient) ZInterWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	return p.client.ZInterWithScores(params, p.keys(keys)...)
+}
+
+//ZLexCount  see comment in redis.go
+func (p *PrefixedClient) ZLexCount(key, min, max string) (int64, error) {
+	return p.client.ZLexCount(p.key(key), min, max)
+}
+
+//ZPopMax  see comment in redis.go
+func (p *PrefixedClient) ZPopMax(key string, count ...int64) ([]Tuple, error) {
+	return p.client.ZPopMax(p.key(key), count...)
+}
+
+//ZPopMin  see comment in redis.go
+func (p *PrefixedClient) ZPopMin(key string, count ...int64) ([]Tuple, error) {
+	return p.client.ZPopMin(p.key(key), count...)
+}
+
+//ZRange  see comment in redis.go
+func (p *PrefixedClient) ZRange(key string, start, stop int64) ([]string, error) {
+	return p.client.ZRange(p.key(key), start, stop)
+}
+
+//ZRangeByLex  see comment in redis.go
+func (p *PrefixedClient) ZRangeByLex(key, min, max string) ([]string, error) {
+	return p.client.ZRangeByLex(p.key(key), min, max)
+}
+
+//ZRangeByLexBatch  see comment in redis.go
+func (p *PrefixedClient) ZRangeByLexBatch(key, min, max string, offset, count int) ([]string, error) {
+	return p.client.ZRangeByLexBatch(p.key(key), min, max, offset, count)
+}
+
+//ZRangeByScore  see comment in redis.go
+func (p *PrefixedClient) ZRangeByScore(key string, min, max float64) ([]string, error) {
+	return p.client.ZRangeByScore(p.key(key), min, max)
+}
+
+//ZRangeByScoreBatch  see comment in redis.go
+func (p *PrefixedClient) ZRangeByScoreBatch(key string, min, max float64, offset, count int) ([]string, error) {
+	return p.client.ZRangeByScoreBatch(p.key(key), min, max, offset, count)
+}
+
+//ZRangeByScoreWithScores  see comment in redis.go
+func (p *PrefixedClient) ZRangeByScoreWithScores(key string, min, max float64) ([]Tuple, error) {
+	return p.client.ZRangeByScoreWithScores(p.key(key), min, max)
+}
+
+//ZRangeByScoreWithScoresBatch  see comment in redis.go
+func (p *PrefixedClient) ZRangeByScoreWithScoresBatch(key string, min, max float64, offset, count int) ([]Tuple, error) {
+	return p.client.ZRangeByScoreWithScoresBatch(p.key(key), min, max, offset, count)
+}
+
+//ZRangeWithScores  see comment in redis.go
+func (p *PrefixedClient) ZRangeWithScores(key string, start, end int64) ([]Tuple, error) {
+	return p.client.ZRangeWithScores(p.key(key), start, end)
+}
+
+//ZRank  see comment in redis.go
+func (p *PrefixedClient) ZRank(key, member string) (int64, error) {
+	return p.client.ZRank(p.key(key), member)
+}
+
+//ZRem  see comment in redis.go
+func (p *PrefixedClient) ZRem(key string, members ...string) (int64, error) {
+	return p.client.ZRem(p.key(key), members...)
+}
+
+//ZRemRangeByLex  see comment in redis.go
+func (p *PrefixedClient) ZRemRangeByLex(key, min, max string) (int64, error) {
+	return p.client.ZRemRangeByLex(p.key(key), min, max)
+}
+
+//ZRemRangeByRank  see comment in redis.go
+func (p *PrefixedClient) ZRemRangeByRank(key string, start, stop int64) (int64, error) {
+	return p.client.ZRemRangeByRank(p.key(key), start, stop)
+}
+
+//ZRemRangeByScore  see comment in redis.go
+func (p *PrefixedClient) ZRemRangeByScore(key string, min, max float64) (int64, error) {
+	return p.client.ZRemRangeByScore(p.key(key), min, max)
+}
+
+//ZRevRange  see comment in redis.go
+func (p *PrefixedClient) ZRevRange(key string, start, stop int64) ([]string, error) {
+	return p.client.ZRevRange(p.key(key), start, stop)
+}
+
+//ZRevRangeByLex  see comment in redis.go
+func (p *PrefixedClient) ZRevRangeByLex(key, max, min string) ([]string, error) {
+	return p.client.ZRevRangeByLex(p.key(key), max, min)
+}
+
+//ZRevRangeByLexBatch  see comment in redis.go
+func (p *PrefixedClient) ZRevRangeByLexBatch(key, max, min string, offset, count int) ([]string, error) {
+	return p.client.ZRevRangeByLexBatch(p.key(key), max, min, offset, count)
+}
+
+//ZRevRangeByScore  see comment in redis.go
+func (p *PrefixedClient) ZRevRangeByScore(key string, max, min float64) ([]string, error) {
+	return p.client.ZRevRangeByScore(p.key(key), max, min)
+}
+
+//ZRevRangeByScoreWithScores  see comment in redis.go
+func (p *PrefixedClient) ZRevRangeByScoreWithScores(key string, max, min float64) ([]Tuple, error) {
+	return p.client.ZRevRangeByScoreWithScores(p.key(key), max, min)
+}
+
+//ZRevRangeByScoreWithScoresBatch  see comment in redis.go
+func (p *PrefixedClient) ZRevRangeByScoreWithScoresBatch(key string, max, min float64, offset, count int) ([]Tuple, error) {
+	return p.client.ZRevRangeByScoreWithScoresBatch(p.key(key), max, min, offset, count)
+}
+
+//ZRevRangeWithScores  see comment in redis.go
+func (p *PrefixedClient) ZRevRangeWithScores(key string, start, end int64) ([]Tuple, error) {
+	return p.client.ZRevRangeWithScores(p.key(key), start, end)
+}
+
+//ZRevRank  see comment in redis.go
+func (p *PrefixedClient) ZRevRank(key, member string) (int64, error) {
+	return p.client.ZRevRank(p.key(key), member)
+}
+
+//ZScan  see comment in redis.go
+func (p *PrefixedClient) ZScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
+	return p.client.ZScan(p.key(key), cursor, params...)
+}
+
+//ZScore  see comment in redis.go
+func (p *PrefixedClient) ZScore(key, member string) (float64, error) {
+	return p.client.ZScore(p.key(key), member)
+}
+
+//ZUnion  see comment in redis.go
+func (p *PrefixedClient) ZUnion(params *ZParams, keys ...string) ([]string, error) {
+	return p.client.ZUnion(params, p.keys(keys)...)
+}
+
+//ZUnionStore  see comment in redis.go
+func (p *PrefixedClient) ZUnionStore(destKey string, srcKeys ...string) (int64, error) {
+	return p.client.ZUnionStore(p.key(destKey), p.keys(srcKeys)...)
+}
+
+//ZUnionStoreWithParams  see comment in redis.go
+func (p *PrefixedClient) ZUnionStoreWithParams(destKey string, params *ZParams, srcKeys ...string) (int64, error) {
+	return p.client.ZUnionStoreWithParams(p.key(destKey), params, p.keys(srcKeys)...)
+}
+
+//ZUnionWithScores  see comment in redis.go
+func (p *PrefixedClient) ZUnionWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	return p.client.ZUnionWithScores(params, p.keys(keys)...)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="geocommands">
+
+//GeoAdd  see comment in redis.go
+func (p *PrefixedClient) GeoAdd(key string, longitude, latitude float64, member string) (int64, error) {
+	return p.client.GeoAdd(p.key(key), longitude, latitude, member)
+}
+
+//GeoAddByMap  see comment in redis.go
+func (p *PrefixedClient) GeoAddByMap(key string, memberCoordinateMap map[string]GeoCoordinate) (int64, error) {
+	return p.client.GeoAddByMap(p.key(key), memberCoordinateMap)
+}
+
+//GeoDist  see comment in redis.go
+func (p *PrefixedClient) GeoDist(key, member1, member2 string, unit ...*GeoUnit) (float64, error) {
+	return p.client.GeoDist(p.key(key), member1, member2, unit...)
+}
+
+//GeoHash  see comment in redis.go
+func (p *PrefixedClient) GeoHash(key string, members ...string) ([]string, error) {
+	return p.client.GeoHash(p.key(key), members...)
+}
+
+//GeoPos  see comment in redis.go
+func (p *PrefixedClient) GeoPos(key string, members ...string) ([]*GeoCoordinate, error) {
+	return p.client.GeoPos(p.key(key), members...)
+}
+
+//GeoRadius  see comment in redis.go
+func (p *PrefixedClient) GeoRadius(key string, longitude, latitude, radius float64, unit *GeoUnit, param ...*GeoRadiusParams) ([]GeoRadiusResponse, error) {
+	return p.client.GeoRadius(p.key(key), longitude, latitude, radius, unit, param...)
+}
+
+//GeoRadiusByMember  see comment in redis.go
+func (p *PrefixedClient) GeoRadiusByMember(key, member string, radius float64, unit *GeoUnit, param ...*GeoRadiusParams) ([]GeoRadiusResponse, error) {
+	return p.client.GeoRadiusByMember(p.key(key), member, radius, unit, param...)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="hyperloglogcommands">
+
+//PfAdd  see comment in redis.go
+func (p *PrefixedClient) PfAdd(key string, elements ...string) (int64, error) {
+	return p.client.PfAdd(p.key(key), elements...)
+}
+
+//PfCount  see comment in redis.go
+func (p *PrefixedClient) PfCount(keys ...string) (int64, error) {
+	return p.client.PfCount(p.keys(keys)...)
+}
+
+//PfMerge  see comment in redis.go
+func (p *PrefixedClient) PfMerge(destKey string, srcKeys ...string) (string, error) {
+	return p.client.PfMerge(p.key(destKey), p.keys(srcKeys)...)
+}
+
+//PfDebugGetReg  see comment in redis.go
+func (p *PrefixedClient) PfDebugGetReg(key string) ([]int64, error) {
+	return p.client.PfDebugGetReg(p.key(key))
+}
+
+//PfDebugEncoding  see comment in redis.go
+func (p *PrefixedClient) PfDebugEncoding(key string) (string, error) {
+	return p.client.PfDebugEncoding(p.key(key))
+}
+
+//</editor-fold>
+
+//<editor-fold desc="streamcommands">
+
+//XAck  see comment in redis.go
+func (p *PrefixedClient) XAck(key, group string, ids ...string) (int64, error) {
+	return p.client.XAck(p.key(key), group, ids...)
+}
+
+//XAdd  see comment in redis.go
+func (p *PrefixedClient) XAdd(key, id string, hash map[string]string) (string, error) {
+	return p.client.XAdd(p.key(key), id, hash)
+}
+
+//XAutoClaim  see comment in redis.go
+func (p *PrefixedClient) XAutoClaim(key, group, consumer string, minIdle time.Duration, start string, count int) (*StreamClaimResult, error) {
+	return p.client.XAutoClaim(p.key(key), group, consumer, minIdle, start, count)
+}
+
+//XDel  see comment in redis.go
+func (p *PrefixedClient) XDel(key string, ids ...string) (int64, error) {
+	return p.client.XDel(p.key(key), ids...)
+}
+
+//XGroupCreate  see comment in redis.go
+func (p *PrefixedClient) XGroupCreate(key, group, id string, mkStream bool) (string, error) {
+	return p.client.XGroupCreate(p.key(key), group, id, mkStream)
+}
+
+//XLen  see comment in redis.go
+func (p *PrefixedClient) XLen(key string) (int64, error) {
+	return p.client.XLen(p.key(key))
+}
+
+//XPending  see comment in redis.go
+func (p *PrefixedClient) XPending(key, group, start, end string, count int64, params ...*XPendingParams) ([]StreamPendingEntry, error) {
+	return p.client.XPending(p.key(key), group, start, end, count, params...)
+}
+
+//XReadGroup  see comment in redis.go
+func (p *PrefixedClient) XReadGroup(group, consumer string, count int, block time.Duration, streams ...string) ([]StreamEntries, error) {
+	//the keys are the first half of streams,followed by their ids
+	args := make([]string, len(streams))
+	copy(args, streams)
+	for i := 0; i < len(args)/2; i++ {
+		args[i] = p.key(args[i])
+	}
+	entries, err := p.client.XReadGroup(group, consumer, count, block, args...)
+	for i := range entries {
+		entries[i].Stream = p.unkey(entries[i].Stream)
+	}
+	return entries, err
+}
+
+//</editor-fold>
+
+//<editor-fold desc="scriptcommands">
+
+//Eval  see comment in redis.go,the first keyCount params are keys
+func (p *PrefixedClient) Eval(script string, keyCount int, params ...string) (interface{}, error) {
+	return p.client.Eval(script, keyCount, p.scriptParams(keyCount, params)...)
+}
+
+//EvalSha  see comment in redis.go
+func (p *PrefixedClient) EvalSha(sha1 string, keyCount int, params ...string) (interface{}, error) {
+	return p.client.EvalSha(sha1, keyCount, p.scriptParams(keyCount, params)...)
+}
+
+//EvalRo  see comment in redis.go,the first keyCount params are keys
+func (p *PrefixedClient) EvalRo(script string, keyCount int, params ...string) (interface{}, error) {
+	return p.client.EvalRo(script, keyCount, p.scriptParams(keyCount, params)...)
+}
+
+//EvalShaRo  see comment in redis.go
+func (p *PrefixedClient) EvalShaRo(sha1 string, keyCount int, params ...string) (interface{}, error) {
+	return p.client.EvalShaRo(sha1, keyCount, p.scriptParams(keyCount, params)...)
+}
+
+//</editor-fold>
+
+//<editor-fold desc="pubsubcommands">
+
+//Publish  see comment in redis.go
+func (p *PrefixedClient) Publish(channel, message string) (int64, error) {
+	return p.client.Publish(channel, message)
+}
+
+//Subscribe  see comment in redis.go
+func (p *PrefixedClient) Subscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	return p.client.Subscribe(redisPubSub, channels...)
+}
+
+//PSubscribe  see comment in redis.go
+func (p *PrefixedClient) PSubscribe(redisPubSub *RedisPubSub, patterns ...string) error {
+	return p.client.PSubscribe(redisPubSub, patterns...)
+}
+
+//SPublish  see comment in redis.go
+func (p *PrefixedClient) SPublish(channel, message string) (int64, error) {
+	return p.client.SPublish(channel, message)
+}
+
+//SSubscribe  see comment in redis.go
+func (p *PrefixedClient) SSubscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	return p.client.SSubscribe(redisPubSub, channels...)
+}
+
+//PubSubShardChannels  see comment in redis.go
+func (p *PrefixedClient) PubSubShardChannels(pattern string) ([]string, error) {
+	return p.client.PubSubShardChannels(pattern)
+}
+
+//PubSubShardNumSub  see comment in redis.go
+func (p *PrefixedClient) PubSubShardNumSub(channels ...string) (map[string]int64, error) {
+	return p.client.PubSubShardNumSub(channels...)
+}
+
+//</editor-fold>
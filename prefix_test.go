@@ -0,0 +1,148 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPrefixedClient(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	app := redis.WithPrefix("app1:")
+	_, err := app.Set("godis", "good")
+	assert.Nil(t, err)
+	s, err := app.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	s, _ = redis.Get("app1:godis")
+	assert.Equal(t, "good", s)
+	c, _ := redis.Exists("godis")
+	assert.Equal(t, int64(0), c)
+
+	_, err = app.MSet("godis1", "good1", "godis2", "good2")
+	assert.Nil(t, err)
+	arr, err := app.MGet("godis1", "godis2", "godis3")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"good1", "good2", ""}, arr)
+	_, err = app.Rename("godis2", "godis3")
+	assert.Nil(t, err)
+	s, _ = redis.Get("app1:godis3")
+	assert.Equal(t, "good2", s)
+
+	app.SAdd("set1", "a", "b")
+	app.SAdd("set2", "b", "c")
+	c, err = app.SInterStore("set3", "set1", "set2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	members, _ := redis.SMembers("app1:set3")
+	assert.Equal(t, []string{"b"}, members)
+
+	reply, err := app.Eval("return redis.call('get', KEYS[1]) .. ARGV[1]", 1, "godis", "!")
+	assert.Nil(t, err)
+	assert.Equal(t, "good!", reply)
+
+	//nested namespaces
+	nested := app.WithPrefix("sub:")
+	nested.Set("godis", "nested")
+	s, _ = redis.Get("app1:sub:godis")
+	assert.Equal(t, "nested", s)
+
+	c, err = app.Del("godis", "godis1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+}
+
+func TestPrefixedClient_Scan(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.MSet("godis1", "a", "app1:godis1", "b", "app1:godis2", "c", "app1:other", "d", "app2:godis3", "e")
+	app := redis.WithPrefix("app1:")
+	keys := make([]string, 0)
+	cursor := "0"
+	for {
+		result, err := app.Scan(cursor)
+		assert.Nil(t, err)
+		keys = append(keys, result.Results...)
+		cursor = result.Cursor
+		if cursor == "0" {
+			break
+		}
+	}
+	sort.Strings(keys)
+	assert.Equal(t, []string{"godis1", "godis2", "other"}, keys)
+
+	params := NewScanParams().Match("godis*").Count(100)
+	result, err := app.Scan("0", params)
+	assert.Nil(t, err)
+	sort.Strings(result.Results)
+	assert.Equal(t, []string{"godis1", "godis2"}, result.Results)
+	//the params of the caller are not changed
+	assert.Equal(t, "godis*", params.GetMatch())
+
+	assert.Equal(t, `a\*b\?\[c\]\\`, escapeGlob(`a*b?[c]\`))
+}
+
+func TestPrefixedClient_Reply(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	app := redis.WithPrefix("app1:")
+	app.RPush("list", "a")
+	arr, err := app.BLPopTimeout(1, "empty", "list")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"list", "a"}, arr)
+	app.RPush("list", "b")
+	arr, err = app.BRPop("list", "1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"list", "b"}, arr)
+
+	_, err = app.XGroupCreate("stream", "group", "$", true)
+	assert.Nil(t, err)
+	app.XAdd("stream", "*", map[string]string{"a": "1"})
+	entries, err := app.XReadGroup("group", "consumer", 1, 0, "stream", ">")
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "stream", entries[0].Stream)
+	c, _ := redis.XLen("app1:stream")
+	assert.Equal(t, int64(1), c)
+}
+
+func TestPrefixedClient_Sort(t *testing.T) {
+	option, closeServer := newFakeServer(t, map[string][]string{
+		"SORT app1:ids BY app1:weight_* GET # GET app1:name_* DESC": {"*4\r\n$1\r\n2\r\n$1\r\nb\r\n$1\r\n1\r\n$1\r\na\r\n"},
+		"SORT app1:ids BY nosort STORE app1:sorted":                 {":2\r\n"},
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(option)
+	defer redis.Close()
+	app := redis.WithPrefix("app1:")
+	params := NewSortParams().By("weight_*").Get("#", "name_*").Desc()
+	arr, err := app.Sort("ids", params)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2", "b", "1", "a"}, arr)
+	//the params of the caller are not changed
+	assert.Equal(t, []string{"BY", "weight_*", "GET", "#", "GET", "name_*", "DESC"}, params.params)
+	c, err := app.SortStore("ids", "sorted", NewSortParams().By("nosort"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+}
+
+func TestRedisCluster_WithPrefix(t *testing.T) {
+	flushAll()
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(16383), []interface{}{[]byte("localhost"), int64(6379)}},
+	})
+	defer cluster.Close()
+	app := cluster.WithPrefix("app2:")
+	_, err := app.PSetEx("godis", int64(time.Minute/time.Millisecond), "good")
+	assert.Nil(t, err)
+	redis := NewRedis(option)
+	defer redis.Close()
+	s, _ := redis.Get("app2:godis")
+	assert.Equal(t, "good", s)
+}
@@ -0,0 +1,159 @@
+package godis
+
+import (
+	"container/list"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultProfilerCapacity = 1000
+
+//HotKey the sampled accesses of a key,reported by KeyProfiler.HotKeys
+type HotKey struct {
+	Key          string
+	Count        int64            //sampled accesses,divide by the sample rate to estimate all accesses
+	Commands     map[string]int64 //command -> sampled accesses
+	TotalLatency time.Duration    //sum of the latency from sending the command to reading its reply
+	MaxLatency   time.Duration
+}
+
+//AvgLatency average latency of the sampled accesses
+func (h HotKey) AvgLatency() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.TotalLatency / time.Duration(h.Count)
+}
+
+//KeyProfiler sample the commands on the client side and count the accesses and latency of every key,
+//to find the hot keys without MONITOR.
+//
+//only the latest capacity keys are kept,the least recently accessed key is evicted when it is full,
+//so keys accessed rarely don't fill the profiler.
+//set it to Option.Profiler,one profiler can be shared by many connections,such as a pool.
+//
+//KeyProfiler is safe for concurrent use
+type KeyProfiler struct {
+	capacity   int
+	sampleRate float64
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	keys    map[string]*list.Element
+	lru     *list.List //front is the most recently accessed
+	sampled int64
+}
+
+//NewKeyProfiler create new key profiler,capacity is the max count of keys kept,default 1000,
+//sampleRate is the fraction of commands sampled,in (0,1],default 1 means every command
+func NewKeyProfiler(capacity int, sampleRate float64) *KeyProfiler {
+	if capacity <= 0 {
+		capacity = defaultProfilerCapacity
+	}
+	if sampleRate <= 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+	return &KeyProfiler{
+		capacity:   capacity,
+		sampleRate: sampleRate,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		keys:       make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+//HotKeys the n most accessed keys in descending order of count,all kept keys if n <= 0
+func (p *KeyProfiler) HotKeys(n int) []HotKey {
+	p.mu.Lock()
+	hotKeys := make([]HotKey, 0, p.lru.Len())
+	for e := p.lru.Front(); e != nil; e = e.Next() {
+		hotKey := *e.Value.(*HotKey)
+		hotKey.Commands = make(map[string]int64, len(hotKey.Commands))
+		for command, count := range e.Value.(*HotKey).Commands {
+			hotKey.Commands[command] = count
+		}
+		hotKeys = append(hotKeys, hotKey)
+	}
+	p.mu.Unlock()
+	sort.SliceStable(hotKeys, func(i, j int) bool {
+		return hotKeys[i].Count > hotKeys[j].Count
+	})
+	if n > 0 && len(hotKeys) > n {
+		hotKeys = hotKeys[:n]
+	}
+	return hotKeys
+}
+
+//Sampled count of the sampled commands
+func (p *KeyProfiler) Sampled() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sampled
+}
+
+//Reset forget all the keys
+func (p *KeyProfiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = make(map[string]*list.Element)
+	p.lru.Init()
+	p.sampled = 0
+}
+
+//sample whether the next command is sampled
+func (p *KeyProfiler) sample() bool {
+	if p.sampleRate >= 1 {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rand.Float64() < p.sampleRate
+}
+
+//record an access of the keys by the command
+func (p *KeyProfiler) record(command string, keys []string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sampled++
+	for _, key := range keys {
+		var hotKey *HotKey
+		if e, ok := p.keys[key]; ok {
+			p.lru.MoveToFront(e)
+			hotKey = e.Value.(*HotKey)
+		} else {
+			if p.lru.Len() >= p.capacity {
+				oldest := p.lru.Back()
+				delete(p.keys, oldest.Value.(*HotKey).Key)
+				p.lru.Remove(oldest)
+			}
+			hotKey = &HotKey{Key: key, Commands: make(map[string]int64)}
+			p.keys[key] = p.lru.PushFront(hotKey)
+		}
+		hotKey.Count++
+		hotKey.Commands[command]++
+		hotKey.TotalLatency += latency
+		if latency > hotKey.MaxLatency {
+			hotKey.MaxLatency = latency
+		}
+	}
+}
+
+//keySample a command waiting for its reply,sampled by the profiler or measured for the slow command log
+type keySample struct {
+	seq      int64 //sequence of the command sent by the connection
+	command  string
+	keys     []string
+	start    time.Time
+	profiled bool //sampled by the profiler
+	poolWait time.Duration
+}
+
+//SlowCommand a command slower than Option.SlowThreshold
+type SlowCommand struct {
+	Command  string
+	Keys     []string
+	Duration time.Duration //from sending the command to reading its reply,a pipelined command waits for Sync too
+	PoolWait time.Duration //time waited to borrow the connection from the pool,only set for the first command after borrowing
+}
@@ -0,0 +1,101 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyProfiler(t *testing.T) {
+	flushAll()
+	profiler := NewKeyProfiler(2, 1)
+	opt := *option
+	opt.Profiler = profiler
+	redis := NewRedis(&opt)
+	defer redis.Close()
+
+	for i := 0; i < 3; i++ {
+		redis.Set("godis", "good")
+	}
+	redis.Get("godis")
+	redis.MGet("godis1", "godis")
+	redis.Ping()
+
+	p := redis.Pipelined()
+	p.Exists("godis2")
+	p.Exists("godis2")
+	err := p.Sync()
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(7), profiler.Sampled())
+	hotKeys := profiler.HotKeys(0)
+	//godis1 is evicted by godis2
+	assert.Len(t, hotKeys, 2)
+	assert.Equal(t, "godis", hotKeys[0].Key)
+	assert.Equal(t, int64(5), hotKeys[0].Count)
+	assert.Equal(t, map[string]int64{"SET": 3, "GET": 1, "MGET": 1}, hotKeys[0].Commands)
+	assert.True(t, hotKeys[0].MaxLatency > 0)
+	assert.True(t, hotKeys[0].AvgLatency() <= hotKeys[0].MaxLatency)
+	assert.Equal(t, "godis2", hotKeys[1].Key)
+	assert.Equal(t, int64(2), hotKeys[1].Count)
+	assert.Len(t, profiler.HotKeys(1), 1)
+
+	profiler.Reset()
+	assert.Len(t, profiler.HotKeys(0), 0)
+	assert.Equal(t, time.Duration(0), HotKey{}.AvgLatency())
+
+	sampled := NewKeyProfiler(0, 0.5)
+	for i := 0; i < 1000; i++ {
+		if sampled.sample() {
+			sampled.record("GET", []string{"godis"}, time.Millisecond)
+		}
+	}
+	n := sampled.HotKeys(1)[0].Count
+	assert.True(t, n > 300 && n < 700)
+}
+
+func TestSlowCommand(t *testing.T) {
+	flushAll()
+	var mu sync.Mutex
+	slows := make([]SlowCommand, 0)
+	opt := *option
+	opt.SlowThreshold = time.Nanosecond
+	opt.OnSlowCommand = func(slow SlowCommand) {
+		mu.Lock()
+		slows = append(slows, slow)
+		mu.Unlock()
+	}
+	redis := NewRedis(&opt)
+	redis.Set("godis", "good")
+	redis.Ping()
+	redis.Close()
+	assert.Len(t, slows, 2)
+	assert.Equal(t, "SET", slows[0].Command)
+	assert.Equal(t, []string{"godis"}, slows[0].Keys)
+	assert.True(t, slows[0].Duration > 0)
+	assert.Equal(t, "PING", slows[1].Command)
+	assert.Len(t, slows[1].Keys, 0)
+
+	//the wait for the pool makes the command slow
+	slows = slows[:0]
+	opt.SlowThreshold = 50 * time.Millisecond
+	pool := NewPool(&PoolConfig{MaxTotal: 1}, &opt)
+	defer pool.Destroy()
+	first, err := pool.GetResource()
+	assert.Nil(t, err)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		first.Close()
+	}()
+	second, err := pool.GetResource()
+	assert.Nil(t, err)
+	second.Get("godis")
+	second.Get("godis")
+	second.Close()
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, slows, 1)
+	assert.Equal(t, "GET", slows[0].Command)
+	assert.True(t, slows[0].PoolWait >= 50*time.Millisecond)
+}
@@ -3,9 +3,12 @@ package godis
 import (
 	"bufio"
 	"fmt"
-	"math"
+	"io"
+	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,6 +18,10 @@ const (
 	clusterDownPrefix = "CLUSTERDOWN "
 	busyPrefix        = "BUSY "
 	noscriptPrefix    = "NOSCRIPT "
+	readOnlyPrefix    = "READONLY "
+	loadingPrefix     = "LOADING "
+	noAuthPrefix      = "NOAUTH "
+	wrongPassPrefix   = "WRONGPASS "
 
 	defaultHost         = "localhost"
 	defaultPort         = 6379
@@ -36,6 +43,11 @@ const (
 	sentinelMonitor             = "monitor"
 	sentinelRemove              = "remove"
 	sentinelSet                 = "set"
+	sentinelMaster              = "master"
+	sentinelSentinels           = "sentinels"
+	sentinelCkQuorum            = "ckquorum"
+	sentinelFlushConfig         = "flushconfig"
+	sentinelSimulateFailure     = "simulate-failure"
 
 	clusterNodes            = "nodes"
 	clusterMeet             = "meet"
@@ -58,9 +70,17 @@ const (
 	clusterSlaves           = "slaves"
 	clusterFailOver         = "failover"
 	clusterSlots            = "slots"
+	clusterShards           = "shards"
 	pubSubChannels          = "channels"
 	pubSubNumSub            = "numsub"
 	pubSubNumPat            = "numpat"
+	pubSubShardChannels     = "shardchannels"
+	pubSubShardNumSub       = "shardnumsub"
+
+	clusterMyID                = "myid"
+	clusterLinks               = "links"
+	clusterBumpEpoch           = "bumpepoch"
+	clusterCountFailureReports = "count-failure-reports"
 )
 
 var (
@@ -72,159 +92,167 @@ var (
 	negativeInfinityBytes = []byte("-inf")
 )
 
-var (
-	sizeTable = []int{9, 99, 999, 9999, 99999, 999999, 9999999, 99999999,
-		999999999, math.MaxInt32}
-
-	digitTens = []byte{'0', '0', '0', '0', '0', '0', '0', '0', '0', '0', '1',
-		'1', '1', '1', '1', '1', '1', '1', '1', '1', '2', '2', '2', '2', '2', '2', '2', '2', '2',
-		'2', '3', '3', '3', '3', '3', '3', '3', '3', '3', '3', '4', '4', '4', '4', '4', '4', '4',
-		'4', '4', '4', '5', '5', '5', '5', '5', '5', '5', '5', '5', '5', '6', '6', '6', '6', '6',
-		'6', '6', '6', '6', '6', '7', '7', '7', '7', '7', '7', '7', '7', '7', '7', '8', '8', '8',
-		'8', '8', '8', '8', '8', '8', '8', '9', '9', '9', '9', '9', '9', '9', '9', '9', '9'}
-
-	digitOnes = []byte{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '0',
-		'1', '2', '3', '4', '5', '6', '7', '8', '9', '0', '1', '2', '3', '4', '5', '6', '7', '8',
-		'9', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '0', '1', '2', '3', '4', '5', '6',
-		'7', '8', '9', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '0', '1', '2', '3', '4',
-		'5', '6', '7', '8', '9', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '0', '1', '2',
-		'3', '4', '5', '6', '7', '8', '9', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9'}
-
-	digits = []byte{'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'a',
-		'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o', 'p', 'q', 'r', 's',
-		't', 'u', 'v', 'w', 'x', 'y', 'z'}
+const (
+	//largeArgSize arguments of at least largeArgSize bytes are written by a vectored write instead of being copied,
+	//the command is flushed at once then
+	largeArgSize = 16 * 1024
+	//maxPendingSize the pending commands are flushed when their size reaches maxPendingSize
+	maxPendingSize = 64 * 1024
+	//maxPooledBufSize buffers grown larger than maxPooledBufSize are not reused
+	maxPooledBufSize = 1024 * 1024
 )
 
+//outputBufPool buffers of the commands being encoded,shared by all the connections,
+//a connection only holds a buffer between encoding commands and flushing them
+var outputBufPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 4096)
+		return &buf
+	},
+}
+
 // send message to redis
+//
+//commands are encoded into a pooled buffer and written to the socket once on flush,
+//large arguments are not copied,they are written along with the buffer by a vectored write
+//as soon as their command is encoded,so the caller may modify them once the command is sent
 type redisOutputStream struct {
-	*bufio.Writer
-	buf   []byte
-	count int
-	c     *connection
+	buf     *[]byte     //encoded commands not flushed yet,nil if there is none
+	bufs    net.Buffers //segments of the pending commands,the buffer before large arguments and the large arguments
+	start   int         //start of the buffer not in bufs yet
+	pending int         //size of the pending commands
+	count   int         //count of the pending commands
+	c       *connection
 }
 
-func newRedisOutputStream(bw *bufio.Writer, c *connection) *redisOutputStream {
-	return &redisOutputStream{
-		Writer: bw,
-		buf:    make([]byte, 8192),
-		c:      c,
-	}
+func newRedisOutputStream(c *connection) *redisOutputStream {
+	return &redisOutputStream{c: c}
 }
 
-func (r *redisOutputStream) writeIntCrLf(b int) error {
-	if b < 0 {
-		if err := r.writeByte('-'); err != nil {
-			return err
-		}
-		b = -b
-	}
-	size := 0
-	for b > sizeTable[size] {
-		size++
-	}
-	size++
-	if size >= len(r.buf)-r.count {
-		if err := r.flushBuffer(); err != nil {
-			return err
-		}
-	}
-	q, p := 0, 0
-	charPos := r.count + size
-	for b >= 65536 {
-		q = b / 100
-		p = b - ((q << 6) + (q << 5) + (q << 2))
-		b = q
-		charPos--
-		r.buf[charPos] = digitOnes[p]
-		charPos--
-		r.buf[charPos] = digitTens[p]
-	}
-	for {
-		q = (b * 52429) >> (16 + 3)
-		p = b - ((q << 3) + (q << 1))
-		charPos--
-		r.buf[charPos] = digits[p]
-		b = q
-		if b == 0 {
-			break
-		}
+//writeCommand encode a command whose arguments are byte arrays
+func (r *redisOutputStream) writeCommand(command string, args ...[]byte) error {
+	size := r.size()
+	r.writeHeader(command, len(args))
+	for _, arg := range args {
+		r.writeArg(arg)
 	}
-	r.count += size
-	return r.writeCrLf()
+	r.c.written += int64(r.size() - size)
+	r.count++
+	return r.flushIfFull()
 }
 
-func (r *redisOutputStream) writeCrLf() error {
-	if 2 >= len(r.buf)-r.count {
-		if err := r.flushBuffer(); err != nil {
-			return err
-		}
+//writeStrCommand encode a command whose arguments are strings,without converting them into byte arrays
+func (r *redisOutputStream) writeStrCommand(command string, args ...string) error {
+	size := r.size()
+	r.writeHeader(command, len(args))
+	for _, arg := range args {
+		r.writeStrArg(arg)
 	}
-	r.buf[r.count] = '\r'
+	r.c.written += int64(r.size() - size)
 	r.count++
-	r.buf[r.count] = '\n'
-	r.count++
-	return nil
+	return r.flushIfFull()
 }
 
-func (r *redisOutputStream) flushBuffer() error {
-	if r.count <= 0 {
-		return nil
-	}
-	if err := r.c.socket.SetDeadline(time.Now().Add(r.c.soTimeout)); err != nil {
-		return newConnectError(err.Error())
+//size size of the commands not flushed yet
+func (r *redisOutputStream) size() int {
+	if r.buf == nil {
+		return 0
 	}
-	_, err := r.Write(r.buf[0:r.count])
-	if err != nil {
-		return err
+	return len(*r.buf) + r.pending
+}
+
+func (r *redisOutputStream) writeHeader(command string, argCount int) {
+	if r.buf == nil {
+		r.buf = outputBufPool.Get().(*[]byte)
 	}
-	r.count = 0
-	return nil
+	b := append(*r.buf, asteriskByte)
+	b = strconv.AppendInt(b, int64(argCount+1), 10)
+	b = append(b, '\r', '\n', dollarByte)
+	b = strconv.AppendInt(b, int64(len(command)), 10)
+	b = append(b, '\r', '\n')
+	b = append(b, command...)
+	*r.buf = append(b, '\r', '\n')
 }
 
-func (r *redisOutputStream) writeByte(b byte) error {
-	if r.count == len(r.buf) {
-		return r.flushBuffer()
+func (r *redisOutputStream) writeArg(arg []byte) {
+	b := append(*r.buf, dollarByte)
+	b = strconv.AppendInt(b, int64(len(arg)), 10)
+	b = append(b, '\r', '\n')
+	if len(arg) >= largeArgSize {
+		r.bufs = append(r.bufs, b[r.start:], arg)
+		r.start = len(b)
+		r.pending += len(arg)
+	} else {
+		b = append(b, arg...)
 	}
-	r.buf[r.count] = b
-	r.count++
-	return nil
+	*r.buf = append(b, '\r', '\n')
 }
 
-func (r *redisOutputStream) write(b []byte) error {
-	return r.writeWithPos(b, 0, len(b))
+func (r *redisOutputStream) writeStrArg(arg string) {
+	b := append(*r.buf, dollarByte)
+	b = strconv.AppendInt(b, int64(len(arg)), 10)
+	b = append(b, '\r', '\n')
+	b = append(b, arg...)
+	*r.buf = append(b, '\r', '\n')
 }
 
-func (r *redisOutputStream) writeWithPos(b []byte, off, size int) error {
-	if size >= len(r.buf) {
-		err := r.flushBuffer()
-		if err != nil {
-			return err
-		}
-		_, err = r.Write(b[off:size])
-		return err
+//flushIfFull write the pending commands if they are large,so a long pipeline doesn't hold them all in memory,
+//or if they reference large arguments,which the caller may modify after the command is encoded
+func (r *redisOutputStream) flushIfFull() error {
+	if len(r.bufs) == 0 && len(*r.buf) < maxPendingSize {
+		return nil
 	}
+	return r.flush()
+}
 
-	if size >= len(r.buf)-r.count {
-		err := r.flushBuffer()
-		if err != nil {
-			return err
-		}
+//flush write the pending commands to the socket
+func (r *redisOutputStream) flush() error {
+	if r.buf == nil {
+		return nil
 	}
-	for i := off; i < size; i++ {
-		r.buf[r.count] = b[i]
-		r.count++
+	b := *r.buf
+	defer r.release()
+	//the commands failed to be written are not mirrored,like the ones discarded
+	defer func() { r.c.mirrorPending = nil }()
+	if err := r.c.setIODeadline(); err != nil {
+		return wrapConnectError(err)
 	}
+	var n int64
+	var err error
+	if len(r.bufs) == 0 {
+		var written int
+		written, err = r.c.socket.Write(b)
+		n = int64(written)
+	} else {
+		bufs := append(r.bufs, b[r.start:])
+		n, err = bufs.WriteTo(r.c.socket)
+	}
+	atomic.AddInt64(&r.c.stats.bytesWritten, n)
+	if err != nil {
+		return wrapConnectError(err)
+	}
+	if r.count > 0 {
+		atomic.AddInt64(&r.c.stats.commands, int64(r.count))
+		atomic.AddInt64(&r.c.stats.flushes, 1)
+	}
+	r.c.mirrorFlushed()
 	return nil
 }
 
-func (r *redisOutputStream) flush() error {
-	if err := r.flushBuffer(); err != nil {
-		return newConnectError(err.Error())
+//release return the buffer to the pool,drop the references to the large arguments
+func (r *redisOutputStream) release() {
+	for i := range r.bufs {
+		r.bufs[i] = nil
 	}
-	if err := r.Flush(); err != nil {
-		return err
+	r.bufs = r.bufs[:0]
+	r.start = 0
+	r.pending = 0
+	r.count = 0
+	if cap(*r.buf) <= maxPooledBufSize {
+		*r.buf = (*r.buf)[:0]
+		outputBufPool.Put(r.buf)
 	}
-	return nil
+	r.buf = nil
 }
 
 // receive message from redis
@@ -233,6 +261,7 @@ type redisInputStream struct {
 	buf   []byte
 	count int
 	limit int
+	total int64 //bytes read into buf
 	c     *connection
 }
 
@@ -244,6 +273,11 @@ func newRedisInputStream(br *bufio.Reader, c *connection) *redisInputStream {
 	}
 }
 
+//position bytes consumed from the stream
+func (r *redisInputStream) position() int64 {
+	return r.total - int64(r.limit-r.count)
+}
+
 func (r *redisInputStream) readByte() (byte, error) {
 	err := r.ensureFill()
 	if err != nil {
@@ -261,12 +295,10 @@ func (r *redisInputStream) ensureFill() error {
 	var err error
 	r.limit, err = r.Read(r.buf)
 	if err != nil {
-		return newConnectError(err.Error())
-	}
-	err = r.c.socket.SetDeadline(time.Now().Add(r.c.soTimeout))
-	if err != nil {
-		return newConnectError(err.Error())
+		//the only place the reading errors are wrapped,the readers return them as they are
+		return wrapConnectError(fmt.Errorf("reading reply: %w", err))
 	}
+	r.total += int64(r.limit)
 	r.count = 0
 	if r.limit == -1 {
 		return newConnectError("Unexpected end of stream")
@@ -331,11 +363,7 @@ func (r *redisInputStream) readLineBytes() ([]byte, error) {
 	}
 	N := pos - r.count - 2
 	line := make([]byte, N)
-	j := 0
-	for i := r.count; i <= N; i++ {
-		line[j] = buf[i]
-		j++
-	}
+	copy(line, buf[r.count:r.count+N])
 	r.count = pos
 	return line, nil
 }
@@ -349,7 +377,7 @@ func (r *redisInputStream) readLineBytesSlowly() ([]byte, error) {
 		}
 		b := r.buf[r.count]
 		r.count++
-		if b == 'r' {
+		if b == '\r' {
 			err := r.ensureFill()
 			if err != nil {
 				return nil, err
@@ -368,6 +396,90 @@ func (r *redisInputStream) readLineBytesSlowly() ([]byte, error) {
 	return buf, nil
 }
 
+//readLineString same as readLineBytes,but converts the line into string without copying it twice if it is buffered
+func (r *redisInputStream) readLineString() (string, error) {
+	err := r.ensureFill()
+	if err != nil {
+		return "", err
+	}
+	for pos := r.count; pos+1 < r.limit; pos++ {
+		if r.buf[pos] == '\r' && r.buf[pos+1] == '\n' {
+			line := string(r.buf[r.count:pos])
+			r.count = pos + 2
+			return line, nil
+		}
+	}
+	line, err := r.readLineBytesSlowly()
+	return string(line), err
+}
+
+//readBulk read the l bytes of a bulk string and the trailing CRLF
+func (r *redisInputStream) readBulk(l int) ([]byte, error) {
+	bulk := make([]byte, l)
+	for n := 0; n < l; {
+		if err := r.ensureFill(); err != nil {
+			return nil, err
+		}
+		c := copy(bulk[n:], r.buf[r.count:r.limit])
+		r.count += c
+		n += c
+	}
+	return bulk, r.readCrLf()
+}
+
+//readBulkString same as readBulk,but converts the bulk into string without copying it twice if it is buffered
+func (r *redisInputStream) readBulkString(l int) (string, error) {
+	if r.limit-r.count < l+2 {
+		bulk, err := r.readBulk(l)
+		return string(bulk), err
+	}
+	bulk := string(r.buf[r.count : r.count+l])
+	r.count += l
+	return bulk, r.readCrLf()
+}
+
+//copyN copy the next n bytes to w,such as a payload without the trailing CRLF
+func (r *redisInputStream) copyN(w io.Writer, n int64) (int64, error) {
+	var written int64
+	for written < n {
+		//the payload may be far larger than a reply,the deadline is renewed by every chunk
+		if r.count >= r.limit {
+			if err := r.c.setReplyDeadline(); err != nil {
+				return written, err
+			}
+		}
+		if err := r.ensureFill(); err != nil {
+			return written, err
+		}
+		chunk := r.buf[r.count:r.limit]
+		if int64(len(chunk)) > n-written {
+			chunk = chunk[:n-written]
+		}
+		m, err := w.Write(chunk)
+		r.count += m
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (r *redisInputStream) readCrLf() error {
+	cr, err := r.readByte()
+	if err != nil {
+		return err
+	}
+	lf, err := r.readByte()
+	if err != nil {
+		return err
+	}
+	if cr != '\r' || lf != '\n' {
+		return newProtocolError("Unexpected character!")
+	}
+	return nil
+}
+
 func (r *redisInputStream) readIntCrLf() (int64, error) {
 	err := r.ensureFill()
 	if err != nil {
@@ -397,7 +509,7 @@ func (r *redisInputStream) readIntCrLf() (int64, error) {
 			c := buf[r.count]
 			r.count++
 			if c != '\n' {
-				return 0, newConnectError("Unexpected character!")
+				return 0, newProtocolError("Unexpected character!")
 			}
 			break
 		} else {
@@ -422,40 +534,12 @@ func newProtocol(os *redisOutputStream, is *redisInputStream) *protocol {
 	}
 }
 
-func (p *protocol) sendCommand(command []byte, args ...[]byte) error {
-	if err := p.os.writeByte(asteriskByte); err != nil {
-		return err
-	}
-	if err := p.os.writeIntCrLf(len(args) + 1); err != nil {
-		return err
-	}
-	if err := p.os.writeByte(dollarByte); err != nil {
-		return err
-	}
-	if err := p.os.writeIntCrLf(len(command)); err != nil {
-		return err
-	}
-	if err := p.os.write(command); err != nil {
-		return err
-	}
-	if err := p.os.writeCrLf(); err != nil {
-		return err
-	}
-	for _, arg := range args {
-		if err := p.os.writeByte(dollarByte); err != nil {
-			return err
-		}
-		if err := p.os.writeIntCrLf(len(arg)); err != nil {
-			return err
-		}
-		if err := p.os.write(arg); err != nil {
-			return err
-		}
-		if err := p.os.writeCrLf(); err != nil {
-			return err
-		}
-	}
-	return nil
+func (p *protocol) sendCommand(command string, args ...[]byte) error {
+	return p.os.writeCommand(command, args...)
+}
+
+func (p *protocol) sendStrCommand(command string, args ...string) error {
+	return p.os.writeStrCommand(command, args...)
 }
 
 func (p *protocol) read() (interface{}, error) {
@@ -465,8 +549,13 @@ func (p *protocol) read() (interface{}, error) {
 func (p *protocol) process() (interface{}, error) {
 	b, err := p.is.readByte()
 	if err != nil {
-		return nil, newConnectError(err.Error())
+		return nil, err
 	}
+	return p.processReply(b)
+}
+
+//processReply read the reply whose type byte is b
+func (p *protocol) processReply(b byte) (interface{}, error) {
 	switch b {
 	case plusByte:
 		return p.processStatusCodeReply()
@@ -479,63 +568,190 @@ func (p *protocol) process() (interface{}, error) {
 	case minusByte:
 		return p.processError()
 	default:
-		return nil, newConnectError(fmt.Sprintf("Unknown reply: %b", b))
+		return nil, newProtocolError(fmt.Sprintf("Unknown reply: %b", b))
 	}
 }
 
-func (p *protocol) processStatusCodeReply() ([]byte, error) {
-	return p.is.readLineBytes()
+//the typed readers below parse the expected type of reply without building interface{} values,
+//a reply of other types,such as nil or error,is read by processReply and returned as reply,
+//reply is nil if the reply is of the expected type
+
+//readString read a status or bulk reply as string
+func (p *protocol) readString() (string, interface{}, error) {
+	b, err := p.is.readByte()
+	if err != nil {
+		return "", nil, err
+	}
+	switch b {
+	case plusByte:
+		s, err := p.is.readLineString()
+		return s, nil, err
+	case dollarByte:
+		l, err := p.is.readIntCrLf()
+		if err != nil {
+			return "", nil, err
+		}
+		if l == -1 {
+			return "", []byte(nil), nil
+		}
+		s, err := p.is.readBulkString(int(l))
+		return s, nil, err
+	}
+	reply, err := p.processReply(b)
+	return "", reply, err
 }
 
-func (p *protocol) processBulkReply() ([]byte, error) {
+//readBytes read a status or bulk reply as byte array
+func (p *protocol) readBytes() ([]byte, interface{}, error) {
+	b, err := p.is.readByte()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch b {
+	case plusByte:
+		line, err := p.is.readLineBytes()
+		return line, nil, err
+	case dollarByte:
+		l, err := p.is.readIntCrLf()
+		if err != nil {
+			return nil, nil, err
+		}
+		if l == -1 {
+			return nil, []byte(nil), nil
+		}
+		bulk, err := p.is.readBulk(int(l))
+		return bulk, nil, err
+	}
+	reply, err := p.processReply(b)
+	return nil, reply, err
+}
+
+//readInteger read an integer reply
+func (p *protocol) readInteger() (int64, interface{}, error) {
+	b, err := p.is.readByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	if b == colonByte {
+		n, err := p.is.readIntCrLf()
+		return n, nil, err
+	}
+	reply, err := p.processReply(b)
+	return 0, reply, err
+}
+
+//readArrayLen read the length of a multi bulk reply,
+//reply is the reply read by processReply if it isn't a multi bulk reply or it is nil
+func (p *protocol) readArrayLen() (int, interface{}, error) {
+	b, err := p.is.readByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	if b != asteriskByte {
+		reply, err := p.processReply(b)
+		return 0, reply, err
+	}
 	l, err := p.is.readIntCrLf()
 	if err != nil {
-		return nil, newConnectError(err.Error())
+		return 0, nil, err
 	}
-	if l == -1 {
-		return nil, nil
+	if l < 0 {
+		return 0, []interface{}(nil), nil
 	}
-	line := make([]byte, 0)
-	for {
-		err := p.is.ensureFill()
+	return int(l), nil, nil
+}
+
+//readStringArray read a multi bulk reply of status or bulk replies as string array,
+//nil elements are empty strings,the elements of other types are read and a DataError is returned
+func (p *protocol) readStringArray() ([]string, interface{}, error) {
+	l, reply, err := p.readArrayLen()
+	if err != nil || reply != nil {
+		return nil, reply, err
+	}
+	arr := make([]string, 0, l)
+	var elemErr error
+	for i := 0; i < l; i++ {
+		s, reply, err := p.readString()
+		if err == nil && reply != nil && !isNilReply(reply) {
+			err = newDataError(fmt.Sprintf("data error:%v", reply))
+		}
 		if err != nil {
-			return nil, err
+			if _, ok := err.(*ConnectError); ok {
+				return nil, nil, err
+			}
+			if elemErr == nil {
+				elemErr = wrapDataError(err)
+			}
+			continue
 		}
-		b := p.is.buf[p.is.count]
-		p.is.count++
-		if b == '\r' {
-			err := p.is.ensureFill()
-			if err != nil {
-				return nil, err
+		arr = append(arr, s)
+	}
+	return arr, nil, elemErr
+}
+
+//readBytesArray read a multi bulk reply of status or bulk replies as byte arrays,
+//nil elements are nil,the elements of other types are read and a DataError is returned
+func (p *protocol) readBytesArray() ([][]byte, interface{}, error) {
+	l, reply, err := p.readArrayLen()
+	if err != nil || reply != nil {
+		return nil, reply, err
+	}
+	arr := make([][]byte, 0, l)
+	var elemErr error
+	for i := 0; i < l; i++ {
+		b, reply, err := p.readBytes()
+		if err == nil && reply != nil && !isNilReply(reply) {
+			err = newDataError(fmt.Sprintf("data error:%v", reply))
+		}
+		if err != nil {
+			if _, ok := err.(*ConnectError); ok {
+				return nil, nil, err
 			}
-			c := p.is.buf[p.is.count]
-			p.is.count++
-			if c != '\n' {
-				return nil, newConnectError("Unexpected character!")
+			if elemErr == nil {
+				elemErr = wrapDataError(err)
 			}
-			break
-		} else {
-			line = append(line, b)
+			continue
 		}
+		arr = append(arr, b)
 	}
-	return line, nil
+	return arr, nil, elemErr
+}
+
+func (p *protocol) processStatusCodeReply() ([]byte, error) {
+	return p.is.readLineBytes()
+}
+
+func (p *protocol) processBulkReply() ([]byte, error) {
+	l, err := p.is.readIntCrLf()
+	if err != nil {
+		return nil, err
+	}
+	if l == -1 {
+		return nil, nil
+	}
+	return p.is.readBulk(int(l))
 }
 
 func (p *protocol) processMultiBulkReply() ([]interface{}, error) {
 	l, err := p.is.readIntCrLf()
 	if err != nil {
-		return nil, newConnectError(err.Error())
+		return nil, err
 	}
 	if l == -1 {
 		return nil, nil
 	}
 	ret := make([]interface{}, 0)
 	for i := 0; i < int(l); i++ {
-		if obj, err := p.process(); err != nil {
-			ret = append(ret, newDataError(err.Error()))
-		} else {
-			ret = append(ret, obj)
+		obj, err := p.process()
+		if err != nil {
+			//the rest of the reply can't be read,only the error replies are elements
+			if _, ok := err.(*ConnectError); ok {
+				return nil, err
+			}
+			ret = append(ret, wrapDataError(err))
+			continue
 		}
+		ret = append(ret, obj)
 	}
 	return ret, nil
 }
@@ -547,7 +763,7 @@ func (p *protocol) processInteger() (int64, error) {
 func (p *protocol) processError() (interface{}, error) {
 	msg, err := p.is.readLine()
 	if err != nil {
-		return nil, newConnectError(err.Error())
+		return nil, err
 	}
 	if strings.HasPrefix(msg, movedPrefix) {
 		host, port, slot := p.parseTargetHostAndSlot(msg)
@@ -556,15 +772,66 @@ func (p *protocol) processError() (interface{}, error) {
 		host, port, slot := p.parseTargetHostAndSlot(msg)
 		return nil, newAskDataError(msg, host, port, slot)
 	} else if strings.HasPrefix(msg, clusterDownPrefix) {
-		return nil, newClusterError(msg)
+		return nil, newClusterDownError(msg)
 	} else if strings.HasPrefix(msg, busyPrefix) {
 		return nil, newBusyError(msg)
 	} else if strings.HasPrefix(msg, noscriptPrefix) {
 		return nil, newNoScriptError(msg)
+	} else if strings.HasPrefix(msg, readOnlyPrefix) {
+		return nil, newReadOnlyError(msg)
+	} else if strings.HasPrefix(msg, loadingPrefix) {
+		return nil, newLoadingError(msg)
+	} else if strings.HasPrefix(msg, noAuthPrefix) || strings.HasPrefix(msg, wrongPassPrefix) {
+		return nil, newAuthError(msg)
 	}
 	return nil, newDataError(msg)
 }
 
+//readFullResync read the +FULLRESYNC reply of PSYNC,return the replication id and offset of the snapshot
+func (p *protocol) readFullResync() (string, int64, error) {
+	reply, err := p.read()
+	if err != nil {
+		return "", 0, err
+	}
+	status, _ := reply.([]byte)
+	parts := strings.Fields(string(status))
+	if len(parts) != 3 || parts[0] != "FULLRESYNC" {
+		return "", 0, newDataError(fmt.Sprintf("unexpected psync reply:%s", reply))
+	}
+	offset, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", 0, newDataError("unexpected psync offset:" + parts[2])
+	}
+	return parts[1], offset, nil
+}
+
+//readSyncPayload copy the RDB payload sent after +FULLRESYNC to w,
+//the newlines sent by the master while it creates the snapshot are skipped
+func (p *protocol) readSyncPayload(w io.Writer) (int64, error) {
+	for {
+		b, err := p.is.readByte()
+		if err != nil {
+			return 0, err
+		}
+		if b == '\n' {
+			continue
+		}
+		if b != dollarByte {
+			return 0, newProtocolError(fmt.Sprintf("Unknown rdb payload: %b", b))
+		}
+		break
+	}
+	line, err := p.is.readLine()
+	if err != nil {
+		return 0, err
+	}
+	size, err := strconv.ParseInt(line, 10, 64)
+	if err != nil {
+		return 0, newProtocolError("unexpected rdb payload size:" + line)
+	}
+	return p.is.copyN(w, size)
+}
+
 func (p *protocol) parseTargetHostAndSlot(clusterRedirectResponse string) (string, int, int) {
 	arr := strings.Split(clusterRedirectResponse, " ")
 	host, port := p.extractParts(arr[2])
@@ -588,7 +855,8 @@ func (p *protocol) extractParts(from string) (string, string) {
 
 // redis protocol command
 type protocolCommand struct {
-	name string // name of command
+	name string       // name of command
+	spec *CommandSpec // spec of command,nil if the command is unknown
 }
 
 // getRaw get name byte array
@@ -597,7 +865,80 @@ func (p protocolCommand) getRaw() []byte {
 }
 
 func newProtocolCommand(name string) protocolCommand {
-	return protocolCommand{name}
+	return protocolCommand{name: name, spec: commandSpecs[strings.ToUpper(name)]}
+}
+
+//checkArity check the number of arguments against the spec before sending,excluding the command name
+func (p protocolCommand) checkArity(argCount int) error {
+	if p.spec == nil {
+		return nil
+	}
+	return p.spec.CheckArity(argCount)
+}
+
+//idempotent whether the command is safe to retry after an ambiguous failure,unknown commands are not
+func (p protocolCommand) idempotent() bool {
+	return p.spec != nil && p.spec.Idempotent
+}
+
+//Command a user defined redis command,such as a module command or a command renamed by rename-command,
+//create it with RegisterCommand and send it with Redis.SendCommand
+type Command struct {
+	protocolCommand
+	arity int
+}
+
+//Name the command name
+func (c *Command) Name() string {
+	return c.name
+}
+
+//Arity the number of arguments including the command name,same as COMMAND INFO,
+//a negative arity means at least -arity arguments,0 means no validation
+func (c *Command) Arity() int {
+	return c.arity
+}
+
+//checkArity check the number of arguments,excluding the command name
+func (c *Command) checkArity(argCount int) error {
+	n := argCount + 1
+	if (c.arity > 0 && n != c.arity) || (c.arity < 0 && n < -c.arity) {
+		return newDataError(fmt.Sprintf("ERR wrong number of arguments for '%s' command", c.name))
+	}
+	return nil
+}
+
+var commandRegistry = struct {
+	sync.RWMutex
+	commands map[string]*Command
+}{commands: make(map[string]*Command)}
+
+//RegisterCommand register a command once and reuse it everywhere,names are case insensitive,
+//registering the same name again returns the existing command if the arity is the same,otherwise an error
+func RegisterCommand(name string, arity int) (*Command, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return nil, newDataError("command name is empty")
+	}
+	commandRegistry.Lock()
+	defer commandRegistry.Unlock()
+	if cmd, ok := commandRegistry.commands[name]; ok {
+		if cmd.arity != arity {
+			return nil, newDataError(fmt.Sprintf("command %s is already registered with arity %d", name, cmd.arity))
+		}
+		return cmd, nil
+	}
+	cmd := &Command{protocolCommand: newProtocolCommand(name), arity: arity}
+	commandRegistry.commands[name] = cmd
+	return cmd, nil
+}
+
+//LookupCommand find a registered command by name
+func LookupCommand(name string) (*Command, bool) {
+	commandRegistry.RLock()
+	defer commandRegistry.RUnlock()
+	cmd, ok := commandRegistry.commands[strings.ToUpper(strings.TrimSpace(name))]
+	return cmd, ok
 }
 
 var (
@@ -623,6 +964,7 @@ var (
 	cmdMove                = newProtocolCommand("MOVE")
 	cmdFlushAll            = newProtocolCommand("FLUSHALL")
 	cmdGetSet              = newProtocolCommand("GETSET")
+	cmdGetDel              = newProtocolCommand("GETDEL")
 	cmdMGet                = newProtocolCommand("MGET")
 	cmdSetNx               = newProtocolCommand("SETNX")
 	cmdSetEx               = newProtocolCommand("SETEX")
@@ -654,6 +996,7 @@ var (
 	cmdLIndex              = newProtocolCommand("LINDEX")
 	cmdLSet                = newProtocolCommand("LSET")
 	cmdLRem                = newProtocolCommand("LREM")
+	cmdLPos                = newProtocolCommand("LPOS")
 	cmdLPop                = newProtocolCommand("LPOP")
 	cmdRPop                = newProtocolCommand("RPOP")
 	cmdRPopLPush           = newProtocolCommand("RPOPLPUSH")
@@ -666,6 +1009,7 @@ var (
 	cmdSIsMember           = newProtocolCommand("SISMEMBER")
 	cmdSInter              = newProtocolCommand("SINTER")
 	cmdSInterStore         = newProtocolCommand("SINTERSTORE")
+	cmdSInterCard          = newProtocolCommand("SINTERCARD")
 	cmdSUnion              = newProtocolCommand("SUNION")
 	cmdSUnionStore         = newProtocolCommand("SUNIONSTORE")
 	cmdSDiff               = newProtocolCommand("SDIFF")
@@ -694,6 +1038,9 @@ var (
 	cmdUnSubscribe         = newProtocolCommand("UNSUBSCRIBE")
 	cmdPSubscribe          = newProtocolCommand("PSUBSCRIBE")
 	cmdPUnSubscribe        = newProtocolCommand("PUNSUBSCRIBE")
+	cmdSPublish            = newProtocolCommand("SPUBLISH")
+	cmdSSubscribe          = newProtocolCommand("SSUBSCRIBE")
+	cmdSUnSubscribe        = newProtocolCommand("SUNSUBSCRIBE")
 	cmdPubSub              = newProtocolCommand("PUBSUB")
 	cmdZCount              = newProtocolCommand("ZCOUNT")
 	cmdZRangeByScore       = newProtocolCommand("ZRANGEBYSCORE")
@@ -702,6 +1049,17 @@ var (
 	cmdZRemRangeByScore    = newProtocolCommand("ZREMRANGEBYSCORE")
 	cmdZUnionStore         = newProtocolCommand("ZUNIONSTORE")
 	cmdZInterStore         = newProtocolCommand("ZINTERSTORE")
+	cmdZDiff               = newProtocolCommand("ZDIFF")
+	cmdZDiffStore          = newProtocolCommand("ZDIFFSTORE")
+	cmdZUnion              = newProtocolCommand("ZUNION")
+	cmdZInter              = newProtocolCommand("ZINTER")
+	cmdZInterCard          = newProtocolCommand("ZINTERCARD")
+	cmdZPopMin             = newProtocolCommand("ZPOPMIN")
+	cmdZPopMax             = newProtocolCommand("ZPOPMAX")
+	cmdBZPopMin            = newProtocolCommand("BZPOPMIN")
+	cmdBZPopMax            = newProtocolCommand("BZPOPMAX")
+	cmdBZMPop              = newProtocolCommand("BZMPOP")
+	cmdBLMPop              = newProtocolCommand("BLMPOP")
 	cmdZLexCount           = newProtocolCommand("ZLEXCOUNT")
 	cmdZRangeByLex         = newProtocolCommand("ZRANGEBYLEX")
 	cmdZRevRangeByLex      = newProtocolCommand("ZREVRANGEBYLEX")
@@ -717,6 +1075,8 @@ var (
 	cmdConfig              = newProtocolCommand("CONFIG")
 	cmdStrLen              = newProtocolCommand("STRLEN")
 	cmdSync                = newProtocolCommand("SYNC")
+	cmdPSync               = newProtocolCommand("PSYNC")
+	cmdReplConf            = newProtocolCommand("REPLCONF")
 	cmdLPushX              = newProtocolCommand("LPUSHX")
 	cmdPersist             = newProtocolCommand("PERSIST")
 	cmdRPushX              = newProtocolCommand("RPUSHX")
@@ -731,6 +1091,8 @@ var (
 	cmdGetRange            = newProtocolCommand("GETRANGE")
 	cmdEval                = newProtocolCommand("EVAL")
 	cmdEvalSha             = newProtocolCommand("EVALSHA")
+	cmdEvalRo              = newProtocolCommand("EVAL_RO")
+	cmdEvalShaRo           = newProtocolCommand("EVALSHA_RO")
 	cmdScript              = newProtocolCommand("SCRIPT")
 	cmdSlowLog             = newProtocolCommand("SLOWLOG")
 	cmdObject              = newProtocolCommand("OBJECT")
@@ -758,6 +1120,7 @@ var (
 	cmdPfAdd               = newProtocolCommand("PFADD")
 	cmdPfCount             = newProtocolCommand("PFCOUNT")
 	cmdPfMerge             = newProtocolCommand("PFMERGE")
+	cmdPfDebug             = newProtocolCommand("PFDEBUG")
 	cmdReadonly            = newProtocolCommand("READONLY")
 	cmdGeoAdd              = newProtocolCommand("GEOADD")
 	cmdGeoDist             = newProtocolCommand("GEODIST")
@@ -769,9 +1132,18 @@ var (
 	cmdGeoRadiusByMemberRo = newProtocolCommand("GEORADIUSBYMEMBER_RO")
 	cmdModule              = newProtocolCommand("MODULE")
 	cmdBitField            = newProtocolCommand("BITFIELD")
+	cmdBitFieldRo          = newProtocolCommand("BITFIELD_RO")
 	cmdHStrLen             = newProtocolCommand("HSTRLEN")
+	cmdHExpire             = newProtocolCommand("HEXPIRE")
+	cmdHPExpire            = newProtocolCommand("HPEXPIRE")
+	cmdHExpireAt           = newProtocolCommand("HEXPIREAT")
+	cmdHPersist            = newProtocolCommand("HPERSIST")
+	cmdHTTL                = newProtocolCommand("HTTL")
+	cmdHPTTL               = newProtocolCommand("HPTTL")
 	cmdTouch               = newProtocolCommand("TOUCH")
 	cmdSwapDB              = newProtocolCommand("SWAPDB")
+	cmdReset               = newProtocolCommand("RESET")
+	cmdLolwut              = newProtocolCommand("LOLWUT")
 	cmdMemory              = newProtocolCommand("MEMORY")
 	cmdXAdd                = newProtocolCommand("XADD")
 	cmdXLen                = newProtocolCommand("XLEN")
@@ -785,6 +1157,7 @@ var (
 	cmdXReadGroup          = newProtocolCommand("XREADGROUP")
 	cmdXPending            = newProtocolCommand("XPENDING")
 	cmdXClaim              = newProtocolCommand("XCLAIM")
+	cmdXAutoClaim          = newProtocolCommand("XAUTOCLAIM")
 )
 
 // redis keyword
@@ -822,11 +1195,15 @@ var (
 	keywordStore        = newKeyword("STORE")
 	keywordSubscribe    = newKeyword("SUBSCRIBE")
 	keywordUnsubscribe  = newKeyword("UNSUBSCRIBE")
+	keywordSMessage     = newKeyword("SMESSAGE")
+	keywordSSubscribe   = newKeyword("SSUBSCRIBE")
+	keywordSUnsubscribe = newKeyword("SUNSUBSCRIBE")
 	keywordWeights      = newKeyword("WEIGHTS")
 	keywordWithScores   = newKeyword("WITHSCORES")
 	keywordResetStat    = newKeyword("RESETSTAT")
 	keywordRewrite      = newKeyword("REWRITE")
 	keywordReset        = newKeyword("RESET")
+	keywordVersion      = newKeyword("VERSION")
 	keywordFlush        = newKeyword("FLUSH")
 	keywordExists       = newKeyword("EXISTS")
 	keywordLoad         = newKeyword("LOAD")
@@ -835,11 +1212,16 @@ var (
 	keywordRefCount     = newKeyword("REFCOUNT")
 	keywordEncoding     = newKeyword("ENCODING")
 	keywordIdleTime     = newKeyword("IDLETIME")
+	keywordUsage        = newKeyword("USAGE")
+	keywordSamples      = newKeyword("SAMPLES")
 	keywordGetName      = newKeyword("GETNAME")
 	keywordSetName      = newKeyword("SETNAME")
 	keywordList         = newKeyword("LIST")
 	keywordMatch        = newKeyword("MATCH")
 	keywordCount        = newKeyword("COUNT")
+	keywordType         = newKeyword("TYPE")
+	keywordNoValues     = newKeyword("NOVALUES")
+	keywordFields       = newKeyword("FIELDS")
 	keywordPing         = newKeyword("PING")
 	keywordPong         = newKeyword("PONG")
 	keywordUnload       = newKeyword("UNLOAD")
@@ -857,9 +1239,22 @@ var (
 	keywordDestroy      = newKeyword("DESTROY")
 	keywordDelConsumer  = newKeyword("DELCONSUMER")
 	keywordMaxLen       = newKeyword("MAXLEN")
+	keywordRank         = newKeyword("RANK")
 	keywordGroup        = newKeyword("GROUP")
 	keywordIdle         = newKeyword("IDLE")
 	keywordTime         = newKeyword("TIME")
 	keywordRetryCount   = newKeyword("RETRYCOUNT")
 	keywordForce        = newKeyword("FORCE")
+	keywordNoSave       = newKeyword("NOSAVE")
+	keywordSave         = newKeyword("SAVE")
+	keywordNow          = newKeyword("NOW")
+	keywordIncrBy       = newKeyword("INCRBY")
+	keywordOverflow     = newKeyword("OVERFLOW")
+	keywordID           = newKeyword("ID")
+	keywordAddr         = newKeyword("ADDR")
+	keywordLAddr        = newKeyword("LADDR")
+	keywordUser         = newKeyword("USER")
+	keywordMaxAge       = newKeyword("MAXAGE")
+	keywordSkipMe       = newKeyword("SKIPME")
+	keywordYes          = newKeyword("YES")
 )
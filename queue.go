@@ -0,0 +1,182 @@
+package godis
+
+import (
+	"time"
+)
+
+const defaultConsumerTTL = 30 * time.Second
+
+//QueueOption reliable queue options
+type QueueOption struct {
+	Name        string        //the list holding pending items
+	Consumer    string        //name of the consumer,its processing list is Name+":processing:"+Consumer
+	PopTimeout  int           //seconds BlockingPop waits for an item,default 1
+	ConsumerTTL time.Duration //a consumer without BlockingPop or Ack for ConsumerTTL is regarded as crashed,default 30 seconds
+}
+
+//Queue reliable fifo queue built on lists,
+//BlockingPop moves an item to the processing list of the consumer atomically,
+//and the item stays there until it is acknowledged by Ack.
+//
+//every consumer refreshes a heartbeat key when it pops or acknowledges,
+//Recover moves the unacknowledged items of the consumers whose heartbeat expired back to the queue,
+//so an item may be delivered more than once
+type Queue struct {
+	pool   *Pool
+	option QueueOption
+}
+
+//NewQueue create new queue
+func NewQueue(pool *Pool, option *QueueOption) *Queue {
+	opt := *option
+	if opt.PopTimeout <= 0 {
+		opt.PopTimeout = defaultPopTimeout
+	}
+	if opt.ConsumerTTL < time.Second {
+		opt.ConsumerTTL = defaultConsumerTTL
+	}
+	return &Queue{pool: pool, option: opt}
+}
+
+//Push append items to the tail of the queue,return the length of the queue
+func (q *Queue) Push(items ...string) (int64, error) {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	//the head of the queue is the right end,which BRPOPLPUSH pops from
+	err = redis.client.sendCommand(cmdLPush, StrStrArrToByteArrArr(q.option.Name, items)...)
+	if err != nil {
+		return 0, err
+	}
+	return redis.client.getIntegerReply()
+}
+
+//BlockingPop move the head item of the queue to the processing list of the consumer,
+//return false if no item arrives within PopTimeout
+func (q *Queue) BlockingPop() (string, bool, error) {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return "", false, err
+	}
+	defer redis.Close()
+	if err := q.heartbeat(redis); err != nil {
+		return "", false, err
+	}
+	redis.client.setBlockingTimeout(time.Duration(q.option.PopTimeout) * time.Second)
+	defer redis.client.clearBlockingTimeout()
+	err = redis.client.brpoplpush(q.option.Name, q.processingKey(q.option.Consumer), q.option.PopTimeout)
+	if err != nil {
+		return "", false, err
+	}
+	//read the raw reply,so an empty item can be told from timeout
+	reply, err := redis.client.getOne()
+	if err != nil {
+		return "", false, err
+	}
+	item, _ := reply.([]byte)
+	if item == nil {
+		return "", false, nil
+	}
+	return string(item), true, nil
+}
+
+//Ack remove the handled item from the processing list of the consumer
+func (q *Queue) Ack(item string) error {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	if err := q.heartbeat(redis); err != nil {
+		return err
+	}
+	_, err = redis.LRem(q.processingKey(q.option.Consumer), 1, item)
+	return err
+}
+
+//Processing return the items popped by the consumer but not acknowledged yet
+func (q *Queue) Processing() ([]string, error) {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.LRange(q.processingKey(q.option.Consumer), 0, -1)
+}
+
+//Recover scan the consumers of the queue,move the items left by the consumers whose heartbeat expired
+//back to the queue,they are delivered after the items already in the queue
+//
+//return the count of items moved back
+func (q *Queue) Recover() (int64, error) {
+	redis, err := q.pool.GetResource()
+	if err != nil {
+		return 0, err
+	}
+	defer redis.Close()
+	consumers, err := redis.SMembers(q.consumersKey())
+	if err != nil {
+		return 0, err
+	}
+	count := int64(0)
+	for _, consumer := range consumers {
+		alive, err := redis.Exists(q.heartbeatKey(consumer))
+		if err != nil {
+			return count, err
+		}
+		if alive > 0 {
+			continue
+		}
+		for {
+			moved, err := q.moveBack(redis, consumer)
+			if err != nil {
+				return count, err
+			}
+			if !moved {
+				break
+			}
+			count++
+		}
+		if _, err := redis.SRem(q.consumersKey(), consumer); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+//moveBack move the oldest item of the processing list of consumer back to the queue atomically
+func (q *Queue) moveBack(redis *Redis, consumer string) (bool, error) {
+	err := redis.client.rpopLpush(q.processingKey(consumer), q.option.Name)
+	if err != nil {
+		return false, err
+	}
+	reply, err := redis.client.getOne()
+	if err != nil {
+		return false, err
+	}
+	item, _ := reply.([]byte)
+	return item != nil, nil
+}
+
+//heartbeat register the consumer and refresh its heartbeat key
+func (q *Queue) heartbeat(redis *Redis) error {
+	if _, err := redis.SAdd(q.consumersKey(), q.option.Consumer); err != nil {
+		return err
+	}
+	_, err := redis.SetEx(q.heartbeatKey(q.option.Consumer), int(q.option.ConsumerTTL/time.Second), "1")
+	return err
+}
+
+func (q *Queue) processingKey(consumer string) string {
+	return q.option.Name + ":processing:" + consumer
+}
+
+func (q *Queue) consumersKey() string {
+	return q.option.Name + ":consumers"
+}
+
+func (q *Queue) heartbeatKey(consumer string) string {
+	return q.option.Name + ":heartbeat:" + consumer
+}
@@ -0,0 +1,85 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	flushAll()
+	pool := NewPool(nil, option)
+	defer pool.Destroy()
+	queue := NewQueue(pool, &QueueOption{Name: "queue", Consumer: "c1"})
+	c, err := queue.Push("a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+	c, err = queue.Push("")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+
+	for _, expected := range []string{"a", "b", ""} {
+		item, ok, err := queue.BlockingPop()
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, expected, item)
+	}
+	_, ok, err := queue.BlockingPop()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	assert.Nil(t, queue.Ack("a"))
+	items, err := queue.Processing()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", "b"}, items)
+
+	//c1 is alive,nothing is recovered
+	other := NewQueue(pool, &QueueOption{Name: "queue", Consumer: "c2"})
+	c, err = other.Recover()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.Del("queue:heartbeat:c1")
+	other.Push("c")
+	c, err = other.Recover()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+	for _, expected := range []string{"c", "b", ""} {
+		item, ok, err := other.BlockingPop()
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, expected, item)
+	}
+	members, err := redis.SMembers("queue:consumers")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c2"}, members)
+	ttl, err := redis.TTL("queue:heartbeat:c2")
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= int64(defaultConsumerTTL/time.Second))
+
+	brokenPool := NewPool(nil, &Option{Host: "localhost1"})
+	defer brokenPool.Destroy()
+	broken := NewQueue(brokenPool, &QueueOption{Name: "queue", Consumer: "c1"})
+	_, err = broken.Push("a")
+	assert.NotNil(t, err)
+	_, _, err = broken.BlockingPop()
+	assert.NotNil(t, err)
+	assert.NotNil(t, broken.Ack("a"))
+	_, err = broken.Processing()
+	assert.NotNil(t, err)
+	_, err = broken.Recover()
+	assert.NotNil(t, err)
+}
+
+func TestQueue_PopTimeoutBeyondSoTimeout(t *testing.T) {
+	flushAll()
+	pool := NewPool(nil, &Option{Host: "localhost", Port: 6379, SoTimeout: 200 * time.Millisecond})
+	defer pool.Destroy()
+	queue := NewQueue(pool, &QueueOption{Name: "queue", Consumer: "c1", PopTimeout: 1})
+	//the pop waits for PopTimeout instead of timing out the read at SoTimeout
+	_, ok, err := queue.BlockingPop()
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
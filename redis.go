@@ -1,19 +1,89 @@
 package godis
 
 import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Option connect options
 type Option struct {
-	Host              string        // redis host
+	Host              string        // redis host,several hosts separated by comma,such as redis-a:6379,redis-b,are dialed in order until one succeeds,the names are resolved by every dial
 	Port              int           // redis port
 	ConnectionTimeout time.Duration // connect timeout
 	SoTimeout         time.Duration // read timeout
+	WriteTimeout      time.Duration // write timeout,if zero,then same as SoTimeout
+	CommandTimeout    time.Duration // time from sending a command to reading its reply,unlike SoTimeout bounding every read,if zero,then no limit
+	KeepAlive         time.Duration // tcp keep-alive period,if zero,then the default period,if negative,then disabled
+	DisableTCPNoDelay bool          // enable Nagle's algorithm,by default TCP_NODELAY is set and small commands are sent without delay
+	Username          string        // redis acl username,if empty,then auth with password only
 	Password          string        // redis password,if empty,then without auth
 	Db                int           // which db to connect
-}
+	LazyConnect       bool          // the pool creates its connections without dialing,they dial on the first command like NewRedis always does
+	ReadOnly          bool          // send READONLY after connecting,required to read from cluster replicas
+	ClientName        string        // set by CLIENT SETNAME after connecting,if empty,then without name
+	Network           string        // tcp or unix,if unix,then Host is the socket path,default tcp
+	TLSConfig         *tls.Config   // connect with tls if not nil
+	Dialer            DialFunc      // dial the connections instead of net.Dialer,such as through a proxy or ssh tunnel,KeepAlive is ignored then
+	ReturnErrNil      bool          // return ErrNil with the zero value for nil replies,such as Get on a missing key
+	BreakOnReadOnly   bool          // mark the connection broken when redis replies READONLY,such as a master demoted by a failover,so a pool dials a new one instead of reusing it
+
+	DisconnectPolicy DisconnectPolicy // what to do with commands when redis is unreachable, default FailFast
+	MaxQueueSize     int              // max commands queued until reconnect with QueueUntilReconnect, default 1000
+	QueueTTL         time.Duration    // how long a command stays queued with QueueUntilReconnect, default 5s
+	BlockTimeout     time.Duration    // how long a command blocks with BlockUntilReconnect, default 5s
+
+	// wait for the replies of blocking commands without deadline,otherwise the read deadline of
+	// BLPOP,BRPOP,BRPOPLPUSH,BZPOPMIN,BZPOPMAX and XREADGROUP BLOCK is the block timeout plus SoTimeout
+	InfiniteBlockingRead bool
+
+	// sample the commands and count the accesses and latency of every key,nil means disabled,see KeyProfiler
+	Profiler *KeyProfiler
+
+	// fail fast with ErrCircuitOpen instead of dialing while redis is down,nil means disabled,see CircuitBreaker
+	CircuitBreaker *CircuitBreaker
+
+	// call OnSlowCommand when a command takes SlowThreshold or longer,measured on the client side,
+	// so the time on the network and waiting for the pool is included,unlike SLOWLOG,0 means disabled
+	SlowThreshold time.Duration
+	OnSlowCommand func(slow SlowCommand) // it must not block
+
+	// query the server version by INFO after connecting,so the commands newer than the server
+	// fail with ErrUnsupportedVersion before sending,instead of an unknown command error,see Redis.ServerVersion
+	DetectVersion bool
+
+	// transform every key before it is sent,such as hashing the keys of a tenant,by the key positions of the commands,
+	// so it applies to pipelines and transactions too,the keys in replies,such as of KEYS,SCAN and BLPOP,are not transformed back
+	KeyMapper KeyMapper
+
+	// check every command before it is sent,in order,the first error fails the command without sending it,
+	// such as DenyCommands("KEYS", "FLUSHALL", "DEBUG") in production,the commands of the handshake aren't checked
+	CommandFilters []CommandFilter
+
+	// called after every successful connection and reconnection,after AUTH,SELECT and CLIENT SETNAME,
+	// such as to load scripts or set the connection state,the connection fails with the returned error.
+	// r shares the connection of the client but not its pipeline or transaction,it must not be closed
+	OnConnect func(r *Redis) error
+	// called after the connection is closed,by Close or when it is evicted from the pool,such as for cleanup or metrics,
+	// r is the same as the one of OnConnect
+	OnConnectionClosed func(r *Redis)
+
+	// fail the commands sent or received while another goroutine is using the connection with ErrConcurrentUse,
+	// instead of interleaving their bytes and corrupting the stream,to find the Redis shared by goroutines by mistake,
+	// it is best effort,only the commands overlapping in time are detected
+	DetectConcurrentUse bool
+}
+
+//KeyMapper transform a key before it is sent,it must return the same key for the same input
+type KeyMapper func(key string) string
+
+//DialFunc dial a connection to addr,the deadline of ctx is the connection timeout,
+//network is tcp or unix,addr is host:port or the socket path
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
 
 // Redis redis client tool
 type Redis struct {
@@ -29,14 +99,76 @@ type Redis struct {
 //NewRedis constructor for creating new redis
 func NewRedis(option *Option) *Redis {
 	client := newClient(option)
+	setConnectionHooks(client, option)
 	return &Redis{client: client}
 }
 
-//Connect connect to redis
+//setConnectionHooks call OnConnect and OnConnectionClosed of the option with a Redis sharing the client,
+//so the hooks run their commands outside the pipeline or transaction which triggered the reconnection
+func setConnectionHooks(client *client, option *Option) {
+	if option.OnConnect == nil && option.OnConnectionClosed == nil {
+		return
+	}
+	hooked := &Redis{client: client}
+	if option.OnConnect != nil {
+		client.onConnect = func() error {
+			return option.OnConnect(hooked)
+		}
+	}
+	if option.OnConnectionClosed != nil {
+		client.connection.onClose = func() {
+			option.OnConnectionClosed(hooked)
+		}
+	}
+}
+
+//Connect connect to redis,when LazyConnect is enabled, the connection is shared with the first command
 func (r *Redis) Connect() error {
+	if r.client.lazyConnect {
+		return r.client.ensureConnected()
+	}
 	return r.client.connect()
 }
 
+//WithTimeout run fn with the command timeout instead of Option.CommandTimeout,
+//every command of fn must be replied within timeout,a pipeline within timeout since its first command,
+//0 timeout means no limit
+//
+//	err := redis.WithTimeout(50*time.Millisecond, func(redis *godis.Redis) error {
+//		value, err = redis.Get("key")
+//		return err
+//	})
+func (r *Redis) WithTimeout(timeout time.Duration, fn func(redis *Redis) error) error {
+	previous := r.client.commandTimeout
+	r.client.commandTimeout = timeout
+	defer func() {
+		r.client.commandTimeout = previous
+	}()
+	return fn(r)
+}
+
+//ConnStats counters of the connection of Redis since it is created,including the reconnections
+type ConnStats struct {
+	Commands     int64 //commands written to the socket
+	Replies      int64 //replies read,including the error replies
+	BytesWritten int64
+	BytesRead    int64
+	Flushes      int64 //writes to the socket,Commands/Flushes is the average count of commands pipelined together
+}
+
+//Stats the counters of the connection,such as to debug the bandwidth or the effect of pipelining,
+//unlike the commands it is safe to call while another goroutine uses r
+func (r *Redis) Stats() ConnStats {
+	stats := &r.client.stats
+	return ConnStats{
+		Commands:     atomic.LoadInt64(&stats.commands),
+		Replies:      atomic.LoadInt64(&stats.replies),
+		BytesWritten: atomic.LoadInt64(&stats.bytesWritten),
+		BytesRead:    atomic.LoadInt64(&stats.bytesRead),
+		Flushes:      atomic.LoadInt64(&stats.flushes),
+	}
+}
+
 //Close close redis connection
 func (r *Redis) Close() error {
 	if r == nil {
@@ -57,27 +189,167 @@ func (r *Redis) Close() error {
 	return nil
 }
 
+//resetState discard the transaction,watched keys,unread pipeline replies and the selected db
+//left on the connection,so it can be reused by the next borrower.
+//a subscribed or broken connection can't be reset,an error is returned
+func (r *Redis) resetState(db int) error {
+	c := r.client
+	if c.isInSubscribe {
+		return newDataError("connection is still subscribed")
+	}
+	if !c.isConnected() {
+		return nil
+	}
+	var err error
+	if c.isInMulti {
+		err = c.discard()
+	} else if c.isInWatch {
+		err = c.unwatch()
+	}
+	if err != nil {
+		return err
+	}
+	if c.pipelinedCommands > 0 {
+		//replies of the pipeline and the DISCARD/UNWATCH above,errors of the commands are ignored
+		if _, err := c.getAll(); err != nil {
+			return err
+		}
+	}
+	if c.Db != db {
+		if err := c.selectDb(db); err != nil {
+			return err
+		}
+		if _, err := c.getStatusCodeReply(); err != nil {
+			return err
+		}
+		c.Db = db
+	}
+	if c.broken {
+		return newConnectError("connection is broken")
+	}
+	return nil
+}
+
 func (r *Redis) setDataSource(pool *Pool) {
 	r.mu.Lock()
 	r.dataSource = pool
 	r.mu.Unlock()
 }
 
+//pool the pool r is borrowed from,nil if r isn't pooled
+func (r *Redis) pool() *Pool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.dataSource
+}
+
 // Send send command to redis
 func (r *Redis) Send(command protocolCommand, args ...[]byte) error {
 	return r.client.sendCommand(command, args...)
 }
 
-// SendByStr send command to redis
+// SendByStr send command to redis,the argument count is checked if the command is registered by RegisterCommand
+// or it is a known command,see LookupCommandSpec
 func (r *Redis) SendByStr(command string, args ...[]byte) error {
+	if cmd, ok := LookupCommand(command); ok {
+		return r.SendCommand(cmd, args...)
+	}
+	if spec, ok := LookupCommandSpec(command); ok {
+		if err := spec.CheckArity(len(args)); err != nil {
+			return err
+		}
+	}
 	return r.client.sendCommandByStr(command, args...)
 }
 
-// Receive receive reply from redis
+// SendCommand send a registered command to redis after checking the argument count
+func (r *Redis) SendCommand(command *Command, args ...[]byte) error {
+	if err := command.checkArity(len(args)); err != nil {
+		return err
+	}
+	return r.client.sendCommand(command.protocolCommand, args...)
+}
+
+//ReplyType the expected type of a reply read by ReceiveTyped
+type ReplyType int
+
+const (
+	//ReplyStatus a status reply,such as OK,received as string
+	ReplyStatus ReplyType = iota
+	//ReplyInteger an integer reply,received as int64
+	ReplyInteger
+	//ReplyBulk a bulk reply,received as string,a nil reply is an empty string
+	ReplyBulk
+	//ReplyBinary a bulk reply,received as []byte,a nil reply is nil
+	ReplyBinary
+	//ReplyMultiBulk a multi bulk reply of bulk replies,received as []string
+	ReplyMultiBulk
+	//ReplyObject any reply,received as it is parsed,nested multi bulk replies are []interface{}
+	ReplyObject
+)
+
+// Receive receive the reply of the oldest command sent by Send,SendByStr or SendCommand,
+// the commands sent are flushed first,so commands can be sent in a batch and their replies received in order,
+// an error reply is returned as the error.
+// return an error without reading if no command is waiting for its reply,instead of blocking until timeout
 func (r *Redis) Receive() (interface{}, error) {
+	if err := r.checkPending(); err != nil {
+		return nil, err
+	}
 	return r.client.getOne()
 }
 
+//ReceiveTyped receive the reply of the oldest command sent like Receive,converted to the type of expect,
+//a reply of another type is read and returned as a DataError,so the replies after it can still be received
+//
+//	redis.Send(cmdIncr, []byte("counter"))
+//	redis.Send(cmdGet, []byte("name"))
+//	n, err := godis.ToInt64Reply(redis.ReceiveTyped(godis.ReplyInteger))
+//	name, err := godis.ToStrReply(redis.ReceiveTyped(godis.ReplyBulk))
+func (r *Redis) ReceiveTyped(expect ReplyType) (interface{}, error) {
+	if err := r.checkPending(); err != nil {
+		return nil, err
+	}
+	switch expect {
+	case ReplyStatus:
+		return r.client.getStatusCodeReply()
+	case ReplyInteger:
+		reply, err := r.client.getOne()
+		if err != nil {
+			return int64(0), err
+		}
+		if n, ok := reply.(int64); ok {
+			return n, nil
+		}
+		if isNilReply(reply) {
+			return int64(-1), r.client.nilReplyError()
+		}
+		return int64(0), newDataError(fmt.Sprintf("data error:%v", reply))
+	case ReplyBulk:
+		return r.client.getBulkReply()
+	case ReplyBinary:
+		return r.client.getBinaryBulkReply()
+	case ReplyMultiBulk:
+		return r.client.getMultiBulkReply()
+	case ReplyObject:
+		return r.client.getOne()
+	}
+	return nil, newDataError(fmt.Sprintf("unknown reply type:%d", expect))
+}
+
+//Pending count of the commands sent whose replies aren't received yet
+func (r *Redis) Pending() int {
+	return r.client.pipelinedCommands
+}
+
+//checkPending whether a command is waiting for its reply
+func (r *Redis) checkPending() error {
+	if r.client.pipelinedCommands <= 0 {
+		return newDataError("no command is waiting for its reply")
+	}
+	return nil
+}
+
 // check current redis is in transaction or pipeline mode
 // if yes,then cannot execute command in redis mode
 func (r *Redis) checkIsInMultiOrPipeline() error {
@@ -253,6 +525,48 @@ func (r *Redis) PTTL(key string) (int64, error) {
 	return r.client.getIntegerReply()
 }
 
+//Dump serialize the value of key in a redis-specific format,the value can be restored by Restore
+//
+//return nil if the key does not exist
+func (r *Redis) Dump(key string) ([]byte, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.dump(key)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getBinaryBulkReply()
+}
+
+//Restore create key with the value serialized by Dump,ttl is in milliseconds,0 means no expire,
+//return an error if key already exists
+func (r *Redis) Restore(key string, ttl int, serializedValue []byte) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.restore(key, ttl, serializedValue)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
+//RestoreReplace same as Restore,but replace key if it already exists
+func (r *Redis) RestoreReplace(key string, ttl int, serializedValue []byte) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.restoreReplace(key, ttl, serializedValue)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
 // SetRange Overwrites part of the string stored at key, starting at the specified offset,
 // for the entire length of value. If the offset is larger than the current length of the string at key,
 // the string is padded with zero-bytes to make offset fit. Non-existing keys are considered as empty strings,
@@ -320,6 +634,21 @@ func (r *Redis) GetSet(key, value string) (string, error) {
 	return r.client.getBulkReply()
 }
 
+//GetDel get the value of key and delete the key,available since redis 6.2
+//
+//return Bulk reply,the value of key,nil if key does not exist
+func (r *Redis) GetDel(key string) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.getDel(key)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getBulkReply()
+}
+
 //SetNx SETNX works exactly like {@link #set(String, String) SET} with the only difference that if the
 //key already exists no operation is performed. SETNX actually means "SET if Not eXists".
 //
@@ -914,6 +1243,44 @@ func (r *Redis) LRem(key string, count int64, value string) (int64, error) {
 	return r.client.getIntegerReply()
 }
 
+//LPos Return the index of the first element equal to element in the list stored at key,
+//scanning from head to tail unless a negative rank is given.
+//
+//return Integer reply, the index of the matching element, or -1 if there is no match
+func (r *Redis) LPos(key, element string, params ...*LPosParams) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.lpos(key, element, params...)
+	if err != nil {
+		return 0, err
+	}
+	reply, err := r.client.getOne()
+	if err != nil {
+		return 0, err
+	}
+	if pos, ok := reply.(int64); ok {
+		return pos, nil
+	}
+	return -1, nil
+}
+
+//LPosCount same as LPos,but return the indexes of at most count matching elements,0 means all matches
+//
+//return Multi bulk reply, the indexes of the matching elements
+func (r *Redis) LPosCount(key, element string, count int64, params ...*LPosParams) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.lposCount(key, element, count, params...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
 //LPop Atomically return and remove the first (LPOP) or last (RPOP) element of the list. For example
 //if the list contains the elements "a","b","c" LPOP will return "a" and the list will become
 //"b","c".
@@ -952,6 +1319,37 @@ func (r *Redis) RPop(key string) (string, error) {
 	return r.client.getBulkReply()
 }
 
+//LPopCount remove and return at most count elements from the head of the list,available since redis 6.2
+//
+//return Multi bulk reply, the popped elements,empty if the key does not exist
+func (r *Redis) LPopCount(key string, count int64) ([]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.lpopCount(key, count)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getMultiBulkReply()
+}
+
+//RPopCount remove and return at most count elements from the tail of the list,the last element first,
+//available since redis 6.2
+//
+//return Multi bulk reply, the popped elements,empty if the key does not exist
+func (r *Redis) RPopCount(key string, count int64) ([]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.rpopCount(key, count)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getMultiBulkReply()
+}
+
 //SAdd Add the specified member to the set value stored at key. If member is already a member of the
 //set no operation is performed. If key does not exist a new set with the specified member as
 //sole member is created. If the key exists but does not hold a set value an error is returned.
@@ -985,6 +1383,21 @@ func (r *Redis) SMembers(key string) ([]string, error) {
 	return r.client.getMultiBulkReply()
 }
 
+//SMembersMap same as SMembers, but return the members as a set for membership checks
+//
+//return Multi bulk reply as map
+func (r *Redis) SMembersMap(key string) (map[string]struct{}, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.sMembers(key)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getMultiBulkSetReply()
+}
+
 //SRem Remove the specified member from the set value stored at key. If member was not a member of the
 //set no operation is performed. If key does not hold a set value an error is returned.
 //
@@ -1021,7 +1434,7 @@ func (r *Redis) SPop(key string) (string, error) {
 	return r.client.getBulkReply()
 }
 
-//SPopBatch remove multi random element
+//SPopBatch remove multi random element,the count must not be negative
 // see SPop(key string)
 func (r *Redis) SPopBatch(key string, count int64) ([]string, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -1105,6 +1518,26 @@ func (r *Redis) SInterStore(destKey string, srcKeys ...string) (int64, error) {
 	return r.client.getIntegerReply()
 }
 
+//SInterCard return the cardinality of the intersection of the sets hold at the specified keys,available since redis 7.0,
+//the count stops at limit,0 means unlimited
+func (r *Redis) SInterCard(limit int64, keys ...string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.sInterCard(limit, keys...)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//EstimateIntersection return the cardinality of the intersection of the sets hold at the specified keys,
+//it uses SINTERCARD,and falls back to SINTERSTORE into a temp key then SCARD on servers older than 7.0
+func (r *Redis) EstimateIntersection(keys ...string) (int64, error) {
+	return estimateIntersection(r, keys...)
+}
+
 //SUnion Return the members of a set resulting from the union of all the sets hold at the specified
 //keys. Like in {@link #lrange(String, long, long) LRANGE} the result is sent to the client as a
 //multi-bulk reply (see the protocol specification for more information). If just a single key is
@@ -1384,6 +1817,44 @@ func (r *Redis) ZScore(key, member string) (float64, error) {
 	return StrToFloat64Reply(r.client.getBulkReply())
 }
 
+//ZPopMin Removes and returns up to count members with the lowest scores in the sorted set stored at key.
+//When left unspecified, the default value for count is 1.
+// Specifying a count value that is higher than the sorted set's cardinality will not produce an error.
+// When returning multiple elements, the one with the lowest score will be the first, followed by the elements with greater scores.
+//
+//Return value
+//Array reply: list of popped elements and scores.
+func (r *Redis) ZPopMin(key string, count ...int64) ([]Tuple, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zPopMin(key, count...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToTupleReply(r.client.getMultiBulkReply())
+}
+
+//ZPopMax Removes and returns up to count members with the highest scores in the sorted set stored at key.
+//When left unspecified, the default value for count is 1.
+// Specifying a count value that is higher than the sorted set's cardinality will not produce an error.
+// When returning multiple elements, the one with the highest score will be the first, followed by the elements with lower scores.
+//
+//Return value
+//Array reply: list of popped elements and scores.
+func (r *Redis) ZPopMax(key string, count ...int64) ([]Tuple, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zPopMax(key, count...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToTupleReply(r.client.getMultiBulkReply())
+}
+
 //Watch Marks the given keys to be watched for conditional execution of a transaction.
 //
 //Return value
@@ -1784,6 +2255,9 @@ func (r *Redis) PSetEx(key string, milliseconds int64, value string) (string, er
 }
 
 //SRandMemberBatch see SRandMember(key string)
+//
+//if count is positive, return an array of distinct elements,
+//if count is negative, the same element may be returned multiple times and the length of result is the absolute value of count
 func (r *Redis) SRandMemberBatch(key string, count int) ([]string, error) {
 	err := r.checkIsInMultiOrPipeline()
 	if err != nil {
@@ -2070,9 +2544,10 @@ func (r *Redis) BitCount(key string) (int64, error) {
 	return r.client.getIntegerReply()
 }
 
-//BitCountRange see BitCount()
-func (r *Redis) BitCountRange(key string, start, end int64) (int64, error) {
-	err := r.client.bitcountRange(key, start, end)
+//BitCountRange see BitCount(),
+//the range is indexed by byte,or by bit if unit is BitUnitBit since redis 7.0
+func (r *Redis) BitCountRange(key string, start, end int64, unit ...*BitUnit) (int64, error) {
+	err := r.client.bitcountRange(key, start, end, unit...)
 	if err != nil {
 		return 0, err
 	}
@@ -2080,6 +2555,7 @@ func (r *Redis) BitCountRange(key string, start, end int64) (int64, error) {
 }
 
 //BitPos Return the position of the first bit set to 1 or 0 in a string.
+//params limit the range to search,such as NewBitPosParams(0).End(7).Unit(BitUnitBit)
 func (r *Redis) BitPos(key string, value bool, params ...*BitPosParams) (int64, error) {
 	err := r.client.bitpos(key, value, params...)
 	if err != nil {
@@ -2101,6 +2577,92 @@ func (r *Redis) HScan(key, cursor string, params ...*ScanParams) (*ScanResult, e
 	return ObjArrToScanResultReply(r.client.getObjectMultiBulkReply())
 }
 
+//HExpire set a timeout in seconds on the fields of the hash,the fields are deleted after the timeout.
+//the optional condition is one of ExpireNX,ExpireXX,ExpireGT and ExpireLT,available since Redis 7.4
+//return Array reply,an integer for each field in order:
+//-2 if the field or the key doesn't exist,0 if the condition isn't met,
+//1 if the timeout is set,2 if the field is deleted at once because the timeout is 0 or in the past
+func (r *Redis) HExpire(key string, seconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.hexpire(key, seconds, fields, condition...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
+//HPExpire works exactly like HExpire but the timeout is in milliseconds
+func (r *Redis) HPExpire(key string, milliseconds int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.hpexpire(key, milliseconds, fields, condition...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
+//HExpireAt works exactly like HExpire but the timeout is an absolute unix time in seconds
+func (r *Redis) HExpireAt(key string, unixTime int64, fields []string, condition ...*ExpireCondition) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.hexpireAt(key, unixTime, fields, condition...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
+//HPersist remove the timeout of the fields of the hash
+//return Array reply,an integer for each field in order:
+//-2 if the field or the key doesn't exist,-1 if the field has no timeout,1 if the timeout is removed
+func (r *Redis) HPersist(key string, fields ...string) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.hpersist(key, fields...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
+//HTtl the remaining time to live in seconds of the fields of the hash
+//return Array reply,an integer for each field in order:
+//-2 if the field or the key doesn't exist,-1 if the field has no timeout,otherwise the remaining seconds
+func (r *Redis) HTtl(key string, fields ...string) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.httl(key, fields...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
+//HPTtl works exactly like HTtl but the remaining time is in milliseconds
+func (r *Redis) HPTtl(key string, fields ...string) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.hpttl(key, fields...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
 //SScan scan keys of set,see scan
 func (r *Redis) SScan(key, cursor string, params ...*ScanParams) (*ScanResult, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -2241,6 +2803,7 @@ func (r *Redis) GeoRadiusByMember(key, member string, radius float64, unit *GeoU
 
 //BitField The command treats a Redis string as a array of bits,
 // and is capable of addressing specific integer fields of varying bit widths and arbitrary non (necessary) aligned offset.
+//BitFieldWithArgs is preferred,it builds the arguments and keeps the nil results of OVERFLOW FAIL
 func (r *Redis) BitField(key string, arguments ...string) ([]int64, error) {
 	err := r.checkIsInMultiOrPipeline()
 	if err != nil {
@@ -2253,7 +2816,44 @@ func (r *Redis) BitField(key string, arguments ...string) ([]int64, error) {
 	return r.client.getIntegerMultiBulkReply()
 }
 
-//</editor-fold>
+//BitFieldWithArgs execute the operations of args on the string at key in order,
+//return a result for every GET,SET and INCRBY,the result is nil if the operation isn't executed because of OverflowFail
+func (r *Redis) BitFieldWithArgs(key string, args *BitFieldArgs) ([]*int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	if args.err != nil {
+		return nil, args.err
+	}
+	err = r.client.bitfield(key, args.args...)
+	if err != nil {
+		return nil, err
+	}
+	return ObjArrToNullableInt64Reply(r.client.getObjectMultiBulkReply())
+}
+
+//BitFieldRo the read only variant of BITFIELD,available since redis 6.0,
+//it can be sent to replicas,args must only contain GET operations
+func (r *Redis) BitFieldRo(key string, args *BitFieldArgs) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	if args.err != nil {
+		return nil, args.err
+	}
+	if !args.readOnly {
+		return nil, newDataError("ERR BITFIELD_RO only supports the GET subcommand")
+	}
+	err = r.client.bitfieldRo(key, args.args...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
+//</editor-fold>
 
 //<editor-fold desc="multikeycommands">
 
@@ -2539,6 +3139,8 @@ func (r *Redis) BLPopTimeout(timeout int, keys ...string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	r.client.setBlockingTimeout(time.Duration(timeout) * time.Second)
+	defer r.client.clearBlockingTimeout()
 	err = r.client.blpopTimout(timeout, keys...)
 	if err != nil {
 		return nil, err
@@ -2552,6 +3154,8 @@ func (r *Redis) BRPopTimeout(timeout int, keys ...string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	r.client.setBlockingTimeout(time.Duration(timeout) * time.Second)
+	defer r.client.clearBlockingTimeout()
 	err = r.client.brpopTimout(timeout, keys...)
 	if err != nil {
 		return nil, err
@@ -2559,6 +3163,95 @@ func (r *Redis) BRPopTimeout(timeout int, keys ...string) ([]string, error) {
 	return r.client.getMultiBulkReply()
 }
 
+//BZPopMin BZPOPMIN is the blocking variant of the sorted set ZPOPMIN primitive.
+//It is the blocking version because it blocks the connection when there are no members to pop from any of the given sorted sets.
+// A member with the lowest score is popped from first sorted set that is non-empty,
+// with the given keys being checked in the order that they are given.
+//The timeout argument is interpreted as an integer value specifying the maximum number of seconds to block.
+// A timeout of zero can be used to block indefinitely.
+//
+//Return value
+//the key where the member was popped, the popped member and its score,
+// nil when no element could be popped and the timeout expired.
+func (r *Redis) BZPopMin(timeout int, keys ...string) (*KeyedTuple, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	r.client.setBlockingTimeout(time.Duration(timeout) * time.Second)
+	defer r.client.clearBlockingTimeout()
+	err = r.client.bzPopMin(timeout, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToKeyedTupleReply(r.client.getMultiBulkReply())
+}
+
+//BZPopMax BZPOPMAX is the blocking variant of the sorted set ZPOPMAX primitive.
+//It is the blocking version because it blocks the connection when there are no members to pop from any of the given sorted sets.
+// A member with the highest score is popped from first sorted set that is non-empty,
+// with the given keys being checked in the order that they are given.
+//The timeout argument is interpreted as an integer value specifying the maximum number of seconds to block.
+// A timeout of zero can be used to block indefinitely.
+//
+//Return value
+//the key where the member was popped, the popped member and its score,
+// nil when no element could be popped and the timeout expired.
+func (r *Redis) BZPopMax(timeout int, keys ...string) (*KeyedTuple, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	r.client.setBlockingTimeout(time.Duration(timeout) * time.Second)
+	defer r.client.clearBlockingTimeout()
+	err = r.client.bzPopMax(timeout, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToKeyedTupleReply(r.client.getMultiBulkReply())
+}
+
+//BLMPop BLMPOP,available since redis 7.0,pop at most count elements from direction,PopLeft or PopRight,
+//of the first non-empty list of keys,it blocks at most timeout seconds while all the lists are empty,
+//0 blocks indefinitely,a count not positive pops one element.
+//
+//Return value
+//the key where the elements were popped and the elements,
+// nil when no element could be popped and the timeout expired.
+func (r *Redis) BLMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedElements, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	r.client.setBlockingTimeout(time.Duration(timeout) * time.Second)
+	defer r.client.clearBlockingTimeout()
+	err = r.client.blmPop(timeout, direction, count, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return ObjArrToKeyedElementsReply(r.client.getObjectMultiBulkReply())
+}
+
+//BZMPop BZMPOP,available since redis 7.0,pop at most count members with the lowest or highest scores,PopMin or PopMax,
+//from the first non-empty sorted set of keys,it blocks like BLMPop.
+//
+//Return value
+//the key where the members were popped and the members with their scores,
+// nil when no member could be popped and the timeout expired.
+func (r *Redis) BZMPop(timeout int, direction *PopDirection, count int64, keys ...string) (*KeyedTuples, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	r.client.setBlockingTimeout(time.Duration(timeout) * time.Second)
+	defer r.client.clearBlockingTimeout()
+	err = r.client.bzmPop(timeout, direction, count, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return ObjArrToKeyedTuplesReply(r.client.getObjectMultiBulkReply())
+}
+
 //BLPop BLPOP (and BRPOP) is a blocking list pop primitive. You can see this commands as blocking
 //versions of LPOP and RPOP able to block if the specified keys don't exist or contain empty
 //lists.
@@ -2620,6 +3313,8 @@ func (r *Redis) BLPop(args ...string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	r.client.setBlockingTimeout(parseBlockTimeout(args))
+	defer r.client.clearBlockingTimeout()
 	err = r.client.blpop(args)
 	if err != nil {
 		return nil, err
@@ -2633,6 +3328,8 @@ func (r *Redis) BRPop(args ...string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	r.client.setBlockingTimeout(parseBlockTimeout(args))
+	defer r.client.clearBlockingTimeout()
 	err = r.client.brpop(args)
 	if err != nil {
 		return nil, err
@@ -2693,12 +3390,145 @@ func (r *Redis) ZUnionStoreWithParams(destKey string, params *ZParams, srcKeys .
 	return r.client.getIntegerReply()
 }
 
+//ZDiff This command is similar to ZDIFFSTORE, but instead of storing the resulting sorted set,
+// it is returned to the client.
+//
+//Return value
+//Array reply: the result of the difference.
+func (r *Redis) ZDiff(keys ...string) ([]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zdiff(false, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getMultiBulkReply()
+}
+
+//ZDiffWithScores see ZDiff(), the result contains the scores of the elements
+func (r *Redis) ZDiffWithScores(keys ...string) ([]Tuple, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zdiff(true, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToTupleReply(r.client.getMultiBulkReply())
+}
+
+//ZDiffStore Computes the difference between the first and all successive input sorted sets
+// and stores the result in destination. The total number of input keys is specified by numkeys.
+//Keys that do not exist are considered to be empty sets.
+//If destination already exists, it is overwritten.
+//
+//Return value
+//Integer reply: the number of elements in the resulting sorted set at destination.
+func (r *Redis) ZDiffStore(destKey string, srcKeys ...string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.zdiffstore(destKey, srcKeys...)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//ZUnion This command is similar to ZUNIONSTORE, but instead of storing the resulting sorted set,
+// it is returned to the client.
+// params is optional,use it to set WEIGHTS and AGGREGATE options, it can be nil.
+//
+//Return value
+//Array reply: the result of union.
+func (r *Redis) ZUnion(params *ZParams, keys ...string) ([]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zunion(params, false, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getMultiBulkReply()
+}
+
+//ZUnionWithScores see ZUnion(), the result contains the scores of the elements
+func (r *Redis) ZUnionWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zunion(params, true, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToTupleReply(r.client.getMultiBulkReply())
+}
+
+//ZInter This command is similar to ZINTERSTORE, but instead of storing the resulting sorted set,
+// it is returned to the client.
+// params is optional,use it to set WEIGHTS and AGGREGATE options, it can be nil.
+//
+//Return value
+//Array reply: the result of intersection.
+func (r *Redis) ZInter(params *ZParams, keys ...string) ([]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zinter(params, false, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getMultiBulkReply()
+}
+
+//ZInterWithScores see ZInter(), the result contains the scores of the elements
+func (r *Redis) ZInterWithScores(params *ZParams, keys ...string) ([]Tuple, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.zinter(params, true, keys...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToTupleReply(r.client.getMultiBulkReply())
+}
+
+//ZInterCard This command is similar to ZINTER, but instead of returning the result set,
+// it returns just the cardinality of the result.
+//By default, the command calculates the cardinality of the intersection of all given sets.
+// When provided with the optional LIMIT argument (limit > 0), if the intersection cardinality reaches limit partway through the computation,
+// the algorithm will exit and yield limit as the cardinality.
+//
+//Return value
+//Integer reply: the number of elements in the resulting intersection.
+func (r *Redis) ZInterCard(limit int64, keys ...string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.zintercard(limit, keys...)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
 //BRPopLPush ...
 func (r *Redis) BRPopLPush(srcKey, destKey string, timeout int) (string, error) {
 	err := r.checkIsInMultiOrPipeline()
 	if err != nil {
 		return "", err
 	}
+	r.client.setBlockingTimeout(time.Duration(timeout) * time.Second)
+	defer r.client.clearBlockingTimeout()
 	err = r.client.brpoplpush(srcKey, destKey, timeout)
 	if err != nil {
 		return "", err
@@ -2719,8 +3549,16 @@ func (r *Redis) Publish(channel, message string) (int64, error) {
 	return r.client.getIntegerReply()
 }
 
-//Subscribe ...
+//Subscribe subscribe the channels and block until all of them are unsubscribed,
+//a Redis borrowed from a pool subscribes by a dedicated connection outside of the pool,
+//it is closed when the subscription ends
 func (r *Redis) Subscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	if pool := r.pool(); pool != nil {
+		//a pooled connection is not blocked by the subscription
+		return pool.subscribe(func(redis *Redis) error {
+			return redis.Subscribe(redisPubSub, channels...)
+		})
+	}
 	err := r.client.connection.setTimeoutInfinite()
 	defer r.client.connection.rollbackTimeout()
 	if err != nil {
@@ -2733,8 +3571,15 @@ func (r *Redis) Subscribe(redisPubSub *RedisPubSub, channels ...string) error {
 	return nil
 }
 
-//PSubscribe ...
+//PSubscribe subscribe the patterns and block until all of them are unsubscribed,
+//a Redis borrowed from a pool subscribes by a dedicated connection like Subscribe
 func (r *Redis) PSubscribe(redisPubSub *RedisPubSub, patterns ...string) error {
+	if pool := r.pool(); pool != nil {
+		//a pooled connection is not blocked by the subscription
+		return pool.subscribe(func(redis *Redis) error {
+			return redis.PSubscribe(redisPubSub, patterns...)
+		})
+	}
 	err := r.checkIsInMultiOrPipeline()
 	if err != nil {
 		return err
@@ -2751,6 +3596,44 @@ func (r *Redis) PSubscribe(redisPubSub *RedisPubSub, patterns ...string) error {
 	return nil
 }
 
+//SPublish post a message to the sharded channel,available since redis 7.0,
+//in cluster mode the message is only propagated within the shard owning the slot of channel
+//
+//return the number of clients that received the message
+func (r *Redis) SPublish(channel, message string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.spublish(channel, message)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//SSubscribe subscribe the sharded channels and block until all of them are unsubscribed,
+//available since redis 7.0,in cluster mode all the channels must be in the same slot,
+//a Redis borrowed from a pool subscribes by a dedicated connection like Subscribe
+func (r *Redis) SSubscribe(redisPubSub *RedisPubSub, channels ...string) error {
+	if pool := r.pool(); pool != nil {
+		//a pooled connection is not blocked by the subscription
+		return pool.subscribe(func(redis *Redis) error {
+			return redis.SSubscribe(redisPubSub, channels...)
+		})
+	}
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.connection.setTimeoutInfinite()
+	defer r.client.connection.rollbackTimeout()
+	if err != nil {
+		return err
+	}
+	return redisPubSub.proceedWithShards(r, channels...)
+}
+
 //RandomKey ...
 func (r *Redis) RandomKey() (string, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -2825,6 +3708,34 @@ func (r *Redis) PfCount(keys ...string) (int64, error) {
 	return r.client.getIntegerReply()
 }
 
+//PfDebugGetReg return the 16384 registers of the HyperLogLog stored at key,
+//a sparse HyperLogLog is converted to the dense encoding by this command
+func (r *Redis) PfDebugGetReg(key string) ([]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.pfdebug("GETREG", key)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getIntegerMultiBulkReply()
+}
+
+//PfDebugEncoding return the encoding of the HyperLogLog stored at key,sparse or dense,
+//a small HyperLogLog is sparse to save memory,it becomes dense when it grows beyond hll-sparse-max-bytes
+func (r *Redis) PfDebugEncoding(key string) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.pfdebug("ENCODING", key)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
 //</editor-fold>
 
 //<editor-fold desc="advancedcommands">
@@ -2840,6 +3751,26 @@ func (r *Redis) ConfigGet(pattern string) ([]string, error) {
 	return r.client.getMultiBulkReply()
 }
 
+//ConfigGetMap CONFIG GET parsed into parameter -> value,
+//several patterns can be read at once since Redis 7.0
+func (r *Redis) ConfigGetMap(patterns ...string) (map[string]string, error) {
+	err := r.client.configGet(patterns...)
+	if err != nil {
+		return nil, err
+	}
+	return StrArrToMapReply(r.client.getMultiBulkReply())
+}
+
+//ConfigRewrite rewrite the redis.conf file the server was started with,
+//applying the minimal changes needed to make it reflect the configuration currently used by the server
+func (r *Redis) ConfigRewrite() (string, error) {
+	err := r.client.configRewrite()
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
 //ConfigSet The CONFIG SET command is used in order to reconfigure the server at run time
 // without the need to restart Redis.
 // You can change both trivial parameters or switch from one to another persistence option using this command.
@@ -2897,6 +3828,55 @@ func (r *Redis) SlowLogGet(entries ...int64) ([]SlowLog, error) {
 	return result, err
 }
 
+//ClientKill close the connection of the client connected from addr,ip:port
+func (r *Redis) ClientKill(addr string) (string, error) {
+	err := r.client.clientKill(addr)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
+//ClientKillFilter close the connections of the clients matching the filter,
+//such as NewClientFilter().Type(ClientTypeNormal).MaxAge(time.Hour),
+//return the count of the clients killed
+func (r *Redis) ClientKillFilter(filter *ClientFilter) (int64, error) {
+	err := r.client.clientKillFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//ClientList the clients connected to the server,parsed from the lines of CLIENT LIST
+func (r *Redis) ClientList() ([]ClientInfo, error) {
+	err := r.client.clientList()
+	if err != nil {
+		return nil, err
+	}
+	return ParseClientList(r.client.getBulkReply())
+}
+
+//ClientListFilter the clients matching the filter,
+//the server filters by ID or else TYPE,the other conditions are checked on the parsed clients
+func (r *Redis) ClientListFilter(filter *ClientFilter) ([]ClientInfo, error) {
+	err := r.client.clientListFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	clients, err := ParseClientList(r.client.getBulkReply())
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]ClientInfo, 0, len(clients))
+	for _, c := range clients {
+		if filter.match(c) {
+			matched = append(matched, c)
+		}
+	}
+	return matched, nil
+}
+
 //ObjectRefCount returns the number of references of the value associated with the specified key.
 // This command is mainly useful for debugging.
 func (r *Redis) ObjectRefCount(str string) (int64, error) {
@@ -2929,35 +3909,49 @@ func (r *Redis) ObjectIdleTime(str string) (int64, error) {
 	return r.client.getIntegerReply()
 }
 
+//MemoryUsage the number of bytes that the key and its value require to be stored in RAM,
+//nested values are sampled,the optional samples is the count of sampled elements,0 means all elements.
+//return -1 if the key doesn't exist
+func (r *Redis) MemoryUsage(key string, samples ...int) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.memoryUsage(key, samples...)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
 //</editor-fold>
 
 //<editor-fold desc="scriptcommands">
 
-//Eval evaluate scripts using the Lua interpreter built into Redis
+//Eval evaluate scripts using the Lua interpreter built into Redis,
+//the reply is read with SoTimeout like other commands,use EvalTimeout for long running scripts
 func (r *Redis) Eval(script string, keyCount int, params ...string) (interface{}, error) {
-	err := r.client.connection.setTimeoutInfinite()
-	defer r.client.connection.rollbackTimeout()
-	if err != nil {
-		return nil, err
-	}
-	err = r.client.eval(script, keyCount, params...)
+	err := r.client.eval(script, keyCount, params...)
 	if err != nil {
 		return nil, err
 	}
 	return ObjToEvalResult(r.client.getOne())
 }
 
+//EvalTimeout evaluate scripts like Eval,the reply is waited for timeout longer than SoTimeout,
+//0 or negative timeout means without deadline
+func (r *Redis) EvalTimeout(timeout time.Duration, script string, keyCount int, params ...string) (interface{}, error) {
+	r.client.setBlockingTimeout(timeout)
+	defer r.client.clearBlockingTimeout()
+	return r.Eval(script, keyCount, params...)
+}
+
 //EvalByKeyArgs evaluate scripts using the Lua interpreter built into Redis
 func (r *Redis) EvalByKeyArgs(script string, keys []string, args []string) (interface{}, error) {
-	err := r.client.connection.setTimeoutInfinite()
-	defer r.client.connection.rollbackTimeout()
-	if err != nil {
-		return nil, err
-	}
 	params := make([]string, 0)
 	params = append(params, keys...)
 	params = append(params, args...)
-	err = r.client.eval(script, len(keys), params...)
+	err := r.client.eval(script, len(keys), params...)
 	if err != nil {
 		return nil, err
 	}
@@ -2975,6 +3969,34 @@ func (r *Redis) EvalSha(sha1 string, keyCount int, params ...string) (interface{
 	return ObjToEvalResult(r.client.getOne())
 }
 
+//EvalShaTimeout evaluate a script cached on the server side like EvalSha,
+//the reply is waited for timeout longer than SoTimeout,0 or negative timeout means without deadline
+func (r *Redis) EvalShaTimeout(timeout time.Duration, sha1 string, keyCount int, params ...string) (interface{}, error) {
+	r.client.setBlockingTimeout(timeout)
+	defer r.client.clearBlockingTimeout()
+	return r.EvalSha(sha1, keyCount, params...)
+}
+
+//EvalRo evaluate a read-only script,like Eval but the script can't modify data,
+//so it can be executed on replicas,available since redis 7.0
+func (r *Redis) EvalRo(script string, keyCount int, params ...string) (interface{}, error) {
+	err := r.client.evalRo(script, keyCount, params...)
+	if err != nil {
+		return nil, err
+	}
+	return ObjToEvalResult(r.client.getOne())
+}
+
+//EvalShaRo evaluate a read-only script cached on the server side by its SHA1 digest,
+//like EvalSha but the script can't modify data,so it can be executed on replicas,available since redis 7.0
+func (r *Redis) EvalShaRo(sha1 string, keyCount int, params ...string) (interface{}, error) {
+	err := r.client.evalshaRo(sha1, keyCount, params...)
+	if err != nil {
+		return nil, err
+	}
+	return ObjToEvalResult(r.client.getOne())
+}
+
 //ScriptExists Returns information about the existence of the scripts in the script cache.
 //Return value
 //Array reply The command returns an array of integers
@@ -3056,9 +4078,71 @@ func (r *Redis) Select(index int) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	s, err := r.client.getStatusCodeReply()
+	if err != nil {
+		return "", err
+	}
+	//remember the db,so a reconnected connection selects it again
+	r.client.Db = index
+	return s, nil
+}
+
+//SwapDB swap the data of two dbs,clients connected to one db see the data of the other immediately
+func (r *Redis) SwapDB(index1, index2 int) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.swapDB(index1, index2)
+	if err != nil {
+		return "", err
+	}
 	return r.client.getStatusCodeReply()
 }
 
+//Reset RESET,available since redis 6.2,discard the transaction,unwatch all keys,unsubscribe all channels,
+//select db 0 and authenticate as the default user.
+//the unread replies of the connection are dropped,then the connection authenticates,selects the db
+//and sets the client name of the option again,so it can be returned to the pool safely.
+//Reset can be called in Multi
+func (r *Redis) Reset() (string, error) {
+	err := r.client.reset()
+	if err != nil {
+		return "", err
+	}
+	//drop the replies of the commands sent before RESET
+	_, err = r.client.getAll(1)
+	if err != nil {
+		return "", err
+	}
+	s, err := r.client.getStatusCodeReply()
+	if err != nil {
+		return "", err
+	}
+	r.client.isInMulti = false
+	r.client.isInWatch = false
+	r.client.Db = r.client.initialDb
+	err = r.client.handshake()
+	if err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+//Lolwut LOLWUT,return the redis version and a piece of generative computer art,
+//version selects the art of a specific redis version
+func (r *Redis) Lolwut(version ...int) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.lolwut(version...)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getBulkReply()
+}
+
 //FlushDB it will clear whole keys in current db
 func (r *Redis) FlushDB() (string, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -3072,6 +4156,19 @@ func (r *Redis) FlushDB() (string, error) {
 	return r.client.getStatusCodeReply()
 }
 
+//FlushDBWithMode see FlushDB,the mode FlushAsync frees the keys in background,so a large db doesn't block the server
+func (r *Redis) FlushDBWithMode(mode *FlushMode) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.flushDB(mode)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
 //DbSize return key count of current db
 func (r *Redis) DbSize() (int64, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -3098,6 +4195,19 @@ func (r *Redis) FlushAll() (string, error) {
 	return r.client.getStatusCodeReply()
 }
 
+//FlushAllWithMode see FlushAll,the mode FlushAsync frees the keys in background,so a large db doesn't block the server
+func (r *Redis) FlushAllWithMode(mode *FlushMode) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.flushAll(mode)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
 //Auth when server is set password,then you need to auth password.
 func (r *Redis) Auth(password string) (string, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -3108,7 +4218,8 @@ func (r *Redis) Auth(password string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return r.client.getStatusCodeReply()
+	s, err := r.client.getStatusCodeReply()
+	return s, authError(err)
 }
 
 //Save ...
@@ -3147,7 +4258,7 @@ func (r *Redis) LastSave() (int64, error) {
 	return r.client.getIntegerReply()
 }
 
-//Shutdown ...
+//Shutdown stop the server,the connection is closed without reply if it succeeds
 func (r *Redis) Shutdown() (string, error) {
 	err := r.client.shutdown()
 	if err != nil {
@@ -3156,6 +4267,15 @@ func (r *Redis) Shutdown() (string, error) {
 	return r.client.getStatusCodeReply()
 }
 
+//ShutdownWithParams see Shutdown,the params decide whether the dataset is saved,such as NewShutdownParams().NoSave()
+func (r *Redis) ShutdownWithParams(params *ShutdownParams) (string, error) {
+	err := r.client.shutdown(params)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
 //Info ...
 func (r *Redis) Info(section ...string) (string, error) {
 	err := r.client.info(section...)
@@ -3165,6 +4285,29 @@ func (r *Redis) Info(section ...string) (string, error) {
 	return r.client.getBulkReply()
 }
 
+//ServerVersion the version of the connected server,it is detected after connecting if Option.DetectVersion is true,
+//otherwise it is queried by INFO on the first call,then the commands newer than the server fail with ErrUnsupportedVersion
+func (r *Redis) ServerVersion() (*ServerVersion, error) {
+	if version := r.client.serverVersion(); version != nil {
+		return version, nil
+	}
+	info, err := r.Info("server")
+	if err != nil {
+		return nil, err
+	}
+	version, err := parseInfoVersion(info)
+	if err != nil {
+		return nil, err
+	}
+	r.client.setServerVersion(version)
+	return version, nil
+}
+
+//InfoParsed INFO parsed into sections and typed fields,such as memory,clients,replication and keyspace
+func (r *Redis) InfoParsed(section ...string) (*ServerInfo, error) {
+	return ParseInfo(r.Info(section...))
+}
+
 //SlaveOf ...
 func (r *Redis) SlaveOf(host string, port int) (string, error) {
 	err := r.client.slaveof(host, port)
@@ -3183,7 +4326,7 @@ func (r *Redis) SlaveOfNoOne() (string, error) {
 	return r.client.getStatusCodeReply()
 }
 
-//Debug ...
+//Debug send DEBUG with the params,such as NewDebugParamsObject,the reply is returned as string
 func (r *Redis) Debug(params DebugParams) (string, error) {
 	err := r.client.debug(params)
 	if err != nil {
@@ -3192,6 +4335,24 @@ func (r *Redis) Debug(params DebugParams) (string, error) {
 	return r.client.getStatusCodeReply()
 }
 
+//DebugObject DEBUG OBJECT parsed into the encoding,refcount and other fields of the value
+func (r *Redis) DebugObject(key string) (*DebugObjectInfo, error) {
+	return ParseDebugObject(r.Debug(*NewDebugParamsObject(key)))
+}
+
+//DebugSleep block the server for the duration,to test the timeouts of other clients,
+//the read deadline of this connection is extended by the duration
+func (r *Redis) DebugSleep(duration time.Duration) (string, error) {
+	r.client.setBlockingTimeout(duration)
+	defer r.client.clearBlockingTimeout()
+	return r.Debug(*NewDebugParamsSleep(duration))
+}
+
+//DebugSetActiveExpire enable or disable the active expiration of keys,to test the lazy expiration
+func (r *Redis) DebugSetActiveExpire(enabled bool) (string, error) {
+	return r.Debug(*NewDebugParamsSetActiveExpire(enabled))
+}
+
 //ConfigResetStat ...
 func (r *Redis) ConfigResetStat() (string, error) {
 	err := r.client.configResetStat()
@@ -3235,6 +4396,11 @@ func (r *Redis) ClusterNodes() (string, error) {
 	return r.client.getBulkReply()
 }
 
+//ClusterNodesParsed CLUSTER NODES parsed into nodes
+func (r *Redis) ClusterNodesParsed() ([]ClusterNode, error) {
+	return ParseClusterNodes(r.ClusterNodes())
+}
+
 //ClusterMeet ...
 func (r *Redis) ClusterMeet(ip string, port int) (string, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -3470,6 +4636,80 @@ func (r *Redis) ClusterSlots() ([]interface{}, error) {
 	return r.client.getObjectMultiBulkReply()
 }
 
+//ClusterSlotRanges CLUSTER SLOTS parsed into slot ranges with their master and replica addresses
+func (r *Redis) ClusterSlotRanges() ([]ClusterSlotRange, error) {
+	return ParseClusterSlots(r.ClusterSlots())
+}
+
+//ClusterShards CLUSTER SHARDS,available since redis 7.0,
+//return the slot ranges and the nodes with their role and health of every shard
+func (r *Redis) ClusterShards() ([]ClusterShard, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.clusterShards()
+	if err != nil {
+		return nil, err
+	}
+	return ParseClusterShards(r.client.getObjectMultiBulkReply())
+}
+
+//ClusterMyID return the id of the node
+func (r *Redis) ClusterMyID() (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.clusterMyID()
+	if err != nil {
+		return "", err
+	}
+	return r.client.getBulkReply()
+}
+
+//ClusterLinks CLUSTER LINKS,available since redis 7.0,
+//return the peer links of the cluster bus,every peer has an outbound and an inbound link
+func (r *Redis) ClusterLinks() ([]ClusterLink, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.clusterLinks()
+	if err != nil {
+		return nil, err
+	}
+	return ParseClusterLinks(r.client.getObjectMultiBulkReply())
+}
+
+//ClusterBumpEpoch advance the config epoch of the node if it isn't the greatest one of the cluster,
+//return BUMPED with the new epoch,or STILL with the current epoch if it isn't changed
+func (r *Redis) ClusterBumpEpoch() (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.clusterBumpEpoch()
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
+//ClusterCountFailureReports return the count of active failure reports of the node,
+//a node is marked as failed when most of the masters report it
+func (r *Redis) ClusterCountFailureReports(nodeID string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.clusterCountFailureReports(nodeID)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
 //ClusterReset ...
 func (r *Redis) ClusterReset(resetType Reset) (string, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -3653,6 +4893,178 @@ func (r *Redis) SentinelSet(masterName string, parameterMap map[string]string) (
 	return r.client.getStatusCodeReply()
 }
 
+//SentinelMasterByName the state of the master monitored by the sentinel,parsed from SENTINEL MASTER
+func (r *Redis) SentinelMasterByName(masterName string) (*SentinelMasterInfo, error) {
+	err := r.client.sentinelMaster(masterName)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSentinelMasterInfo(r.client.getObjectMultiBulkReply())
+}
+
+//SentinelSentinels the other sentinels monitoring the master,with the same fields as SentinelSlaves
+func (r *Redis) SentinelSentinels(masterName string) ([]map[string]string, error) {
+	err := r.client.sentinelSentinels(masterName)
+	if err != nil {
+		return nil, err
+	}
+	return ObjArrToMapArrayReply(r.client.getObjectMultiBulkReply())
+}
+
+//SentinelCkQuorum check if the sentinels monitoring the master can reach the quorum to failover,
+//return a status such as "OK 3 usable Sentinels. Quorum and failover authorization can be reached",
+//an error explaining why if they can't
+func (r *Redis) SentinelCkQuorum(masterName string) (string, error) {
+	err := r.client.sentinelCkQuorum(masterName)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
+//SentinelFlushConfig force the sentinel to rewrite its configuration on disk
+func (r *Redis) SentinelFlushConfig() (string, error) {
+	err := r.client.sentinelFlushConfig()
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
+//SentinelSimulateFailure crash the sentinel at the given point of the next failover,for testing
+func (r *Redis) SentinelSimulateFailure(failures ...*SimulateFailure) (string, error) {
+	err := r.client.sentinelSimulateFailure(failures...)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
+//</editor-fold>
+
+//<editor-fold desc="streamcommands">
+
+//XAdd append an entry with the fields of hash to the stream key,id * lets redis generate the id
+//
+//Return value
+//Bulk string reply: the id of the added entry
+func (r *Redis) XAdd(key, id string, hash map[string]string) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.xadd(key, id, hash)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getBulkReply()
+}
+
+//XLen return the number of entries of the stream
+func (r *Redis) XLen(key string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.xlen(key)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//XDel remove the entries of ids from the stream,return the number of entries actually deleted
+func (r *Redis) XDel(key string, ids ...string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.xdel(key, ids...)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//XAck remove the entries of ids from the pending list of the group,
+//return the number of entries actually acknowledged
+func (r *Redis) XAck(key, group string, ids ...string) (int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return 0, err
+	}
+	err = r.client.xack(key, group, ids...)
+	if err != nil {
+		return 0, err
+	}
+	return r.client.getIntegerReply()
+}
+
+//XGroupCreate create the consumer group of the stream starting after id,$ means new entries only,
+//create the stream if it does not exist and mkStream is true
+func (r *Redis) XGroupCreate(key, group, id string, mkStream bool) (string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return "", err
+	}
+	err = r.client.xgroupCreate(key, group, id, mkStream)
+	if err != nil {
+		return "", err
+	}
+	return r.client.getStatusCodeReply()
+}
+
+//XReadGroup read at most count entries of every stream as consumer of the group,
+//streams are the keys followed by the ids of every key,> means entries never delivered to the group,
+//block waits for entries if it is positive,
+//count 0 means no limit
+//
+//Return value
+//the entries of the streams that have entries,empty if block times out
+func (r *Redis) XReadGroup(group, consumer string, count int, block time.Duration, streams ...string) ([]StreamEntries, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	if block > 0 {
+		r.client.setBlockingTimeout(block)
+		defer r.client.clearBlockingTimeout()
+	}
+	err = r.client.xreadGroup(group, consumer, count, block, streams...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamEntriesArr(r.client.getObjectMultiBulkReply())
+}
+
+//XPending return at most count pending entries of the group with id between start and end,
+//- and + mean the smallest and the greatest id
+func (r *Redis) XPending(key, group, start, end string, count int64, params ...*XPendingParams) ([]StreamPendingEntry, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.xpending(key, group, start, end, count, params...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamPendingEntries(r.client.getObjectMultiBulkReply())
+}
+
+//XAutoClaim transfer at most count pending entries idle for at least minIdle to consumer,
+//scanning the pending list of the group from start,available since redis 6.2
+func (r *Redis) XAutoClaim(key, group, consumer string, minIdle time.Duration, start string, count int) (*StreamClaimResult, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.xautoClaim(key, group, consumer, minIdle, start, count)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamClaimResult(r.client.getObjectMultiBulkReply())
+}
+
 //</editor-fold>
 
 //<editor-fold desc="other commands">
@@ -3670,6 +5082,32 @@ func (r *Redis) PubSubChannels(pattern string) ([]string, error) {
 	return r.client.getMultiBulkReply()
 }
 
+//PubSubShardChannels list the active sharded channels matching pattern,available since redis 7.0
+func (r *Redis) PubSubShardChannels(pattern string) ([]string, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.pubsubShardChannels(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.getMultiBulkReply()
+}
+
+//PubSubShardNumSub return the number of subscribers of the sharded channels,available since redis 7.0
+func (r *Redis) PubSubShardNumSub(channels ...string) (map[string]int64, error) {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.pubsubShardNumSub(channels...)
+	if err != nil {
+		return nil, err
+	}
+	return ObjArrToNumSubReply(r.client.getObjectMultiBulkReply())
+}
+
 // Asking ...
 func (r *Redis) Asking() (string, error) {
 	err := r.checkIsInMultiOrPipeline()
@@ -3697,4 +5135,10 @@ func (r *Redis) Pipelined() *Pipeline {
 	return newPipeline(r.client)
 }
 
+//PipelinedWithOption get pipeline syncing automatically when the limits of the option are reached,
+//the replies of the commands queued after the last sync are read by Sync
+func (r *Redis) PipelinedWithOption(option *PipelineOption) *Pipeline {
+	return newPipelineWithOption(r.client, option)
+}
+
 //</editor-fold>
@@ -2,6 +2,7 @@ package godis
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -45,6 +46,35 @@ func TestRedis_ConfigSet(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_ConfigGetMap(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"CONFIG GET timeout":            {"*2\r\n$7\r\ntimeout\r\n$1\r\n0\r\n"},
+		"CONFIG GET timeout maxmemory*": {"*4\r\n$7\r\ntimeout\r\n$1\r\n0\r\n$9\r\nmaxmemory\r\n$3\r\n100\r\n"},
+		"CONFIG REWRITE":                {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	config, err := redis.ConfigGetMap("timeout")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"timeout": "0"}, config)
+	config, err = redis.ConfigGetMap("timeout", "maxmemory*")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"timeout": "0", "maxmemory": "100"}, config)
+	s, err := redis.ConfigRewrite()
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.ConfigGetMap("timeout")
+	assert.NotNil(t, err)
+	_, err = redisBroken.ConfigRewrite()
+	assert.NotNil(t, err)
+}
+
 func TestRedis_SlowlogGet(t *testing.T) {
 	redis := NewRedis(option)
 	defer redis.Close()
@@ -149,3 +179,58 @@ func TestRedis_ObjectRefcount(t *testing.T) {
 	_, err = redisBroken.ObjectRefCount("godis")
 	assert.NotNil(t, err)
 }
+
+func TestRedis_ClientList(t *testing.T) {
+	list := "id=3 addr=127.0.0.1:52555 laddr=127.0.0.1:6379 fd=8 name=godis age=120 idle=0 flags=N db=0 sub=0 psub=0 multi=-1 cmd=client|list user=default\n" +
+		"id=4 addr=127.0.0.1:52556 laddr=127.0.0.1:6379 fd=9 name= age=5 idle=5 flags=P db=1 sub=2 psub=1 multi=-1 cmd=subscribe user=app\n"
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"CLIENT LIST":                 {fakeBulk(list)},
+		"CLIENT LIST TYPE normal":     {fakeBulk(list[:strings.Index(list, "\n")+1])},
+		"CLIENT LIST ID 4":            {fakeBulk(list[strings.Index(list, "\n")+1:])},
+		"CLIENT KILL 127.0.0.1:52556": {"+OK\r\n"},
+		"CLIENT KILL TYPE pubsub USER app MAXAGE 60 SKIPME NO":       {":1\r\n"},
+		"CLIENT KILL ID 5 ADDR 127.0.0.1:52557 LADDR 127.0.0.1:6379": {":0\r\n"},
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	clients, err := redis.ClientList()
+	assert.Nil(t, err)
+	assert.Len(t, clients, 2)
+	assert.Equal(t, int64(3), clients[0].ID)
+	assert.Equal(t, "127.0.0.1:52555", clients[0].Addr)
+	assert.Equal(t, "127.0.0.1:6379", clients[0].LAddr)
+	assert.Equal(t, "godis", clients[0].Name)
+	assert.Equal(t, int64(120), clients[0].Age)
+	assert.Equal(t, int64(-1), clients[0].Multi)
+	assert.Equal(t, "client|list", clients[0].Cmd)
+	assert.Equal(t, ClientTypeNormal, clients[0].Type())
+	assert.Equal(t, 1, clients[1].DB)
+	assert.Equal(t, int64(2), clients[1].Sub)
+	assert.Equal(t, "app", clients[1].User)
+	assert.Equal(t, ClientTypePubSub, clients[1].Type())
+	assert.Equal(t, "", clients[1].Fields["name"])
+
+	clients, err = redis.ClientListFilter(NewClientFilter().Type(ClientTypeNormal))
+	assert.Nil(t, err)
+	assert.Len(t, clients, 1)
+	assert.Equal(t, int64(3), clients[0].ID)
+	//the conditions the server doesn't filter by are checked on the parsed clients
+	clients, err = redis.ClientListFilter(NewClientFilter().ID(4).User("default"))
+	assert.Nil(t, err)
+	assert.Len(t, clients, 0)
+	clients, err = redis.ClientListFilter(NewClientFilter().ID(4).Type(ClientTypePubSub).MaxAge(5 * time.Second))
+	assert.Nil(t, err)
+	assert.Len(t, clients, 1)
+
+	s, err := redis.ClientKill("127.0.0.1:52556")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	c, err := redis.ClientKillFilter(NewClientFilter().Type(ClientTypePubSub).User("app").MaxAge(time.Minute).SkipMe(false))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	c, err = redis.ClientKillFilter(NewClientFilter().ID(5).Addr("127.0.0.1:52557").LAddr("127.0.0.1:6379"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+}
@@ -193,6 +193,90 @@ func TestRedis_Select(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_SwapDB(t *testing.T) {
+	initDb()
+	redis := NewRedis(option)
+	defer redis.Close()
+	ret, err := redis.SwapDB(0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", ret)
+	c, err := redis.Exists("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+	redis.Select(1)
+	s, err := redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.SwapDB(0, 1)
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.SwapDB(0, 1)
+	assert.NotNil(t, err)
+}
+
+func TestRedis_Reset(t *testing.T) {
+	//RESET and LOLWUT need redis 6.2,so serve the replies from a fake server
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"AUTH pass":            {"+OK\r\n"},
+		"SELECT 2":             {"+OK\r\n"},
+		"SELECT 3":             {"+OK\r\n"},
+		"CLIENT SETNAME godis": {"+OK\r\n"},
+		"MULTI":                {"+OK\r\n"},
+		"DEL godis":            {"+QUEUED\r\n"},
+		"RESET":                {"+RESET\r\n"},
+		"PING":                 {"+PONG\r\n"},
+		"LOLWUT":               {"$15\r\nRedis ver. 7.0\n\r\n"},
+		"LOLWUT VERSION 5":     {"$15\r\nRedis ver. 5.0\n\r\n"},
+	})
+	defer closeServer()
+	fakeOption.Password = "pass"
+	fakeOption.Db = 2
+	fakeOption.ClientName = "godis"
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	_, err := redis.Select(3)
+	assert.Nil(t, err)
+	m, err := redis.Multi()
+	assert.Nil(t, err)
+	m.Del("godis")
+	_, err = redis.Ping()
+	assert.NotNil(t, err)
+
+	s, err := redis.Reset()
+	assert.Nil(t, err)
+	assert.Equal(t, "RESET", s)
+	assert.Equal(t, 2, redis.client.Db)
+	s, err = redis.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+
+	s, err = redis.Lolwut()
+	assert.Nil(t, err)
+	assert.Equal(t, "Redis ver. 7.0\n", s)
+	s, err = redis.Lolwut(5)
+	assert.Nil(t, err)
+	assert.Equal(t, "Redis ver. 5.0\n", s)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ = redisBroken.Multi()
+	_, err = redisBroken.Lolwut()
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.Reset()
+	assert.NotNil(t, err)
+	_, err = redisBroken.Lolwut()
+	assert.NotNil(t, err)
+}
+
 func TestRedis_Save(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -278,7 +362,66 @@ func TestRedis_Lastsave(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+//the modes are taken by the WithMode|WithParams variants,the method values of the callers keep their types
+var (
+	_ func(*Redis) (string, error)       = (*Redis).FlushDB
+	_ func(*Redis) (string, error)       = (*Redis).FlushAll
+	_ func(*Redis) (string, error)       = (*Redis).Shutdown
+	_ func(*Pipeline) (*Response, error) = (*Pipeline).FlushDB
+)
+
 // ignore this case,cause it will shutdown redis
+func TestRedis_FlushMode(t *testing.T) {
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.Set("godis", "good")
+	reply, err := redis.FlushDBWithMode(FlushAsync)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+	n, _ := redis.Exists("godis")
+	assert.Equal(t, int64(0), n)
+	redis.Set("godis", "good")
+	reply, err = redis.FlushAllWithMode(FlushAsync)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+
+	p := redis.Pipelined()
+	resp, err := p.FlushAllWithMode(FlushAsync)
+	assert.Nil(t, err)
+	p.Sync()
+	obj, err := ToStrReply(resp.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", obj)
+
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"FLUSHALL SYNC": {"+OK\r\n"},
+		"FLUSHDB SYNC":  {"+OK\r\n"},
+	})
+	defer closeServer()
+	fake := NewRedis(fakeOption)
+	defer fake.Close()
+	reply, err = fake.FlushAllWithMode(FlushSync)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+	reply, err = fake.FlushDBWithMode(FlushSync)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", reply)
+}
+
+func TestRedis_ShutdownParams(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SHUTDOWN NOSAVE NOW FORCE": {"-ERR Errors trying to SHUTDOWN. Check logs.\r\n"},
+		"SHUTDOWN SAVE":             {"-ERR Errors trying to SHUTDOWN. Check logs.\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	_, err := redis.ShutdownWithParams(NewShutdownParams().NoSave().Now().Force())
+	assert.Equal(t, "ERR Errors trying to SHUTDOWN. Check logs.", err.Error())
+	_, err = redis.ShutdownWithParams(NewShutdownParams().Save())
+	assert.IsType(t, &DataError{}, err)
+}
+
 func TestRedis_Shutdown(t *testing.T) {
 	redis := NewRedis(&Option{
 		Host: "localhost",
@@ -321,6 +464,12 @@ func TestRedis_Info(t *testing.T) {
 	redis.Close()
 	assert.NotNil(t, err)
 
+	redis = NewRedis(option)
+	info, err := redis.InfoParsed()
+	redis.Close()
+	assert.Nil(t, err)
+	assert.True(t, info.Clients.ConnectedClients > 0)
+
 	redisBroken := NewRedis(option1)
 	defer redisBroken.Close()
 	m, _ := redisBroken.Multi()
@@ -410,6 +559,46 @@ func TestRedis_Debug(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_DebugParams(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"DEBUG OBJECT godis":                    {"+Value at:0x7f1c refcount:1 encoding:embstr serializedlength:5 lru:9013 lru_seconds_idle:12\r\n"},
+		"DEBUG SLEEP 0.5":                       {"+OK\r\n"},
+		"DEBUG SET-ACTIVE-EXPIRE 0":             {"+OK\r\n"},
+		"DEBUG QUICKLIST-PACKED-THRESHOLD 1024": {"+OK\r\n"},
+		"DEBUG STRINGMATCH-LEN":                 {"+OK\r\n"},
+		"DEBUG HTSTATS 0":                       {"$6\r\nstats\n\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	info, err := redis.DebugObject("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "embstr", info.Encoding)
+	assert.Equal(t, int64(1), info.RefCount)
+	assert.Equal(t, int64(5), info.SerializedLength)
+	assert.Equal(t, int64(9013), info.LRU)
+	assert.Equal(t, int64(12), info.LRUSecondsIdle)
+	assert.Equal(t, "0x7f1c", info.Fields["at"])
+	s, err := redis.DebugSleep(500 * time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.DebugSetActiveExpire(false)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.Debug(*NewDebugParamsQuicklistPackedThreshold(1024))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.Debug(*NewDebugParamsStringMatchLen())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.Debug(*NewDebugParams("HTSTATS", "0"))
+	assert.Nil(t, err)
+	assert.Equal(t, "stats\n", s)
+
+	_, err = ParseDebugObject("", newDataError("ERR no such key"))
+	assert.NotNil(t, err)
+}
+
 func TestRedis_ConfigResetStat(t *testing.T) {
 	redis := NewRedis(option)
 	defer redis.Close()
@@ -1,6 +1,12 @@
 package godis
 
-import "testing"
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
 
 func BenchmarkSet(b *testing.B) {
 	b.ResetTimer()
@@ -33,3 +39,63 @@ func BenchmarkIncr(b *testing.B) {
 		redis.Close()
 	}
 }
+
+//replayConn a connection replaying data forever,so replies are parsed without redis
+type replayConn struct {
+	net.Conn
+	data []byte
+	pos  int
+}
+
+func (c *replayConn) Read(b []byte) (int, error) {
+	n := copy(b, c.data[c.pos:])
+	c.pos = (c.pos + n) % len(c.data)
+	return n, nil
+}
+
+func (c *replayConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func newReplayConnection(reply string) *connection {
+	c := newConnection("localhost", 6379, 0, 0)
+	c.socket = &replayConn{data: []byte(strings.Repeat(reply, 64))}
+	c.protocol = newProtocol(newRedisOutputStream(c), newRedisInputStream(bufio.NewReader(c.socket), c))
+	return c
+}
+
+func BenchmarkReadIntegerReply(b *testing.B) {
+	c := newReplayConnection(":1024\r\n")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.getIntegerReply()
+	}
+}
+
+func BenchmarkReadBulkReply(b *testing.B) {
+	c := newReplayConnection("$5\r\ngodis\r\n")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.getBulkReply()
+	}
+}
+
+func BenchmarkReadMultiBulkReply(b *testing.B) {
+	c := newReplayConnection("*3\r\n$5\r\ngodis\r\n$4\r\ngood\r\n$-1\r\n")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.getMultiBulkReply()
+	}
+}
+
+func BenchmarkReadObjectMultiBulkReply(b *testing.B) {
+	c := newReplayConnection("*3\r\n$5\r\ngodis\r\n$4\r\ngood\r\n$-1\r\n")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.getObjectMultiBulkReply()
+	}
+}
@@ -376,3 +376,111 @@ func TestRedis_ClusterSlots(t *testing.T) {
 	_, err = redisBroken.ClusterSlots()
 	assert.NotNil(t, err)
 }
+
+func TestRedis_ClusterNodesParsed(t *testing.T) {
+	redis := NewRedis(option1)
+	defer redis.Close()
+	nodes, err := redis.ClusterNodesParsed()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, nodes)
+	myself := 0
+	for _, node := range nodes {
+		if node.HasFlag("myself") {
+			myself++
+			assert.Equal(t, 7000, node.Port)
+		}
+	}
+	assert.Equal(t, 1, myself)
+
+	redisBroken := NewRedis(option1)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.ClusterNodesParsed()
+	assert.NotNil(t, err)
+}
+
+func TestRedis_ClusterSlotRanges(t *testing.T) {
+	redis := NewRedis(option1)
+	defer redis.Close()
+	ranges, err := redis.ClusterSlotRanges()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, ranges)
+	assert.NotEmpty(t, ranges[0].Master.Host)
+
+	redisBroken := NewRedis(option1)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.ClusterSlotRanges()
+	assert.NotNil(t, err)
+}
+
+func TestRedis_ClusterShards(t *testing.T) {
+	redis := NewRedis(option1)
+	defer redis.Close()
+	shards, err := redis.ClusterShards()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, shards)
+	assert.NotEmpty(t, shards[0].Nodes)
+
+	redisBroken := NewRedis(option1)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.ClusterShards()
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.ClusterShards()
+	assert.NotNil(t, err)
+}
+
+func TestRedis_ClusterAdmin(t *testing.T) {
+	//the replies of a cluster node are served by a fake server
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"CLUSTER myid":                      {"$3\r\nid0\r\n"},
+		"CLUSTER links":                     {"*1\r\n*4\r\n$9\r\ndirection\r\n$2\r\nto\r\n$4\r\nnode\r\n$3\r\nid1\r\n"},
+		"CLUSTER bumpepoch":                 {"+BUMPED 7\r\n"},
+		"CLUSTER count-failure-reports id1": {":2\r\n"},
+		"QUIT":                              {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	id, err := redis.ClusterMyID()
+	assert.Nil(t, err)
+	assert.Equal(t, "id0", id)
+	links, err := redis.ClusterLinks()
+	assert.Nil(t, err)
+	assert.Equal(t, []ClusterLink{{Direction: "to", Node: "id1"}}, links)
+	s, err := redis.ClusterBumpEpoch()
+	assert.Nil(t, err)
+	assert.Equal(t, "BUMPED 7", s)
+	c, err := redis.ClusterCountFailureReports("id1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.ClusterMyID()
+	assert.NotNil(t, err)
+	_, err = redisBroken.ClusterLinks()
+	assert.NotNil(t, err)
+	_, err = redisBroken.ClusterBumpEpoch()
+	assert.NotNil(t, err)
+	_, err = redisBroken.ClusterCountFailureReports("id1")
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.ClusterMyID()
+	assert.NotNil(t, err)
+	_, err = redisBroken.ClusterLinks()
+	assert.NotNil(t, err)
+	_, err = redisBroken.ClusterBumpEpoch()
+	assert.NotNil(t, err)
+	_, err = redisBroken.ClusterCountFailureReports("id1")
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,7 @@
 package godis
 
 import (
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -103,6 +104,109 @@ func TestRedis_BlpopTimout(t *testing.T) {
 	assert.NotNil(t, e)
 }
 
+func TestRedis_BlockingReadTimeout(t *testing.T) {
+	flushAll()
+	redis := NewRedis(&Option{Host: "localhost", Port: 6379, SoTimeout: 200 * time.Millisecond})
+	defer redis.Close()
+	//the block timeout is longer than SoTimeout,the read waits for the timeout of the command
+	arr, e := redis.BLPopTimeout(1, "command")
+	assert.Nil(t, e)
+	assert.Empty(t, arr)
+	arr, e = redis.BRPop("command", "0.5")
+	assert.Nil(t, e)
+	assert.Empty(t, arr)
+	assert.Equal(t, time.Duration(0), redis.client.blockingRead)
+
+	redis.client.setBlockingTimeout(0)
+	assert.True(t, redis.client.readDeadline(time.Now()).IsZero())
+	redis.client.clearBlockingTimeout()
+	redis.client.infiniteBlockingRead = true
+	redis.client.setBlockingTimeout(time.Second)
+	assert.True(t, redis.client.readDeadline(time.Now()).IsZero())
+	redis.client.clearBlockingTimeout()
+	assert.False(t, redis.client.readDeadline(time.Now()).IsZero())
+
+	assert.Equal(t, 1500*time.Millisecond, parseBlockTimeout([]string{"command", "1.5"}))
+	assert.Equal(t, time.Duration(0), parseBlockTimeout([]string{"command"}))
+	assert.Equal(t, time.Duration(0), parseBlockTimeout(nil))
+}
+
+func TestRedis_BZPopMin(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.ZAddByMap("godis2", map[string]float64{"a": 1, "b": 2})
+
+	tuple, err := redis.BZPopMin(1, "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyedTuple{Key: "godis2", Tuple: Tuple{element: "a", score: 1}}, tuple)
+
+	tuple, err = redis.BZPopMax(1, "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyedTuple{Key: "godis2", Tuple: Tuple{element: "b", score: 2}}, tuple)
+
+	go func() {
+		redis := NewRedis(option)
+		defer redis.Close()
+		tuple, e := redis.BZPopMin(5, "godis1")
+		assert.Nil(t, e)
+		assert.Equal(t, &KeyedTuple{Key: "godis1", Tuple: Tuple{element: "c", score: 3}}, tuple)
+	}()
+	time.Sleep(1 * time.Second)
+	redis.ZAdd("godis1", 3, "c")
+	time.Sleep(1 * time.Second)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.BZPopMin(1, "godis1")
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.BZPopMax(1, "godis1")
+	assert.NotNil(t, err)
+}
+
+func TestRedis_BLMPop(t *testing.T) {
+	option, closeServer := newFakeServer(t, map[string][]string{
+		"BLMPOP 1 2 godis1 godis2 LEFT COUNT 2": {"*2\r\n$6\r\ngodis2\r\n*2\r\n$1\r\na\r\n$1\r\nb\r\n"},
+		"BLMPOP 1 1 godis1 RIGHT":               {"*-1\r\n"},
+		"BZMPOP 1 1 godis3 MAX":                 {"*2\r\n$6\r\ngodis3\r\n*1\r\n*2\r\n$1\r\nc\r\n$1\r\n3\r\n"},
+		"BZMPOP 0 2 godis1 godis3 MIN COUNT 5":  {"*2\r\n$6\r\ngodis3\r\n*2\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n*2\r\n$1\r\nb\r\n$3\r\n2.5\r\n"},
+		"QUIT":                                  {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(option)
+	defer redis.Close()
+	elements, err := redis.BLMPop(1, PopLeft, 2, "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyedElements{Key: "godis2", Elements: []string{"a", "b"}}, elements)
+	elements, err = redis.BLMPop(1, PopRight, 0, "godis1")
+	assert.Nil(t, err)
+	assert.Nil(t, elements)
+
+	tuples, err := redis.BZMPop(1, PopMax, 0, "godis3")
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyedTuples{Key: "godis3", Tuples: []Tuple{{element: "c", score: 3}}}, tuples)
+
+	p := redis.Pipelined()
+	resp, _ := p.BZMPop(0, PopMin, 5, "godis1", "godis3")
+	timeout, _ := p.BLMPop(1, PopRight, 0, "godis1")
+	assert.Nil(t, p.Sync())
+	tuples, err = ToKeyedTuplesReply(resp.Get())
+	assert.Nil(t, err)
+	assert.Equal(t, &KeyedTuples{Key: "godis3", Tuples: []Tuple{{element: "a", score: 1}, {element: "b", score: 2.5}}}, tuples)
+	elements, err = ToKeyedElementsReply(timeout.Get())
+	assert.Nil(t, err)
+	assert.Nil(t, elements)
+
+	prefixed := redis.WithPrefix("godis")
+	elements, err = prefixed.BLMPop(1, PopLeft, 2, "1", "2")
+	assert.Nil(t, err)
+	assert.Equal(t, "2", elements.Key)
+}
+
 func TestRedis_Brpop(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -240,6 +344,56 @@ func TestRedis_Brpoplpush(t *testing.T) {
 	assert.NotNil(t, e)
 }
 
+//redisWithoutSInterCard a server older than 7.0
+type redisWithoutSInterCard struct {
+	*Redis
+}
+
+func (r *redisWithoutSInterCard) SInterCard(limit int64, keys ...string) (int64, error) {
+	return 0, newDataError("ERR unknown command `SINTERCARD`, with args beginning with: ")
+}
+
+func TestRedis_SInterCard(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.SAdd("godis1", "1", "2", "3")
+	redis.SAdd("godis2", "2", "3", "4")
+
+	c, e := redis.SInterCard(0, "godis1", "godis2")
+	assert.Nil(t, e)
+	assert.Equal(t, int64(2), c)
+	c, e = redis.SInterCard(1, "godis1", "godis2")
+	assert.Nil(t, e)
+	assert.Equal(t, int64(1), c)
+	c, e = redis.EstimateIntersection("godis1", "godis2")
+	assert.Nil(t, e)
+	assert.Equal(t, int64(2), c)
+
+	//falls back to SINTERSTORE,the temp key is deleted
+	c, e = estimateIntersection(&redisWithoutSInterCard{redis}, "godis1", "godis2")
+	assert.Nil(t, e)
+	assert.Equal(t, int64(2), c)
+	keys, e := redis.Keys("*")
+	assert.Nil(t, e)
+	assert.ElementsMatch(t, []string{"godis1", "godis2"}, keys)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, e = redisBroken.SInterCard(0, "godis1", "godis2")
+	assert.NotNil(t, e)
+	_, e = redisBroken.EstimateIntersection("godis1", "godis2")
+	assert.NotNil(t, e)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, e = redisBroken.SInterCard(0, "godis1", "godis2")
+	assert.NotNil(t, e)
+	_, e = redisBroken.EstimateIntersection("godis1", "godis2")
+	assert.NotNil(t, e)
+}
+
 func TestRedis_Sdiff(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -474,6 +628,66 @@ func TestRedis_Zinterstore(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_ZDiff(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	c, err := redis.ZAddByMap("godis1", map[string]float64{"a": 1, "b": 2, "c": 3})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), c)
+
+	c, err = redis.ZAddByMap("godis2", map[string]float64{"a": 1, "b": 2})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+
+	arr, err := redis.ZDiff("godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c"}, arr)
+
+	tuples, err := redis.ZDiffWithScores("godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "c", score: 3}}, tuples)
+
+	c, err = redis.ZDiffStore("godis3", "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	arr, err = redis.ZUnion(nil, "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "c", "b"}, arr)
+
+	tuples, err = redis.ZUnionWithScores(NewZParams().Aggregate(AggregateMax), "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "a", score: 1}, {element: "b", score: 2}, {element: "c", score: 3}}, tuples)
+
+	arr, err = redis.ZInter(nil, "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, arr)
+
+	tuples, err = redis.ZInterWithScores(NewZParams().WeightsByDouble(2, 3), "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "a", score: 5}, {element: "b", score: 10}}, tuples)
+
+	c, err = redis.ZInterCard(0, "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+
+	c, err = redis.ZInterCard(1, "godis1", "godis2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.ZDiff("godis1", "godis2")
+	assert.NotNil(t, err)
+	_, err = redisBroken.ZUnion(nil, "godis1", "godis2")
+	assert.NotNil(t, err)
+	_, err = redisBroken.ZInterCard(0, "godis1", "godis2")
+	assert.NotNil(t, err)
+	m.Discard()
+}
+
 func TestRedis_Subscribe(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -564,6 +778,157 @@ func TestRedis_Subscribe(t *testing.T) {
 	pubsub2.UnSubscribe("godis1")
 }
 
+func TestRedis_ShardedPubSub(t *testing.T) {
+	//sharded pubsub needs redis 7.0,so serve the replies from a fake server
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SPUBLISH godis hello":     {":1\r\n"},
+		"PUBSUB shardchannels *":   {"*1\r\n$5\r\ngodis\r\n"},
+		"PUBSUB shardnumsub godis": {"*2\r\n$5\r\ngodis\r\n:1\r\n"},
+		"SSUBSCRIBE godis":         {"*3\r\n$10\r\nssubscribe\r\n$5\r\ngodis\r\n:1\r\n", "*3\r\n$8\r\nsmessage\r\n$5\r\ngodis\r\n$5\r\nhello\r\n"},
+		"SUNSUBSCRIBE godis":       {"*3\r\n$12\r\nsunsubscribe\r\n$5\r\ngodis\r\n:0\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	c, err := redis.SPublish("godis", "hello")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	channels, err := redis.PubSubShardChannels("*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"godis"}, channels)
+	numSub, err := redis.PubSubShardNumSub("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int64{"godis": 1}, numSub)
+
+	events := make([]string, 0)
+	var pubsub *RedisPubSub
+	pubsub = &RedisPubSub{
+		OnMessage: func(channel, message string) {
+			events = append(events, "message "+channel+" "+message)
+			assert.Nil(t, pubsub.SUnSubscribe(channel))
+		},
+		OnSubscribe: func(channel string, subscribedChannels int) {
+			events = append(events, fmt.Sprint("subscribe ", channel, " ", subscribedChannels))
+		},
+		OnUnSubscribe: func(channel string, subscribedChannels int) {
+			events = append(events, fmt.Sprint("unsubscribe ", channel, " ", subscribedChannels))
+		},
+	}
+	assert.Nil(t, redis.SSubscribe(pubsub, "godis"))
+	assert.Equal(t, []string{"subscribe godis 1", "message godis hello", "unsubscribe godis 0"}, events)
+	assert.NotNil(t, pubsub.SSubscribe("godis"))
+	assert.NotNil(t, pubsub.SUnSubscribe("godis"))
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.SPublish("godis", "hello")
+	assert.NotNil(t, err)
+	_, err = redisBroken.PubSubShardChannels("*")
+	assert.NotNil(t, err)
+	_, err = redisBroken.PubSubShardNumSub("godis")
+	assert.NotNil(t, err)
+	assert.NotNil(t, redisBroken.SSubscribe(pubsub, "godis"))
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.SPublish("godis", "hello")
+	assert.NotNil(t, err)
+	_, err = redisBroken.PubSubShardChannels("*")
+	assert.NotNil(t, err)
+	_, err = redisBroken.PubSubShardNumSub("godis")
+	assert.NotNil(t, err)
+	assert.NotNil(t, redisBroken.SSubscribe(pubsub, "godis"))
+}
+
+func TestRedis_PubSubMessage(t *testing.T) {
+	//the payload contains CRLF and invalid utf-8,it must be delivered as it is published
+	payload := []byte("a\r\n\x00\xffb")
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"PSUBSCRIBE god*": {"*3\r\n$10\r\npsubscribe\r\n$4\r\ngod*\r\n:1\r\n",
+			"*2\r\n$4\r\npong\r\n$0\r\n\r\n",
+			"*4\r\n$8\r\npmessage\r\n$4\r\ngod*\r\n$5\r\ngodis\r\n$6\r\n" + string(payload) + "\r\n"},
+		"PUNSUBSCRIBE god*": {"*3\r\n$12\r\npunsubscribe\r\n$4\r\ngod*\r\n:0\r\n"},
+		"QUIT":              {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+
+	messages := make([]*Message, 0)
+	var pmessage string
+	var pubsub *RedisPubSub
+	pubsub = &RedisPubSub{
+		OnReceive: func(msg *Message) {
+			messages = append(messages, msg)
+			if msg.Kind == MessageKindPMessage {
+				assert.Nil(t, pubsub.PUnSubscribe(msg.Pattern))
+			}
+		},
+		OnPMessage: func(pattern string, channel, message string) {
+			pmessage = message
+		},
+	}
+	assert.Nil(t, redis.PSubscribe(pubsub, "god*"))
+	assert.Equal(t, []*Message{
+		{Kind: MessageKindPSubscribe, Pattern: "god*", Count: 1},
+		{Kind: MessageKindPong, Payload: []byte{}},
+		{Kind: MessageKindPMessage, Pattern: "god*", Channel: "godis", Payload: payload},
+		{Kind: MessageKindPUnSubscribe, Pattern: "god*", Count: 0},
+	}, messages)
+	assert.Equal(t, string(payload), pmessage)
+	assert.Equal(t, "pmessage", MessageKindPMessage.String())
+
+	_, err := parsePubSubMessage([]interface{}{[]byte("unknown"), []byte("godis")})
+	assert.NotNil(t, err)
+	_, err = parsePubSubMessage([]interface{}{[]byte("message"), []byte("godis")})
+	assert.NotNil(t, err)
+}
+
+func TestRedis_PubSubKeepAlive(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SUBSCRIBE godis":   {"*3\r\n$9\r\nsubscribe\r\n$5\r\ngodis\r\n:1\r\n"},
+		"UNSUBSCRIBE godis": {"*3\r\n$11\r\nunsubscribe\r\n$5\r\ngodis\r\n:0\r\n"},
+		"PING":              {"*2\r\n$4\r\npong\r\n$0\r\n\r\n"},
+		"PING hello":        {"*2\r\n$4\r\npong\r\n$5\r\nhello\r\n"},
+		"QUIT":              {"+OK\r\n"},
+	})
+	defer closeServer()
+	//the subscription outlives SoTimeout,the pongs keep it alive
+	fakeOption.SoTimeout = 100 * time.Millisecond
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	pongs := make([]string, 0)
+	var pubsub *RedisPubSub
+	pubsub = &RedisPubSub{
+		PingInterval: 50 * time.Millisecond,
+		OnSubscribe: func(channel string, subscribedChannels int) {
+			assert.Nil(t, pubsub.Ping("hello"))
+		},
+		OnPong: func(channel string) {
+			pongs = append(pongs, channel)
+			if len(pongs) == 5 {
+				assert.Nil(t, pubsub.UnSubscribe("godis"))
+			}
+		},
+	}
+	assert.Nil(t, redis.Subscribe(pubsub, "godis"))
+	assert.Equal(t, []string{"hello", "", "", "", ""}, pongs)
+	assert.NotNil(t, pubsub.Ping())
+
+	//the server never replies to PING,the dead connection is detected
+	silentOption, closeSilentServer := newFakeServer(t, map[string][]string{
+		"SUBSCRIBE godis": {"*3\r\n$9\r\nsubscribe\r\n$5\r\ngodis\r\n:1\r\n"},
+	})
+	defer closeSilentServer()
+	silent := NewRedis(silentOption)
+	defer silent.Close()
+	start := time.Now()
+	err := silent.Subscribe(&RedisPubSub{PingInterval: 50 * time.Millisecond, PingTimeout: 50 * time.Millisecond}, "godis")
+	assert.True(t, errors.Is(err, ErrPingTimeout))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
 func TestRedis_Psubscribe(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -778,6 +1143,22 @@ func TestRedis_Scan(t *testing.T) {
 	}
 	assert.Equal(t, 1000, total)
 
+	redis.HSet("godis_hash", "a", "1")
+	params = NewScanParams().Match("godis*").Count(100).Type("hash")
+	assert.Equal(t, "hash", params.GetType())
+	cursor = "0"
+	keys := make([]string, 0)
+	for {
+		result, err := redis.Scan(cursor, params)
+		assert.Nil(t, err)
+		keys = append(keys, result.Results...)
+		cursor = result.Cursor
+		if result.Cursor == "0" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"godis_hash"}, keys)
+
 	redisBroken := NewRedis(option1)
 	defer redisBroken.Close()
 	m, _ := redisBroken.Multi()
@@ -117,3 +117,60 @@ func TestRedis_SentinelSlaves(t *testing.T) {
 	_, err = redisBroken.SentinelSlaves("a")
 	assert.NotNil(t, err)
 }
+
+func TestRedis_SentinelMasterByName(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SENTINEL master mymaster":    {"*20\r\n$4\r\nname\r\n$8\r\nmymaster\r\n$2\r\nip\r\n$9\r\n127.0.0.1\r\n$4\r\nport\r\n$4\r\n6379\r\n$5\r\nrunid\r\n$3\r\nabc\r\n$5\r\nflags\r\n$13\r\nmaster,s_down\r\n$10\r\nnum-slaves\r\n$1\r\n1\r\n$19\r\nnum-other-sentinels\r\n$1\r\n2\r\n$6\r\nquorum\r\n$1\r\n2\r\n$23\r\ndown-after-milliseconds\r\n$5\r\n30000\r\n$12\r\nconfig-epoch\r\n$1\r\n3\r\n"},
+		"SENTINEL master unknown":     {"-ERR No such master with that name\r\n"},
+		"SENTINEL sentinels mymaster": {"*1\r\n*6\r\n$4\r\nname\r\n$2\r\ns1\r\n$2\r\nip\r\n$9\r\n127.0.0.1\r\n$4\r\nport\r\n$5\r\n26380\r\n"},
+		"SENTINEL ckquorum mymaster":  {"+OK 3 usable Sentinels. Quorum and failover authorization can be reached\r\n"},
+		"SENTINEL flushconfig":        {"+OK\r\n"},
+		"SENTINEL simulate-failure crash-after-election crash-after-promotion": {"+OK\r\n"},
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	master, err := redis.SentinelMasterByName("mymaster")
+	assert.Nil(t, err)
+	assert.Equal(t, "mymaster", master.Name)
+	assert.Equal(t, "127.0.0.1", master.IP)
+	assert.Equal(t, 6379, master.Port)
+	assert.Equal(t, []string{"master", "s_down"}, master.Flags)
+	assert.Equal(t, int64(1), master.NumSlaves)
+	assert.Equal(t, int64(2), master.NumOtherSentinels)
+	assert.Equal(t, int64(2), master.Quorum)
+	assert.Equal(t, int64(30000), master.DownAfterMilliseconds)
+	assert.Equal(t, int64(3), master.ConfigEpoch)
+	assert.Equal(t, "abc", master.Fields["runid"])
+	_, err = redis.SentinelMasterByName("unknown")
+	assert.NotNil(t, err)
+
+	sentinels, err := redis.SentinelSentinels("mymaster")
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]string{{"name": "s1", "ip": "127.0.0.1", "port": "26380"}}, sentinels)
+	s, err := redis.SentinelCkQuorum("mymaster")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK 3 usable Sentinels. Quorum and failover authorization can be reached", s)
+	s, err = redis.SentinelFlushConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.SentinelSimulateFailure(SimulateFailureCrashAfterElection, SimulateFailureCrashAfterPromotion)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.SentinelMasterByName("mymaster")
+	assert.NotNil(t, err)
+	_, err = redisBroken.SentinelSentinels("mymaster")
+	assert.NotNil(t, err)
+	_, err = redisBroken.SentinelCkQuorum("mymaster")
+	assert.NotNil(t, err)
+	_, err = redisBroken.SentinelFlushConfig()
+	assert.NotNil(t, err)
+	_, err = redisBroken.SentinelSimulateFailure(SimulateFailureCrashAfterElection)
+	assert.NotNil(t, err)
+}
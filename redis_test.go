@@ -1,8 +1,14 @@
 package godis
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"net"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -30,6 +36,82 @@ func initDb() {
 	redis.Close()
 }
 
+//newFakeServer serve the commands newer than the test server with canned replies,
+//replies maps a command joined by spaces to the raw replies written for it,unknown commands get no reply
+func newFakeServer(t *testing.T, replies map[string][]string) (*Option, func()) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeConn(conn, replies)
+		}
+	}()
+	return &Option{Host: "localhost", Port: listener.Addr().(*net.TCPAddr).Port}, func() {
+		listener.Close()
+	}
+}
+
+func serveFakeConn(conn net.Conn, replies map[string][]string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			reader.ReadString('\n')
+			arg, _ := reader.ReadString('\n')
+			args = append(args, strings.TrimSpace(arg))
+		}
+		for _, reply := range replies[strings.Join(args, " ")] {
+			conn.Write([]byte(reply))
+		}
+	}
+}
+
+//listenLater serve at a free port after delay,the i-th connection is served with replies[i],
+//the last replies serve the rest,replies maps a command joined by spaces to the raw replies written for it
+func listenLater(t *testing.T, delay time.Duration, replies ...map[string][]string) (int, func()) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	closed := make(chan struct{})
+	go func() {
+		select {
+		case <-time.After(delay):
+		case <-closed:
+			return
+		}
+		listener, err := net.Listen("tcp", fmt.Sprint("localhost:", port))
+		assert.Nil(t, err)
+		go func() {
+			<-closed
+			listener.Close()
+		}()
+		for i := 0; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if i >= len(replies) {
+				i = len(replies) - 1
+			}
+			go serveFakeConn(conn, replies[i])
+		}
+	}()
+	return port, func() {
+		close(closed)
+	}
+}
+
 func TestRedis_Append(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -136,27 +218,108 @@ func TestRedis_Bitfield(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_BitFieldWithArgs(t *testing.T) {
+	//the replies of BITFIELD are served by a fake server
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"BITFIELD godis SET u8 0 255 GET u8 0":                                     {"*2\r\n:0\r\n:255\r\n"},
+		"BITFIELD godis OVERFLOW FAIL INCRBY u8 0 1 OVERFLOW SAT INCRBY i8 8 -200": {"*2\r\n$-1\r\n:-128\r\n"},
+		"BITFIELD_RO godis GET u8 0 GET i64 8":                                     {"*2\r\n:255\r\n:-128\r\n"},
+		"QUIT":                                                                     {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	arr, err := redis.BitFieldWithArgs("godis", NewBitFieldArgs().Set("u8", 0, 255).Get("u8", 0))
+	assert.Nil(t, err)
+	assert.Len(t, arr, 2)
+	assert.Equal(t, int64(0), *arr[0])
+	assert.Equal(t, int64(255), *arr[1])
+	arr, err = redis.BitFieldWithArgs("godis", NewBitFieldArgs().Overflow(OverflowFail).IncrBy("u8", 0, 1).Overflow(OverflowSat).IncrBy("i8", 8, -200))
+	assert.Nil(t, err)
+	assert.Len(t, arr, 2)
+	assert.Nil(t, arr[0])
+	assert.Equal(t, int64(-128), *arr[1])
+	ro, err := redis.BitFieldRo("godis", NewBitFieldArgs().Get("u8", 0).Get("i64", 8))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{255, -128}, ro)
+
+	_, err = redis.BitFieldRo("godis", NewBitFieldArgs().Set("u8", 0, 1))
+	assert.NotNil(t, err)
+	_, err = redis.BitFieldWithArgs("godis", NewBitFieldArgs().Get("u64", 0))
+	assert.NotNil(t, err)
+	_, err = redis.BitFieldWithArgs("godis", NewBitFieldArgs().Get("x8", 0))
+	assert.NotNil(t, err)
+	_, err = redis.BitFieldRo("godis", NewBitFieldArgs().Get("u8", -1))
+	assert.NotNil(t, err)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.BitFieldWithArgs("godis", NewBitFieldArgs().Get("u8", 0))
+	assert.NotNil(t, err)
+	_, err = redisBroken.BitFieldRo("godis", NewBitFieldArgs().Get("u8", 0))
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.BitFieldWithArgs("godis", NewBitFieldArgs().Get("u8", 0))
+	assert.NotNil(t, err)
+	_, err = redisBroken.BitFieldRo("godis", NewBitFieldArgs().Get("u8", 0))
+	assert.NotNil(t, err)
+}
+
 func TestRedis_Bitpos(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
 	defer redis.Close()
 	redis.Set("godis", "\x00\xff\xf0")
-	s, err := redis.BitPos("godis", true, &BitPosParams{params: [][]byte{IntToByteArr(0)}})
+	s, err := redis.BitPos("godis", true, NewBitPosParams(0))
 	assert.Nil(t, err)
 	assert.Equal(t, int64(8), s)
 
 	redisBroken := NewRedis(option)
 	defer redisBroken.Close()
 	m, _ := redisBroken.Multi()
-	_, err = redisBroken.BitPos("godis", true, &BitPosParams{params: [][]byte{IntToByteArr(0)}})
+	_, err = redisBroken.BitPos("godis", true, NewBitPosParams(0))
 	assert.Nil(t, err)
 	m.Discard()
 	redisBroken.client.connection.host = "localhost1"
 	redisBroken.Close()
-	_, err = redisBroken.BitPos("godis", true, &BitPosParams{params: [][]byte{IntToByteArr(0)}})
+	_, err = redisBroken.BitPos("godis", true, NewBitPosParams(0))
 	assert.NotNil(t, err)
 }
 
+func TestRedis_BitUnit(t *testing.T) {
+	//the BIT unit is served by a fake server
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"BITCOUNT godis 5 30 BIT": {":17\r\n"},
+		"BITCOUNT godis 0 1 BYTE": {":8\r\n"},
+		"BITPOS godis 1 7 15 BIT": {":8\r\n"},
+		"BITPOS godis 0 1 -1":     {":12\r\n"},
+		"BITPOS godis 1 2":        {":16\r\n"},
+		"QUIT":                    {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	c, err := redis.BitCountRange("godis", 5, 30, BitUnitBit)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(17), c)
+	c, err = redis.BitCountRange("godis", 0, 1, BitUnitByte)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8), c)
+	p, err := redis.BitPos("godis", true, NewBitPosParams(7).End(15).Unit(BitUnitBit))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(8), p)
+	p, err = redis.BitPos("godis", false, NewBitPosParams(1).End(-1))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(12), p)
+	//the unit is ignored without the end
+	p, err = redis.BitPos("godis", true, NewBitPosParams(2).Unit(BitUnitBit))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(16), p)
+}
+
 func TestRedis_Decr(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -205,6 +368,442 @@ func TestRedis_DecrBy(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_DisconnectPolicy(t *testing.T) {
+	redis := NewRedis(&Option{Host: "localhost", Port: 1})
+	defer redis.Close()
+	_, err := redis.Get("godis")
+	assert.True(t, errors.Is(err, ErrDisconnected))
+	_, ok := err.(*ConnectError)
+	assert.True(t, ok)
+
+	redis = NewRedis(&Option{Host: "localhost", Port: 1, DisconnectPolicy: BlockUntilReconnect, BlockTimeout: 300 * time.Millisecond})
+	defer redis.Close()
+	start := time.Now()
+	_, err = redis.Get("godis")
+	assert.True(t, errors.Is(err, ErrDisconnected))
+	assert.True(t, time.Since(start) >= 300*time.Millisecond)
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		listener, err := net.Listen("tcp", fmt.Sprint("localhost:", port))
+		assert.Nil(t, err)
+		defer listener.Close()
+		conn, err := listener.Accept()
+		assert.Nil(t, err)
+		conn.Close()
+	}()
+	redis = NewRedis(&Option{Host: "localhost", Port: port, DisconnectPolicy: BlockUntilReconnect, BlockTimeout: 2 * time.Second})
+	defer redis.Close()
+	assert.Nil(t, redis.Connect())
+
+	redis = NewRedis(&Option{Host: "localhost", Port: 1, DisconnectPolicy: QueueUntilReconnect, MaxQueueSize: 1, QueueTTL: 300 * time.Millisecond})
+	defer redis.Close()
+	assert.Nil(t, redis.Send(cmdGet, []byte("godis")))
+	err = redis.Send(cmdGet, []byte("godis"))
+	assert.True(t, errors.Is(err, ErrQueueFull))
+	//the queued command expires while reconnecting
+	_, err = redis.Receive()
+	assert.True(t, errors.Is(err, ErrQueueFull))
+}
+
+func TestRedis_QueueUntilReconnect(t *testing.T) {
+	//the queued commands are sent in order once reconnected
+	port, closeServer := listenLater(t, 200*time.Millisecond, map[string][]string{
+		"RPUSH godis first":  {":1\r\n"},
+		"RPUSH godis second": {":2\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(&Option{Host: "localhost", Port: port, DisconnectPolicy: QueueUntilReconnect, QueueTTL: 2 * time.Second})
+	defer redis.Close()
+	assert.Nil(t, redis.Send(cmdRPush, []byte("godis"), []byte("first")))
+	assert.Nil(t, redis.Send(cmdRPush, []byte("godis"), []byte("second")))
+	obj, err := redis.Receive()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), obj)
+	obj, err = redis.Receive()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), obj)
+
+	//the command expired while reconnecting doesn't spoil the deadline of the later commands
+	port, closeServer = listenLater(t, 550*time.Millisecond, map[string][]string{
+		"GET b": {"$1\r\nb\r\n"},
+		"GET c": {"$1\r\nc\r\n"},
+	})
+	defer closeServer()
+	redis = NewRedis(&Option{Host: "localhost", Port: port, DisconnectPolicy: QueueUntilReconnect, QueueTTL: 500 * time.Millisecond, CommandTimeout: 200 * time.Millisecond})
+	defer redis.Close()
+	assert.Nil(t, redis.Send(cmdGet, []byte("a")))
+	time.Sleep(300 * time.Millisecond)
+	assert.Nil(t, redis.Send(cmdGet, []byte("b")))
+	_, err = redis.Receive()
+	assert.True(t, errors.Is(err, ErrQueueFull))
+	obj, err = redis.Receive()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("b"), obj)
+	assert.Equal(t, redis.client.sent, redis.client.replied)
+	time.Sleep(300 * time.Millisecond)
+	s, err := redis.Get("c")
+	assert.Nil(t, err)
+	assert.Equal(t, "c", s)
+
+	//the handshake on reconnect isn't taken for a concurrent use
+	port, closeServer = listenLater(t, 200*time.Millisecond, map[string][]string{
+		"AUTH godis": {"+OK\r\n"},
+		"GET a":      {"$1\r\na\r\n"},
+	})
+	defer closeServer()
+	redis = NewRedis(&Option{Host: "localhost", Port: port, Password: "godis", DisconnectPolicy: QueueUntilReconnect, DetectConcurrentUse: true})
+	defer redis.Close()
+	assert.Nil(t, redis.Send(cmdGet, []byte("a")))
+	obj, err = redis.Receive()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("a"), obj)
+
+	//the commands stay queued when the handshake is rejected,the expired ones still fail first
+	port, closeServer = listenLater(t, 550*time.Millisecond, map[string][]string{
+		"AUTH godis": {"-ERR invalid password\r\n"},
+	}, map[string][]string{
+		"AUTH godis": {"+OK\r\n"},
+		"GET b":      {"$1\r\nb\r\n"},
+	})
+	defer closeServer()
+	redis = NewRedis(&Option{Host: "localhost", Port: port, Password: "godis", DisconnectPolicy: QueueUntilReconnect, QueueTTL: 500 * time.Millisecond})
+	defer redis.Close()
+	assert.Nil(t, redis.Send(cmdGet, []byte("a")))
+	time.Sleep(300 * time.Millisecond)
+	assert.Nil(t, redis.Send(cmdGet, []byte("b")))
+	_, err = redis.Receive()
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrQueueFull))
+	_, err = redis.Receive()
+	assert.True(t, errors.Is(err, ErrQueueFull))
+	obj, err = redis.Receive()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("b"), obj)
+}
+
+func TestRedis_DetectConcurrentUse(t *testing.T) {
+	flushAll()
+	redis := NewRedis(&Option{Host: "localhost", Port: 6379, DetectConcurrentUse: true})
+	defer redis.Close()
+	pusher := NewRedis(option)
+	defer pusher.Close()
+	redis.Set("godis", "good")
+	done := make(chan []string)
+	go func() {
+		arr, _ := redis.BLPopTimeout(5, "queue")
+		done <- arr
+	}()
+	time.Sleep(100 * time.Millisecond)
+	//the connection is waiting for the reply of BLPOP
+	_, err := redis.Get("godis")
+	assert.Equal(t, ErrConcurrentUse, err)
+	err = redis.Send(cmdGet, []byte("godis"))
+	assert.Equal(t, ErrConcurrentUse, err)
+	//reading the reply of BLPOP from another goroutine fails without counting it
+	_, err = redis.client.getOne()
+	assert.Equal(t, ErrConcurrentUse, err)
+	pusher.LPush("queue", "godis")
+	assert.Equal(t, []string{"queue", "godis"}, <-done)
+	assert.Equal(t, 0, redis.Pending())
+	assert.Equal(t, redis.client.sent, redis.client.replied)
+
+	//the connection is not corrupted
+	s, err := redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	p := redis.Pipelined()
+	exists, _ := p.Exists("godis")
+	assert.Nil(t, p.Sync())
+	c, _ := ToInt64Reply(exists.Get())
+	assert.Equal(t, int64(1), c)
+	assert.Equal(t, int32(0), redis.client.users)
+}
+
+func TestRedis_TCPOptions(t *testing.T) {
+	redis := NewRedis(&Option{Host: "localhost", Port: 6379, WriteTimeout: time.Second, KeepAlive: 10 * time.Second, DisableTCPNoDelay: true})
+	defer redis.Close()
+	s, err := redis.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+	assert.Equal(t, time.Second, redis.client.connection.writeTimeout)
+	assert.Equal(t, 10*time.Second, redis.client.connection.keepAlive)
+	assert.True(t, redis.client.connection.disableTCPNoDelay)
+
+	redis = NewRedis(&Option{Host: "localhost", Port: 6379, KeepAlive: -1})
+	defer redis.Close()
+	s, err = redis.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+}
+
+func TestRedis_WriteCommand(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	//a large argument is written by a vectored write
+	large := []byte(strings.Repeat("g", largeArgSize*2))
+	err := redis.client.sendCommand(cmdSet, []byte("godis"), large)
+	assert.Nil(t, err)
+	s, err := redis.client.getStatusCodeReply()
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, string(large), s)
+	assert.Nil(t, redis.client.protocol.os.buf)
+
+	//pending commands are flushed before they grow over maxPendingSize
+	count := maxPendingSize/20 + 1
+	for i := 0; i < count; i++ {
+		err = redis.client.sendCommandStr(cmdIncr, "godis1")
+		assert.Nil(t, err)
+		assert.True(t, redis.client.protocol.os.buf == nil || len(*redis.client.protocol.os.buf) < maxPendingSize)
+	}
+	replies, err := redis.client.getAll()
+	assert.Nil(t, err)
+	assert.Len(t, replies, count)
+	s, err = redis.Get("godis1")
+	assert.Nil(t, err)
+	assert.Equal(t, strconv.Itoa(count), s)
+}
+
+func TestRedis_WriteCommand_ReuseLargeArg(t *testing.T) {
+	large := []byte(strings.Repeat("g", largeArgSize))
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SET godis " + string(large): {"+OK\r\n"},
+		"QUIT":                       {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.SoTimeout = time.Second
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	//the large argument is written before sendCommand returns,so it can be reused before the reply is read
+	err := redis.client.sendCommand(cmdSet, []byte("godis"), large)
+	assert.Nil(t, err)
+	assert.Nil(t, redis.client.protocol.os.buf)
+	for i := range large {
+		large[i] = 'x'
+	}
+	s, err := redis.client.getStatusCodeReply()
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+}
+
+func TestRedis_ReadReply(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	//bulk replies are read by length,so they may contain CRLF
+	value := "line1\r\nline2\r"
+	s, err := redis.Set("godis", value)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, value, s)
+	b, err := redis.Dump("godis")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, b)
+
+	arr, err := redis.MGet("godis", "godis1", "godis")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{value, "", value}, arr)
+	arr, err = redis.LRange("godis1", 0, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, arr)
+
+	c, err := redis.Incr("godis")
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(0), c)
+	c, err = redis.Incr("godis1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	//a bulk reply larger than the read buffer
+	large := strings.Repeat("godis\r\n", 4096)
+	redis.Set("godis", large)
+	s, err = redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, large, s)
+	arr, err = redis.MGet("godis", "godis")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{large, large}, arr)
+}
+
+func TestRedis_ErrorTypes(t *testing.T) {
+	server, socket := net.Pipe()
+	defer server.Close()
+	defer socket.Close()
+	c := &connection{socket: socket, soTimeout: time.Second}
+	p := newProtocol(nil, newRedisInputStream(bufio.NewReader(socket), c))
+	go server.Write([]byte("-MOVED 3999 127.0.0.1:6381\r\n-ASK 3999 127.0.0.1:6382\r\n" +
+		"-CLUSTERDOWN The cluster is down\r\n-BUSY Redis is busy running a script\r\n" +
+		"-NOSCRIPT No matching script\r\n-READONLY You can't write against a read only replica.\r\n" +
+		"-LOADING Redis is loading the dataset in memory\r\n-ERR unknown command\r\n"))
+
+	_, err := p.read()
+	var moved *MovedError
+	assert.True(t, errors.As(err, &moved))
+	assert.Equal(t, 3999, moved.Slot)
+	assert.Equal(t, "127.0.0.1:6381", moved.Addr)
+	assert.True(t, IsRedirectError(err))
+	assert.False(t, IsRetryableError(err))
+
+	_, err = p.read()
+	var ask *AskError
+	assert.True(t, errors.As(err, &ask))
+	assert.Equal(t, "127.0.0.1:6382", ask.Addr)
+	assert.True(t, IsRedirectError(err))
+
+	_, err = p.read()
+	var clusterDown *ClusterDownError
+	assert.True(t, errors.As(err, &clusterDown))
+	var clusterErr *ClusterError
+	assert.True(t, errors.As(err, &clusterErr))
+	assert.Equal(t, clusterDown.Message, clusterErr.Message)
+	assert.True(t, IsRetryableError(err))
+
+	_, err = p.read()
+	var busy *BusyError
+	assert.True(t, errors.As(err, &busy))
+	assert.True(t, IsRetryableError(err))
+
+	_, err = p.read()
+	var noScript *NoScriptError
+	assert.True(t, errors.As(err, &noScript))
+	assert.False(t, IsRetryableError(err))
+
+	_, err = p.read()
+	var readOnly *ReadOnlyError
+	assert.True(t, errors.As(err, &readOnly))
+	assert.False(t, IsRedirectError(err))
+
+	_, err = p.read()
+	var loading *LoadingError
+	assert.True(t, errors.As(err, &loading))
+	assert.True(t, IsRetryableError(err))
+
+	_, err = p.read()
+	var dataErr *DataError
+	assert.True(t, errors.As(err, &dataErr))
+	assert.False(t, IsRedirectError(err))
+	assert.False(t, IsRetryableError(err))
+
+	assert.True(t, IsRetryableError(fmt.Errorf("wrapped: %w", newConnectError("broken"))))
+}
+
+func TestRedis_ArrayElementError(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"EXEC":   {"*2\r\n-ERR wrong type\r\n:1\r\n"},
+		"KEYS *": {"*2\r\n$5\r\ngodis"},
+		"PING":   {"+PONG\r\n"},
+		"QUIT":   {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.SoTimeout = 200 * time.Millisecond
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	//an error reply is an element of the array
+	assert.Nil(t, redis.SendByStr("EXEC"))
+	reply, err := redis.Receive()
+	assert.Nil(t, err)
+	arr := reply.([]interface{})
+	assert.IsType(t, &DataError{}, arr[0])
+	assert.Equal(t, int64(1), arr[1])
+	//the array can't be read to the end,the connection is broken
+	assert.Nil(t, redis.SendByStr("KEYS", []byte("*")))
+	reply, err = redis.Receive()
+	assert.Nil(t, reply)
+	assert.IsType(t, &ConnectError{}, err)
+	assert.True(t, redis.client.connection.broken)
+}
+
+func TestRedis_ErrorKinds(t *testing.T) {
+	server, socket := net.Pipe()
+	defer server.Close()
+	defer socket.Close()
+	c := &connection{socket: socket, soTimeout: time.Second}
+	p := newProtocol(nil, newRedisInputStream(bufio.NewReader(socket), c))
+	go server.Write([]byte("-NOAUTH Authentication required.\r\n-WRONGPASS invalid username-password pair\r\n!bad\r\n"))
+	_, err := p.read()
+	assert.True(t, errors.Is(err, ErrAuth))
+	var dataErr *DataError
+	assert.True(t, errors.As(err, &dataErr))
+	_, err = p.read()
+	assert.True(t, errors.Is(err, ErrAuth))
+	assert.False(t, errors.Is(err, ErrConnect))
+	_, err = p.read()
+	assert.True(t, errors.Is(err, ErrProtocol))
+	assert.True(t, errors.Is(err, ErrConnect))
+	assert.False(t, errors.Is(err, ErrTimeout))
+
+	redis := NewRedis(option)
+	defer redis.Close()
+	_, err = redis.Auth("wrong")
+	assert.True(t, errors.Is(err, ErrAuth))
+
+	fake, closeServer := newFakeServer(t, map[string][]string{
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	fake.SoTimeout = 100 * time.Millisecond
+	redis = NewRedis(fake)
+	_, err = redis.Get("godis")
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, errors.Is(err, ErrConnect))
+	var netErr net.Error
+	assert.True(t, errors.As(err, &netErr))
+	redis.Close()
+
+	redis = NewRedis(&Option{Host: "localhost", Port: 1})
+	_, err = redis.Get("godis")
+	assert.True(t, errors.Is(err, ErrConnect))
+	assert.False(t, errors.Is(err, ErrTimeout))
+	assert.False(t, errors.Is(err, ErrAuth))
+	redis.Close()
+
+	pool := NewPool(nil, option)
+	pool.Destroy()
+	_, err = pool.GetResource()
+	assert.True(t, errors.Is(err, ErrPoolClosed))
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestRedis_ErrorWrappedOnce(t *testing.T) {
+	fake, closeServer := newFakeServer(t, map[string][]string{
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	fake.SoTimeout = 100 * time.Millisecond
+	//the typed readers and the generic one return the error of the read as it is
+	redis := NewRedis(fake)
+	_, err := redis.Get("godis")
+	var connectErr *ConnectError
+	assert.True(t, errors.As(err, &connectErr))
+	assert.False(t, errors.As(errors.Unwrap(err), &connectErr))
+	assert.Contains(t, err.Error(), "reading reply")
+	assert.True(t, errors.Is(err, ErrTimeout))
+	redis.Close()
+	redis = NewRedis(fake)
+	assert.Nil(t, redis.Send(cmdGet, []byte("godis")))
+	_, err = redis.Receive()
+	assert.True(t, errors.As(err, &connectErr))
+	assert.False(t, errors.As(errors.Unwrap(err), &connectErr))
+	assert.True(t, errors.Is(err, ErrTimeout))
+	redis.Close()
+
+	pool := NewPool(nil, &Option{Host: "localhost", Port: 1})
+	defer pool.Destroy()
+	_, err = pool.GetResource()
+	assert.True(t, errors.As(err, &connectErr))
+	assert.False(t, errors.As(errors.Unwrap(err), &connectErr))
+}
+
 func TestRedis_Echo(t *testing.T) {
 	redis := NewRedis(option)
 	defer redis.Close()
@@ -402,6 +1001,41 @@ func TestRedis_Get(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_ReturnErrNil(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	s, err := redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "", s)
+
+	redis = NewRedis(&Option{Host: "localhost", Port: 6379, ReturnErrNil: true})
+	defer redis.Close()
+	s, err = redis.Get("godis")
+	assert.Equal(t, ErrNil, err)
+	assert.Equal(t, "", s)
+	s, err = redis.HGet("godis", "field")
+	assert.Equal(t, ErrNil, err)
+	assert.Equal(t, "", s)
+	c, err := redis.ZRank("godis", "member")
+	assert.Equal(t, ErrNil, err)
+	assert.Equal(t, int64(-1), c)
+	arr, err := redis.BLPopTimeout(1, "godis")
+	assert.Equal(t, ErrNil, err)
+	assert.Empty(t, arr)
+
+	redis.Set("godis", "")
+	s, err = redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "", s)
+	s, err = redis.SetWithParams("godis", "good", "nx")
+	assert.Equal(t, ErrNil, err)
+	assert.Equal(t, "", s)
+	c, err = redis.Del("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+}
+
 func TestRedis_GetSet(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -425,6 +1059,32 @@ func TestRedis_GetSet(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_GetDel(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	s, err := redis.GetDel("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "", s)
+	redis.Set("godis", "good")
+	s, err = redis.GetDel("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	c, _ := redis.Exists("godis")
+	assert.Equal(t, int64(0), c)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.GetDel("godis")
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.GetDel("godis")
+	assert.NotNil(t, err)
+}
+
 func TestRedis_Getbit(t *testing.T) {
 	initDb()
 	redis := NewRedis(option)
@@ -744,6 +1404,60 @@ func TestRedis_Hscan(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_HscanNoValues(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	for i := 0; i < 1000; i++ {
+		redis.HSet("godis", fmt.Sprintf("a%d", i), fmt.Sprintf("%d", i))
+	}
+	params := NewScanParams().Match("a*").Count(10).NoValues()
+	cursor := "0"
+	total := 0
+	for {
+		result, err := redis.HScan("godis", cursor, params)
+		assert.Nil(t, err)
+		if err != nil {
+			break
+		}
+		total += len(result.Results)
+		cursor = result.Cursor
+		if result.Cursor == "0" {
+			break
+		}
+	}
+	//total contains key only
+	assert.Equal(t, 1000, total)
+}
+
+func TestRedis_NoValuesOnlyForHscan(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SCAN 0":                 {"*2\r\n$1\r\n0\r\n*1\r\n$5\r\ngodis\r\n"},
+		"SSCAN godis 0":          {"*2\r\n$1\r\n0\r\n*1\r\n$1\r\na\r\n"},
+		"ZSCAN godis 0":          {"*2\r\n$1\r\n0\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n"},
+		"HSCAN godis 0 NOVALUES": {"*2\r\n$1\r\n0\r\n*1\r\n$1\r\na\r\n"},
+		"QUIT":                   {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.SoTimeout = time.Second
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	//NOVALUES is only sent with HSCAN,a command without reply would time out
+	params := NewScanParams().NoValues()
+	result, err := redis.Scan("0", params)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"godis"}, result.Results)
+	result, err = redis.SScan("godis", "0", params)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, result.Results)
+	result, err = redis.ZScan("godis", "0", params)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "1"}, result.Results)
+	result, err = redis.HScan("godis", "0", params)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, result.Results)
+}
+
 func TestRedis_Hset(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -820,26 +1534,80 @@ func TestRedis_Hvals(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
-func TestRedis_Incr(t *testing.T) {
+func TestRedis_HExpire(t *testing.T) {
 	flushAll()
-	pool := NewPool(nil, option)
-	i := 0
-	for ; i < 10000; i++ {
-		redis, err := pool.GetResource()
-		if err != nil {
-			assert.Errorf(t, err, "err happen")
-			return
-		}
-		_, err = redis.Incr("godis")
-		assert.Nil(t, err)
-		redis.Close()
-	}
-	redis, err := pool.GetResource()
-	if err != nil {
-		assert.Errorf(t, err, "err happen")
-		return
-	}
-	reply, err := redis.Get("godis")
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.HSet("godis", "a", "1")
+	redis.HSet("godis", "b", "2")
+
+	arr, err := redis.HExpire("godis", 100, []string{"a", "c"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, -2}, arr)
+	arr, err = redis.HExpire("godis", 200, []string{"a", "b"}, ExpireNX)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{0, 1}, arr)
+	_, err = redis.HExpire("godis", 100, []string{})
+	assert.IsType(t, &DataError{}, err)
+
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"HPEXPIRE godis 1500 XX FIELDS 1 a":     {"*1\r\n:1\r\n"},
+		"HEXPIREAT godis 1700000000 FIELDS 1 a": {"*1\r\n:2\r\n"},
+		"HTTL godis FIELDS 2 a b":               {"*2\r\n:100\r\n:-1\r\n"},
+		"HPTTL godis FIELDS 1 a":                {"*1\r\n:99000\r\n"},
+		"HPERSIST godis FIELDS 2 a c":           {"*2\r\n:1\r\n:-2\r\n"},
+	})
+	defer closeServer()
+	fake := NewRedis(fakeOption)
+	defer fake.Close()
+	arr, err = fake.HPExpire("godis", 1500, []string{"a"}, ExpireXX)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1}, arr)
+	arr, err = fake.HExpireAt("godis", 1700000000, []string{"a"})
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, arr)
+	arr, err = fake.HTtl("godis", "a", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{100, -1}, arr)
+	arr, err = fake.HPTtl("godis", "a")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{99000}, arr)
+	arr, err = fake.HPersist("godis", "a", "c")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, -2}, arr)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.HTtl("godis", "a")
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.HTtl("godis", "a")
+	assert.NotNil(t, err)
+}
+
+func TestRedis_Incr(t *testing.T) {
+	flushAll()
+	pool := NewPool(nil, option)
+	i := 0
+	for ; i < 10000; i++ {
+		redis, err := pool.GetResource()
+		if err != nil {
+			assert.Errorf(t, err, "err happen")
+			return
+		}
+		_, err = redis.Incr("godis")
+		assert.Nil(t, err)
+		redis.Close()
+	}
+	redis, err := pool.GetResource()
+	if err != nil {
+		assert.Errorf(t, err, "err happen")
+		return
+	}
+	reply, err := redis.Get("godis")
 	assert.Nil(t, err)
 	assert.Equal(t, "10000", reply)
 	redis.Close()
@@ -924,6 +1692,79 @@ func TestRedis_IncrByFloat(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_LazyConnect(t *testing.T) {
+	flushAll()
+	redis := NewRedis(&Option{Host: "localhost", Port: 6379, Db: 2, LazyConnect: true})
+	defer redis.Close()
+	assert.False(t, redis.client.isConnected())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, redis.client.ensureConnected())
+		}()
+	}
+	wg.Wait()
+	assert.True(t, redis.client.isConnected())
+
+	s, err := redis.Set("godis", "good")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+
+	redis1 := NewRedis(option)
+	defer redis1.Close()
+	redis1.Select(2)
+	s, err = redis1.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+
+	redisBroken := NewRedis(&Option{Host: "localhost1", Port: 6379, LazyConnect: true})
+	defer redisBroken.Close()
+	_, err = redisBroken.Get("godis")
+	assert.NotNil(t, err)
+	assert.NotNil(t, redisBroken.Connect())
+	redisBroken.client.connection.host = "localhost"
+	assert.Nil(t, redisBroken.Connect())
+	s, err = redisBroken.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+}
+
+func TestRedis_Handshake(t *testing.T) {
+	flushAll()
+	redis := NewRedis(&Option{Host: "localhost", Port: 6379, Db: 2, ClientName: "godis"})
+	defer redis.Close()
+	s, err := redis.Set("godis", "db2")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	err = redis.SendByStr("client", []byte("getname"))
+	assert.Nil(t, err)
+	s, err = ToStrReply(redis.Receive())
+	assert.Nil(t, err)
+	assert.Equal(t, "godis", s)
+
+	//reconnect lands on the same db
+	redis.client.connection.close()
+	s, err = redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "db2", s)
+
+	_, err = redis.Select(3)
+	assert.Nil(t, err)
+	redis.client.connection.close()
+	s, err = redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "", s)
+
+	redisBroken := NewRedis(&Option{Host: "localhost", Port: 6379, Password: "wrong password"})
+	defer redisBroken.Close()
+	_, err = redisBroken.Get("godis")
+	assert.NotNil(t, err)
+	assert.False(t, redisBroken.client.isConnected())
+}
+
 func TestRedis_Lindex(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -956,6 +1797,95 @@ func TestRedis_Lindex(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_LPos(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	c, err := redis.RPush("godis", "a", "b", "c", "b", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), c)
+
+	c, err = redis.LPos("godis", "b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	c, err = redis.LPos("godis", "b", NewLPosParams().Rank(-1))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), c)
+
+	c, err = redis.LPos("godis", "b", NewLPosParams().Rank(2).MaxLen(3))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), c)
+
+	c, err = redis.LPos("godis", "d")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), c)
+
+	arr, err := redis.LPosCount("godis", "b", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 3, 4}, arr)
+
+	arr, err = redis.LPosCount("godis", "b", 2, NewLPosParams().Rank(-1))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{4, 3}, arr)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.LPos("godis", "b")
+	assert.NotNil(t, err)
+	_, err = redisBroken.LPosCount("godis", "b", 0)
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.LPos("godis", "b")
+	assert.NotNil(t, err)
+	_, err = redisBroken.LPosCount("godis", "b", 0)
+	assert.NotNil(t, err)
+}
+
+func TestRedis_PopCount(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.RPush("godis", "a", "b", "c", "d", "e")
+	arr, err := redis.LPopCount("godis", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, arr)
+	arr, err = redis.RPopCount("godis", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"e", "d"}, arr)
+	arr, err = redis.LPopCount("missing", 2)
+	assert.Nil(t, err)
+	assert.Empty(t, arr)
+
+	redis.RPush("godis", "d", "e")
+	p := redis.Pipelined()
+	lpop, _ := p.LPopCount("godis", 1)
+	rpop, _ := p.RPopCount("godis", 10)
+	assert.Nil(t, p.Sync())
+	arr, _ = ToStrArrReply(lpop.Get())
+	assert.Equal(t, []string{"c"}, arr)
+	arr, _ = ToStrArrReply(rpop.Get())
+	assert.Equal(t, []string{"e", "d"}, arr)
+
+	redis.RPush("godis", "a", "b")
+	tx, _ := redis.Multi()
+	lpop, _ = tx.LPopCount("godis", 2)
+	_, err = tx.Exec()
+	assert.Nil(t, err)
+	arr, _ = ToStrArrReply(lpop.Get())
+	assert.Equal(t, []string{"a", "b"}, arr)
+
+	m, _ := redis.Multi()
+	_, err = redis.LPopCount("godis", 1)
+	assert.NotNil(t, err)
+	_, err = redis.RPopCount("godis", 1)
+	assert.NotNil(t, err)
+	m.Discard()
+}
+
 func TestRedis_List0(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -1361,6 +2291,69 @@ func TestRedis_Send(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestRedis_ReceiveTyped(t *testing.T) {
+	initDb()
+	redis := NewRedis(option)
+	defer redis.Close()
+	//nothing was sent,Receive doesn't block
+	_, err := redis.Receive()
+	assert.NotNil(t, err)
+	_, err = redis.ReceiveTyped(ReplyBulk)
+	assert.NotNil(t, err)
+
+	redis.Send(cmdSet, []byte("godis1"), []byte("good1"))
+	redis.Send(cmdIncr, []byte("counter"))
+	redis.Send(cmdGet, []byte("godis"))
+	redis.Send(cmdGet, []byte("missing"))
+	redis.Send(cmdGet, []byte("godis1"))
+	redis.Send(cmdMGet, []byte("godis"), []byte("missing"))
+	redis.Send(cmdIncr, []byte("godis"))
+	redis.Send(cmdGet, []byte("godis"))
+	redis.Send(cmdEcho, []byte("godis"))
+	assert.Equal(t, 9, redis.Pending())
+
+	s, err := ToStrReply(redis.ReceiveTyped(ReplyStatus))
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	c, err := ToInt64Reply(redis.ReceiveTyped(ReplyInteger))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	s, err = ToStrReply(redis.ReceiveTyped(ReplyBulk))
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	s, err = ToStrReply(redis.ReceiveTyped(ReplyBulk))
+	assert.Nil(t, err)
+	assert.Equal(t, "", s)
+	b, err := redis.ReceiveTyped(ReplyBinary)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("good1"), b)
+	arr, err := ToStrArrReply(redis.ReceiveTyped(ReplyMultiBulk))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"good", ""}, arr)
+	//an error reply doesn't affect the replies after it
+	_, err = redis.ReceiveTyped(ReplyInteger)
+	assert.NotNil(t, err)
+	//a reply of another type is read
+	_, err = redis.ReceiveTyped(ReplyInteger)
+	assert.NotNil(t, err)
+	obj, err := redis.ReceiveTyped(ReplyObject)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("godis"), obj)
+	assert.Equal(t, 0, redis.Pending())
+	//a nil bulk reply is a nil integer reply
+	redis.Send(cmdGet, []byte("missing"))
+	c, err = ToInt64Reply(redis.ReceiveTyped(ReplyInteger))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-1), c)
+
+	redis.Send(cmdPing)
+	_, err = redis.ReceiveTyped(ReplyType(100))
+	assert.NotNil(t, err)
+	s, err = ToStrReply(redis.Receive())
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+}
+
 func TestRedis_SendByStr(t *testing.T) {
 	initDb()
 	redis := NewRedis(option)
@@ -1383,6 +2376,68 @@ func TestRedis_SendByStr(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+//unregisterCommands remove the commands registered by the test when it finishes,so the tests don't depend on their order
+func unregisterCommands(t *testing.T, names ...string) {
+	t.Cleanup(func() {
+		commandRegistry.Lock()
+		defer commandRegistry.Unlock()
+		for _, name := range names {
+			delete(commandRegistry.commands, name)
+		}
+	})
+}
+
+func TestRedis_SendCommand(t *testing.T) {
+	initDb()
+	redis := NewRedis(option)
+	defer redis.Close()
+	unregisterCommands(t, "STRLEN", "MSET")
+	strlen, err := RegisterCommand("strlen", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "STRLEN", strlen.Name())
+	assert.Equal(t, 2, strlen.Arity())
+	cmd, err := RegisterCommand("STRLEN", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, strlen, cmd)
+	_, err = RegisterCommand("STRLEN", -2)
+	assert.NotNil(t, err)
+	_, err = RegisterCommand(" ", 1)
+	assert.NotNil(t, err)
+	cmd, ok := LookupCommand("StrLen")
+	assert.True(t, ok)
+	assert.Equal(t, strlen, cmd)
+	_, ok = LookupCommand("godis")
+	assert.False(t, ok)
+
+	err = redis.SendCommand(strlen, []byte("godis"))
+	assert.Nil(t, err)
+	c, err := ToInt64Reply(redis.Receive())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), c)
+
+	err = redis.SendCommand(strlen)
+	assert.NotNil(t, err)
+	err = redis.SendByStr("strlen", []byte("godis"), []byte("godis"))
+	assert.NotNil(t, err)
+
+	mset, err := RegisterCommand("MSET", -3)
+	assert.Nil(t, err)
+	err = redis.SendCommand(mset, []byte("godis"))
+	assert.NotNil(t, err)
+	err = redis.SendByStr("mset", []byte("godis"), []byte("good"), []byte("godis1"), []byte("good1"))
+	assert.Nil(t, err)
+	s, err := ToStrReply(redis.Receive())
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	err = redisBroken.SendCommand(strlen, []byte("godis"))
+	assert.NotNil(t, err)
+}
+
 func TestRedis_Set(t *testing.T) {
 	flushAll()
 	redis := NewRedis(option)
@@ -1580,6 +2635,14 @@ func TestRedis_Smembers(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"1", "2", "3"}, arr)
 
+	set, err := redis.SMembersMap("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]struct{}{"1": {}, "2": {}, "3": {}}, set)
+
+	set, err = redis.SMembersMap("godis1")
+	assert.Nil(t, err)
+	assert.Empty(t, set)
+
 	s, err := redis.SRandMember("godis")
 	assert.Nil(t, err)
 	assert.Contains(t, []string{"1", "2", "3"}, s)
@@ -1588,6 +2651,10 @@ func TestRedis_Smembers(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Len(t, arr, 2)
 
+	arr, err = redis.SRandMemberBatch("godis", -5)
+	assert.Nil(t, err)
+	assert.Len(t, arr, 5)
+
 	c, err = redis.SRem("godis", "1")
 	assert.Nil(t, err)
 	assert.Equal(t, int64(1), c)
@@ -1611,6 +2678,8 @@ func TestRedis_Smembers(t *testing.T) {
 	assert.NotNil(t, err)
 	_, err = redisBroken.SMembers("godis")
 	assert.NotNil(t, err)
+	_, err = redisBroken.SMembersMap("godis")
+	assert.NotNil(t, err)
 	_, err = redisBroken.SRandMember("godis")
 	assert.NotNil(t, err)
 	_, err = redisBroken.SRandMemberBatch("godis", 2)
@@ -1632,6 +2701,8 @@ func TestRedis_Smembers(t *testing.T) {
 	assert.NotNil(t, err)
 	_, err = redisBroken.SMembers("godis")
 	assert.NotNil(t, err)
+	_, err = redisBroken.SMembersMap("godis")
+	assert.NotNil(t, err)
 	_, err = redisBroken.SRandMember("godis")
 	assert.NotNil(t, err)
 	_, err = redisBroken.SRandMemberBatch("godis", 2)
@@ -2061,6 +3132,56 @@ func TestRedis_Zadd2(t *testing.T) {
 	arr, err := redis.ZRange("godis", 0, -1)
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"a", "c"}, arr)
+
+	//only raise the score of an existing member
+	zaddParam = NewZAddParams().GT().CH()
+	c, err = redis.ZAdd("godis", 1, "c", zaddParam)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), c)
+	c, err = redis.ZAdd("godis", 5, "c", zaddParam)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	c, err = redis.ZAdd("godis", 0, "a", NewZAddParams().LT().CH())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	score, _ := redis.ZScore("godis", "c")
+	assert.Equal(t, float64(5), score)
+}
+
+func TestRedis_ZPopMin(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	c, err := redis.ZAddByMap("godis", map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), c)
+
+	tuples, err := redis.ZPopMin("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "a", score: 1}}, tuples)
+
+	tuples, err = redis.ZPopMax("godis", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "d", score: 4}, {element: "c", score: 3}}, tuples)
+
+	tuples, err = redis.ZPopMin("godis", 5)
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "b", score: 2}}, tuples)
+
+	tuples, err = redis.ZPopMax("godis")
+	assert.Nil(t, err)
+	assert.Empty(t, tuples)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.ZPopMin("godis")
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.ZPopMax("godis")
+	assert.NotNil(t, err)
 }
 
 func TestRedis_Zscan(t *testing.T) {
@@ -2099,3 +3220,505 @@ func TestRedis_Zscan(t *testing.T) {
 	_, err = redisBroken.ZScan("godis", cursor, params)
 	assert.NotNil(t, err)
 }
+
+func TestRedis_Stream(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	s, err := redis.XGroupCreate("stream", "group", "$", true)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	_, err = redis.XGroupCreate("stream", "group", "$", true)
+	assert.NotNil(t, err)
+
+	id1, err := redis.XAdd("stream", "*", map[string]string{"name": "a"})
+	assert.Nil(t, err)
+	id2, err := redis.XAdd("stream", "*", map[string]string{"name": "b"})
+	assert.Nil(t, err)
+	c, err := redis.XLen("stream")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+
+	streams, err := redis.XReadGroup("group", "c1", 1, 0, "stream", ">")
+	assert.Nil(t, err)
+	assert.Equal(t, []StreamEntries{{Stream: "stream", Entries: []StreamEntry{{ID: id1, Fields: map[string]string{"name": "a"}}}}}, streams)
+	streams, err = redis.XReadGroup("group", "c1", 0, 0, "stream", ">")
+	assert.Nil(t, err)
+	assert.Equal(t, []StreamEntry{{ID: id2, Fields: map[string]string{"name": "b"}}}, streams[0].Entries)
+	streams, err = redis.XReadGroup("group", "c1", 0, 100*time.Millisecond, "stream", ">")
+	assert.Nil(t, err)
+	assert.Empty(t, streams)
+
+	pending, err := redis.XPending("stream", "group", "-", "+", 10)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, id1, pending[0].ID)
+	assert.Equal(t, "c1", pending[0].Consumer)
+	assert.Equal(t, int64(1), pending[0].Deliveries)
+	pending, err = redis.XPending("stream", "group", "-", "+", 10, NewXPendingParams().Consumer("c2"))
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+	pending, err = redis.XPending("stream", "group", "-", "+", 10, NewXPendingParams().Idle(time.Hour))
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+
+	c, err = redis.XAck("stream", "group", id1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	result, err := redis.XAutoClaim("stream", "group", "c2", 0, "0-0", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, "0-0", result.Next)
+	assert.Equal(t, []StreamEntry{{ID: id2, Fields: map[string]string{"name": "b"}}}, result.Entries)
+	pending, err = redis.XPending("stream", "group", "-", "+", 10, NewXPendingParams().Consumer("c2"))
+	assert.Nil(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, int64(2), pending[0].Deliveries)
+
+	c, err = redis.XDel("stream", id1, id2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.XAdd("stream", "*", map[string]string{"name": "a"})
+	assert.NotNil(t, err)
+	_, err = redisBroken.XLen("stream")
+	assert.NotNil(t, err)
+	_, err = redisBroken.XDel("stream", id1)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XAck("stream", "group", id1)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XGroupCreate("stream", "group", "$", true)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XReadGroup("group", "c1", 0, 0, "stream", ">")
+	assert.NotNil(t, err)
+	_, err = redisBroken.XPending("stream", "group", "-", "+", 10)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XAutoClaim("stream", "group", "c2", 0, "0-0", 10)
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.XAdd("stream", "*", map[string]string{"name": "a"})
+	assert.NotNil(t, err)
+	_, err = redisBroken.XLen("stream")
+	assert.NotNil(t, err)
+	_, err = redisBroken.XDel("stream", id1)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XAck("stream", "group", id1)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XGroupCreate("stream", "group", "$", true)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XReadGroup("group", "c1", 0, 0, "stream", ">")
+	assert.NotNil(t, err)
+	_, err = redisBroken.XPending("stream", "group", "-", "+", 10)
+	assert.NotNil(t, err)
+	_, err = redisBroken.XAutoClaim("stream", "group", "c2", 0, "0-0", 10)
+	assert.NotNil(t, err)
+}
+
+func TestRedis_Dump(t *testing.T) {
+	initDb()
+	redis := NewRedis(option)
+	defer redis.Close()
+	value, err := redis.Dump("godis")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, value)
+	missing, err := redis.Dump("missing")
+	assert.Nil(t, err)
+	assert.Nil(t, missing)
+
+	s, err := redis.Restore("godis1", 0, value)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	_, err = redis.Restore("godis1", 0, value)
+	assert.NotNil(t, err)
+	s, err = redis.RestoreReplace("godis1", 10000, value)
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+	s, err = redis.Get("godis1")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m, _ := redisBroken.Multi()
+	_, err = redisBroken.Dump("godis")
+	assert.NotNil(t, err)
+	_, err = redisBroken.Restore("godis1", 0, value)
+	assert.NotNil(t, err)
+	_, err = redisBroken.RestoreReplace("godis1", 0, value)
+	assert.NotNil(t, err)
+	m.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	_, err = redisBroken.Dump("godis")
+	assert.NotNil(t, err)
+	_, err = redisBroken.Restore("godis1", 0, value)
+	assert.NotNil(t, err)
+	_, err = redisBroken.RestoreReplace("godis1", 0, value)
+	assert.NotNil(t, err)
+}
+
+func TestOption_KeyMapper(t *testing.T) {
+	flushAll()
+	mapped := *option
+	mapped.KeyMapper = func(key string) string {
+		return "tenant1:" + key
+	}
+	redis := NewRedis(&mapped)
+	defer redis.Close()
+	raw := NewRedis(option)
+	defer raw.Close()
+
+	_, err := redis.Set("godis", "good")
+	assert.Nil(t, err)
+	s, _ := raw.Get("tenant1:godis")
+	assert.Equal(t, "good", s)
+	s, err = redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+
+	//the values aren't keys
+	_, err = redis.MSet("godis1", "godis2", "godis2", "godis1")
+	assert.Nil(t, err)
+	arr, _ := raw.MGet("tenant1:godis1", "tenant1:godis2")
+	assert.Equal(t, []string{"godis2", "godis1"}, arr)
+	reply, err := redis.Eval("return KEYS[1] .. ARGV[1]", 1, "godis", "godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "tenant1:godisgodis", reply)
+
+	p := redis.Pipelined()
+	p.MSet("pipelined", "good")
+	p.Sync()
+	tx, err := redis.Multi()
+	assert.Nil(t, err)
+	tx.MSet("transaction", "good")
+	_, err = tx.Exec()
+	assert.Nil(t, err)
+	c, _ := raw.Exists("tenant1:pipelined", "tenant1:transaction")
+	assert.Equal(t, int64(2), c)
+
+	spec, _ := LookupCommandSpec("XREADGROUP")
+	args := []string{"GROUP", "g", "c", "STREAMS", "s1", "s2", ">", ">"}
+	assert.Equal(t, []int{4, 5}, spec.keyIndexes(args))
+	assert.Equal(t, []string{"GROUP", "g", "c", "STREAMS", "tenant1:s1", "tenant1:s2", ">", ">"}, redis.client.mapStrKeys(spec, args))
+	//the arguments of the caller are not changed
+	assert.Equal(t, "s1", args[4])
+}
+
+func TestOption_OnConnect(t *testing.T) {
+	flushAll()
+	var connected, closed int
+	hooked := *option
+	hooked.Db = 1
+	hooked.OnConnect = func(r *Redis) error {
+		connected++
+		_, err := r.Incr("godis:connects")
+		return err
+	}
+	hooked.OnConnectionClosed = func(r *Redis) {
+		closed++
+	}
+	redis := NewRedis(&hooked)
+	s, err := redis.Get("godis:connects")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", s)
+	//the hook runs outside the pipeline which reconnects
+	redis.Close()
+	assert.Equal(t, 1, closed)
+	p := redis.Pipelined()
+	p.Exists("godis:connects")
+	err = p.Sync()
+	assert.Nil(t, err)
+	s, _ = redis.Get("godis:connects")
+	assert.Equal(t, "2", s)
+	redis.Close()
+	assert.Equal(t, 2, connected)
+	assert.Equal(t, 2, closed)
+
+	//the commands of the hook don't wait for the lazy connection being made
+	hooked.LazyConnect = true
+	redis = NewRedis(&hooked)
+	assert.Nil(t, redis.Connect())
+	redis.Close()
+	assert.Equal(t, 3, connected)
+
+	hooked.OnConnect = func(r *Redis) error {
+		return errors.New("rejected")
+	}
+	redis = NewRedis(&hooked)
+	_, err = redis.Get("godis:connects")
+	assert.EqualError(t, err, "rejected")
+	redis.Close()
+	//the connection failed,so it wasn't closed
+	assert.Equal(t, 3, closed)
+
+	hooked.LazyConnect = false
+	pool := NewPool(&PoolConfig{MaxTotal: 1}, &hooked)
+	_, err = pool.GetResource()
+	assert.NotNil(t, err)
+	pool.Destroy()
+	hooked.OnConnect = nil
+	pool = NewPool(&PoolConfig{MaxTotal: 1}, &hooked)
+	pooled, err := pool.GetResource()
+	assert.Nil(t, err)
+	pooled.Close()
+	pool.Destroy()
+	assert.Equal(t, 4, closed)
+}
+
+func TestOption_CommandTimeout(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"GET godis": {"$4\r\ngood\r\n"},
+		"QUIT":      {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.SoTimeout = 5 * time.Second
+	fakeOption.CommandTimeout = 100 * time.Millisecond
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	s, err := redis.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", s)
+	//the reply of GET slow never comes,the command times out long before SoTimeout
+	start := time.Now()
+	_, err = redis.Get("slow")
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+
+	fakeOption.CommandTimeout = 0
+	redis = NewRedis(fakeOption)
+	defer redis.Close()
+	start = time.Now()
+	err = redis.WithTimeout(100*time.Millisecond, func(redis *Redis) error {
+		_, err := redis.Get("slow")
+		return err
+	})
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, time.Duration(0), redis.client.commandTimeout)
+}
+
+func TestOption_CommandTimeoutSlowReply(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reader.ReadString('\n')
+		//a byte of the reply every 50ms,every read is well within SoTimeout
+		conn.Write([]byte("$20\r\n"))
+		for i := 0; i < 20; i++ {
+			time.Sleep(50 * time.Millisecond)
+			if _, err := conn.Write([]byte("x")); err != nil {
+				return
+			}
+		}
+		conn.Write([]byte("\r\n"))
+	}()
+	redis := NewRedis(&Option{Host: "localhost", Port: listener.Addr().(*net.TCPAddr).Port,
+		SoTimeout: 5 * time.Second, CommandTimeout: 200 * time.Millisecond})
+	defer redis.Close()
+	//the deadline renewed by every read is still capped by the command timeout
+	start := time.Now()
+	_, err = redis.Get("slow")
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+}
+
+func TestOption_SoTimeoutSlowReply(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reader.ReadString('\n')
+		conn.Write([]byte("$20\r\n"))
+		for i := 0; i < 20; i++ {
+			time.Sleep(50 * time.Millisecond)
+			if _, err := conn.Write([]byte("x")); err != nil {
+				return
+			}
+		}
+		conn.Write([]byte("\r\n"))
+	}()
+	redis := NewRedis(&Option{Host: "localhost", Port: listener.Addr().(*net.TCPAddr).Port, SoTimeout: 200 * time.Millisecond})
+	defer redis.Close()
+	//the read deadline is set once per reply,a reply trickling in doesn't renew it
+	start := time.Now()
+	_, err = redis.Get("slow")
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+}
+
+func TestOption_CommandTimeoutBlocking(t *testing.T) {
+	flushAll()
+	timeoutOption := *option
+	timeoutOption.CommandTimeout = 200 * time.Millisecond
+	redis := NewRedis(&timeoutOption)
+	defer redis.Close()
+	//the block timeout of a blocking command extends the command timeout
+	arr, err := redis.BLPopTimeout(1, "godis")
+	assert.Nil(t, err)
+	assert.Empty(t, arr)
+	p := redis.Pipelined()
+	p.MSet("godis", "good")
+	p.Exists("godis")
+	replies, _, err := p.SyncAll()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"OK", int64(1)}, replies)
+
+	pooled := NewPooledRedis(option, &PoolConfig{MaxTotal: 1})
+	defer pooled.Close()
+	err = pooled.WithTimeout(time.Second, func(redis *Redis) error {
+		s, err := redis.Get("godis")
+		assert.Equal(t, "good", s)
+		return err
+	})
+	assert.Nil(t, err)
+}
+
+func TestOption_HostList(t *testing.T) {
+	assert.Equal(t, []string{"redis-a:6379", "redis-b:6380", "127.0.0.1:6379", "[::1]:6379", "[::1]:6380"},
+		parseHostList("redis-a, redis-b:6380,,127.0.0.1,::1,[::1]:6380", 6379))
+
+	flushAll()
+	failover := *option
+	failover.Host = "localhost:1,localhost"
+	redis := NewRedis(&failover)
+	defer redis.Close()
+	_, err := redis.Set("godis", "good")
+	assert.Nil(t, err)
+	s, _ := redis.Get("godis")
+	assert.Equal(t, "good", s)
+	//the address connected is the host answered,not the whole host list
+	assert.Equal(t, "localhost:6379", redis.client.addr())
+
+	failover.Host = "localhost:1,localhost:2"
+	redis = NewRedis(&failover)
+	defer redis.Close()
+	_, err = redis.Get("godis")
+	assert.NotNil(t, err)
+	//the error of the last host is returned
+	assert.Contains(t, err.Error(), ":2:")
+
+	failover.Host = " , "
+	redis = NewRedis(&failover)
+	defer redis.Close()
+	_, err = redis.Get("godis")
+	assert.NotNil(t, err)
+}
+
+func TestOption_Dialer(t *testing.T) {
+	var mu sync.Mutex
+	dialed := make([]string, 0)
+	dialer := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		mu.Lock()
+		dialed = append(dialed, network+" "+addr)
+		mu.Unlock()
+		if addr == "unreachable:6379" {
+			return nil, errors.New("proxy refused")
+		}
+		var d net.Dialer
+		return d.DialContext(ctx, network, "localhost:6379")
+	}
+	dialerOption := *option
+	dialerOption.Host = "unreachable,redis.internal"
+	dialerOption.Dialer = dialer
+	redis := NewRedis(&dialerOption)
+	defer redis.Close()
+	s, err := redis.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+	assert.Equal(t, []string{"tcp unreachable:6379", "tcp redis.internal:6379"}, dialed)
+
+	dialerOption.Host = "unreachable"
+	redis = NewRedis(&dialerOption)
+	defer redis.Close()
+	_, err = redis.Ping()
+	assert.EqualError(t, err, "proxy refused")
+
+	//the connections to the cluster nodes are dialed by the dialer too
+	dialed = dialed[:0]
+	handler := newRedisClusterConnectionHandler(nil, time.Second, time.Second, "", nil, dialer)
+	defer handler.close()
+	pool := handler.cache.setupNodeIfNotExist(false, "node1", 7000)
+	redis, err = pool.GetResource()
+	assert.Nil(t, err)
+	redis.Close()
+	assert.Equal(t, []string{"tcp node1:7000"}, dialed)
+}
+
+func TestRedisCluster_SetupNodeOnce(t *testing.T) {
+	var mu sync.Mutex
+	dials := 0
+	dialer := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		mu.Lock()
+		dials++
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		return nil, errors.New("proxy refused")
+	}
+	//every pool prepares one connection,concurrent refreshes must not dial a pool for the same node twice
+	handler := newRedisClusterConnectionHandler(nil, time.Second, time.Second, "", &PoolConfig{MinIdle: 1}, dialer)
+	defer handler.close()
+	var wg sync.WaitGroup
+	pools := make([]*Pool, 10)
+	for i := range pools {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pools[i] = handler.cache.setupNodeIfNotExist(false, "node1", 7000)
+		}(i)
+	}
+	wg.Wait()
+	for _, pool := range pools {
+		assert.Equal(t, pools[0], pool)
+	}
+	assert.Equal(t, 1, dials)
+}
+
+func TestRedis_Stats(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	assert.Equal(t, ConnStats{}, redis.Stats())
+	_, err := redis.Set("godis", "good")
+	assert.Nil(t, err)
+	stats := redis.Stats()
+	assert.Equal(t, int64(1), stats.Commands)
+	assert.Equal(t, int64(1), stats.Replies)
+	assert.Equal(t, int64(1), stats.Flushes)
+	assert.Equal(t, int64(len("*3\r\n$3\r\nSET\r\n$5\r\ngodis\r\n$4\r\ngood\r\n")), stats.BytesWritten)
+	assert.Equal(t, int64(len("+OK\r\n")), stats.BytesRead)
+
+	//the commands of a pipeline are sent together
+	pipeline := redis.Pipelined()
+	for i := 0; i < 10; i++ {
+		pipeline.Exists("godis")
+	}
+	err = pipeline.Sync()
+	assert.Nil(t, err)
+	stats = redis.Stats()
+	assert.Equal(t, int64(11), stats.Commands)
+	assert.Equal(t, int64(11), stats.Replies)
+	assert.Equal(t, int64(2), stats.Flushes)
+
+	//error replies are counted as replies
+	_, err = redis.LPush("godis", "a")
+	assert.NotNil(t, err)
+	assert.Equal(t, int64(12), redis.Stats().Replies)
+}
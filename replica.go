@@ -0,0 +1,365 @@
+package godis
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+//ReadPolicy decides which replica serves a read
+type ReadPolicy int
+
+const (
+	//ReadRoundRobin use the replicas in turn
+	ReadRoundRobin ReadPolicy = iota
+	//ReadLowestLatency use the replica with the lowest recent latency,
+	//a replica without a recent latency is tried as if it were the fastest,so its latency is measured again
+	ReadLowestLatency
+	//ReadRandom use a random replica
+	ReadRandom
+)
+
+//IsReadOnlyCommand whether the command never writes and can be served by a replica
+func IsReadOnlyCommand(command string) bool {
+	spec, ok := LookupCommandSpec(command)
+	return ok && spec.ReadOnly
+}
+
+//ReplicaOption replica aware client options
+type ReplicaOption struct {
+	Master     *Option     //the master serves all writes
+	Replicas   []*Option   //the replicas serve reads,set ReadOnly of the options for cluster replicas
+	Policy     ReadPolicy  //how to choose a replica,default ReadRoundRobin
+	PoolConfig *PoolConfig //config of the pool of every node
+}
+
+const (
+	//latencyTTL a latency sample older than latencyTTL is stale,it doesn't count any more
+	latencyTTL = 10 * time.Second
+	//replicaRetryInterval a replica failing to connect is skipped for replicaRetryInterval
+	replicaRetryInterval = 5 * time.Second
+)
+
+type replicaNode struct {
+	pool     *Pool
+	latency  int64 //moving average of latency in nanoseconds
+	observed int64 //unix nanoseconds of the last latency sample
+	failed   int64 //unix nanoseconds of the last failure to connect,0 if the replica is reachable
+}
+
+func (n *replicaNode) observe(d time.Duration) {
+	now := time.Now().UnixNano()
+	old := n.recentLatency(now)
+	if old == 0 {
+		atomic.StoreInt64(&n.latency, int64(d))
+	} else {
+		atomic.StoreInt64(&n.latency, (old*7+int64(d))/8)
+	}
+	atomic.StoreInt64(&n.observed, now)
+	atomic.StoreInt64(&n.failed, 0)
+}
+
+//recentLatency the moving average of the latency,0 if the last sample is older than latencyTTL
+func (n *replicaNode) recentLatency(now int64) int64 {
+	if now-atomic.LoadInt64(&n.observed) >= int64(latencyTTL) {
+		return 0
+	}
+	return atomic.LoadInt64(&n.latency)
+}
+
+//fail mark the replica unreachable if err is a connection error,it is skipped for replicaRetryInterval
+func (n *replicaNode) fail(err error) {
+	if _, ok := err.(*ConnectError); ok {
+		atomic.StoreInt64(&n.failed, time.Now().UnixNano())
+	}
+}
+
+//available whether the replica didn't fail to connect within replicaRetryInterval
+func (n *replicaNode) available(now int64) bool {
+	failed := atomic.LoadInt64(&n.failed)
+	return failed == 0 || now-failed >= int64(replicaRetryInterval)
+}
+
+//ReplicaAwareClient read/write splitting client,
+//writes go to the master,read-only commands go to a replica chosen by the ReadPolicy,
+//a replica failing to connect is skipped for a while,
+//reads fall back to the master when there is no available replica
+type ReplicaAwareClient struct {
+	master   *Pool
+	replicas []*replicaNode
+	policy   ReadPolicy
+	counter  uint32
+}
+
+//NewReplicaAwareClient create new replica aware client
+func NewReplicaAwareClient(option *ReplicaOption) *ReplicaAwareClient {
+	c := &ReplicaAwareClient{
+		master:   NewPool(option.PoolConfig, option.Master),
+		replicas: make([]*replicaNode, 0, len(option.Replicas)),
+		policy:   option.Policy,
+	}
+	for _, o := range option.Replicas {
+		c.replicas = append(c.replicas, &replicaNode{pool: NewPool(option.PoolConfig, o)})
+	}
+	return c
+}
+
+//NewReplicaAwareClientFromSentinel discover the master and its replicas of masterName from sentinel,
+//the Master and Replicas of option are ignored,the other fields of template apply to every node
+func NewReplicaAwareClientFromSentinel(sentinel *Option, masterName string, template *Option, option *ReplicaOption) (*ReplicaAwareClient, error) {
+	redis := NewRedis(sentinel)
+	defer redis.Close()
+	addr, err := redis.SentinelGetMasterAddrByName(masterName)
+	if err != nil {
+		return nil, err
+	}
+	if len(addr) < 2 {
+		return nil, errors.New("master " + masterName + " is unknown by sentinel")
+	}
+	master, err := nodeOption(template, addr[0], addr[1])
+	if err != nil {
+		return nil, err
+	}
+	slaves, err := redis.SentinelSlaves(masterName)
+	if err != nil {
+		return nil, err
+	}
+	replicas := make([]*Option, 0, len(slaves))
+	for _, s := range slaves {
+		if strings.Contains(s["flags"], "s_down") || strings.Contains(s["flags"], "disconnected") {
+			continue
+		}
+		replica, err := nodeOption(template, s["ip"], s["port"])
+		if err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, replica)
+	}
+	opt := *option
+	opt.Master = master
+	opt.Replicas = replicas
+	return NewReplicaAwareClient(&opt), nil
+}
+
+//NewReplicaAwareClientFromCluster discover the master of the shard node belongs to and its replicas by CLUSTER NODES,
+//node may be the master or any replica of the shard,the Master and Replicas of option are ignored,
+//the other fields of node apply to every node,ReadOnly is set for the replicas and cleared for the master.
+//the replicas marked fail or fail? and the disconnected ones are skipped
+func NewReplicaAwareClientFromCluster(node *Option, option *ReplicaOption) (*ReplicaAwareClient, error) {
+	redis := NewRedis(node)
+	defer redis.Close()
+	nodes, err := redis.ClusterNodesParsed()
+	if err != nil {
+		return nil, err
+	}
+	masterID := ""
+	for _, n := range nodes {
+		if n.HasFlag("myself") {
+			masterID = n.MasterID
+			if n.HasFlag("master") {
+				masterID = n.ID
+			}
+		}
+	}
+	if masterID == "" {
+		return nil, errors.New("the master of the node is unknown by cluster nodes")
+	}
+	var master *Option
+	replicas := make([]*Option, 0)
+	for _, n := range nodes {
+		if n.ID == masterID {
+			master = clusterNodeOption(node, n, false)
+			continue
+		}
+		if n.MasterID != masterID || n.HasFlag("fail") || n.HasFlag("fail?") || n.LinkState != "connected" {
+			continue
+		}
+		replicas = append(replicas, clusterNodeOption(node, n, true))
+	}
+	if master == nil {
+		return nil, errors.New("master " + masterID + " is unknown by cluster nodes")
+	}
+	opt := *option
+	opt.Master = master
+	opt.Replicas = replicas
+	return NewReplicaAwareClient(&opt), nil
+}
+
+//clusterNodeOption the option of the cluster node,a node which doesn't know its own ip has the host of template
+func clusterNodeOption(template *Option, node ClusterNode, readOnly bool) *Option {
+	o := *template
+	if node.Host != "" {
+		o.Host = node.Host
+	}
+	o.Port = node.Port
+	o.ReadOnly = readOnly
+	return &o
+}
+
+func nodeOption(template *Option, host, port string) (*Option, error) {
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, err
+	}
+	o := *template
+	o.Host = host
+	o.Port = p
+	return &o, nil
+}
+
+//Master get a master connection,close it after use
+func (c *ReplicaAwareClient) Master() (*Redis, error) {
+	return c.master.GetResource()
+}
+
+//Replica get a connection of the replica chosen by the ReadPolicy,close it after use,
+//a master connection if no replica is available
+func (c *ReplicaAwareClient) Replica() (*Redis, error) {
+	redis, _, err := c.replica()
+	return redis, err
+}
+
+//replica borrow a connection of the replica chosen by the ReadPolicy,
+//the node is nil if it is a master connection since no replica is available
+func (c *ReplicaAwareClient) replica() (*Redis, *replicaNode, error) {
+	node := c.pick()
+	if node == nil {
+		redis, err := c.master.GetResource()
+		return redis, nil, err
+	}
+	redis, err := node.pool.GetResource()
+	if err != nil {
+		node.fail(err)
+		redis, err := c.master.GetResource()
+		return redis, nil, err
+	}
+	return redis, node, nil
+}
+
+func (c *ReplicaAwareClient) pick() *replicaNode {
+	now := time.Now().UnixNano()
+	available := make([]*replicaNode, 0, len(c.replicas))
+	for _, n := range c.replicas {
+		if n.available(now) {
+			available = append(available, n)
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+	switch c.policy {
+	case ReadLowestLatency:
+		best := available[0]
+		for _, n := range available[1:] {
+			if n.recentLatency(now) < best.recentLatency(now) {
+				best = n
+			}
+		}
+		return best
+	case ReadRandom:
+		return available[rand.Intn(len(available))]
+	}
+	i := atomic.AddUint32(&c.counter, 1)
+	return available[int(i)%len(available)]
+}
+
+//Read run fn on a replica chosen by the ReadPolicy,or on the master if no replica is available
+func (c *ReplicaAwareClient) Read(fn func(redis *Redis) error) error {
+	redis, node, err := c.replica()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	start := time.Now()
+	err = fn(redis)
+	if node != nil {
+		if err != nil {
+			node.fail(err)
+		} else {
+			node.observe(time.Since(start))
+		}
+	}
+	return err
+}
+
+//Write run fn on the master
+func (c *ReplicaAwareClient) Write(fn func(redis *Redis) error) error {
+	redis, err := c.master.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return fn(redis)
+}
+
+//Do send the command to a replica if it is read-only,otherwise to the master,and return the reply
+func (c *ReplicaAwareClient) Do(command string, args ...string) (interface{}, error) {
+	var reply interface{}
+	fn := func(redis *Redis) error {
+		err := redis.SendByStr(command, StrArrToByteArrArr(args)...)
+		if err != nil {
+			return err
+		}
+		reply, err = redis.Receive()
+		return err
+	}
+	var err error
+	if IsReadOnlyCommand(command) {
+		err = c.Read(fn)
+	} else {
+		err = c.Write(fn)
+	}
+	return reply, err
+}
+
+//EvalRo evaluate the read-only script on a replica chosen by the ReadPolicy
+func (c *ReplicaAwareClient) EvalRo(script string, keyCount int, params ...string) (interface{}, error) {
+	var reply interface{}
+	err := c.Read(func(redis *Redis) error {
+		var err error
+		reply, err = redis.EvalRo(script, keyCount, params...)
+		return err
+	})
+	return reply, err
+}
+
+//EvalShaRo evaluate the read-only script cached by its SHA1 digest on a replica chosen by the ReadPolicy
+func (c *ReplicaAwareClient) EvalShaRo(sha1 string, keyCount int, params ...string) (interface{}, error) {
+	var reply interface{}
+	err := c.Read(func(redis *Redis) error {
+		var err error
+		reply, err = redis.EvalShaRo(sha1, keyCount, params...)
+		return err
+	})
+	return reply, err
+}
+
+//RefreshLatency ping every replica to update the latency used by ReadLowestLatency
+func (c *ReplicaAwareClient) RefreshLatency() {
+	for _, n := range c.replicas {
+		redis, err := n.pool.GetResource()
+		if err != nil {
+			n.fail(err)
+			continue
+		}
+		start := time.Now()
+		_, err = redis.Ping()
+		if err == nil {
+			n.observe(time.Since(start))
+		} else {
+			n.fail(err)
+		}
+		redis.Close()
+	}
+}
+
+//Close destroy the pools of all nodes
+func (c *ReplicaAwareClient) Close() {
+	c.master.Destroy()
+	for _, n := range c.replicas {
+		n.pool.Destroy()
+	}
+}
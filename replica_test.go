@@ -0,0 +1,153 @@
+package godis
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReplicaAwareClient(t *testing.T) {
+	flushAll()
+	//use db 1 as the replica,so the routing can be observed
+	replica := &Option{Host: "localhost", Port: 6379, Db: 1}
+	for _, policy := range []ReadPolicy{ReadRoundRobin, ReadLowestLatency, ReadRandom} {
+		client := NewReplicaAwareClient(&ReplicaOption{Master: option, Replicas: []*Option{replica, replica}, Policy: policy})
+		reply, err := client.Do("set", "godis", "good")
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", string(reply.([]byte)))
+		reply, err = client.Do("get", "godis")
+		assert.Nil(t, err)
+		assert.Nil(t, reply)
+
+		err = client.Write(func(redis *Redis) error {
+			s, err := redis.Get("godis")
+			assert.Equal(t, "good", s)
+			return err
+		})
+		assert.Nil(t, err)
+		err = client.Read(func(redis *Redis) error {
+			c, err := redis.Exists("godis")
+			assert.Equal(t, int64(0), c)
+			return err
+		})
+		assert.Nil(t, err)
+		client.RefreshLatency()
+
+		redis, err := client.Replica()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, redis.client.Db)
+		redis.Close()
+		redis, err = client.Master()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, redis.client.Db)
+		redis.Close()
+		client.Close()
+	}
+
+	client := NewReplicaAwareClient(&ReplicaOption{Master: option})
+	defer client.Close()
+	reply, err := client.Do("get", "godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", string(reply.([]byte)))
+
+	assert.True(t, IsReadOnlyCommand("zrange"))
+	assert.False(t, IsReadOnlyCommand("zadd"))
+
+	brokenClient := NewReplicaAwareClient(&ReplicaOption{Master: &Option{Host: "localhost1"}, Replicas: []*Option{{Host: "localhost1"}}})
+	defer brokenClient.Close()
+	_, err = brokenClient.Do("get", "godis")
+	assert.NotNil(t, err)
+	_, err = brokenClient.Do("set", "godis", "good")
+	assert.NotNil(t, err)
+}
+
+func TestReplicaAwareClient_Unavailable(t *testing.T) {
+	master, closeMaster := newFakeServer(t, map[string][]string{
+		"GET godis": {"$6\r\nmaster\r\n"},
+		"QUIT":      {"+OK\r\n"},
+	})
+	defer closeMaster()
+	replica, closeReplica := newFakeServer(t, map[string][]string{
+		"GET godis": {"$7\r\nreplica\r\n"},
+		"PING":      {"+PONG\r\n"},
+		"QUIT":      {"+OK\r\n"},
+	})
+	defer closeReplica()
+	unreachable := &Option{Host: "localhost", Port: 1}
+
+	//the unreachable replica is skipped after failing,the read falls back to the master meanwhile
+	client := NewReplicaAwareClient(&ReplicaOption{Master: master, Replicas: []*Option{unreachable, replica}, Policy: ReadLowestLatency})
+	defer client.Close()
+	reply, err := client.Do("GET", "godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "master", string(reply.([]byte)))
+	assert.False(t, client.replicas[0].available(time.Now().UnixNano()))
+	reply, err = client.Do("GET", "godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "replica", string(reply.([]byte)))
+	//it is tried again after replicaRetryInterval
+	assert.True(t, client.replicas[0].available(time.Now().Add(replicaRetryInterval).UnixNano()))
+
+	//the master serves the reads when no replica is available
+	client = NewReplicaAwareClient(&ReplicaOption{Master: master, Replicas: []*Option{unreachable}})
+	defer client.Close()
+	for i := 0; i < 2; i++ {
+		reply, err = client.Do("GET", "godis")
+		assert.Nil(t, err)
+		assert.Equal(t, "master", string(reply.([]byte)))
+	}
+
+	//a stale latency doesn't count,so the slow replica is measured again
+	client = NewReplicaAwareClient(&ReplicaOption{Master: master, Replicas: []*Option{replica, replica}, Policy: ReadLowestLatency})
+	defer client.Close()
+	slow, fast := client.replicas[0], client.replicas[1]
+	slow.observe(time.Second)
+	fast.observe(time.Millisecond)
+	assert.Equal(t, fast, client.pick())
+	atomic.StoreInt64(&fast.observed, time.Now().Add(-latencyTTL).UnixNano())
+	assert.Equal(t, int64(0), fast.recentLatency(time.Now().UnixNano()))
+	atomic.StoreInt64(&slow.observed, time.Now().Add(-latencyTTL).UnixNano())
+	client.RefreshLatency()
+	assert.True(t, slow.recentLatency(time.Now().UnixNano()) < int64(time.Second))
+}
+
+func TestNewReplicaAwareClientFromCluster(t *testing.T) {
+	nodes := "m1 127.0.0.1:7000@17000 master - 0 0 1 connected 0-8191\n" +
+		"r1 127.0.0.1:7001@17001 myself,slave m1 0 0 1 connected\n" +
+		"r2 :7002@17002 slave m1 0 0 1 connected\n" +
+		"r3 127.0.0.1:7003@17003 slave,fail m1 0 0 1 connected\n" +
+		"m2 127.0.0.1:7004@17004 master - 0 0 2 connected 8192-16383\n" +
+		"r4 127.0.0.1:7005@17005 slave m2 0 0 2 connected\n"
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"CLUSTER nodes": {fmt.Sprintf("$%d\r\n%s\r\n", len(nodes), nodes)},
+		"QUIT":          {"+OK\r\n"},
+	})
+	defer closeServer()
+	client, err := NewReplicaAwareClientFromCluster(fakeOption, &ReplicaOption{Policy: ReadRandom})
+	assert.Nil(t, err)
+	defer client.Close()
+	assert.Equal(t, "127.0.0.1", client.master.option.Host)
+	assert.Equal(t, 7000, client.master.option.Port)
+	assert.False(t, client.master.option.ReadOnly)
+	//the failed replica and the replica of another shard are skipped
+	assert.Len(t, client.replicas, 2)
+	assert.Equal(t, "127.0.0.1", client.replicas[0].pool.option.Host)
+	assert.Equal(t, 7001, client.replicas[0].pool.option.Port)
+	assert.Equal(t, "localhost", client.replicas[1].pool.option.Host)
+	assert.Equal(t, 7002, client.replicas[1].pool.option.Port)
+	for _, replica := range client.replicas {
+		assert.True(t, replica.pool.option.ReadOnly)
+	}
+	assert.Equal(t, ReadRandom, client.policy)
+
+	nodes = "m1 127.0.0.1:7000@17000 master - 0 0 1 connected 0-16383\n"
+	fakeOption, closeServer = newFakeServer(t, map[string][]string{
+		"CLUSTER nodes": {fmt.Sprintf("$%d\r\n%s\r\n", len(nodes), nodes)},
+		"QUIT":          {"+OK\r\n"},
+	})
+	defer closeServer()
+	_, err = NewReplicaAwareClientFromCluster(fakeOption, &ReplicaOption{})
+	assert.NotNil(t, err)
+}
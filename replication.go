@@ -0,0 +1,129 @@
+package godis
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultReplicationAckInterval = time.Second
+
+//ReplicationTailOption options of ReplicationTail
+type ReplicationTailOption struct {
+	Snapshot    io.Writer     //receives the RDB snapshot sent before the stream,default discard it
+	AckInterval time.Duration //min interval of acknowledging the offset,the master drops a link not acknowledged for repl-timeout,default 1s
+}
+
+//ReplicationCommand a write command of the replication stream
+type ReplicationCommand struct {
+	Db     int      //db the command is executed in
+	Name   string   //upper case name,such as SET
+	Args   []string //arguments after the name
+	Offset int64    //replication offset of the master after the command
+}
+
+//ReplicationTail experimental,consume the replication stream of a master like a replica,
+//for change data capture such as fanning out cache invalidations or auditing the writes.
+//
+//the master sends a snapshot of the dataset first,it is written to Snapshot or discarded,
+//then every write executed by the master is passed to the handler in order,
+//including MULTI/EXEC around transactions and the effects of scripts,
+//the PING,REPLCONF and SELECT of the stream are handled by the tail,SELECT only changes Db of the commands after it.
+//the tail always starts with a full resynchronization,the writes while it is disconnected are lost.
+//
+//	tail := godis.NewReplicationTail(godis.NewRedis(option), nil)
+//	err := tail.Run(ctx, func(command godis.ReplicationCommand) error {
+//		if command.Name == "DEL" {
+//			invalidate(command.Args...)
+//		}
+//		return nil
+//	})
+type ReplicationTail struct {
+	redis  *Redis
+	option ReplicationTailOption
+}
+
+//NewReplicationTail create new replication tail,the connection of redis is used as the replication link
+func NewReplicationTail(redis *Redis, option *ReplicationTailOption) *ReplicationTail {
+	opt := ReplicationTailOption{}
+	if option != nil {
+		opt = *option
+	}
+	if opt.Snapshot == nil {
+		opt.Snapshot = ioutil.Discard
+	}
+	if opt.AckInterval <= 0 {
+		opt.AckInterval = defaultReplicationAckInterval
+	}
+	return &ReplicationTail{redis: redis, option: opt}
+}
+
+//Run start the replication by PSYNC and pass the commands of the stream to handler,
+//until ctx is done,the link fails or handler returns an error,which is returned by Run.
+//the connection is closed when Run returns,since it can't be used for other commands anymore
+func (t *ReplicationTail) Run(ctx context.Context, handler func(command ReplicationCommand) error) error {
+	err := t.redis.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	c := t.redis.client
+	defer c.close()
+	//the stream is idle while there are no writes,it is read without deadline
+	c.setBlockingTimeout(0)
+	defer c.clearBlockingTimeout()
+	err = c.psync("?", -1)
+	if err != nil {
+		return err
+	}
+	stop := c.interruptOnDone(ctx)
+	defer stop()
+	_, offset, _, err := c.getSyncPayload(t.option.Snapshot)
+	if err != nil {
+		return t.runError(ctx, err)
+	}
+	db := 0
+	acked := time.Now()
+	for {
+		command, size, err := c.readReplicationCommand()
+		if err != nil {
+			return t.runError(ctx, err)
+		}
+		name := strings.ToUpper(command[0])
+		args := command[1:]
+		switch {
+		case name == "REPLCONF" && len(args) > 0 && strings.ToUpper(args[0]) == "GETACK":
+			//the offset before GETACK,like a replica
+			err = c.sendReplicationAck(offset)
+			acked = time.Now()
+		case name == "SELECT" && len(args) == 1:
+			db, err = strconv.Atoi(args[0])
+		case name == "PING" || name == "REPLCONF":
+		default:
+			err = handler(ReplicationCommand{Db: db, Name: name, Args: args, Offset: offset + size})
+		}
+		if err != nil {
+			return err
+		}
+		offset += size
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if time.Since(acked) >= t.option.AckInterval {
+			if err := c.sendReplicationAck(offset); err != nil {
+				return t.runError(ctx, err)
+			}
+			acked = time.Now()
+		}
+	}
+}
+
+//runError the error of ctx if the link is broken because ctx is done
+func (t *ReplicationTail) runError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
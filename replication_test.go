@@ -0,0 +1,65 @@
+package godis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestReplicationTail(t *testing.T) {
+	selectDb := "*2\r\n$6\r\nSELECT\r\n$1\r\n1\r\n"
+	set := "*3\r\n$3\r\nSET\r\n$1\r\na\r\n$1\r\n1\r\n"
+	ping := "*1\r\n$4\r\nPING\r\n"
+	getAck := "*3\r\n$8\r\nREPLCONF\r\n$6\r\nGETACK\r\n$1\r\n*\r\n"
+	del := "*2\r\n$3\r\nDEL\r\n$1\r\nb\r\n"
+	//the master sends DEL after the offset before GETACK is acknowledged
+	ack := 100 + len(selectDb) + len(set) + len(ping)
+	option, closeServer := newFakeServer(t, map[string][]string{
+		"PSYNC ? -1":                        {"+FULLRESYNC 8de1787ba490483314a4d30f1c628bc5025eb761 100\r\n\n$9\r\nREDIS0009" + selectDb + set + ping + getAck},
+		"REPLCONF ACK " + strconv.Itoa(ack): {del},
+		"QUIT":                              {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(option)
+	defer redis.Close()
+	var snapshot bytes.Buffer
+	tail := NewReplicationTail(redis, &ReplicationTailOption{Snapshot: &snapshot, AckInterval: time.Hour})
+	commands := make([]ReplicationCommand, 0)
+	errStop := errors.New("stop")
+	err := tail.Run(context.Background(), func(command ReplicationCommand) error {
+		commands = append(commands, command)
+		if command.Name == "DEL" {
+			return errStop
+		}
+		return nil
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, "REDIS0009", snapshot.String())
+	assert.Equal(t, []ReplicationCommand{
+		{Db: 1, Name: "SET", Args: []string{"a", "1"}, Offset: int64(100 + len(selectDb) + len(set))},
+		{Db: 1, Name: "DEL", Args: []string{"b"}, Offset: int64(ack + len(getAck) + len(del))},
+	}, commands)
+	assert.False(t, redis.client.isConnected())
+}
+
+func TestReplicationTail_Cancel(t *testing.T) {
+	option, closeServer := newFakeServer(t, map[string][]string{
+		"PSYNC ? -1": {"+FULLRESYNC 8de1787ba490483314a4d30f1c628bc5025eb761 0\r\n$0\r\n"},
+		"QUIT":       {"+OK\r\n"},
+	})
+	defer closeServer()
+	redis := NewRedis(option)
+	defer redis.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := NewReplicationTail(redis, nil).Run(ctx, func(command ReplicationCommand) error {
+		return nil
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
@@ -0,0 +1,366 @@
+package godis
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+//ScanReply convert the reply into dest,dest must be a non-nil pointer to one of:
+//
+//	string, []byte, bool, int*, uint*, float*, time.Time, encoding.TextUnmarshaler, interface{}
+//	slice or array of the above,for multi bulk replies
+//	map of the above,for multi bulk replies of field/value pairs,such as HGETALL
+//
+//time.Time is parsed from unix seconds or RFC3339,bool is parsed from 1/0 or true/false,
+//nil elements of multi bulk replies are converted into zero values
+func ScanReply(reply interface{}, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("scan destination must be a non-nil pointer,got %T", dest)
+	}
+	return scanValue(reply, v.Elem())
+}
+
+func scanValue(reply interface{}, v reflect.Value) error {
+	if isNilReply(reply) {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch r := reply.(type) {
+	case error:
+		return r
+	case []interface{}:
+		return scanArray(r, v)
+	case []string:
+		arr := make([]interface{}, 0, len(r))
+		for _, s := range r {
+			arr = append(arr, s)
+		}
+		return scanArray(arr, v)
+	case map[string]string:
+		arr := make([]interface{}, 0, len(r)*2)
+		for k, s := range r {
+			arr = append(arr, k, s)
+		}
+		return scanArray(arr, v)
+	}
+	return scanScalar(reply, v)
+}
+
+func scanScalar(reply interface{}, v reflect.Value) error {
+	var s string
+	var n int64
+	isInt := false
+	switch r := reply.(type) {
+	case []byte:
+		s = string(r)
+	case string:
+		s = r
+	case int64:
+		s, n, isInt = strconv.FormatInt(r, 10), r, true
+	case float64:
+		s = strconv.FormatFloat(r, 'f', -1, 64)
+	case bool:
+		s = strconv.FormatBool(r)
+		if r {
+			n = 1
+		}
+		isInt = true
+	default:
+		return fmt.Errorf("can't scan %T", reply)
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return scanScalar(reply, v.Elem())
+	}
+	if v.Type() == timeType {
+		return scanTime(s, n, isInt, v)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		if isInt {
+			v.SetBool(n != 0)
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("can't scan %q into %s", s, v.Type())
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if isInt {
+			v.SetInt(n)
+			return nil
+		}
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("can't scan %q into %s", s, v.Type())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("can't scan %q into %s", s, v.Type())
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("can't scan %q into %s", s, v.Type())
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("can't scan %T into %s", reply, v.Type())
+		}
+		v.SetBytes([]byte(s))
+	case reflect.Interface:
+		if isInt {
+			v.Set(reflect.ValueOf(reply))
+		} else {
+			v.Set(reflect.ValueOf(s))
+		}
+	default:
+		return fmt.Errorf("can't scan %T into %s", reply, v.Type())
+	}
+	return nil
+}
+
+func scanTime(s string, n int64, isInt bool, v reflect.Value) error {
+	if !isInt {
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+		var err error
+		if n, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return fmt.Errorf("can't scan %q into %s", s, v.Type())
+		}
+	}
+	v.Set(reflect.ValueOf(time.Unix(n, 0)))
+	return nil
+}
+
+func scanArray(arr []interface{}, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return scanArray(arr, v.Elem())
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), len(arr), len(arr))
+		for i, item := range arr {
+			if err := scanValue(item, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Array:
+		for i := 0; i < v.Len() && i < len(arr); i++ {
+			if err := scanValue(arr[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if len(arr)%2 != 0 {
+			return fmt.Errorf("can't scan %d elements into %s", len(arr), v.Type())
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(arr)/2)
+		for i := 0; i < len(arr); i += 2 {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := scanValue(arr[i], key); err != nil {
+				return err
+			}
+			value := reflect.New(v.Type().Elem()).Elem()
+			if err := scanValue(arr[i+1], value); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	case reflect.Interface:
+		items := make([]interface{}, 0, len(arr))
+		for _, item := range arr {
+			var i interface{}
+			if err := scanValue(item, reflect.ValueOf(&i).Elem()); err != nil {
+				return err
+			}
+			items = append(items, i)
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("can't scan multi bulk reply into %s", v.Type())
+	}
+	return nil
+}
+
+//<editor-fold desc="scancommands">
+
+//GetScan get the value of key into dest,see ScanReply,return ErrNil if key does not exist
+func (r *Redis) GetScan(key string, dest interface{}) error {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.get(key)
+	if err != nil {
+		return err
+	}
+	return r.client.getScanReply(dest)
+}
+
+//MGetScan get the values of keys into dest,a slice or array,missing keys are zero values
+func (r *Redis) MGetScan(dest interface{}, keys ...string) error {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.mget(keys...)
+	if err != nil {
+		return err
+	}
+	return r.client.getScanReply(dest)
+}
+
+//HGetScan get the value of field into dest,return ErrNil if field or key does not exist
+func (r *Redis) HGetScan(key, field string, dest interface{}) error {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.hget(key, field)
+	if err != nil {
+		return err
+	}
+	return r.client.getScanReply(dest)
+}
+
+//HMGetScan get the values of fields into dest,a slice or array,missing fields are zero values
+func (r *Redis) HMGetScan(key string, dest interface{}, fields ...string) error {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.hmget(key, fields...)
+	if err != nil {
+		return err
+	}
+	return r.client.getScanReply(dest)
+}
+
+//HGetAllScan get all the fields and values of key into dest,a map
+func (r *Redis) HGetAllScan(key string, dest interface{}) error {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.hgetAll(key)
+	if err != nil {
+		return err
+	}
+	return r.client.getScanReply(dest)
+}
+
+//LRangeScan get the elements of the list into dest,a slice or array
+func (r *Redis) LRangeScan(key string, start, stop int64, dest interface{}) error {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.lrange(key, start, stop)
+	if err != nil {
+		return err
+	}
+	return r.client.getScanReply(dest)
+}
+
+//SMembersScan get the members of the set into dest,a slice or array
+func (r *Redis) SMembersScan(key string, dest interface{}) error {
+	err := r.checkIsInMultiOrPipeline()
+	if err != nil {
+		return err
+	}
+	err = r.client.sMembers(key)
+	if err != nil {
+		return err
+	}
+	return r.client.getScanReply(dest)
+}
+
+//GetScan  see comment in redis.go
+func (r *RedisCluster) GetScan(key string, dest interface{}) error {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return nil, redis.GetScan(key, dest)
+	}
+	_, err := command.run(key)
+	return err
+}
+
+//HGetScan  see comment in redis.go
+func (r *RedisCluster) HGetScan(key, field string, dest interface{}) error {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return nil, redis.HGetScan(key, field, dest)
+	}
+	_, err := command.run(key)
+	return err
+}
+
+//HMGetScan  see comment in redis.go
+func (r *RedisCluster) HMGetScan(key string, dest interface{}, fields ...string) error {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return nil, redis.HMGetScan(key, dest, fields...)
+	}
+	_, err := command.run(key)
+	return err
+}
+
+//HGetAllScan  see comment in redis.go
+func (r *RedisCluster) HGetAllScan(key string, dest interface{}) error {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return nil, redis.HGetAllScan(key, dest)
+	}
+	_, err := command.run(key)
+	return err
+}
+
+//LRangeScan  see comment in redis.go
+func (r *RedisCluster) LRangeScan(key string, start, stop int64, dest interface{}) error {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return nil, redis.LRangeScan(key, start, stop, dest)
+	}
+	_, err := command.run(key)
+	return err
+}
+
+//SMembersScan  see comment in redis.go
+func (r *RedisCluster) SMembersScan(key string, dest interface{}) error {
+	command := newRedisClusterCommand(r.MaxAttempts, r.connectionHandler)
+	command.execute = func(redis *Redis) (interface{}, error) {
+		return nil, redis.SMembersScan(key, dest)
+	}
+	_, err := command.run(key)
+	return err
+}
+
+//</editor-fold>
@@ -0,0 +1,176 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestScanReply(t *testing.T) {
+	var s string
+	assert.Nil(t, ScanReply([]byte("good"), &s))
+	assert.Equal(t, "good", s)
+	var b []byte
+	assert.Nil(t, ScanReply([]byte("good"), &b))
+	assert.Equal(t, []byte("good"), b)
+	var i int
+	assert.Nil(t, ScanReply([]byte("-10"), &i))
+	assert.Equal(t, -10, i)
+	var i8 int8
+	assert.NotNil(t, ScanReply([]byte("1000"), &i8))
+	var u uint32
+	assert.Nil(t, ScanReply(int64(10), &u))
+	assert.Equal(t, uint32(10), u)
+	var f float64
+	assert.Nil(t, ScanReply([]byte("1.5"), &f))
+	assert.Equal(t, 1.5, f)
+	var ok bool
+	assert.Nil(t, ScanReply(int64(1), &ok))
+	assert.True(t, ok)
+	assert.Nil(t, ScanReply([]byte("false"), &ok))
+	assert.False(t, ok)
+	var tm time.Time
+	assert.Nil(t, ScanReply([]byte("1600000000"), &tm))
+	assert.Equal(t, int64(1600000000), tm.Unix())
+	assert.Nil(t, ScanReply([]byte("2020-09-13T12:26:40Z"), &tm))
+	assert.Equal(t, int64(1600000000), tm.Unix())
+	var ip net.IP
+	assert.Nil(t, ScanReply([]byte("127.0.0.1"), &ip))
+	assert.Equal(t, "127.0.0.1", ip.String())
+	var p *int
+	assert.Nil(t, ScanReply(int64(3), &p))
+	assert.Equal(t, 3, *p)
+	var any interface{}
+	assert.Nil(t, ScanReply([]byte("good"), &any))
+	assert.Equal(t, "good", any)
+
+	var ints []int
+	assert.Nil(t, ScanReply([]interface{}{[]byte("1"), nil, int64(3)}, &ints))
+	assert.Equal(t, []int{1, 0, 3}, ints)
+	var arr [2]string
+	assert.Nil(t, ScanReply([]string{"a", "b", "c"}, &arr))
+	assert.Equal(t, [2]string{"a", "b"}, arr)
+	var m map[string]int
+	assert.Nil(t, ScanReply([]interface{}{[]byte("a"), []byte("1"), []byte("b"), []byte("2")}, &m))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, m)
+	assert.Nil(t, ScanReply(map[string]string{"c": "3"}, &m))
+	assert.Equal(t, map[string]int{"c": 3}, m)
+	assert.Nil(t, ScanReply([]interface{}{[]byte("a"), int64(1)}, &any))
+	assert.Equal(t, []interface{}{"a", int64(1)}, any)
+
+	assert.NotNil(t, ScanReply([]byte("good"), s))
+	assert.NotNil(t, ScanReply([]byte("good"), &i))
+	assert.NotNil(t, ScanReply([]byte("good"), &ints))
+	assert.NotNil(t, ScanReply([]interface{}{[]byte("a")}, &m))
+	assert.NotNil(t, ScanReply([]interface{}{[]byte("a")}, &s))
+	assert.NotNil(t, ScanReply(newDataError("error"), &s))
+	assert.NotNil(t, ScanReply(struct{}{}, &s))
+}
+
+func TestRedis_GetScan(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	var i int64
+	assert.Equal(t, ErrNil, redis.GetScan("godis", &i))
+	redis.Set("godis", "10")
+	assert.Nil(t, redis.GetScan("godis", &i))
+	assert.Equal(t, int64(10), i)
+
+	var arr []int
+	assert.Nil(t, redis.MGetScan(&arr, "godis", "godis1"))
+	assert.Equal(t, []int{10, 0}, arr)
+
+	redis.HSet("godis1", "a", "1")
+	redis.HSet("godis1", "b", "2")
+	var f float64
+	assert.Nil(t, redis.HGetScan("godis1", "a", &f))
+	assert.Equal(t, float64(1), f)
+	assert.Equal(t, ErrNil, redis.HGetScan("godis1", "c", &f))
+	assert.Nil(t, redis.HMGetScan("godis1", &arr, "a", "c"))
+	assert.Equal(t, []int{1, 0}, arr)
+	var m map[string]int
+	assert.Nil(t, redis.HGetAllScan("godis1", &m))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, m)
+
+	redis.RPush("godis2", "1", "2")
+	assert.Nil(t, redis.LRangeScan("godis2", 0, -1, &arr))
+	assert.Equal(t, []int{1, 2}, arr)
+	redis.SAdd("godis3", "1")
+	assert.Nil(t, redis.SMembersScan("godis3", &arr))
+	assert.Equal(t, []int{1}, arr)
+
+	p := redis.Pipelined()
+	mget, _ := p.MGet("godis", "godis4")
+	exists, _ := p.Exists("godis")
+	assert.NotNil(t, mget.Scan(&arr))
+	p.Sync()
+	assert.Nil(t, mget.Scan(&arr))
+	assert.Equal(t, []int{10, 0}, arr)
+	var ok bool
+	assert.Nil(t, exists.Scan(&ok))
+	assert.True(t, ok)
+	obj, err := mget.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"10", ""}, obj)
+	assert.Nil(t, mget.Scan(&arr))
+	assert.Equal(t, []int{10, 0}, arr)
+	response := newResponse()
+	response.set([]byte(nil))
+	assert.Equal(t, ErrNil, response.Scan(&i))
+	response = newResponse()
+	response.builder = StrBuilder
+	response.set(newDataError("error"))
+	response.Get()
+	assert.NotNil(t, response.Scan(&i))
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	m2, _ := redisBroken.Multi()
+	assert.NotNil(t, redisBroken.GetScan("godis", &i))
+	assert.NotNil(t, redisBroken.MGetScan(&arr, "godis"))
+	assert.NotNil(t, redisBroken.HGetScan("godis1", "a", &i))
+	assert.NotNil(t, redisBroken.HMGetScan("godis1", &arr, "a"))
+	assert.NotNil(t, redisBroken.HGetAllScan("godis1", &m))
+	assert.NotNil(t, redisBroken.LRangeScan("godis2", 0, -1, &arr))
+	assert.NotNil(t, redisBroken.SMembersScan("godis3", &arr))
+	m2.Discard()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	assert.NotNil(t, redisBroken.GetScan("godis", &i))
+	assert.NotNil(t, redisBroken.MGetScan(&arr, "godis"))
+	assert.NotNil(t, redisBroken.HGetScan("godis1", "a", &i))
+	assert.NotNil(t, redisBroken.HMGetScan("godis1", &arr, "a"))
+	assert.NotNil(t, redisBroken.HGetAllScan("godis1", &m))
+	assert.NotNil(t, redisBroken.LRangeScan("godis2", 0, -1, &arr))
+	assert.NotNil(t, redisBroken.SMembersScan("godis3", &arr))
+}
+
+func TestRedisCluster_GetScan(t *testing.T) {
+	flushAll()
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(16383), []interface{}{[]byte("localhost"), int64(6379)}},
+	})
+	defer cluster.Close()
+	var i int
+	assert.Equal(t, ErrNil, cluster.GetScan("godis", &i))
+	cluster.Set("godis", "1")
+	assert.Nil(t, cluster.GetScan("godis", &i))
+	assert.Equal(t, 1, i)
+	cluster.HSet("godis1", "a", "2")
+	assert.Nil(t, cluster.HGetScan("godis1", "a", &i))
+	assert.Equal(t, 2, i)
+	var arr []int
+	assert.Nil(t, cluster.HMGetScan("godis1", &arr, "a"))
+	assert.Equal(t, []int{2}, arr)
+	var m map[string]int
+	assert.Nil(t, cluster.HGetAllScan("godis1", &m))
+	assert.Equal(t, map[string]int{"a": 2}, m)
+	cluster.RPush("godis2", "3")
+	assert.Nil(t, cluster.LRangeScan("godis2", 0, -1, &arr))
+	assert.Equal(t, []int{3}, arr)
+	cluster.SAdd("godis3", "4")
+	assert.Nil(t, cluster.SMembersScan("godis3", &arr))
+	assert.Equal(t, []int{4}, arr)
+}
@@ -0,0 +1,254 @@
+package godis
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+//ScriptRunner the commands Script needs,Redis,RedisCluster and PooledRedis implement it
+type ScriptRunner interface {
+	Eval(script string, keyCount int, params ...string) (interface{}, error)
+	EvalSha(sha1 string, keyCount int, params ...string) (interface{}, error)
+}
+
+//ScriptTimeoutRunner the commands a Script with timeout needs,Redis,RedisCluster and PooledRedis implement it
+type ScriptTimeoutRunner interface {
+	EvalTimeout(timeout time.Duration, script string, keyCount int, params ...string) (interface{}, error)
+	EvalShaTimeout(timeout time.Duration, sha1 string, keyCount int, params ...string) (interface{}, error)
+}
+
+//ReadOnlyScriptRunner the commands Script.RunRo needs,
+//Redis,RedisCluster,PooledRedis and ReplicaAwareClient implement it
+type ReadOnlyScriptRunner interface {
+	EvalRo(script string, keyCount int, params ...string) (interface{}, error)
+	EvalShaRo(sha1 string, keyCount int, params ...string) (interface{}, error)
+}
+
+var (
+	_ ScriptRunner         = (*Redis)(nil)
+	_ ScriptRunner         = (*RedisCluster)(nil)
+	_ ScriptRunner         = (*PooledRedis)(nil)
+	_ ScriptTimeoutRunner  = (*Redis)(nil)
+	_ ScriptTimeoutRunner  = (*RedisCluster)(nil)
+	_ ScriptTimeoutRunner  = (*PooledRedis)(nil)
+	_ ReadOnlyScriptRunner = (*Redis)(nil)
+	_ ReadOnlyScriptRunner = (*RedisCluster)(nil)
+	_ ReadOnlyScriptRunner = (*PooledRedis)(nil)
+	_ ReadOnlyScriptRunner = (*ReplicaAwareClient)(nil)
+)
+
+//Script a lua script run by EVALSHA with its SHA1 digest computed locally,
+//so only the digest is sent,the script is sent by EVAL when the server doesn't have it cached,
+//such as after SCRIPT FLUSH or a failover
+type Script struct {
+	src  string
+	hash string
+
+	timeout    time.Duration
+	hasTimeout bool
+}
+
+//NewScript create new script
+func NewScript(src string) *Script {
+	sum := sha1.Sum([]byte(src))
+	return &Script{src: src, hash: hex.EncodeToString(sum[:])}
+}
+
+//Hash the SHA1 digest of the script
+func (s *Script) Hash() string {
+	return s.hash
+}
+
+//WithTimeout return a copy of the script whose reply is waited for timeout longer than SoTimeout,
+//for long running scripts,0 or negative timeout means without deadline,
+//the timeout applies if the client of Run implements ScriptTimeoutRunner
+func (s *Script) WithTimeout(timeout time.Duration) *Script {
+	return &Script{src: s.src, hash: s.hash, timeout: timeout, hasTimeout: true}
+}
+
+//Run run the script with the keys and args,the reply is converted like Eval
+func (s *Script) Run(client ScriptRunner, keys []string, args ...string) (interface{}, error) {
+	params := scriptParams(keys, args)
+	if t, ok := client.(ScriptTimeoutRunner); ok && s.hasTimeout {
+		reply, err := t.EvalShaTimeout(s.timeout, s.hash, len(keys), params...)
+		if isNoScriptError(err) {
+			return t.EvalTimeout(s.timeout, s.src, len(keys), params...)
+		}
+		return reply, err
+	}
+	reply, err := client.EvalSha(s.hash, len(keys), params...)
+	if isNoScriptError(err) {
+		//EVAL caches the script,the next run is sent by digest again
+		return client.Eval(s.src, len(keys), params...)
+	}
+	return reply, err
+}
+
+//RunRo run the read-only script by EVALSHA_RO,or EVAL_RO if it isn't cached,
+//so it can be executed on replicas,such as by ReplicaAwareClient,available since redis 7.0
+func (s *Script) RunRo(client ReadOnlyScriptRunner, keys []string, args ...string) (interface{}, error) {
+	params := scriptParams(keys, args)
+	reply, err := client.EvalShaRo(s.hash, len(keys), params...)
+	if isNoScriptError(err) {
+		return client.EvalRo(s.src, len(keys), params...)
+	}
+	return reply, err
+}
+
+func scriptParams(keys, args []string) []string {
+	params := make([]string, 0, len(keys)+len(args))
+	params = append(params, keys...)
+	return append(params, args...)
+}
+
+func isNoScriptError(err error) bool {
+	var noScript *NoScriptError
+	return errors.As(err, &noScript)
+}
+
+var (
+	//compareAndDeleteScript delete the key only if its value is ARGV[1]
+	compareAndDeleteScript = NewScript(cacheUnlockScript)
+
+	//compareAndSwapScript set the key to ARGV[2] only if its value is ARGV[1],the ttl is kept
+	compareAndSwapScript = NewScript(`if redis.call("get", KEYS[1]) ~= ARGV[1] then return 0 end
+local ttl = redis.call("pttl", KEYS[1])
+if ttl > 0 then
+	redis.call("set", KEYS[1], ARGV[2], "px", ttl)
+else
+	redis.call("set", KEYS[1], ARGV[2])
+end
+return 1`)
+
+	//incrWithExpireScript increment the key,set the ttl if the key has none,such as a new key
+	incrWithExpireScript = NewScript(`local n = redis.call("incrby", KEYS[1], ARGV[1])
+if redis.call("pttl", KEYS[1]) == -1 then
+	redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return n`)
+
+	//zPopByScoreScript remove and return at most ARGV[3] members with score between ARGV[1] and ARGV[2]
+	zPopByScoreScript = NewScript(`local items = redis.call("zrangebyscore", KEYS[1], ARGV[1], ARGV[2], "withscores", "limit", 0, ARGV[3])
+for i = 1, #items, 2 do
+	redis.call("zrem", KEYS[1], items[i])
+end
+return items`)
+)
+
+func compareAndDelete(client ScriptRunner, key, value string) (bool, error) {
+	reply, err := compareAndDeleteScript.Run(client, []string{key}, value)
+	if err != nil {
+		return false, err
+	}
+	return reply == int64(1), nil
+}
+
+func compareAndSwap(client ScriptRunner, key, oldValue, newValue string) (bool, error) {
+	reply, err := compareAndSwapScript.Run(client, []string{key}, oldValue, newValue)
+	if err != nil {
+		return false, err
+	}
+	return reply == int64(1), nil
+}
+
+func incrWithExpire(client ScriptRunner, key string, increment int64, ttl time.Duration) (int64, error) {
+	reply, err := incrWithExpireScript.Run(client, []string{key}, strconv.FormatInt(increment, 10), strconv.FormatInt(int64(ttl/time.Millisecond), 10))
+	if err != nil {
+		return 0, err
+	}
+	n, _ := reply.(int64)
+	return n, nil
+}
+
+func zPopByScore(client ScriptRunner, key string, min, max float64, count int64) ([]Tuple, error) {
+	if count <= 0 {
+		count = -1
+	}
+	reply, err := zPopByScoreScript.Run(client, []string{key}, Float64ToStr(min), Float64ToStr(max), strconv.FormatInt(count, 10))
+	if err != nil {
+		return nil, err
+	}
+	items, _ := reply.([]interface{})
+	tuples := make([]Tuple, 0, len(items)/2)
+	for i := 0; i+1 < len(items); i += 2 {
+		element, _ := items[i].(string)
+		score, _ := items[i+1].(string)
+		f, err := strconv.ParseFloat(score, 64)
+		if err != nil {
+			return nil, err
+		}
+		tuples = append(tuples, Tuple{element: element, score: f})
+	}
+	return tuples, nil
+}
+
+//<editor-fold desc="atomiccommands">
+
+//CompareAndDelete delete the key only if its value is value,return true if it is deleted,
+//such as releasing a lock held by a token
+func (r *Redis) CompareAndDelete(key, value string) (bool, error) {
+	return compareAndDelete(r, key, value)
+}
+
+//CompareAndSwap set the key to newValue only if its value is oldValue,the ttl is kept,
+//return true if it is set,false if the value is changed or the key doesn't exist
+func (r *Redis) CompareAndSwap(key, oldValue, newValue string) (bool, error) {
+	return compareAndSwap(r, key, oldValue, newValue)
+}
+
+//IncrWithExpire increment the key by increment,and set its ttl if it has none,such as a new key,
+//so a fixed window counter expires ttl after the first increment,return the value after the increment
+func (r *Redis) IncrWithExpire(key string, increment int64, ttl time.Duration) (int64, error) {
+	return incrWithExpire(r, key, increment, ttl)
+}
+
+//ZPopByScore remove and return at most count members with score between min and max,ordered by score,
+//0 means all of them
+func (r *Redis) ZPopByScore(key string, min, max float64, count int64) ([]Tuple, error) {
+	return zPopByScore(r, key, min, max, count)
+}
+
+//CompareAndDelete  see comment in scripts.go
+func (r *RedisCluster) CompareAndDelete(key, value string) (bool, error) {
+	return compareAndDelete(r, key, value)
+}
+
+//CompareAndSwap  see comment in scripts.go
+func (r *RedisCluster) CompareAndSwap(key, oldValue, newValue string) (bool, error) {
+	return compareAndSwap(r, key, oldValue, newValue)
+}
+
+//IncrWithExpire  see comment in scripts.go
+func (r *RedisCluster) IncrWithExpire(key string, increment int64, ttl time.Duration) (int64, error) {
+	return incrWithExpire(r, key, increment, ttl)
+}
+
+//ZPopByScore  see comment in scripts.go
+func (r *RedisCluster) ZPopByScore(key string, min, max float64, count int64) ([]Tuple, error) {
+	return zPopByScore(r, key, min, max, count)
+}
+
+//CompareAndDelete  see comment in scripts.go
+func (p *PooledRedis) CompareAndDelete(key, value string) (bool, error) {
+	return compareAndDelete(p, key, value)
+}
+
+//CompareAndSwap  see comment in scripts.go
+func (p *PooledRedis) CompareAndSwap(key, oldValue, newValue string) (bool, error) {
+	return compareAndSwap(p, key, oldValue, newValue)
+}
+
+//IncrWithExpire  see comment in scripts.go
+func (p *PooledRedis) IncrWithExpire(key string, increment int64, ttl time.Duration) (int64, error) {
+	return incrWithExpire(p, key, increment, ttl)
+}
+
+//ZPopByScore  see comment in scripts.go
+func (p *PooledRedis) ZPopByScore(key string, min, max float64, count int64) ([]Tuple, error) {
+	return zPopByScore(p, key, min, max, count)
+}
+
+//</editor-fold>
@@ -0,0 +1,190 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestScript_Run(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	//a new script isn't cached by the server,it is sent by EVAL first
+	script := NewScript("return KEYS[1] .. ARGV[1] --" + randomKeySuffix())
+	exists, err := redis.ScriptExists(script.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false}, exists)
+	reply, err := script.Run(redis, []string{"godis"}, "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "godis1", reply)
+	exists, err = redis.ScriptExists(script.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{true}, exists)
+	reply, err = script.Run(redis, []string{"godis"}, "2")
+	assert.Nil(t, err)
+	assert.Equal(t, "godis2", reply)
+
+	_, err = NewScript("return redis.call('incr')").Run(redis, nil)
+	assert.NotNil(t, err)
+}
+
+func TestRedis_CompareAndDelete(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.Set("godis", "token1")
+	ok, err := redis.CompareAndDelete("godis", "token2")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = redis.CompareAndDelete("godis", "token1")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	c, _ := redis.Exists("godis")
+	assert.Equal(t, int64(0), c)
+	ok, err = redis.CompareAndDelete("godis", "token1")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestRedis_CompareAndSwap(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	ok, err := redis.CompareAndSwap("godis", "1", "2")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	redis.SetWithParamsAndTime("godis", "1", "nx", "px", 100000)
+	ok, err = redis.CompareAndSwap("godis", "0", "2")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = redis.CompareAndSwap("godis", "1", "2")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	s, _ := redis.Get("godis")
+	assert.Equal(t, "2", s)
+	//the ttl is kept
+	ttl, _ := redis.PTTL("godis")
+	assert.True(t, ttl > 0)
+
+	redis.Set("godis2", "1")
+	ok, err = redis.CompareAndSwap("godis2", "1", "3")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ttl, _ = redis.PTTL("godis2")
+	assert.Equal(t, int64(-1), ttl)
+}
+
+func TestRedis_IncrWithExpire(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	c, err := redis.IncrWithExpire("godis", 2, 10*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+	ttl, _ := redis.PTTL("godis")
+	assert.True(t, ttl > 0 && ttl <= 10000)
+	redis.PExpire("godis", 100000)
+	c, err = redis.IncrWithExpire("godis", 3, 10*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), c)
+	//the ttl of an existing counter isn't reset
+	ttl, _ = redis.PTTL("godis")
+	assert.True(t, ttl > 10000)
+
+	redis.Set("godis2", "a")
+	_, err = redis.IncrWithExpire("godis2", 1, time.Second)
+	assert.NotNil(t, err)
+}
+
+func TestRedis_ZPopByScore(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.ZAddByMap("godis", map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4})
+	tuples, err := redis.ZPopByScore("godis", 2, 4, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "b", score: 2}, {element: "c", score: 3}}, tuples)
+	tuples, err = redis.ZPopByScore("godis", 0, 10, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []Tuple{{element: "a", score: 1}, {element: "d", score: 4}}, tuples)
+	c, _ := redis.ZCard("godis")
+	assert.Equal(t, int64(0), c)
+	tuples, err = redis.ZPopByScore("godis", 0, 10, 0)
+	assert.Nil(t, err)
+	assert.Empty(t, tuples)
+}
+
+func TestPooledRedis_AtomicScripts(t *testing.T) {
+	flushAll()
+	redis := NewPooledRedis(option, &PoolConfig{MaxTotal: 2})
+	c, err := redis.IncrWithExpire("godis", 1, 10*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	ok, err := redis.CompareAndSwap("godis", "1", "2")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = redis.CompareAndDelete("godis", "2")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	redis.ZAdd("godis", 1, "a")
+	tuples, err := redis.ZPopByScore("godis", 1, 1, 0)
+	assert.Nil(t, err)
+	assert.Len(t, tuples, 1)
+}
+
+func TestRedis_EvalRo(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.Set("godis", "good")
+	reply, err := redis.EvalRo("return redis.call('get', KEYS[1])", 1, "godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "good", reply)
+	script := NewScript("return redis.call('get', KEYS[1]) .. ARGV[1] --" + randomKeySuffix())
+	reply, err = script.RunRo(redis, []string{"godis"}, "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "good1", reply)
+	reply, err = redis.EvalShaRo(script.Hash(), 1, "godis", "2")
+	assert.Nil(t, err)
+	assert.Equal(t, "good2", reply)
+
+	spec, _ := LookupCommandSpec("EVAL_RO")
+	assert.True(t, spec.ReadOnly)
+	assert.True(t, IsReadOnlyCommand("EVALSHA_RO"))
+	assert.False(t, IsReadOnlyCommand("EVAL"))
+
+	client := NewReplicaAwareClient(&ReplicaOption{Master: option, Replicas: []*Option{option}})
+	defer client.Close()
+	reply, err = script.RunRo(client, []string{"godis"}, "3")
+	assert.Nil(t, err)
+	assert.Equal(t, "good3", reply)
+	reply, err = client.Do("EVAL_RO", "return ARGV[1]", "0", "4")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("4"), reply)
+}
+
+func TestRedis_EvalTimeout(t *testing.T) {
+	//the fake server never replies to EVAL and EVALSHA
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.SoTimeout = 100 * time.Millisecond
+
+	redis := NewRedis(fakeOption)
+	start := time.Now()
+	_, err := redis.Eval("return 1", 0)
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+	redis.Close()
+
+	redis = NewRedis(fakeOption)
+	start = time.Now()
+	_, err = NewScript("return 1").WithTimeout(200*time.Millisecond).Run(redis, nil)
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) >= 300*time.Millisecond)
+	//the timeout is only extended for the script
+	assert.Equal(t, time.Duration(0), redis.client.blockingRead)
+	redis.Close()
+}
@@ -0,0 +1,254 @@
+//Package sessions http sessions stored in redis,referenced by a cookie.
+//
+//the session is any value encoded by a godis.Codec,such as a struct:
+//
+//	store := sessions.NewStore(godis.NewPooledRedis(option, nil), &sessions.Options{TTL: time.Hour})
+//	http.Handle("/", store.Middleware(handler))
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		var user User
+//		found, err := store.Get(r, &user)
+//		...
+//		err = store.Save(w, r, &user)
+//	}
+//
+//call Regenerate when the user logs in,so a session id known before the login,
+//such as one planted by an attacker,is useless after it:
+//
+//	err := store.Regenerate(w, r)
+//	err = store.Save(w, r, &user)
+package sessions
+
+import (
+	"errors"
+	"github.com/piaohao/godis"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultCookieName = "session_id"
+	defaultPrefix     = "session"
+	defaultTTL        = 24 * time.Hour
+	idSize            = 32
+)
+
+//Client the commands Store needs,godis.Redis,godis.RedisCluster and godis.PooledRedis implement it,
+//a Redis must not be shared by concurrent requests,use PooledRedis instead
+type Client interface {
+	Get(key string) (string, error)
+	PSetEx(key string, milliseconds int64, value string) (string, error)
+	PExpire(key string, milliseconds int64) (int64, error)
+	Del(keys ...string) (int64, error)
+}
+
+var (
+	_ Client = (*godis.Redis)(nil)
+	_ Client = (*godis.RedisCluster)(nil)
+	_ Client = (*godis.PooledRedis)(nil)
+)
+
+//Options session store options
+type Options struct {
+	CookieName string        //name of the cookie holding the session id,default session_id
+	Prefix     string        //key prefix of the sessions,default session
+	TTL        time.Duration //the session expires after TTL without requests,default 24 hours
+	Codec      godis.Codec   //encode the sessions,default godis.JSONCodec
+
+	//attributes of the cookie,it is always HttpOnly
+	Path     string //default /
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+//Store session store,every session is a key with the ttl renewed on every request,
+//so it expires after TTL of inactivity.
+//
+//Store is safe for concurrent use if the client is
+type Store struct {
+	client  Client
+	options Options
+}
+
+//NewStore create new session store
+func NewStore(client Client, options *Options) *Store {
+	opt := Options{}
+	if options != nil {
+		opt = *options
+	}
+	if opt.CookieName == "" {
+		opt.CookieName = defaultCookieName
+	}
+	if opt.Prefix == "" {
+		opt.Prefix = defaultPrefix
+	}
+	if opt.TTL < time.Millisecond {
+		opt.TTL = defaultTTL
+	}
+	if opt.Codec == nil {
+		opt.Codec = godis.JSONCodec{}
+	}
+	if opt.Path == "" {
+		opt.Path = "/"
+	}
+	return &Store{client: client, options: opt}
+}
+
+//ID return the session id of the request,empty if the request has no session cookie
+func (s *Store) ID(r *http.Request) string {
+	cookie, err := r.Cookie(s.options.CookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+//Get decode the session of the request into v and renew its ttl,
+//return false if the request has no session or it is expired
+func (s *Store) Get(r *http.Request, v interface{}) (bool, error) {
+	id := s.ID(r)
+	if id == "" {
+		return false, nil
+	}
+	data, err := s.client.Get(s.key(id))
+	if errors.Is(err, godis.ErrNil) || (err == nil && data == "") {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := s.options.Codec.Unmarshal([]byte(data), v); err != nil {
+		return false, err
+	}
+	if _, err := s.client.PExpire(s.key(id), s.ttl()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//Save encode v as the session of the request,a new session is created and its cookie is set
+//if the request has none,or its session id is not stored,so Save must be called before the response is written,
+//an id sent by the client is never taken for a new session
+func (s *Store) Save(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	data, err := s.options.Codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	id := s.ID(r)
+	if id != "" {
+		stored, err := s.client.PExpire(s.key(id), s.ttl())
+		if err != nil {
+			return err
+		}
+		if stored == 0 {
+			id = ""
+		}
+	}
+	if id == "" {
+		if id, err = s.newID(r); err != nil {
+			return err
+		}
+	}
+	if _, err := s.client.PSetEx(s.key(id), s.ttl(), string(data)); err != nil {
+		return err
+	}
+	http.SetCookie(w, s.cookie(id, int(s.options.TTL/time.Second)))
+	return nil
+}
+
+//Regenerate move the session of the request to a new id and set its cookie,the old id is deleted,
+//call it when the privilege of the session changes,such as when the user logs in,
+//so Regenerate must be called before the response is written.
+//
+//it does nothing if the request has no stored session,Save creates one with a new id then
+func (s *Store) Regenerate(w http.ResponseWriter, r *http.Request) error {
+	oldID := s.ID(r)
+	if oldID == "" {
+		return nil
+	}
+	data, err := s.client.Get(s.key(oldID))
+	if errors.Is(err, godis.ErrNil) || (err == nil && data == "") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	id, err := s.newID(r)
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.PSetEx(s.key(id), s.ttl(), data); err != nil {
+		return err
+	}
+	if _, err := s.client.Del(s.key(oldID)); err != nil {
+		return err
+	}
+	http.SetCookie(w, s.cookie(id, int(s.options.TTL/time.Second)))
+	return nil
+}
+
+//Destroy delete the session of the request and its cookie,such as when the user logs out
+func (s *Store) Destroy(w http.ResponseWriter, r *http.Request) error {
+	id := s.ID(r)
+	if id == "" {
+		return nil
+	}
+	if _, err := s.client.Del(s.key(id)); err != nil {
+		return err
+	}
+	http.SetCookie(w, s.cookie("", -1))
+	return nil
+}
+
+//Middleware renew the ttl of the session and its cookie on every request with a session,
+//so the handlers only Get the session they read
+func (s *Store) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := s.ID(r); id != "" {
+			renewed, err := s.client.PExpire(s.key(id), s.ttl())
+			if err == nil && renewed == 1 {
+				http.SetCookie(w, s.cookie(id, int(s.options.TTL/time.Second)))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+//newID generate a session id,the handlers after it see the new id as the session cookie of the request
+func (s *Store) newID(r *http.Request) (string, error) {
+	id, err := godis.RandomToken(idSize)
+	if err != nil {
+		return "", err
+	}
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, cookie := range cookies {
+		if cookie.Name != s.options.CookieName {
+			r.AddCookie(cookie)
+		}
+	}
+	r.AddCookie(&http.Cookie{Name: s.options.CookieName, Value: id})
+	return id, nil
+}
+
+func (s *Store) cookie(id string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     s.options.CookieName,
+		Value:    id,
+		Path:     s.options.Path,
+		Domain:   s.options.Domain,
+		MaxAge:   maxAge,
+		Secure:   s.options.Secure,
+		HttpOnly: true,
+		SameSite: s.options.SameSite,
+	}
+}
+
+func (s *Store) key(id string) string {
+	return s.options.Prefix + ":" + id
+}
+
+func (s *Store) ttl() int64 {
+	return int64(s.options.TTL / time.Millisecond)
+}
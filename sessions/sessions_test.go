@@ -0,0 +1,158 @@
+package sessions
+
+import (
+	"github.com/piaohao/godis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var option = &godis.Option{
+	Host: "localhost",
+	Port: 6379,
+}
+
+type user struct {
+	Name  string
+	Visit int
+}
+
+func newTestStore(t *testing.T) (*Store, *godis.PooledRedis) {
+	flush := godis.NewRedis(option)
+	flush.FlushAll()
+	flush.Close()
+	redis := godis.NewPooledRedis(option, &godis.PoolConfig{MaxTotal: 2})
+	return NewStore(redis, &Options{TTL: time.Minute}), redis
+}
+
+func TestStore(t *testing.T) {
+	store, redis := newTestStore(t)
+
+	//no session yet
+	r := httptest.NewRequest("GET", "/", nil)
+	var u user
+	found, err := store.Get(r, &u)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	w := httptest.NewRecorder()
+	assert.Nil(t, store.Save(w, r, &user{Name: "godis", Visit: 1}))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "session_id", cookies[0].Name)
+	assert.Equal(t, 60, cookies[0].MaxAge)
+	assert.True(t, cookies[0].HttpOnly)
+	id := cookies[0].Value
+	assert.NotEmpty(t, id)
+	//the request after Save has the new session
+	assert.Equal(t, id, store.ID(r))
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: id})
+	found, err = store.Get(r, &u)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, user{Name: "godis", Visit: 1}, u)
+	ttl, _ := redis.PTTL("session:" + id)
+	assert.True(t, ttl > 0 && ttl <= 60000)
+
+	//saved again under the same id
+	u.Visit++
+	w = httptest.NewRecorder()
+	assert.Nil(t, store.Save(w, r, &u))
+	cookies = w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, id, cookies[0].Value)
+	var u2 user
+	found, err = store.Get(r, &u2)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 2, u2.Visit)
+
+	w = httptest.NewRecorder()
+	assert.Nil(t, store.Destroy(w, r))
+	cookies = w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, -1, cookies[0].MaxAge)
+	found, err = store.Get(r, &u2)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	//unknown session
+	r = httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: "unknown"})
+	found, err = store.Get(r, &u2)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	//an unknown id sent by the client is not taken for the new session
+	w = httptest.NewRecorder()
+	assert.Nil(t, store.Save(w, r, &user{Name: "godis"}))
+	cookies = w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.NotEqual(t, "unknown", cookies[0].Value)
+	assert.Equal(t, cookies[0].Value, store.ID(r))
+	exists, _ := redis.Exists("session:unknown")
+	assert.Equal(t, int64(0), exists)
+}
+
+func TestStore_Regenerate(t *testing.T) {
+	store, redis := newTestStore(t)
+	redis.PSetEx("session:abc", 60000, `{"Name":"godis"}`)
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: "abc"})
+	w := httptest.NewRecorder()
+	assert.Nil(t, store.Regenerate(w, r))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	id := cookies[0].Value
+	assert.NotEqual(t, "abc", id)
+	//the handlers after Regenerate see the new id,the old one is gone
+	assert.Equal(t, id, store.ID(r))
+	var u user
+	found, err := store.Get(r, &u)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "godis", u.Name)
+	exists, _ := redis.Exists("session:abc")
+	assert.Equal(t, int64(0), exists)
+
+	//nothing to move without a stored session
+	r = httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: "unknown"})
+	w = httptest.NewRecorder()
+	assert.Nil(t, store.Regenerate(w, r))
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestStore_Middleware(t *testing.T) {
+	store, redis := newTestStore(t)
+	redis.PSetEx("session:abc", 1000, `{"Name":"godis"}`)
+	var u user
+	handler := store.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		found, err := store.Get(r, &u)
+		assert.Nil(t, err)
+		assert.True(t, found)
+	}))
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: "abc"})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, "godis", u.Name)
+	//the ttl and the cookie are renewed
+	ttl, _ := redis.PTTL("session:abc")
+	assert.True(t, ttl > 1000)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, 60, cookies[0].MaxAge)
+
+	//no cookie is set for an unknown session
+	r = httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: "unknown"})
+	w = httptest.NewRecorder()
+	store.Middleware(http.NotFoundHandler()).ServeHTTP(w, r)
+	assert.Empty(t, w.Result().Cookies())
+}
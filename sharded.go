@@ -0,0 +1,201 @@
+package godis
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"sort"
+	"strconv"
+)
+
+const (
+	defaultShardWeight = 1
+	ketamaPointsPerMD5 = 4
+	ketamaMD5PerWeight = 40
+)
+
+//ShardInfo a standalone redis serving a part of the keys of ShardedRedis
+type ShardInfo struct {
+	Option *Option //default localhost:6379
+	Name   string  //identify the shard on the ring,default host:port,keep it when the shard moves to another address
+	Weight int     //relative count of keys served by the shard,default 1
+}
+
+//ShardedRedis distribute the keys across standalone redis servers by consistent hashing,like ShardedJedis,
+//for deployments of several independent redis instead of a cluster.
+//
+//the shards are placed on a ketama ring,160 points for every weight,a key is served by the first point following its hash,
+//so adding or removing a shard only moves the keys of its neighbours.
+//only the part of the key inside {} is hashed if it is present,so keys with the same tag are on the same shard.
+//
+//ShardedRedis is safe for concurrent use
+type ShardedRedis struct {
+	shards []*shard
+	points []uint32 //sorted hash points of the ring
+	owners []*shard //owners[i] is the shard of points[i]
+	tag    *redisClusterHashTagUtil
+}
+
+type shard struct {
+	info *ShardInfo
+	pool *Pool
+}
+
+//NewShardedRedis create new sharded redis,every shard has a pool created with poolConfig
+func NewShardedRedis(shards []*ShardInfo, poolConfig *PoolConfig) *ShardedRedis {
+	r := &ShardedRedis{tag: newRedisClusterHashTagUtil()}
+	for _, info := range shards {
+		i := *info
+		if i.Option == nil {
+			i.Option = &Option{Host: defaultHost, Port: defaultPort}
+		}
+		if i.Name == "" {
+			i.Name = i.Option.Host + ":" + strconv.Itoa(i.Option.Port)
+		}
+		if i.Weight <= 0 {
+			i.Weight = defaultShardWeight
+		}
+		r.shards = append(r.shards, &shard{info: &i, pool: NewPool(poolConfig, i.Option)})
+	}
+	r.initRing()
+	return r
+}
+
+//initRing place every shard on the ring by the ketama algorithm
+func (r *ShardedRedis) initRing() {
+	type point struct {
+		hash  uint32
+		owner *shard
+	}
+	points := make([]point, 0)
+	for _, s := range r.shards {
+		for n := 0; n < ketamaMD5PerWeight*s.info.Weight; n++ {
+			digest := md5.Sum([]byte(s.info.Name + "-" + strconv.Itoa(n)))
+			for h := 0; h < ketamaPointsPerMD5; h++ {
+				points = append(points, point{hash: binary.LittleEndian.Uint32(digest[h*4:]), owner: s})
+			}
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].hash < points[j].hash
+	})
+	r.points = make([]uint32, len(points))
+	r.owners = make([]*shard, len(points))
+	for i, p := range points {
+		r.points[i] = p.hash
+		r.owners[i] = p.owner
+	}
+}
+
+//ketamaHash the hash of the key on the ring
+func ketamaHash(key string) uint32 {
+	digest := md5.Sum([]byte(key))
+	return binary.LittleEndian.Uint32(digest[:4])
+}
+
+func (r *ShardedRedis) shard(key string) *shard {
+	if len(r.points) == 0 {
+		return nil
+	}
+	hash := ketamaHash(r.tag.getHashTag(key))
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i] >= hash
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[i]
+}
+
+//GetShardInfo the shard serving the key
+func (r *ShardedRedis) GetShardInfo(key string) *ShardInfo {
+	s := r.shard(key)
+	if s == nil {
+		return nil
+	}
+	return s.info
+}
+
+//GetShard get a connection of the shard serving the key,close it after use
+func (r *ShardedRedis) GetShard(key string) (*Redis, error) {
+	s := r.shard(key)
+	if s == nil {
+		return nil, newDataError("no shard")
+	}
+	return s.pool.GetResource()
+}
+
+//AllShardInfo the infos of all shards
+func (r *ShardedRedis) AllShardInfo() []*ShardInfo {
+	infos := make([]*ShardInfo, 0, len(r.shards))
+	for _, s := range r.shards {
+		infos = append(infos, s.info)
+	}
+	return infos
+}
+
+//With run fn with a connection of the shard serving the key
+func (r *ShardedRedis) With(key string, fn func(redis *Redis) error) error {
+	redis, err := r.GetShard(key)
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	return fn(redis)
+}
+
+//ForEach run fn with a connection of every shard in order,stop at the first error,
+//for commands without key,such as FLUSHDB and DBSIZE
+func (r *ShardedRedis) ForEach(fn func(info *ShardInfo, redis *Redis) error) error {
+	for _, s := range r.shards {
+		redis, err := s.pool.GetResource()
+		if err != nil {
+			return err
+		}
+		err = fn(s.info, redis)
+		redis.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Do send the command to the shard serving its keys and return the reply,the reply is the same as Redis.Receive.
+//all the keys of the command must be on the same shard,use hash tags to keep them together,
+//commands without key are rejected,use ForEach for them
+func (r *ShardedRedis) Do(command string, args ...string) (interface{}, error) {
+	spec, ok := LookupCommandSpec(command)
+	if !ok {
+		return nil, newDataError("unknown command " + command + ",the key can't be found")
+	}
+	if err := spec.CheckArity(len(args)); err != nil {
+		return nil, err
+	}
+	keys := spec.Keys(args...)
+	if len(keys) == 0 {
+		return nil, newDataError("command " + spec.Name + " has no key,use ForEach instead")
+	}
+	s := r.shard(keys[0])
+	for _, key := range keys[1:] {
+		if r.shard(key) != s {
+			return nil, newDataError("keys of command " + spec.Name + " are on different shards")
+		}
+	}
+	var reply interface{}
+	err := r.With(keys[0], func(redis *Redis) error {
+		err := redis.SendByStr(command, StrArrToByteArrArr(args)...)
+		if err != nil {
+			return err
+		}
+		reply, err = redis.Receive()
+		return err
+	})
+	return reply, err
+}
+
+//Close destroy the pools of all shards
+func (r *ShardedRedis) Close() {
+	for _, s := range r.shards {
+		s.pool.Destroy()
+	}
+}
@@ -0,0 +1,103 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestShardedRedis(t *testing.T) {
+	flushAll()
+	//db 0 and db 1 are the shards,so the routing can be observed
+	shards := []*ShardInfo{
+		{Option: option, Name: "shard0"},
+		{Option: &Option{Host: "localhost", Port: 6379, Db: 1}, Name: "shard1"},
+	}
+	sharded := NewShardedRedis(shards, nil)
+	defer sharded.Close()
+	counts := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		key := "godis" + strconv.Itoa(i)
+		reply, err := sharded.Do("set", key, "good")
+		assert.Nil(t, err)
+		assert.Equal(t, "OK", string(reply.([]byte)))
+		info := sharded.GetShardInfo(key)
+		counts[info.Name]++
+		redis := NewRedis(info.Option)
+		value, err := redis.Get(key)
+		assert.Nil(t, err)
+		assert.Equal(t, "good", value)
+		redis.Close()
+	}
+	assert.True(t, counts["shard0"] > 20)
+	assert.True(t, counts["shard1"] > 20)
+
+	err := sharded.With("godis0", func(redis *Redis) error {
+		value, err := redis.Get("godis0")
+		assert.Equal(t, "good", value)
+		return err
+	})
+	assert.Nil(t, err)
+
+	//keys with the same tag are on the same shard
+	reply, err := sharded.Do("mset", "{user}.a", "1", "{user}.b", "2")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", string(reply.([]byte)))
+	var differentShards bool
+	for i := 1; i < 100 && !differentShards; i++ {
+		differentShards = sharded.GetShardInfo("godis0") != sharded.GetShardInfo("godis"+strconv.Itoa(i))
+		if differentShards {
+			_, err = sharded.Do("mget", "godis0", "godis"+strconv.Itoa(i))
+			assert.IsType(t, &DataError{}, err)
+		}
+	}
+	assert.True(t, differentShards)
+	_, err = sharded.Do("ping")
+	assert.IsType(t, &DataError{}, err)
+
+	var total int64
+	err = sharded.ForEach(func(info *ShardInfo, redis *Redis) error {
+		size, err := redis.DbSize()
+		total += size
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(102), total)
+	assert.Len(t, sharded.AllShardInfo(), 2)
+	sharded.ForEach(func(info *ShardInfo, redis *Redis) error {
+		_, err := redis.FlushDB()
+		return err
+	})
+}
+
+func TestShardedRedis_Ring(t *testing.T) {
+	shard := func(name string, weight int) *ShardInfo {
+		return &ShardInfo{Option: &Option{Host: "localhost", Port: 6380}, Name: name, Weight: weight}
+	}
+	three := NewShardedRedis([]*ShardInfo{shard("a", 1), shard("b", 1), shard("c", 2)}, nil)
+	defer three.Close()
+	two := NewShardedRedis([]*ShardInfo{shard("a", 1), shard("c", 2)}, nil)
+	defer two.Close()
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		key := "godis" + strconv.Itoa(i)
+		name := three.GetShardInfo(key).Name
+		counts[name]++
+		//removing b only moves the keys of b
+		if name != "b" {
+			assert.Equal(t, name, two.GetShardInfo(key).Name)
+		}
+	}
+	assert.True(t, counts["c"] > counts["a"])
+	assert.True(t, counts["c"] > counts["b"])
+
+	//the option defaults to localhost:6379
+	local := NewShardedRedis([]*ShardInfo{{Weight: 1}}, nil)
+	defer local.Close()
+	assert.Equal(t, "localhost:6379", local.GetShardInfo("godis").Name)
+
+	empty := NewShardedRedis(nil, nil)
+	assert.Nil(t, empty.GetShardInfo("godis"))
+	_, err := empty.GetShard("godis")
+	assert.NotNil(t, err)
+}
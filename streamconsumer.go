@@ -0,0 +1,279 @@
+package godis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStreamCount = 10
+	defaultStreamBlock = time.Second
+)
+
+//StreamConsumerOption stream consumer options
+type StreamConsumerOption struct {
+	Stream           string          //the stream to consume
+	Group            string          //the consumer group,created with the stream if it does not exist
+	Consumer         string          //name of the consumer in the group,shared by all the workers
+	StartID          string          //id the group is created from,default $,means new entries only
+	Workers          int             //goroutines handling entries,default 1
+	Count            int             //max entries read by a worker at a time,default 10
+	Block            time.Duration   //time a worker waits for new entries,default 1s
+	ClaimMinIdle     time.Duration   //claim pending entries idle for at least ClaimMinIdle,0 means never claim
+	ClaimInterval    time.Duration   //interval of claiming,default ClaimMinIdle
+	MaxDeliveries    int64           //entries delivered MaxDeliveries times are moved to DeadLetterStream when claimed,0 means no limit
+	DeadLetterStream string          //stream receiving dead entries,default Stream+":dead"
+	OnError          func(err error) //called with the errors of redis and handler,default ignore them
+}
+
+//StreamConsumer consume a stream as a member of a consumer group,
+//every entry is acknowledged after the handler returns nil,
+//an entry whose handler fails stays pending until it is claimed again.
+//
+//with ClaimMinIdle,entries left pending by failed handlers or crashed consumers are claimed
+//and handled again once they are idle for ClaimMinIdle,so the handler may see an entry more than once,
+//ClaimMinIdle must be longer than the handler takes,or entries being handled are claimed too.
+//
+//the pool needs a connection for every worker and one more for claiming
+type StreamConsumer struct {
+	pool    *Pool
+	option  StreamConsumerOption
+	handler func(entry StreamEntry) error
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+//NewStreamConsumer create new stream consumer
+func NewStreamConsumer(pool *Pool, option *StreamConsumerOption, handler func(entry StreamEntry) error) *StreamConsumer {
+	opt := *option
+	if opt.StartID == "" {
+		opt.StartID = "$"
+	}
+	if opt.Workers <= 0 {
+		opt.Workers = 1
+	}
+	if opt.Count <= 0 {
+		opt.Count = defaultStreamCount
+	}
+	if opt.Block <= 0 {
+		opt.Block = defaultStreamBlock
+	}
+	if opt.ClaimInterval <= 0 {
+		opt.ClaimInterval = opt.ClaimMinIdle
+	}
+	if opt.DeadLetterStream == "" {
+		opt.DeadLetterStream = opt.Stream + ":dead"
+	}
+	if opt.OnError == nil {
+		opt.OnError = func(err error) {}
+	}
+	return &StreamConsumer{pool: pool, option: opt, handler: handler, stop: make(chan struct{})}
+}
+
+//Start create the group if necessary,then start the workers and the claiming
+func (c *StreamConsumer) Start() error {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	_, err = redis.XGroupCreate(c.option.Stream, c.option.Group, c.option.StartID, true)
+	redis.Close()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	for i := 0; i < c.option.Workers; i++ {
+		c.wg.Add(1)
+		go c.work()
+	}
+	if c.option.ClaimMinIdle > 0 {
+		c.wg.Add(1)
+		go c.claimLoop()
+	}
+	return nil
+}
+
+//Shutdown stop reading new entries and wait for the entries being handled,
+//return the error of ctx if it is done first
+func (c *StreamConsumer) Shutdown(ctx context.Context) error {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *StreamConsumer) stopped() bool {
+	select {
+	case <-c.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+//pause wait d after an error,return false if the consumer is stopped meanwhile
+func (c *StreamConsumer) pause(d time.Duration) bool {
+	select {
+	case <-c.stop:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func (c *StreamConsumer) work() {
+	defer c.wg.Done()
+	for !c.stopped() {
+		entries, err := c.read()
+		if err != nil {
+			c.option.OnError(err)
+			if !c.pause(c.option.Block) {
+				return
+			}
+			continue
+		}
+		for _, entry := range entries {
+			c.handle(entry)
+		}
+	}
+}
+
+func (c *StreamConsumer) read() ([]StreamEntry, error) {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	streams, err := redis.XReadGroup(c.option.Group, c.option.Consumer, c.option.Count, c.option.Block, c.option.Stream, ">")
+	if err != nil || len(streams) == 0 {
+		return nil, err
+	}
+	return streams[0].Entries, nil
+}
+
+//handle run the handler and acknowledge the entry if it succeeds
+func (c *StreamConsumer) handle(entry StreamEntry) {
+	if err := c.handler(entry); err != nil {
+		c.option.OnError(err)
+		return
+	}
+	if err := c.ack(entry.ID); err != nil {
+		c.option.OnError(err)
+	}
+}
+
+func (c *StreamConsumer) ack(ids ...string) error {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	_, err = redis.XAck(c.option.Stream, c.option.Group, ids...)
+	return err
+}
+
+func (c *StreamConsumer) claimLoop() {
+	defer c.wg.Done()
+	for c.pause(c.option.ClaimInterval) {
+		if err := c.claim(); err != nil {
+			c.option.OnError(err)
+		}
+	}
+}
+
+//claim take over the idle pending entries page by page,
+//dead letter the entries delivered MaxDeliveries times and handle the others
+func (c *StreamConsumer) claim() error {
+	start := "0-0"
+	for !c.stopped() {
+		dead, err := c.deadEntries(start)
+		if err != nil {
+			return err
+		}
+		result, err := c.autoClaim(start)
+		if err != nil {
+			return err
+		}
+		if len(result.Deleted) > 0 {
+			if err := c.ack(result.Deleted...); err != nil {
+				return err
+			}
+		}
+		for _, entry := range result.Entries {
+			if entry.Fields == nil {
+				err = c.ack(entry.ID)
+			} else if dead[entry.ID] {
+				err = c.deadLetter(entry)
+			} else {
+				c.handle(entry)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if result.Next == "0-0" || len(result.Entries) == 0 {
+			return nil
+		}
+		start = result.Next
+	}
+	return nil
+}
+
+//deadEntries ids of the idle pending entries from start which reach MaxDeliveries
+func (c *StreamConsumer) deadEntries(start string) (map[string]bool, error) {
+	dead := make(map[string]bool)
+	if c.option.MaxDeliveries <= 0 {
+		return dead, nil
+	}
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	pending, err := redis.XPending(c.option.Stream, c.option.Group, start, "+", int64(c.option.Count),
+		NewXPendingParams().Idle(c.option.ClaimMinIdle))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pending {
+		if p.Deliveries >= c.option.MaxDeliveries {
+			dead[p.ID] = true
+		}
+	}
+	return dead, nil
+}
+
+func (c *StreamConsumer) autoClaim(start string) (*StreamClaimResult, error) {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return nil, err
+	}
+	defer redis.Close()
+	return redis.XAutoClaim(c.option.Stream, c.option.Group, c.option.Consumer, c.option.ClaimMinIdle, start, c.option.Count)
+}
+
+//deadLetter move the entry to DeadLetterStream and acknowledge it
+func (c *StreamConsumer) deadLetter(entry StreamEntry) error {
+	redis, err := c.pool.GetResource()
+	if err != nil {
+		return err
+	}
+	defer redis.Close()
+	if _, err := redis.XAdd(c.option.DeadLetterStream, "*", entry.Fields); err != nil {
+		return err
+	}
+	_, err = redis.XAck(c.option.Stream, c.option.Group, entry.ID)
+	return err
+}
@@ -0,0 +1,80 @@
+package godis
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamConsumer(t *testing.T) {
+	flushAll()
+	pool := NewPool(&PoolConfig{MaxTotal: 4}, option)
+	defer pool.Destroy()
+
+	var mu sync.Mutex
+	handled := make([]string, 0)
+	failed := 0
+	consumer := NewStreamConsumer(pool, &StreamConsumerOption{
+		Stream:        "stream",
+		Group:         "group",
+		Consumer:      "c1",
+		Workers:       2,
+		Block:         100 * time.Millisecond,
+		ClaimMinIdle:  50 * time.Millisecond,
+		MaxDeliveries: 2,
+	}, func(entry StreamEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if entry.Fields["name"] == "bad" {
+			failed++
+			return errors.New("handle failed")
+		}
+		if entry.Fields["name"] == "flaky" && failed == 0 {
+			failed++
+			return errors.New("handle failed")
+		}
+		handled = append(handled, entry.Fields["name"])
+		return nil
+	})
+	assert.Nil(t, consumer.Start())
+	//the group exists already
+	other := NewStreamConsumer(pool, &StreamConsumerOption{Stream: "stream", Group: "group", Block: 10 * time.Millisecond}, nil)
+	assert.Nil(t, other.Start())
+	assert.Nil(t, other.Shutdown(context.Background()))
+
+	redis := NewRedis(option)
+	defer redis.Close()
+	for _, name := range []string{"a", "flaky", "bad", "b"} {
+		_, err := redis.XAdd("stream", "*", map[string]string{"name": name})
+		assert.Nil(t, err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		c, _ := redis.XLen("stream:dead")
+		if c == 1 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, consumer.Shutdown(ctx))
+	assert.Nil(t, consumer.Shutdown(ctx))
+
+	mu.Lock()
+	assert.ElementsMatch(t, []string{"a", "b", "flaky"}, handled)
+	mu.Unlock()
+	pending, err := redis.XPending("stream", "group", "-", "+", 10)
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+	c, err := redis.XLen("stream:dead")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+
+	brokenPool := NewPool(nil, &Option{Host: "localhost1"})
+	defer brokenPool.Destroy()
+	assert.NotNil(t, NewStreamConsumer(brokenPool, &StreamConsumerOption{Stream: "stream", Group: "group"}, nil).Start())
+}
@@ -0,0 +1,126 @@
+package godis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTempKeyPrefix = "godis:tmp"
+	defaultTempKeyTTL    = time.Minute
+)
+
+//TempKeyClient the commands TempKey needs,both Redis and RedisCluster implement it
+type TempKeyClient interface {
+	PExpire(key string, milliseconds int64) (int64, error)
+	Del(keys ...string) (int64, error)
+}
+
+var (
+	_ TempKeyClient = (*Redis)(nil)
+	_ TempKeyClient = (*RedisCluster)(nil)
+)
+
+//TempKeyOption temp key options
+type TempKeyOption struct {
+	Prefix  string        //key prefix,default godis:tmp
+	TTL     time.Duration //the key expires after TTL even if it is never released,default 1 minute
+	SlotKey string        //the temp key is put in the same cluster slot as SlotKey,required by cluster *STORE commands
+}
+
+//TempKey a collision-safe temporary destination key for the *STORE commands,
+//such as SInterStore,ZUnionStore and SortStore.
+//
+//	tmp := godis.NewTempKey(redis, &godis.TempKeyOption{SlotKey: "{user:1}:a"})
+//	defer tmp.Release()
+//	c, err := tmp.Store(func(dest string) (int64, error) {
+//		return redis.SInterStore(dest, "{user:1}:a", "{user:1}:b")
+//	})
+type TempKey struct {
+	Name string //the generated key name
+
+	client TempKeyClient
+	ttl    time.Duration
+}
+
+//NewTempKey create a temp key with a random name,the key is not created in redis until a store command writes it
+func NewTempKey(client TempKeyClient, option *TempKeyOption) *TempKey {
+	if option == nil {
+		option = &TempKeyOption{}
+	}
+	prefix := option.Prefix
+	if prefix == "" {
+		prefix = defaultTempKeyPrefix
+	}
+	ttl := option.TTL
+	if ttl <= 0 {
+		ttl = defaultTempKeyTTL
+	}
+	name := prefix + ":" + randomKeySuffix()
+	if option.SlotKey != "" {
+		tag := newRedisClusterHashTagUtil().getHashTag(option.SlotKey)
+		if !strings.ContainsAny(tag, "{}") {
+			name = prefix + ":{" + tag + "}" + name[len(prefix):]
+		}
+	}
+	return &TempKey{Name: name, client: client, ttl: ttl}
+}
+
+//Store run the store command with the temp key as destination,then set the TTL of the temp key
+func (t *TempKey) Store(store func(dest string) (int64, error)) (int64, error) {
+	c, err := store(t.Name)
+	if err != nil {
+		return 0, err
+	}
+	if err := t.Expire(); err != nil {
+		return 0, err
+	}
+	return c, nil
+}
+
+//Expire set the TTL of the temp key,call it after the key is written by a store command
+func (t *TempKey) Expire() error {
+	_, err := t.client.PExpire(t.Name, t.ttl.Nanoseconds()/1e6)
+	return err
+}
+
+//Release delete the temp key,usually called with defer
+func (t *TempKey) Release() error {
+	_, err := t.client.Del(t.Name)
+	return err
+}
+
+//intersectionClient the commands EstimateIntersection needs
+type intersectionClient interface {
+	TempKeyClient
+	SInterCard(limit int64, keys ...string) (int64, error)
+	SInterStore(destKey string, srcKeys ...string) (int64, error)
+}
+
+func estimateIntersection(client intersectionClient, keys ...string) (int64, error) {
+	c, err := client.SInterCard(0, keys...)
+	if !IsUnknownCommandError(err) {
+		return c, err
+	}
+	option := &TempKeyOption{}
+	if len(keys) > 0 {
+		option.SlotKey = keys[0]
+	}
+	tmp := NewTempKey(client, option)
+	defer tmp.Release()
+	//SINTERSTORE returns the cardinality of the stored set,SCARD isn't needed
+	return tmp.Store(func(dest string) (int64, error) {
+		return client.SInterStore(dest, keys...)
+	})
+}
+
+func randomKeySuffix() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,54 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTempKey(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	redis.SAdd("{user:1}:a", "1", "2", "3")
+	redis.SAdd("{user:1}:b", "2", "3", "4")
+
+	tmp := NewTempKey(redis, &TempKeyOption{SlotKey: "{user:1}:a", TTL: 10 * time.Second})
+	assert.True(t, strings.HasPrefix(tmp.Name, "godis:tmp:{user:1}:"))
+	assert.NotEqual(t, tmp.Name, NewTempKey(redis, &TempKeyOption{SlotKey: "{user:1}:a"}).Name)
+
+	c, err := tmp.Store(func(dest string) (int64, error) {
+		return redis.SInterStore(dest, "{user:1}:a", "{user:1}:b")
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), c)
+	arr, err := redis.SMembers(tmp.Name)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"2", "3"}, arr)
+	ttl, err := redis.TTL(tmp.Name)
+	assert.Nil(t, err)
+	assert.True(t, ttl > 0 && ttl <= 10)
+
+	assert.Nil(t, tmp.Release())
+	b, err := redis.Exists(tmp.Name)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), b)
+
+	tmp = NewTempKey(redis, &TempKeyOption{Prefix: "tmp", SlotKey: "user"})
+	assert.True(t, strings.HasPrefix(tmp.Name, "tmp:{user}:"))
+	tmp = NewTempKey(redis, nil)
+	assert.True(t, strings.HasPrefix(tmp.Name, "godis:tmp:"))
+	assert.False(t, strings.Contains(tmp.Name, "{"))
+
+	redisBroken := NewRedis(option)
+	defer redisBroken.Close()
+	redisBroken.client.connection.host = "localhost1"
+	redisBroken.Close()
+	tmp = NewTempKey(redisBroken, nil)
+	_, err = tmp.Store(func(dest string) (int64, error) {
+		return redisBroken.SInterStore(dest, "{user:1}:a", "{user:1}:b")
+	})
+	assert.NotNil(t, err)
+	assert.NotNil(t, tmp.Release())
+}
@@ -0,0 +1,155 @@
+package godis
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultTokensPrefix = "godis:token"
+	defaultTokenSize    = 32
+)
+
+//tokenConsumeScript the GETDEL of the servers older than 6.2
+var tokenConsumeScript = NewScript(`local v = redis.call("get", KEYS[1])
+if v then
+	redis.call("del", KEYS[1])
+end
+return v`)
+
+//TokensClient the commands Tokens needs,Redis,RedisCluster and PooledRedis implement it
+type TokensClient interface {
+	ScriptRunner
+	SetWithParamsAndTime(key, value, nxxx, expx string, time int64) (string, error)
+	GetDel(key string) (string, error)
+}
+
+var (
+	_ TokensClient = (*Redis)(nil)
+	_ TokensClient = (*RedisCluster)(nil)
+	_ TokensClient = (*PooledRedis)(nil)
+)
+
+//TokensOption one-time token options
+type TokensOption struct {
+	Prefix string //key prefix of the tokens,default godis:token
+	Size   int    //random bytes of a token,default 32,the token is their url safe base64 encoding
+}
+
+//Tokens one-time tokens,such as password reset and email verification links.
+//
+//a token is a secure random string stored with a ttl,it can be consumed only once,
+//by GETDEL,or by a lua script on servers older than 6.2.
+//
+//	token, err := tokens.IssueValue(userID, 30*time.Minute)
+//	//send the link with token,then when it is clicked
+//	userID, ok, err := tokens.ConsumeValue(token)
+//
+//Tokens is safe for concurrent use
+type Tokens struct {
+	client TokensClient
+	option TokensOption
+
+	noGetDel int32 //1 if the server doesn't support GETDEL
+}
+
+//NewTokens create new one-time tokens
+func NewTokens(client TokensClient, option *TokensOption) *Tokens {
+	opt := TokensOption{}
+	if option != nil {
+		opt = *option
+	}
+	if opt.Prefix == "" {
+		opt.Prefix = defaultTokensPrefix
+	}
+	if opt.Size <= 0 {
+		opt.Size = defaultTokenSize
+	}
+	return &Tokens{client: client, option: opt}
+}
+
+//RandomToken return a url safe string of size random bytes from crypto/rand,
+//it is suitable for secrets,unlike a string from math/rand
+func RandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+//Issue create a token expiring after ttl
+func (t *Tokens) Issue(ttl time.Duration) (string, error) {
+	return t.IssueValue("1", ttl)
+}
+
+//IssueValue create a token expiring after ttl with the value returned by ConsumeValue,
+//such as the user the token is sent to,value must not be empty and ttl must be at least 1ms
+func (t *Tokens) IssueValue(value string, ttl time.Duration) (string, error) {
+	if value == "" {
+		return "", newDataError("token value must not be empty")
+	}
+	//PX takes whole positive milliseconds
+	if ttl < time.Millisecond {
+		return "", newDataError(fmt.Sprintf("token ttl %v is shorter than 1ms", ttl))
+	}
+	token, err := RandomToken(t.option.Size)
+	if err != nil {
+		return "", err
+	}
+	status, err := t.client.SetWithParamsAndTime(t.key(token), value, "nx", "px", int64(ttl/time.Millisecond))
+	if errors.Is(err, ErrNil) {
+		//the nil reply of SET NX with Option.ReturnErrNil
+		return "", newDataError("token collision")
+	}
+	if err != nil {
+		return "", err
+	}
+	if status != keywordOk.name {
+		return "", newDataError("token collision")
+	}
+	return token, nil
+}
+
+//Consume consume the token,return false if it is expired,consumed or never issued
+func (t *Tokens) Consume(token string) (bool, error) {
+	_, ok, err := t.ConsumeValue(token)
+	return ok, err
+}
+
+//ConsumeValue consume the token and return its value,
+//return false if it is expired,consumed or never issued
+func (t *Tokens) ConsumeValue(token string) (string, bool, error) {
+	value, err := t.getDel(t.key(token))
+	if errors.Is(err, ErrNil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, value != "", nil
+}
+
+func (t *Tokens) getDel(key string) (string, error) {
+	if atomic.LoadInt32(&t.noGetDel) == 0 {
+		value, err := t.client.GetDel(key)
+		if !IsUnknownCommandError(err) && !errors.Is(err, ErrUnsupportedVersion) {
+			return value, err
+		}
+		atomic.StoreInt32(&t.noGetDel, 1)
+	}
+	reply, err := tokenConsumeScript.Run(t.client, []string{key})
+	if err != nil {
+		return "", err
+	}
+	value, _ := reply.(string)
+	return value, nil
+}
+
+func (t *Tokens) key(token string) string {
+	return t.option.Prefix + ":" + token
+}
@@ -0,0 +1,105 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+//redisWithoutGetDel a server older than 6.2
+type redisWithoutGetDel struct {
+	*Redis
+}
+
+func (r *redisWithoutGetDel) GetDel(key string) (string, error) {
+	return "", newDataError("ERR unknown command `GETDEL`, with args beginning with: ")
+}
+
+//redisCollidingSet a server where every token already exists,with Option.ReturnErrNil
+type redisCollidingSet struct {
+	*Redis
+}
+
+func (r *redisCollidingSet) SetWithParamsAndTime(key, value, nxxx, expx string, time int64) (string, error) {
+	return "", ErrNil
+}
+
+func TestRandomToken(t *testing.T) {
+	token, err := RandomToken(32)
+	assert.Nil(t, err)
+	assert.Len(t, token, 43)
+	assert.NotContains(t, token, "/")
+	token2, _ := RandomToken(32)
+	assert.NotEqual(t, token, token2)
+}
+
+func TestTokens(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	tokens := NewTokens(redis, nil)
+	token, err := tokens.Issue(time.Minute)
+	assert.Nil(t, err)
+	ttl, _ := redis.PTTL("godis:token:" + token)
+	assert.True(t, ttl > 0 && ttl <= 60000)
+	ok, err := tokens.Consume(token)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = tokens.Consume(token)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = tokens.Consume("unknown")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	token, err = tokens.IssueValue("user1", time.Minute)
+	assert.Nil(t, err)
+	value, ok, err := tokens.ConsumeValue(token)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "user1", value)
+	_, err = tokens.IssueValue("", time.Minute)
+	assert.NotNil(t, err)
+
+	//ErrNil means the token isn't found
+	errNilOption := *option
+	errNilOption.ReturnErrNil = true
+	redis2 := NewRedis(&errNilOption)
+	defer redis2.Close()
+	ok, err = NewTokens(redis2, nil).Consume("unknown")
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestTokens_WithoutGetDel(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	tokens := NewTokens(&redisWithoutGetDel{redis}, &TokensOption{Prefix: "reset", Size: 16})
+	token, err := tokens.IssueValue("user1", time.Minute)
+	assert.Nil(t, err)
+	assert.Len(t, token, 22)
+	value, ok, err := tokens.ConsumeValue(token)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "user1", value)
+	_, ok, err = tokens.ConsumeValue(token)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	c, _ := redis.Exists("reset:" + token)
+	assert.Equal(t, int64(0), c)
+}
+
+func TestTokens_IssueValue(t *testing.T) {
+	tokens := NewTokens(&redisCollidingSet{}, nil)
+	_, err := tokens.IssueValue("user1", time.Minute)
+	assert.EqualError(t, err, "token collision")
+	//PX would get 0 or a negative ttl
+	for _, ttl := range []time.Duration{0, time.Microsecond, -time.Minute} {
+		_, err = tokens.IssueValue("user1", ttl)
+		var dataErr *DataError
+		assert.True(t, errors.As(err, &dataErr), ttl)
+		assert.Contains(t, err.Error(), "shorter than 1ms")
+	}
+}
@@ -0,0 +1,116 @@
+package godis
+
+import (
+	"strconv"
+	"time"
+)
+
+//formatBool the value stored by SetBool,1 or 0 like the replies of redis
+func formatBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+//<editor-fold desc="typedcommands">
+
+//SetInt64 set key to the decimal string of value,it can be changed by INCRBY later
+func (r *Redis) SetInt64(key string, value int64) (string, error) {
+	return r.Set(key, strconv.FormatInt(value, 10))
+}
+
+//SetFloat64 set key to the shortest decimal string of value,it can be changed by INCRBYFLOAT later
+func (r *Redis) SetFloat64(key string, value float64) (string, error) {
+	return r.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+//SetBool set key to 1 or 0
+func (r *Redis) SetBool(key string, value bool) (string, error) {
+	return r.Set(key, formatBool(value))
+}
+
+//SetTime set key to value in RFC3339 with nanoseconds,so GetTime returns the same time
+func (r *Redis) SetTime(key string, value time.Time) (string, error) {
+	return r.Set(key, value.Format(time.RFC3339Nano))
+}
+
+//GetInt64 get the value of key as int64,
+//return ErrNil if key does not exist,even if Option.ReturnErrNil is false,so a missing key is told from 0,
+//return an error if the value isn't an integer
+func (r *Redis) GetInt64(key string) (int64, error) {
+	var value int64
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//GetFloat64 get the value of key as float64,return ErrNil if key does not exist
+func (r *Redis) GetFloat64(key string) (float64, error) {
+	var value float64
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//GetBool get the value of key as bool,1/0 or true/false,return ErrNil if key does not exist
+func (r *Redis) GetBool(key string) (bool, error) {
+	var value bool
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//GetTime get the value of key as time,RFC3339 or unix seconds,return ErrNil if key does not exist
+func (r *Redis) GetTime(key string) (time.Time, error) {
+	var value time.Time
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//SetInt64  see comment in typed.go
+func (r *RedisCluster) SetInt64(key string, value int64) (string, error) {
+	return r.Set(key, strconv.FormatInt(value, 10))
+}
+
+//SetFloat64  see comment in typed.go
+func (r *RedisCluster) SetFloat64(key string, value float64) (string, error) {
+	return r.Set(key, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+//SetBool  see comment in typed.go
+func (r *RedisCluster) SetBool(key string, value bool) (string, error) {
+	return r.Set(key, formatBool(value))
+}
+
+//SetTime  see comment in typed.go
+func (r *RedisCluster) SetTime(key string, value time.Time) (string, error) {
+	return r.Set(key, value.Format(time.RFC3339Nano))
+}
+
+//GetInt64  see comment in typed.go
+func (r *RedisCluster) GetInt64(key string) (int64, error) {
+	var value int64
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//GetFloat64  see comment in typed.go
+func (r *RedisCluster) GetFloat64(key string) (float64, error) {
+	var value float64
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//GetBool  see comment in typed.go
+func (r *RedisCluster) GetBool(key string) (bool, error) {
+	var value bool
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//GetTime  see comment in typed.go
+func (r *RedisCluster) GetTime(key string) (time.Time, error) {
+	var value time.Time
+	err := r.GetScan(key, &value)
+	return value, err
+}
+
+//</editor-fold>
@@ -0,0 +1,64 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRedis_TypedGetters(t *testing.T) {
+	flushAll()
+	redis := NewRedis(option)
+	defer redis.Close()
+	_, err := redis.SetInt64("int", -42)
+	assert.Nil(t, err)
+	i, err := redis.GetInt64("int")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-42), i)
+	redis.IncrBy("int", 2)
+	i, _ = redis.GetInt64("int")
+	assert.Equal(t, int64(-40), i)
+
+	redis.SetFloat64("float", 1.5)
+	s, _ := redis.Get("float")
+	assert.Equal(t, "1.5", s)
+	f, err := redis.GetFloat64("float")
+	assert.Nil(t, err)
+	assert.Equal(t, 1.5, f)
+
+	redis.SetBool("bool", true)
+	b, err := redis.GetBool("bool")
+	assert.Nil(t, err)
+	assert.True(t, b)
+	redis.Set("bool", "false")
+	b, err = redis.GetBool("bool")
+	assert.Nil(t, err)
+	assert.False(t, b)
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	redis.SetTime("time", now)
+	tm, err := redis.GetTime("time")
+	assert.Nil(t, err)
+	assert.True(t, now.Equal(tm))
+
+	//a missing key is told from zero
+	i, err = redis.GetInt64("missing")
+	assert.Equal(t, ErrNil, err)
+	assert.Equal(t, int64(0), i)
+	_, err = redis.GetBool("missing")
+	assert.Equal(t, ErrNil, err)
+	_, err = redis.GetInt64("float")
+	assert.NotNil(t, err)
+
+	cluster := newFakeRedisCluster([]interface{}{
+		[]interface{}{int64(0), int64(16383), []interface{}{[]byte("localhost"), int64(6379)}},
+	})
+	defer cluster.Close()
+	_, err = cluster.SetInt64("int", 7)
+	assert.Nil(t, err)
+	i, err = cluster.GetInt64("int")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), i)
+	_, err = cluster.GetTime("missing")
+	assert.Equal(t, ErrNil, err)
+}
@@ -0,0 +1,124 @@
+package godis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+)
+
+//UniversalOption option of NewUniversalClient,the kind of client is chosen by it:
+//a sentinel backed client if MasterName is set,a cluster client if Cluster is true or there are many addresses,
+//otherwise a standalone client
+type UniversalOption struct {
+	Addrs      []string    //host:port of the redis,the sentinels or the cluster nodes
+	MasterName string      //name of the master monitored by the sentinels
+	Cluster    bool        //connect to a cluster even if there is only one address
+	Option     *Option     //template of the connections,such as password,db and timeouts,Host and Port are ignored
+	PoolConfig *PoolConfig //config of the pool of every node
+
+	MaxAttempts int //attempts of a cluster command,default 5
+}
+
+//ManagedClient a UniversalClient holding pools of connections,close it when it is no longer used
+type ManagedClient interface {
+	UniversalClient
+	Close()
+}
+
+var (
+	_ ManagedClient = (*PooledRedis)(nil)
+	_ ManagedClient = (*RedisCluster)(nil)
+)
+
+//NewUniversalClient create a standalone,sentinel backed or cluster client by the option,
+//so a library can support all of them with one code path.
+//
+//the standalone and sentinel backed clients are PooledRedis,the cluster client is RedisCluster,
+//all of them are safe for concurrent use.
+//the sentinel backed client connects to the master found by the first reachable sentinel,
+//it asks the sentinels again for every new connection,and the connections broken by a connection error
+//or a READONLY reply of a demoted master are replaced,so the client follows a failover
+func NewUniversalClient(option *UniversalOption) (ManagedClient, error) {
+	if len(option.Addrs) == 0 {
+		return nil, errors.New("no address")
+	}
+	template := &Option{}
+	if option.Option != nil {
+		template = option.Option
+	}
+	switch {
+	case option.MasterName != "":
+		master, err := discoverMaster(option.Addrs, option.MasterName, template)
+		if err != nil {
+			return nil, err
+		}
+		master.Dialer = masterDialer(option.Addrs, option.MasterName, template)
+		master.BreakOnReadOnly = true
+		return NewPooledRedis(master, option.PoolConfig), nil
+	case option.Cluster || len(option.Addrs) > 1:
+		return NewRedisCluster(&ClusterOption{
+			Nodes:             option.Addrs,
+			ConnectionTimeout: template.ConnectionTimeout,
+			SoTimeout:         template.SoTimeout,
+			MaxAttempts:       option.MaxAttempts,
+			Password:          template.Password,
+			PoolConfig:        option.PoolConfig,
+			Dialer:            template.Dialer,
+		}), nil
+	}
+	host, port, err := net.SplitHostPort(option.Addrs[0])
+	if err != nil {
+		return nil, err
+	}
+	opt, err := nodeOption(template, host, port)
+	if err != nil {
+		return nil, err
+	}
+	return NewPooledRedis(opt, option.PoolConfig), nil
+}
+
+//discoverMaster ask the sentinels for the address of the master,return the error of the last sentinel if all fail
+func discoverMaster(sentinels []string, masterName string, template *Option) (*Option, error) {
+	var lastErr error
+	for _, addr := range sentinels {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, err
+		}
+		sentinel := NewRedis(&Option{Host: host, Port: p, ConnectionTimeout: template.ConnectionTimeout, SoTimeout: template.SoTimeout, Dialer: template.Dialer})
+		master, err := sentinel.SentinelGetMasterAddrByName(masterName)
+		sentinel.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(master) < 2 {
+			lastErr = errors.New("master " + masterName + " is unknown by sentinel " + addr)
+			continue
+		}
+		return nodeOption(template, master[0], master[1])
+	}
+	return nil, lastErr
+}
+
+//masterDialer dial the master known by the sentinels at the moment instead of addr,
+//so the connections dialed after a failover go to the new master
+func masterDialer(sentinels []string, masterName string, template *Option) DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		master, err := discoverMaster(sentinels, masterName, template)
+		if err != nil {
+			return nil, err
+		}
+		addr = net.JoinHostPort(master.Host, strconv.Itoa(master.Port))
+		if template.Dialer != nil {
+			return template.Dialer(ctx, network, addr)
+		}
+		dialer := &net.Dialer{KeepAlive: template.KeepAlive}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
@@ -0,0 +1,98 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewUniversalClient(t *testing.T) {
+	flushAll()
+	client, err := NewUniversalClient(&UniversalOption{Addrs: []string{"localhost:6379"}})
+	assert.Nil(t, err)
+	assert.IsType(t, &PooledRedis{}, client)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Incr("godis")
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	value, err := client.Get("godis")
+	assert.Nil(t, err)
+	assert.Equal(t, "10", value)
+	client.Close()
+
+	sentinelOption, closeSentinel := newFakeServer(t, map[string][]string{
+		"SENTINEL get-master-addr-by-name mymaster": {"*2\r\n$9\r\nlocalhost\r\n$4\r\n6379\r\n"},
+		"SENTINEL get-master-addr-by-name unknown":  {"*-1\r\n"},
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeSentinel()
+	sentinel := "localhost:" + strconv.Itoa(sentinelOption.Port)
+	client, err = NewUniversalClient(&UniversalOption{Addrs: []string{"localhost:6380", sentinel}, MasterName: "mymaster", Option: &Option{Db: 1}})
+	assert.Nil(t, err)
+	_, err = client.Set("godis", "good")
+	assert.Nil(t, err)
+	redis, err := client.(*PooledRedis).Pool().GetResource()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, redis.client.Db)
+	redis.Close()
+	client.Close()
+	_, err = NewUniversalClient(&UniversalOption{Addrs: []string{sentinel}, MasterName: "unknown"})
+	assert.NotNil(t, err)
+
+	client, err = NewUniversalClient(&UniversalOption{Addrs: []string{"localhost:7000", "localhost:7001"}})
+	assert.Nil(t, err)
+	assert.IsType(t, &RedisCluster{}, client)
+	client.Close()
+
+	_, err = NewUniversalClient(&UniversalOption{})
+	assert.NotNil(t, err)
+	_, err = NewUniversalClient(&UniversalOption{Addrs: []string{"localhost"}})
+	assert.NotNil(t, err)
+}
+
+func TestNewUniversalClient_Failover(t *testing.T) {
+	demoted, closeDemoted := newFakeServer(t, map[string][]string{
+		"SET godis good": {"-READONLY You can't write against a read only replica.\r\n"},
+		"QUIT":           {"+OK\r\n"},
+	})
+	defer closeDemoted()
+	promoted, closePromoted := newFakeServer(t, map[string][]string{
+		"SET godis good": {"+OK\r\n"},
+		"QUIT":           {"+OK\r\n"},
+	})
+	defer closePromoted()
+	masterAddr := func(option *Option) map[string][]string {
+		port := strconv.Itoa(option.Port)
+		return map[string][]string{
+			"SENTINEL get-master-addr-by-name mymaster": {"*2\r\n$9\r\nlocalhost\r\n$" + strconv.Itoa(len(port)) + "\r\n" + port + "\r\n"},
+			"QUIT": {"+OK\r\n"},
+		}
+	}
+	//the sentinel knows the demoted master until the failover
+	sentinelPort, closeSentinel := listenLater(t, 0, masterAddr(demoted), masterAddr(demoted), masterAddr(promoted))
+	defer closeSentinel()
+	time.Sleep(50 * time.Millisecond)
+	client, err := NewUniversalClient(&UniversalOption{
+		Addrs:      []string{"localhost:" + strconv.Itoa(sentinelPort)},
+		MasterName: "mymaster",
+		PoolConfig: &PoolConfig{MaxTotal: 1},
+	})
+	assert.Nil(t, err)
+	defer client.Close()
+	_, err = client.Set("godis", "good")
+	var readOnly *ReadOnlyError
+	assert.True(t, errors.As(err, &readOnly))
+	//the connection to the demoted master is dropped,the new one asks the sentinel again
+	s, err := client.Set("godis", "good")
+	assert.Nil(t, err)
+	assert.Equal(t, "OK", s)
+}
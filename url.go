@@ -0,0 +1,157 @@
+package godis
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ParseURL parse a redis url into option and pool config,supported formats:
+//
+//	redis://[[user]:password@]host[:port][/db][?option=value]
+//	rediss://[[user]:password@]host[:port][/db][?option=value]
+//	unix://[[user]:password@]/path/to/redis.sock[?db=db][&option=value]
+//
+//supported query options:
+//
+//	db                     db index,overrides the path
+//	dial_timeout           connect timeout,such as 3s,a bare number means seconds
+//	read_timeout           read timeout
+//	write_timeout          write timeout
+//	client_name            client name set by CLIENT SETNAME
+//	pool_size              max connections of the pool
+//	max_idle               max idle connections of the pool
+//	min_idle               min idle connections of the pool
+//	skip_verify            true to skip verifying the server certificate,only for rediss
+//
+//the pool config is nil if the url has no pool option
+func ParseURL(redisURL string) (*Option, *PoolConfig, error) {
+	u, err := url.Parse(redisURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	option := &Option{}
+	if u.User != nil {
+		option.Username = u.User.Username()
+		option.Password, _ = u.User.Password()
+	}
+	switch u.Scheme {
+	case "redis", "rediss":
+		if err := parseHostPort(u, option); err != nil {
+			return nil, nil, err
+		}
+		if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+			if option.Db, err = strconv.Atoi(db); err != nil {
+				return nil, nil, fmt.Errorf("invalid database: %s", db)
+			}
+		}
+		if u.Scheme == "rediss" {
+			option.TLSConfig = &tls.Config{ServerName: option.Host}
+		}
+	case "unix":
+		if u.Path == "" {
+			return nil, nil, errors.New("socket path is empty")
+		}
+		option.Network = "unix"
+		option.Host = u.Path
+	default:
+		return nil, nil, fmt.Errorf("invalid redis url scheme: %s", u.Scheme)
+	}
+	poolConfig, err := parseQuery(u.Query(), option)
+	if err != nil {
+		return nil, nil, err
+	}
+	return option, poolConfig, nil
+}
+
+func parseHostPort(u *url.URL, option *Option) error {
+	option.Host = u.Hostname()
+	if option.Host == "" {
+		option.Host = defaultHost
+	}
+	option.Port = defaultPort
+	if port := u.Port(); port != "" {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return fmt.Errorf("invalid port: %s", port)
+		}
+		option.Port = p
+	}
+	return nil
+}
+
+func parseQuery(query url.Values, option *Option) (*PoolConfig, error) {
+	var poolConfig *PoolConfig
+	var err error
+	for key := range query {
+		value := query.Get(key)
+		switch key {
+		case "db":
+			option.Db, err = strconv.Atoi(value)
+		case "dial_timeout":
+			option.ConnectionTimeout, err = parseURLDuration(value)
+		case "read_timeout":
+			option.SoTimeout, err = parseURLDuration(value)
+		case "write_timeout":
+			option.WriteTimeout, err = parseURLDuration(value)
+		case "client_name":
+			option.ClientName = value
+		case "pool_size", "max_idle", "min_idle":
+			if poolConfig == nil {
+				poolConfig = &PoolConfig{}
+			}
+			var n int
+			n, err = strconv.Atoi(value)
+			switch key {
+			case "pool_size":
+				poolConfig.MaxTotal = n
+			case "max_idle":
+				poolConfig.MaxIdle = n
+			default:
+				poolConfig.MinIdle = n
+			}
+		case "skip_verify":
+			var skip bool
+			skip, err = strconv.ParseBool(value)
+			if option.TLSConfig != nil {
+				option.TLSConfig.InsecureSkipVerify = skip
+			}
+		default:
+			return nil, fmt.Errorf("unknown redis url option: %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url option %s: %s", key, value)
+		}
+	}
+	return poolConfig, nil
+}
+
+//parseURLDuration parse 3s or 500ms,a bare number means seconds
+func parseURLDuration(value string) (time.Duration, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		return time.Duration(n) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
+
+//NewRedisFromURL create new redis by url,see ParseURL
+func NewRedisFromURL(redisURL string) (*Redis, error) {
+	option, _, err := ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewRedis(option), nil
+}
+
+//NewPoolFromURL create new pool by url,see ParseURL
+func NewPoolFromURL(redisURL string) (*Pool, error) {
+	option, poolConfig, err := ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewPool(poolConfig, option), nil
+}
@@ -0,0 +1,74 @@
+package godis
+
+import (
+	"strconv"
+	"strings"
+)
+
+//ServerVersion version of the redis server,such as 7.2.4
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+//ParseServerVersion parse the version such as 7.2.4,the missing parts are 0
+func ParseServerVersion(version string) (*ServerVersion, error) {
+	parts := strings.SplitN(strings.TrimSpace(version), ".", 3)
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, newDataError("invalid server version " + version)
+		}
+		numbers[i] = n
+	}
+	return &ServerVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+//String the version formatted as major.minor.patch
+func (v ServerVersion) String() string {
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)
+}
+
+//Less whether v is older than the other version
+func (v ServerVersion) Less(other ServerVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+//AtLeast whether v is the version major.minor or newer
+func (v ServerVersion) AtLeast(major, minor int) bool {
+	return !v.Less(ServerVersion{Major: major, Minor: minor})
+}
+
+//commandSince the version introducing the commands newer than redis 3.0,keyed by upper case name,
+//they fail with ErrUnsupportedVersion before sending if the server version is known to be older
+var commandSince = map[string]ServerVersion{
+	"GEOADD": {3, 2, 0}, "GEODIST": {3, 2, 0}, "GEOHASH": {3, 2, 0}, "GEOPOS": {3, 2, 0},
+	"GEORADIUS": {3, 2, 0}, "GEORADIUSBYMEMBER": {3, 2, 0}, "GEORADIUS_RO": {3, 2, 10}, "GEORADIUSBYMEMBER_RO": {3, 2, 10},
+	"HSTRLEN": {3, 2, 0}, "TOUCH": {3, 2, 1}, "BITFIELD": {3, 2, 0},
+	"UNLINK": {4, 0, 0}, "SWAPDB": {4, 0, 0}, "MEMORY": {4, 0, 0}, "MODULE": {4, 0, 0},
+	"ZPOPMIN": {5, 0, 0}, "ZPOPMAX": {5, 0, 0}, "BZPOPMIN": {5, 0, 0}, "BZPOPMAX": {5, 0, 0}, "LOLWUT": {5, 0, 0},
+	"XADD": {5, 0, 0}, "XLEN": {5, 0, 0}, "XDEL": {5, 0, 0}, "XTRIM": {5, 0, 0}, "XRANGE": {5, 0, 0}, "XREVRANGE": {5, 0, 0},
+	"XREAD": {5, 0, 0}, "XACK": {5, 0, 0}, "XGROUP": {5, 0, 0}, "XREADGROUP": {5, 0, 0}, "XPENDING": {5, 0, 0}, "XCLAIM": {5, 0, 0},
+	"BITFIELD_RO": {6, 0, 0}, "LPOS": {6, 0, 6},
+	"RESET": {6, 2, 0}, "GETDEL": {6, 2, 0}, "ZDIFF": {6, 2, 0}, "ZDIFFSTORE": {6, 2, 0}, "ZUNION": {6, 2, 0}, "ZINTER": {6, 2, 0}, "XAUTOCLAIM": {6, 2, 0},
+	"EVAL_RO": {7, 0, 0}, "LMPOP": {7, 0, 0}, "BLMPOP": {7, 0, 0}, "ZMPOP": {7, 0, 0}, "BZMPOP": {7, 0, 0}, "EVALSHA_RO": {7, 0, 0}, "SINTERCARD": {7, 0, 0}, "ZINTERCARD": {7, 0, 0}, "SPUBLISH": {7, 0, 0}, "SSUBSCRIBE": {7, 0, 0}, "SUNSUBSCRIBE": {7, 0, 0},
+	"HEXPIRE": {7, 4, 0}, "HPEXPIRE": {7, 4, 0}, "HEXPIREAT": {7, 4, 0}, "HPERSIST": {7, 4, 0}, "HTTL": {7, 4, 0}, "HPTTL": {7, 4, 0},
+}
+
+//parseInfoVersion find redis_version in the server section of INFO
+func parseInfoVersion(info string) (*ServerVersion, error) {
+	for _, line := range strings.Split(info, "\n") {
+		if strings.HasPrefix(line, "redis_version:") {
+			return ParseServerVersion(strings.TrimPrefix(line, "redis_version:"))
+		}
+	}
+	return nil, newDataError("redis_version not found in INFO")
+}
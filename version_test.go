@@ -0,0 +1,93 @@
+package godis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	v, err := ParseServerVersion("7.2.4")
+	assert.Nil(t, err)
+	assert.Equal(t, &ServerVersion{Major: 7, Minor: 2, Patch: 4}, v)
+	assert.Equal(t, "7.2.4", v.String())
+	assert.True(t, v.AtLeast(7, 0))
+	assert.True(t, v.AtLeast(7, 2))
+	assert.False(t, v.AtLeast(7, 4))
+	assert.True(t, ServerVersion{6, 0, 5}.Less(ServerVersion{6, 0, 6}))
+	assert.False(t, ServerVersion{6, 2, 0}.Less(ServerVersion{6, 0, 6}))
+
+	v, err = ParseServerVersion("6.2")
+	assert.Nil(t, err)
+	assert.Equal(t, "6.2.0", v.String())
+	_, err = ParseServerVersion("unstable")
+	assert.NotNil(t, err)
+
+	v, err = parseInfoVersion("# Server\r\nredis_version:5.0.7\r\nredis_mode:standalone\r\n")
+	assert.Nil(t, err)
+	assert.Equal(t, "5.0.7", v.String())
+	_, err = parseInfoVersion("# Server\r\n")
+	assert.NotNil(t, err)
+}
+
+func TestRedis_DetectVersion(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"INFO server":      {fakeBulk("# Server\r\nredis_version:6.2.14\r\n")},
+		"PING":             {"+PONG\r\n"},
+		"LPOS godis a":     {":0\r\n"},
+		"SINTERCARD 1 god": {":1\r\n"},
+		"QUIT":             {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.DetectVersion = true
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	//the first command is checked too
+	_, err := redis.SInterCard(0, "god")
+	assert.True(t, errors.Is(err, ErrUnsupportedVersion))
+	var versionErr *UnsupportedVersionError
+	assert.True(t, errors.As(err, &versionErr))
+	assert.Equal(t, "SINTERCARD", versionErr.Command)
+	assert.Equal(t, ServerVersion{7, 0, 0}, versionErr.Required)
+	assert.Equal(t, ServerVersion{6, 2, 14}, versionErr.Server)
+	v, err := redis.ServerVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, "6.2.14", v.String())
+	//the connection is still usable
+	s, err := redis.Ping()
+	assert.Nil(t, err)
+	assert.Equal(t, "PONG", s)
+	_, err = redis.LPos("godis", "a")
+	assert.Nil(t, err)
+
+	//without DetectVersion the commands are sent until the version is queried
+	fakeOption.DetectVersion = false
+	redis2 := NewRedis(fakeOption)
+	defer redis2.Close()
+	c, err := redis2.SInterCard(0, "god")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	v, err = redis2.ServerVersion()
+	assert.Nil(t, err)
+	assert.Equal(t, "6.2.14", v.String())
+	_, err = redis2.SInterCard(0, "god")
+	assert.True(t, errors.Is(err, ErrUnsupportedVersion))
+}
+
+func TestRedis_DetectVersionRejected(t *testing.T) {
+	//INFO is rejected,the version is unknown and nothing is checked
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"INFO server":      {"-NOPERM this user has no permissions to run the 'info' command\r\n"},
+		"SINTERCARD 1 god": {":1\r\n"},
+		"QUIT":             {"+OK\r\n"},
+	})
+	defer closeServer()
+	fakeOption.DetectVersion = true
+	redis := NewRedis(fakeOption)
+	defer redis.Close()
+	c, err := redis.SInterCard(0, "god")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), c)
+	_, err = redis.ServerVersion()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,196 @@
+package godis
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatcherQueueSize     = 100
+	defaultWatcherRetryInterval = time.Second
+)
+
+//the event channels of sentinel watched by default
+var defaultSentinelChannels = []string{"+sdown", "-sdown", "+odown", "-odown", "+switch-master", "+slave"}
+
+//SentinelWatcherOption option of SentinelWatcher
+type SentinelWatcherOption struct {
+	Channels      []string        //event channels to subscribe,default +sdown,-sdown,+odown,-odown,+switch-master and +slave
+	QueueSize     int             //max count of events waiting to be read from Events,default 100
+	RetryInterval time.Duration   //wait before subscribing again after the connection is lost,default 1s
+	PingInterval  time.Duration   //keep-alive of the subscription,see RedisPubSub.PingInterval,0 means disabled
+	OnError       func(err error) //called when the subscription fails,it must not block
+}
+
+//SentinelEvent event published by sentinel,
+//most events are formatted as: <instance-type> <name> <ip> <port> @ <master-name> <master-ip> <master-port>,
+//the part after @ is absent for masters
+type SentinelEvent struct {
+	Channel      string //type of the event,such as +sdown or +switch-master
+	InstanceType string //master,slave or sentinel
+	Name         string
+	IP           string //address of the instance,the new address of the master for +switch-master
+	Port         int
+	MasterName   string //the master of the instance,the instance itself for masters
+	MasterIP     string
+	MasterPort   int
+	OldIP        string //the old address of the master for +switch-master
+	OldPort      int
+	Payload      string //the raw message
+}
+
+//SentinelWatcher subscribe the event channels of a sentinel and deliver the events to Events,
+//the subscription is established again when the connection is lost,
+//events published while it is down are lost.
+//
+//SentinelWatcher is safe for concurrent use
+type SentinelWatcher struct {
+	sentinel Option
+	option   SentinelWatcherOption
+	events   chan *SentinelEvent
+	done     chan struct{}
+	stopped  chan struct{}
+
+	mu     sync.Mutex
+	pubsub *RedisPubSub //the running subscription,nil until it is acknowledged
+	closed bool
+}
+
+//NewSentinelWatcher create new sentinel watcher and start watching the sentinel in background
+func NewSentinelWatcher(sentinel *Option, option *SentinelWatcherOption) *SentinelWatcher {
+	opt := SentinelWatcherOption{}
+	if option != nil {
+		opt = *option
+	}
+	if len(opt.Channels) == 0 {
+		opt.Channels = defaultSentinelChannels
+	}
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = defaultWatcherQueueSize
+	}
+	if opt.RetryInterval <= 0 {
+		opt.RetryInterval = defaultWatcherRetryInterval
+	}
+	w := &SentinelWatcher{
+		sentinel: *sentinel,
+		option:   opt,
+		events:   make(chan *SentinelEvent, opt.QueueSize),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+//Events the events published by the sentinel,it is closed after Close
+func (w *SentinelWatcher) Events() <-chan *SentinelEvent {
+	return w.events
+}
+
+//Close unsubscribe the channels and wait until the watcher stops
+func (w *SentinelWatcher) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	var err error
+	if w.pubsub != nil {
+		err = w.pubsub.UnSubscribe()
+	}
+	w.mu.Unlock()
+	<-w.stopped
+	return err
+}
+
+//run subscribe the channels until the watcher is closed
+func (w *SentinelWatcher) run() {
+	defer close(w.stopped)
+	defer close(w.events)
+	for {
+		err := w.watch()
+		if w.isClosed() {
+			return
+		}
+		if err != nil && w.option.OnError != nil {
+			w.option.OnError(err)
+		}
+		select {
+		case <-time.After(w.option.RetryInterval):
+		case <-w.done:
+			return
+		}
+	}
+}
+
+//watch subscribe the channels and deliver the events until the subscription ends
+func (w *SentinelWatcher) watch() error {
+	redis := NewRedis(&w.sentinel)
+	defer redis.Close()
+	pubsub := &RedisPubSub{PingInterval: w.option.PingInterval}
+	pubsub.OnSubscribe = func(channel string, subscribedChannels int) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.closed {
+			//closed before the subscription is acknowledged
+			pubsub.UnSubscribe(channel)
+			return
+		}
+		w.pubsub = pubsub
+	}
+	pubsub.OnMessage = func(channel, message string) {
+		select {
+		case w.events <- parseSentinelEvent(channel, message):
+		case <-w.done:
+		}
+	}
+	defer func() {
+		w.mu.Lock()
+		w.pubsub = nil
+		w.mu.Unlock()
+	}()
+	return redis.Subscribe(pubsub, w.option.Channels...)
+}
+
+func (w *SentinelWatcher) isClosed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closed
+}
+
+//parseSentinelEvent parse the message published to the event channel of sentinel
+func parseSentinelEvent(channel, message string) *SentinelEvent {
+	event := &SentinelEvent{Channel: channel, Payload: message}
+	fields := strings.Fields(message)
+	if channel == "+switch-master" {
+		//<master-name> <old-ip> <old-port> <new-ip> <new-port>
+		if len(fields) == 5 {
+			event.InstanceType = "master"
+			event.Name, event.MasterName = fields[0], fields[0]
+			event.OldIP, event.OldPort = fields[1], atoiOrZero(fields[2])
+			event.IP, event.Port = fields[3], atoiOrZero(fields[4])
+			event.MasterIP, event.MasterPort = event.IP, event.Port
+		}
+		return event
+	}
+	if len(fields) < 4 {
+		return event
+	}
+	event.InstanceType, event.Name = fields[0], fields[1]
+	event.IP, event.Port = fields[2], atoiOrZero(fields[3])
+	if len(fields) >= 8 && fields[4] == "@" {
+		event.MasterName, event.MasterIP, event.MasterPort = fields[5], fields[6], atoiOrZero(fields[7])
+	} else if event.InstanceType == "master" {
+		event.MasterName, event.MasterIP, event.MasterPort = event.Name, event.IP, event.Port
+	}
+	return event
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,72 @@
+package godis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSentinelWatcher(t *testing.T) {
+	fakeOption, closeServer := newFakeServer(t, map[string][]string{
+		"SUBSCRIBE +sdown +switch-master": {
+			"*3\r\n$9\r\nsubscribe\r\n$6\r\n+sdown\r\n:1\r\n",
+			"*3\r\n$9\r\nsubscribe\r\n$14\r\n+switch-master\r\n:2\r\n",
+			"*3\r\n$7\r\nmessage\r\n$6\r\n+sdown\r\n" + fakeBulk("slave 127.0.0.1:6380 127.0.0.1 6380 @ mymaster 127.0.0.1 6379"),
+			"*3\r\n$7\r\nmessage\r\n$14\r\n+switch-master\r\n" + fakeBulk("mymaster 127.0.0.1 6379 127.0.0.1 6380"),
+		},
+		"UNSUBSCRIBE": {
+			"*3\r\n$11\r\nunsubscribe\r\n$6\r\n+sdown\r\n:1\r\n",
+			"*3\r\n$11\r\nunsubscribe\r\n$14\r\n+switch-master\r\n:0\r\n",
+		},
+		"QUIT": {"+OK\r\n"},
+	})
+	defer closeServer()
+	watcher := NewSentinelWatcher(fakeOption, &SentinelWatcherOption{Channels: []string{"+sdown", "+switch-master"}})
+	event := <-watcher.Events()
+	assert.Equal(t, &SentinelEvent{
+		Channel:      "+sdown",
+		InstanceType: "slave",
+		Name:         "127.0.0.1:6380",
+		IP:           "127.0.0.1",
+		Port:         6380,
+		MasterName:   "mymaster",
+		MasterIP:     "127.0.0.1",
+		MasterPort:   6379,
+		Payload:      "slave 127.0.0.1:6380 127.0.0.1 6380 @ mymaster 127.0.0.1 6379",
+	}, event)
+	event = <-watcher.Events()
+	assert.Equal(t, "mymaster", event.MasterName)
+	assert.Equal(t, "127.0.0.1", event.OldIP)
+	assert.Equal(t, 6379, event.OldPort)
+	assert.Equal(t, 6380, event.Port)
+	assert.Equal(t, 6380, event.MasterPort)
+	assert.Nil(t, watcher.Close())
+	_, ok := <-watcher.Events()
+	assert.False(t, ok)
+	assert.Nil(t, watcher.Close())
+
+	//the sentinel is unreachable,the errors are reported until the watcher is closed
+	errs := make(chan error, 10)
+	watcher = NewSentinelWatcher(&Option{Host: "localhost", Port: 6380}, &SentinelWatcherOption{
+		RetryInterval: 10 * time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+	assert.NotNil(t, <-errs)
+	assert.Nil(t, watcher.Close())
+}
+
+func TestParseSentinelEvent(t *testing.T) {
+	event := parseSentinelEvent("+odown", "master mymaster 127.0.0.1 6379 #quorum 2/2")
+	assert.Equal(t, "master", event.InstanceType)
+	assert.Equal(t, "mymaster", event.MasterName)
+	assert.Equal(t, 6379, event.MasterPort)
+	event = parseSentinelEvent("+new-epoch", "10")
+	assert.Equal(t, &SentinelEvent{Channel: "+new-epoch", Payload: "10"}, event)
+	event = parseSentinelEvent("+switch-master", "mymaster")
+	assert.Equal(t, "", event.MasterName)
+}